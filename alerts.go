@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Alert representa una alerta disparada por una regla sobre un símbolo
+type Alert struct {
+	Rule     string    `json:"rule"`
+	Severity string    `json:"severity"`
+	Symbol   string    `json:"symbol"`
+	Value    float64   `json:"value"`
+	Message  string    `json:"message"`
+	Time     time.Time `json:"time"`
+}
+
+// AlertRecord es una entrada del registro de auditoría: una alerta enviada por un canal
+type AlertRecord struct {
+	Time    time.Time `json:"time"`
+	Rule    string    `json:"rule"`
+	Symbol  string    `json:"symbol"`
+	Value   float64   `json:"value"`
+	Channel string    `json:"channel"`
+	Result  string    `json:"result"` // "ok" o el motivo resumido del fallo
+}
+
+const alertHistoryFile = "alerts_history.jsonl"
+
+var alertHistoryMu sync.Mutex
+
+// dispatchAlerts envía las alertas agrupadas por los canales que les asigna la matriz de ruteo
+// y registra cada envío en el historial; el snapshot del ciclo queda disponible para el template "alerts"
+func dispatchAlerts(notifiers []Notifier, title string, alerts []Alert, snapshot *Snapshot) {
+	if len(alerts) == 0 {
+		return
+	}
+
+	for channels, group := range routeAlerts(notifiers, alerts) {
+		var b strings.Builder
+		for _, alert := range group {
+			fmt.Fprintf(&b, "  %s\n", alert.Message)
+		}
+		text := renderMessage(TemplateAlerts, AlertsMessage{Title: title, Alerts: group, Snapshot: snapshot, Default: b.String()}, b.String())
+
+		deliveries := notifyAll(notifiersFor(notifiers, channels), Notification{Title: title, Text: text})
+		if err := recordAlerts(group, deliveries); err != nil {
+			fmt.Printf("Error al registrar el historial de alertas: %v\n", err)
+		}
+	}
+
+	for _, alert := range alerts {
+		runHooks(EventAlert, alert)
+	}
+}
+
+// deliveryReason resume por qué falló un envío sin copiar el texto del error: los errores de los notificadores
+// pueden incluir URLs con tokens (Telegram, webhooks) o direcciones de correo
+func deliveryReason(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "error: timeout"
+	case errors.As(err, &netErr):
+		return "error: red"
+	default:
+		return "error: rechazado por el canal"
+	}
+}
+
+// recordAlerts agrega al historial una entrada por alerta y canal de envío
+func recordAlerts(alerts []Alert, deliveries []Delivery) error {
+	path, err := appFile(alertHistoryFile)
+	if err != nil {
+		return err
+	}
+
+	alertHistoryMu.Lock()
+	defer alertHistoryMu.Unlock()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	encoder := json.NewEncoder(file)
+	for _, alert := range alerts {
+		for _, delivery := range deliveries {
+			result := "ok"
+			if delivery.Err != nil {
+				result = deliveryReason(delivery.Err)
+			}
+			record := AlertRecord{
+				Time:    alert.Time,
+				Rule:    alert.Rule,
+				Symbol:  alert.Symbol,
+				Value:   alert.Value,
+				Channel: delivery.Channel,
+				Result:  result,
+			}
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// readAlertHistory lee el historial de alertas completo
+func readAlertHistory() ([]AlertRecord, error) {
+	path, err := appFile(alertHistoryFile)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []AlertRecord
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var record AlertRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			fmt.Printf("Línea %d del historial inválida: %v\n", line, err)
+			continue
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// runAlerts implementa `bolsa alerts <subcomando>`
+func runAlerts(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: bolsa alerts history|list|ack|snooze|disable|enable [opciones]")
+	}
+
+	switch args[0] {
+	case "history":
+		return runAlertsHistory(args[1:])
+	case "list", "ack", "snooze", "disable", "enable":
+		return alertManagementCommand(args)
+	default:
+		return fmt.Errorf("subcomando desconocido de alerts: %s", args[0])
+	}
+}
+
+// runAlertsHistory muestra el historial de alertas disparadas con filtros opcionales
+func runAlertsHistory(args []string) error {
+	fs := flag.NewFlagSet("alerts history", flag.ExitOnError)
+	symbol := fs.String("symbol", "", "filtrar por símbolo")
+	rule := fs.String("rule", "", "filtrar por regla")
+	since := fs.String("since", "", "mostrar alertas desde esta fecha (AAAA-MM-DD)")
+	failed := fs.Bool("failed", false, "mostrar solo los envíos fallidos")
+	limit := fs.Int("limit", 50, "cantidad máxima de entradas (las más recientes)")
+	fs.Parse(args)
+
+	var sinceTime time.Time
+	if *since != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", *since, time.Local)
+		if err != nil {
+			return fmt.Errorf("fecha inválida %q: %v", *since, err)
+		}
+		sinceTime = parsed
+	}
+
+	records, err := readAlertHistory()
+	if err != nil {
+		return err
+	}
+
+	var filtered []AlertRecord
+	for _, r := range records {
+		if *symbol != "" && !strings.EqualFold(r.Symbol, *symbol) {
+			continue
+		}
+		if *rule != "" && r.Rule != *rule {
+			continue
+		}
+		if r.Time.Before(sinceTime) {
+			continue
+		}
+		if *failed && r.Result == "ok" {
+			continue
+		}
+		filtered = append(filtered, r)
+	}
+
+	if *limit > 0 && len(filtered) > *limit {
+		filtered = filtered[len(filtered)-*limit:]
+	}
+	if len(filtered) == 0 {
+		fmt.Println("No hay alertas registradas con esos filtros.")
+		return nil
+	}
+
+	fmt.Printf("%-20s %-16s %-10s %12s %-10s %s\n", "Fecha", "Regla", "Símbolo", "Valor", "Canal", "Resultado")
+	for _, r := range filtered {
+		resultColor := Green
+		if r.Result != "ok" {
+			resultColor = Red
+		}
+		fmt.Printf("%-20s %-16s %-10s %12.2f %-10s %s%s%s\n",
+			r.Time.Local().Format("2006-01-02 15:04:05"), r.Rule, r.Symbol, r.Value, r.Channel,
+			resultColor, r.Result, Reset)
+	}
+	return nil
+}
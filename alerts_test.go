@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAlertsStoresSanitizedReason(t *testing.T) {
+	path, err := appFile(alertHistoryFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+
+	const secret = "123456:AAH-secreto"
+	leaky := &url.Error{Op: "Post", URL: "https://api.telegram.org/bot" + secret + "/sendMessage", Err: errors.New("connection refused")}
+	alert := Alert{Rule: "ggal-baja", Symbol: "GGAL.BA", Value: -5, Time: time.Now()}
+	deliveries := []Delivery{
+		{Channel: "telegram", Err: leaky},
+		{Channel: "email", Err: errors.New("550 buzón lleno de usuario@ejemplo.com")},
+		{Channel: "desktop"},
+	}
+	if err := recordAlerts([]Alert{alert}, deliveries); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, leak := range []string{secret, "usuario@ejemplo.com"} {
+		if strings.Contains(string(data), leak) {
+			t.Errorf("el historial contiene %q:\n%s", leak, data)
+		}
+	}
+
+	records, err := readAlertHistory()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"telegram": "error: red", "email": "error: rechazado por el canal", "desktop": "ok"}
+	if len(records) != len(want) {
+		t.Fatalf("se esperaban %d registros, hay %d", len(want), len(records))
+	}
+	for _, r := range records {
+		if r.Result != want[r.Channel] {
+			t.Errorf("%s: resultado %q, se esperaba %q", r.Channel, r.Result, want[r.Channel])
+		}
+	}
+}
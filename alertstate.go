@@ -0,0 +1,362 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AlertRule es una regla de alerta del usuario sobre un símbolo ("*" aplica a todos)
+type AlertRule struct {
+	Name      string `json:"name"`
+	Symbol    string `json:"symbol"`
+	Condition string `json:"condition"`
+	Severity  string `json:"severity"` // info, warning (por defecto) o critical; la usa la matriz de ruteo
+
+	parsed Condition
+}
+
+// AlertEpisode representa una condición que se mantiene activa para un símbolo
+type AlertEpisode struct {
+	Since        time.Time `json:"since"`
+	LastNotified time.Time `json:"lastNotified"`
+	Acked        bool      `json:"acked"`
+}
+
+// AlertRuleState guarda la gestión de una regla: deshabilitada, pospuesta y episodios activos
+type AlertRuleState struct {
+	Disabled      bool                     `json:"disabled"`
+	DisabledUntil time.Time                `json:"disabledUntil,omitempty"` // Vacío = hasta rehabilitarla
+	SnoozedUntil  time.Time                `json:"snoozedUntil,omitempty"`
+	Active        map[string]*AlertEpisode `json:"active,omitempty"` // Por símbolo
+}
+
+const alertStateFile = "alert_state.json"
+
+// El estado se comparte entre el monitor y los comandos `bolsa alerts`, a través del archivo
+var alertStateMu sync.Mutex
+
+// loadAlertState lee el estado de gestión de alertas
+func loadAlertState() (map[string]*AlertRuleState, error) {
+	state := make(map[string]*AlertRuleState)
+
+	path, err := appFile(alertStateFile)
+	if err != nil {
+		return state, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return make(map[string]*AlertRuleState), fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return state, nil
+}
+
+// saveAlertState escribe el estado de gestión de alertas
+func saveAlertState(state map[string]*AlertRuleState) error {
+	path, err := appFile(alertStateFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// ruleState devuelve el estado de una regla, creándolo si no existe
+func ruleState(state map[string]*AlertRuleState, rule string) *AlertRuleState {
+	rs, ok := state[rule]
+	if !ok {
+		rs = &AlertRuleState{}
+		state[rule] = rs
+	}
+	if rs.Active == nil {
+		rs.Active = make(map[string]*AlertEpisode)
+	}
+	return rs
+}
+
+// isDisabled indica si la regla está deshabilitada en el instante now
+func (rs *AlertRuleState) isDisabled(now time.Time) bool {
+	return rs.Disabled && (rs.DisabledUntil.IsZero() || now.Before(rs.DisabledUntil))
+}
+
+// isSnoozed indica si la regla está pospuesta en el instante now
+func (rs *AlertRuleState) isSnoozed(now time.Time) bool {
+	return now.Before(rs.SnoozedUntil)
+}
+
+// alertAllowed indica si una regla puede notificar ahora (no deshabilitada ni pospuesta)
+func alertAllowed(rule string, now time.Time) bool {
+	alertStateMu.Lock()
+	defer alertStateMu.Unlock()
+
+	state, err := loadAlertState()
+	if err != nil {
+		fmt.Printf("Error al leer el estado de alertas: %v\n", err)
+		return true
+	}
+	rs, ok := state[rule]
+	if !ok {
+		return true
+	}
+	return !rs.isDisabled(now) && !rs.isSnoozed(now)
+}
+
+// evaluateAlertRules evalúa las reglas del usuario sobre el snapshot y dispara las alertas que correspondan:
+// una condición nueva notifica enseguida, una persistente se repite cada alertRepeat hasta que se reconozca
+func evaluateAlertRules(snapshot *Snapshot, notifiers []Notifier) {
+	cfg := appConfig()
+	rules := alertRules()
+	if len(rules) == 0 {
+		return
+	}
+
+	// Valores de cada símbolo disponibles para las condiciones
+	values := make(map[string]map[string]float64)
+	for _, forex := range snapshot.Forex {
+		values[forex.Symbol] = forexFields(forex)
+	}
+	for _, stock := range snapshot.Stocks {
+		values[stock.Symbol] = stockFields(stock)
+	}
+
+	alertStateMu.Lock()
+	defer alertStateMu.Unlock()
+
+	state, err := loadAlertState()
+	if err != nil {
+		fmt.Printf("Error al leer el estado de alertas: %v\n", err)
+	}
+
+	now := time.Now()
+	repeat := time.Duration(cfg.AlertRepeat)
+	var alerts []Alert
+
+	for _, rule := range rules {
+		rs := ruleState(state, rule.Name)
+		if rs.isDisabled(now) {
+			continue
+		}
+		// Una deshabilitación temporal vencida se limpia sola
+		if rs.Disabled {
+			rs.Disabled, rs.DisabledUntil = false, time.Time{}
+		}
+
+		for symbol, fields := range values {
+			if rule.Symbol != "*" && !strings.EqualFold(rule.Symbol, symbol) {
+				continue
+			}
+
+			matched, err := rule.parsed.Eval(fields)
+			if err != nil || !matched {
+				// La condición dejó de cumplirse: el próximo disparo vuelve a notificar
+				delete(rs.Active, symbol)
+				continue
+			}
+
+			episode, ok := rs.Active[symbol]
+			if !ok {
+				episode = &AlertEpisode{Since: now}
+				rs.Active[symbol] = episode
+			}
+			if episode.Acked || rs.isSnoozed(now) {
+				continue
+			}
+			if !episode.LastNotified.IsZero() && now.Sub(episode.LastNotified) < repeat {
+				continue
+			}
+
+			episode.LastNotified = now
+			alerts = append(alerts, Alert{
+				Rule:     rule.Name,
+				Severity: rule.Severity,
+				Symbol:   symbol,
+				Value:    fields["price"],
+				Message:  fmt.Sprintf("[%s] %s cumple %s (precio %.2f, %+.2f%%)", rule.Name, symbol, rule.parsed, fields["price"], fields["changePercent"]),
+				Time:     now,
+			})
+		}
+	}
+
+	if err := saveAlertState(state); err != nil {
+		fmt.Printf("Error al guardar el estado de alertas: %v\n", err)
+	}
+
+	sort.Slice(alerts, func(i, j int) bool { return alerts[i].Rule < alerts[j].Rule })
+	dispatchAlerts(notifiers, "Alertas activas", alerts, snapshot)
+}
+
+// updateAlertState aplica un cambio de gestión a una regla y lo persiste
+func updateAlertState(rule string, change func(rs *AlertRuleState)) error {
+	alertStateMu.Lock()
+	defer alertStateMu.Unlock()
+
+	state, err := loadAlertState()
+	if err != nil {
+		return err
+	}
+	change(ruleState(state, rule))
+	return saveAlertState(state)
+}
+
+// alertRules devuelve las reglas de config.json más las creadas desde Telegram
+func alertRules() []AlertRule {
+	rules := append([]AlertRule(nil), appConfig().Alerts...)
+	remote, err := loadRemoteAlerts()
+	if err != nil {
+		fmt.Printf("Error al leer las alertas creadas desde Telegram: %v\n", err)
+	}
+	return append(rules, remote...)
+}
+
+// knownAlertRule valida que la regla exista en la configuración o en Telegram (o sea una regla interna)
+func knownAlertRule(name string) error {
+	if name == "gap_apertura" {
+		return nil
+	}
+	for _, rule := range alertRules() {
+		if rule.Name == name {
+			return nil
+		}
+	}
+	return fmt.Errorf("regla de alerta desconocida: %s", name)
+}
+
+// alertManagementCommand ejecuta ack, snooze, disable, enable o list; lo usan la CLI y la TUI
+func alertManagementCommand(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: list | ack REGLA [SIMBOLO] | snooze REGLA DURACION | disable REGLA [DURACION] | enable REGLA")
+	}
+
+	if args[0] == "list" {
+		return listAlertRules()
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("falta el nombre de la regla")
+	}
+
+	name := args[1]
+	if err := knownAlertRule(name); err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "ack":
+		symbol := ""
+		if len(args) > 2 {
+			symbol = strings.ToUpper(args[2])
+		}
+		count := 0
+		err := updateAlertState(name, func(rs *AlertRuleState) {
+			for s, episode := range rs.Active {
+				if symbol == "" || s == symbol {
+					episode.Acked = true
+					count++
+				}
+			}
+		})
+		if err == nil {
+			fmt.Printf("Regla %s: %d alerta(s) reconocida(s); no se repetirán mientras la condición siga activa\n", name, count)
+		}
+		return err
+
+	case "snooze":
+		if len(args) < 3 {
+			return fmt.Errorf("uso: snooze REGLA DURACION (por ejemplo 2h)")
+		}
+		d, err := time.ParseDuration(args[2])
+		if err != nil {
+			return fmt.Errorf("duración inválida %q: %v", args[2], err)
+		}
+		until := time.Now().Add(d)
+		err = updateAlertState(name, func(rs *AlertRuleState) { rs.SnoozedUntil = until })
+		if err == nil {
+			fmt.Printf("Regla %s pospuesta hasta %s\n", name, until.Format("2006-01-02 15:04"))
+		}
+		return err
+
+	case "disable":
+		var until time.Time
+		if len(args) > 2 {
+			d, err := time.ParseDuration(args[2])
+			if err != nil {
+				return fmt.Errorf("duración inválida %q: %v", args[2], err)
+			}
+			until = time.Now().Add(d)
+		}
+		err := updateAlertState(name, func(rs *AlertRuleState) {
+			rs.Disabled, rs.DisabledUntil = true, until
+		})
+		if err == nil {
+			if until.IsZero() {
+				fmt.Printf("Regla %s deshabilitada\n", name)
+			} else {
+				fmt.Printf("Regla %s deshabilitada hasta %s\n", name, until.Format("2006-01-02 15:04"))
+			}
+		}
+		return err
+
+	case "enable":
+		err := updateAlertState(name, func(rs *AlertRuleState) {
+			rs.Disabled, rs.DisabledUntil, rs.SnoozedUntil = false, time.Time{}, time.Time{}
+		})
+		if err == nil {
+			fmt.Printf("Regla %s habilitada\n", name)
+		}
+		return err
+	}
+
+	return fmt.Errorf("acción de alertas desconocida: %s", args[0])
+}
+
+// listAlertRules muestra las reglas configuradas con su estado de gestión
+func listAlertRules() error {
+	alertStateMu.Lock()
+	state, err := loadAlertState()
+	alertStateMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	rules := append([]AlertRule{{Name: "gap_apertura", Symbol: "*", Condition: "gap de apertura (--gap)"}}, alertRules()...)
+	now := time.Now()
+
+	fmt.Printf("%-16s %-8s %-40s %s\n", "Regla", "Símbolo", "Condición", "Estado")
+	for _, rule := range rules {
+		status := Green + "activa" + Reset
+		if rs, ok := state[rule.Name]; ok {
+			switch {
+			case rs.isDisabled(now) && rs.DisabledUntil.IsZero():
+				status = Red + "deshabilitada" + Reset
+			case rs.isDisabled(now):
+				status = Red + "deshabilitada hasta " + rs.DisabledUntil.Format("02/01 15:04") + Reset
+			case rs.isSnoozed(now):
+				status = Yellow + "pospuesta hasta " + rs.SnoozedUntil.Format("02/01 15:04") + Reset
+			}
+			for symbol, episode := range rs.Active {
+				mark := "disparada"
+				if episode.Acked {
+					mark = "reconocida"
+				}
+				status += fmt.Sprintf(" [%s %s desde %s]", symbol, mark, episode.Since.Format("15:04"))
+			}
+		}
+		fmt.Printf("%-16s %-8s %-40s %s\n", rule.Name, rule.Symbol, rule.Condition, status)
+	}
+	return nil
+}
@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Regla con la que se registran y rutean las alertas de hechos relevantes
+const announcementRule = "hecho-relevante"
+
+// Los feeds de CNV/BYMA se consultan como mucho con esta frecuencia
+const announcementInterval = 10 * time.Minute
+
+const announcementsSeenFile = "announcements_seen.json"
+
+// Los identificadores vistos se olvidan pasado este plazo
+const announcementsRetention = 30 * 24 * time.Hour
+
+// Announcement es un hecho relevante publicado por una emisora
+type Announcement struct {
+	ID        string
+	Title     string
+	Summary   string
+	Link      string
+	Published time.Time
+	Feed      string
+}
+
+// feedDocument cubre RSS 2.0 y Atom: encoding/xml solo completa los campos del formato recibido
+type feedDocument struct {
+	Channel struct {
+		Items []struct {
+			Title       string `xml:"title"`
+			Link        string `xml:"link"`
+			GUID        string `xml:"guid"`
+			Description string `xml:"description"`
+			PubDate     string `xml:"pubDate"`
+		} `xml:"item"`
+	} `xml:"channel"`
+	Entries []struct {
+		Title   string `xml:"title"`
+		ID      string `xml:"id"`
+		Summary string `xml:"summary"`
+		Updated string `xml:"updated"`
+		Links   []struct {
+			Href string `xml:"href,attr"`
+			Rel  string `xml:"rel,attr"`
+		} `xml:"link"`
+	} `xml:"entry"`
+}
+
+// Formatos de fecha que usan los feeds RSS (RFC 822 con variantes) y Atom
+var feedTimeLayouts = []string{time.RFC1123Z, time.RFC1123, "Mon, 2 Jan 2006 15:04:05 -0700", "Mon, 2 Jan 2006 15:04:05 MST", time.RFC3339, "2006-01-02 15:04:05"}
+
+// parseFeedTime interpreta la fecha de un ítem; si no se entiende devuelve el cero
+func parseFeedTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range feedTimeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseFeed convierte un feed RSS o Atom en anuncios
+func parseFeed(data []byte, feed string) ([]Announcement, error) {
+	var doc feedDocument
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("feed inválido %s: %v", feed, err)
+	}
+
+	var announcements []Announcement
+	for _, item := range doc.Channel.Items {
+		id := strings.TrimSpace(item.GUID)
+		if id == "" {
+			id = strings.TrimSpace(item.Link)
+		}
+		announcements = append(announcements, Announcement{
+			ID: id, Title: strings.TrimSpace(item.Title), Summary: strings.TrimSpace(item.Description),
+			Link: strings.TrimSpace(item.Link), Published: parseFeedTime(item.PubDate), Feed: feed,
+		})
+	}
+	for _, entry := range doc.Entries {
+		var link string
+		for _, l := range entry.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+		id := strings.TrimSpace(entry.ID)
+		if id == "" {
+			id = link
+		}
+		announcements = append(announcements, Announcement{
+			ID: id, Title: strings.TrimSpace(entry.Title), Summary: strings.TrimSpace(entry.Summary),
+			Link: link, Published: parseFeedTime(entry.Updated), Feed: feed,
+		})
+	}
+	return announcements, nil
+}
+
+// fetchAnnouncements descarga y parsea un feed de hechos relevantes
+func fetchAnnouncements(feed string, client QuoteFetcher) ([]Announcement, error) {
+	resp, err := client.GetWithRetry(feed, map[string]string{"Accept": "application/rss+xml, application/atom+xml, application/xml, text/xml"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "al consultar %s", feed)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return parseFeed(data, feed)
+}
+
+// announcementTerms arma, por símbolo de la watchlist, los textos que lo identifican en un anuncio:
+// el ticker sin sufijo de mercado y el nombre de la empresa
+func announcementTerms(watchlist []WatchlistEntry) map[string][]string {
+	terms := make(map[string][]string)
+	for _, stock := range watchlist {
+		symbol := stock.Symbol
+		ticker := strings.TrimSuffix(symbol, ".BA")
+		list := []string{ticker}
+		if entry, ok := catalogLookup(symbol); ok {
+			list = append(list, foldAccents(entry.Name))
+		}
+		if custom, ok := userName(symbol); ok && custom.Name != "" {
+			list = append(list, foldAccents(custom.Name))
+		}
+		terms[symbol] = list
+	}
+	return terms
+}
+
+// matchAnnouncement devuelve los símbolos de la watchlist que menciona un anuncio
+func matchAnnouncement(a Announcement, terms map[string][]string) []string {
+	text := a.Title + " " + a.Summary
+	folded := foldAccents(text)
+
+	// Los tickers se buscan como palabra completa y en mayúsculas, para no confundir "PAM" con "campaña"
+	words := make(map[string]bool)
+	for _, w := range strings.FieldsFunc(text, func(r rune) bool {
+		return !(r >= 'A' && r <= 'Z' || r >= '0' && r <= '9')
+	}) {
+		words[w] = true
+	}
+
+	var symbols []string
+	for symbol, list := range terms {
+		for i, term := range list {
+			if (i == 0 && words[term]) || (i > 0 && strings.Contains(folded, term)) {
+				symbols = append(symbols, symbol)
+				break
+			}
+		}
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// feedHistory recuerda los ítems ya vistos de un grupo de feeds, para no repetir alertas tras un reinicio
+type feedHistory struct {
+	file    string
+	seen    map[string]time.Time // Identificador → cuándo se vio por primera vez
+	loaded  bool
+	seeding bool // Sin historial previo, la primera consulta solo registra lo publicado
+}
+
+// load lee los ítems ya vistos
+func (h *feedHistory) load() {
+	h.loaded = true
+	h.seen = make(map[string]time.Time)
+	path, err := appFile(h.file)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		h.seeding = true
+		return
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &h.seen)
+	}
+	if err != nil {
+		fmt.Printf("Error al leer %s: %v\n", path, err)
+	}
+}
+
+// isNew registra un ítem y dice si hay que alertarlo: no si ya se había visto o si es la primera consulta
+func (h *feedHistory) isNew(id string, now time.Time) bool {
+	if !h.loaded {
+		h.load()
+	}
+	if _, ok := h.seen[id]; ok || id == "" {
+		return false
+	}
+	h.seen[id] = now
+	return !h.seeding
+}
+
+// save persiste los ítems vistos, olvidando los más viejos
+func (h *feedHistory) save(now time.Time) {
+	if !h.loaded {
+		return // Ninguna consulta respondió: no hay nada nuevo que guardar
+	}
+	h.seeding = false
+	for id, t := range h.seen {
+		if now.Sub(t) > announcementsRetention {
+			delete(h.seen, id)
+		}
+	}
+	path, err := appFile(h.file)
+	if err != nil {
+		return
+	}
+	data, err := json.MarshalIndent(h.seen, "", "  ")
+	if err == nil {
+		err = os.WriteFile(path, data, 0o644)
+	}
+	if err != nil {
+		fmt.Printf("Error al guardar %s: %v\n", path, err)
+	}
+}
+
+// AnnouncementWatcher consulta los feeds periódicamente y alerta los hechos relevantes nuevos de la watchlist
+type AnnouncementWatcher struct {
+	Feeds []string
+
+	mu      sync.Mutex
+	last    time.Time
+	history feedHistory
+}
+
+// NewAnnouncementWatcher crea el vigilante de hechos relevantes
+func NewAnnouncementWatcher(feeds []string) *AnnouncementWatcher {
+	return &AnnouncementWatcher{Feeds: feeds, history: feedHistory{file: announcementsSeenFile}}
+}
+
+// Check consulta los feeds si pasó el intervalo y alerta los anuncios nuevos que mencionan papeles de la watchlist
+func (w *AnnouncementWatcher) Check(snapshot *Snapshot, notifiers []Notifier, client QuoteFetcher) {
+	if w == nil || len(w.Feeds) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(w.last) < announcementInterval {
+		return
+	}
+	w.last = now
+
+	terms := announcementTerms(watchlistSnapshot())
+	var alerts []Alert
+	for _, feed := range w.Feeds {
+		announcements, err := fetchAnnouncements(feed, client)
+		if err != nil {
+			fmt.Printf("%sNo se pudieron consultar los hechos relevantes: %v%s\n", Yellow, err, Reset)
+			continue
+		}
+		for _, a := range announcements {
+			if !w.history.isNew(a.ID, now) {
+				continue
+			}
+			for _, symbol := range matchAnnouncement(a, terms) {
+				alerts = append(alerts, Alert{
+					Rule:     announcementRule,
+					Severity: SeverityInfo,
+					Symbol:   symbol,
+					Message:  fmt.Sprintf("%s publicó un hecho relevante: %s\n    %s", symbol, a.Title, a.Link),
+					Time:     now,
+				})
+			}
+		}
+	}
+	w.history.save(now)
+
+	if len(alerts) > 0 && alertAllowed(announcementRule, now) {
+		dispatchAlerts(notifiers, "Hechos relevantes", alerts, snapshot)
+	}
+}
+
+// runAnnouncements implementa `bolsa announcements`: últimos hechos relevantes y cuáles mencionan la watchlist
+func runAnnouncements(args []string) error {
+	fs := flag.NewFlagSet("announcements", flag.ExitOnError)
+	feed := fs.String("feed", "", "consultar este feed RSS/Atom en lugar de los de config.json")
+	all := fs.Bool("all", false, "mostrar también los anuncios que no mencionan papeles de la watchlist")
+	limit := fs.Int("n", 20, "cantidad máxima de anuncios a mostrar")
+	fs.Parse(args)
+
+	feeds := appConfig().AnnouncementFeeds
+	if *feed != "" {
+		feeds = []string{*feed}
+	}
+	if len(feeds) == 0 {
+		return fmt.Errorf("no hay feeds de hechos relevantes: agregar \"announcementFeeds\" en config.json o usar --feed URL")
+	}
+
+	client := NewHTTPClient()
+	var announcements []Announcement
+	for _, f := range feeds {
+		list, err := fetchAnnouncements(f, client)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", Red, err, Reset)
+			continue
+		}
+		announcements = append(announcements, list...)
+	}
+	sort.SliceStable(announcements, func(i, j int) bool { return announcements[i].Published.After(announcements[j].Published) })
+
+	terms := announcementTerms(watchlistSnapshot())
+	shown := 0
+	for _, a := range announcements {
+		if shown >= *limit {
+			break
+		}
+		symbols := matchAnnouncement(a, terms)
+		if len(symbols) == 0 && !*all {
+			continue
+		}
+		shown++
+
+		date := "s/f"
+		if !a.Published.IsZero() {
+			date = a.Published.In(argentinaLocation).Format("2006-01-02 15:04")
+		}
+		tag := ""
+		if len(symbols) > 0 {
+			tag = fmt.Sprintf(" %s[%s]%s", Yellow, strings.Join(symbols, ", "), Reset)
+		}
+		fmt.Printf("%s%s%s%s %s\n    %s\n", Cyan, date, Reset, tag, a.Title, a.Link)
+	}
+	if shown == 0 {
+		fmt.Println("No hay hechos relevantes recientes de los papeles de la watchlist (usá --all para ver todos).")
+	}
+	return nil
+}
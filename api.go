@@ -0,0 +1,321 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rangos de /api/stats hacia atrás desde hoy: los de días ("1d", "5d") cuentan ruedas del mercado del
+// símbolo, los demás días corridos
+var statsRanges = map[string]int{
+	"1d":  1,
+	"5d":  5,
+	"1mo": 31,
+	"3mo": 92,
+	"6mo": 183,
+	"1y":  366,
+}
+
+// SymbolStats son las estadísticas de un símbolo en un período, calculadas desde el historial intradiario
+type SymbolStats struct {
+	Symbol        string    `json:"symbol"`
+	Range         string    `json:"range"`
+	From          time.Time `json:"from"`
+	To            time.Time `json:"to"`
+	Points        int       `json:"points"`
+	Days          int       `json:"days"`
+	First         float64   `json:"first"`
+	Last          float64   `json:"last"`
+	Min           float64   `json:"min"`
+	Max           float64   `json:"max"`
+	Average       float64   `json:"average"`
+	ChangePercent float64   `json:"change_percent"`
+	Volatility    *float64  `json:"volatility"`   // Anualizada (%), desvío de los retornos diarios; null con menos de 3 días
+	CloseSource   string    `json:"close_source"` // "oficial" si la variación y la volatilidad salen de los cierres oficiales, "ticks" si del último precio visto
+}
+
+// pricePoint es el precio de un símbolo en un snapshot guardado
+type pricePoint struct {
+	Time  time.Time
+	Price float64
+}
+
+// DayPriceCache guarda los precios por símbolo de los días cerrados del historial, que no cambian; conserva
+// hasta max días y descarta el usado hace más tiempo
+type DayPriceCache struct {
+	mu    sync.Mutex
+	max   int
+	days  map[string]*dayPriceEntry
+	clock uint64
+}
+
+// dayPriceEntry son los precios de un día y el momento de su último uso
+type dayPriceEntry struct {
+	prices map[string][]pricePoint
+	used   uint64
+}
+
+// dayPrices alcanza para el rango más largo de /api/stats sin releer el historial en cada consulta
+var dayPrices = NewDayPriceCache(maxStatsDays())
+
+// maxStatsDays devuelve los días del rango más largo de /api/stats
+func maxStatsDays() int {
+	widest := 0
+	for _, days := range statsRanges {
+		widest = max(widest, days)
+	}
+	return widest
+}
+
+// NewDayPriceCache crea una caché vacía de hasta days días
+func NewDayPriceCache(days int) *DayPriceCache {
+	return &DayPriceCache{max: days, days: make(map[string]*dayPriceEntry)}
+}
+
+// Get devuelve los precios de un día si están en la caché
+func (c *DayPriceCache) Get(day string) (map[string][]pricePoint, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.days[day]
+	if !ok {
+		return nil, false
+	}
+	c.clock++
+	entry.used = c.clock
+	return entry.prices, true
+}
+
+// Put guarda los precios de un día; si la caché está llena descarta el día usado hace más tiempo
+func (c *DayPriceCache) Put(day string, prices map[string][]pricePoint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clock++
+	if entry, ok := c.days[day]; ok {
+		entry.prices, entry.used = prices, c.clock
+		return
+	}
+	for len(c.days) >= c.max && len(c.days) > 0 {
+		oldest := ""
+		for d, entry := range c.days {
+			if oldest == "" || entry.used < c.days[oldest].used {
+				oldest = d
+			}
+		}
+		delete(c.days, oldest)
+	}
+	c.days[day] = &dayPriceEntry{prices: prices, used: c.clock}
+}
+
+// Len devuelve cuántos días hay en la caché
+func (c *DayPriceCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.days)
+}
+
+// pricesForDay devuelve los precios guardados de un día por símbolo (acciones, tipos de cambio y bonos)
+func pricesForDay(day string, today bool) (map[string][]pricePoint, error) {
+	if cached, ok := dayPrices.Get(day); ok && !today {
+		return cached, nil
+	}
+
+	snapshots, err := loadHistory(day)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make(map[string][]pricePoint)
+	for _, snapshot := range snapshots {
+		for _, stock := range snapshot.Stocks {
+			prices[stock.Symbol] = append(prices[stock.Symbol], pricePoint{snapshot.Time, stock.Price})
+		}
+		for _, forex := range snapshot.Forex {
+			prices[forex.Symbol] = append(prices[forex.Symbol], pricePoint{snapshot.Time, forex.Price})
+		}
+		for _, bond := range snapshot.Bonds {
+			if bond.Bond != nil {
+				prices[bond.Bond.Symbol] = append(prices[bond.Bond.Symbol], pricePoint{snapshot.Time, bond.CleanPrice})
+			}
+		}
+	}
+
+	if !today {
+		dayPrices.Put(day, prices)
+	}
+	return prices, nil
+}
+
+// symbolStats calcula las estadísticas de un símbolo en los últimos días del rango
+func symbolStats(symbol, rangeName string, now time.Time) (*SymbolStats, error) {
+	days, ok := statsRanges[rangeName]
+	if !ok {
+		return nil, fmt.Errorf("rango inválido %q (usar 1d, 5d, 1mo, 3mo, 6mo o 1y)", rangeName)
+	}
+
+	available, err := historyDays()
+	if err != nil {
+		return nil, err
+	}
+
+	today := now.In(argentinaLocation).Format("2006-01-02")
+	since := now.In(argentinaLocation).AddDate(0, 0, -days+1).Format("2006-01-02")
+	// Los días sin rueda se saltean: el monitor sigue guardando snapshots con el último precio y
+	// contarlos agregaría retornos nulos a la volatilidad y acortaría la semana
+	hours, hasSessions := symbolHours(symbol)
+	if hasSessions && strings.HasSuffix(rangeName, "d") {
+		since = tradingDaysBack(hours, now, days).Format("2006-01-02")
+	}
+
+	stats := &SymbolStats{Symbol: symbol, Range: rangeName}
+	var sum float64
+	var closes []float64
+	var closedDays []string
+	for _, day := range available {
+		if day < since || day > today {
+			continue
+		}
+		if date, err := time.ParseInLocation("2006-01-02", day, argentinaLocation); err == nil && hasSessions && !hours.IsTradingDay(date.Add(12*time.Hour)) {
+			continue
+		}
+		prices, err := pricesForDay(day, day == today)
+		if err != nil {
+			return nil, err
+		}
+		points := prices[symbol]
+		if len(points) == 0 {
+			continue
+		}
+
+		for _, p := range points {
+			if stats.Points == 0 {
+				stats.From, stats.First, stats.Min, stats.Max = p.Time, p.Price, p.Price, p.Price
+			}
+			stats.Min = math.Min(stats.Min, p.Price)
+			stats.Max = math.Max(stats.Max, p.Price)
+			stats.To, stats.Last = p.Time, p.Price
+			sum += p.Price
+			stats.Points++
+		}
+		closes = append(closes, points[len(points)-1].Price)
+		if day != today {
+			closedDays = append(closedDays, day)
+		}
+	}
+
+	if stats.Points == 0 {
+		return nil, fmt.Errorf("no hay datos guardados de %s en el rango %s", symbol, rangeName)
+	}
+
+	stats.Days = len(closes)
+	stats.Average = sum / float64(stats.Points)
+	stats.CloseSource = OfficialSourceTicks
+	if stats.First != 0 {
+		stats.ChangePercent = (stats.Last/stats.First - 1) * 100
+	}
+	// Con al menos dos cierres oficiales la variación del período es de cierre a cierre, sin depender del último tick visto
+	if official := officialCloses(symbol, closedDays); len(official) >= 2 {
+		closes = official
+		stats.ChangePercent = (official[len(official)-1]/official[0] - 1) * 100
+		stats.CloseSource = OfficialSourceExchange
+	}
+	if vol, ok := annualizedVolatility(closes); ok {
+		stats.Volatility = &vol
+	}
+	return stats, nil
+}
+
+// annualizedVolatility calcula el desvío estándar de los retornos logarítmicos diarios, anualizado en %
+func annualizedVolatility(closes []float64) (float64, bool) {
+	if len(closes) < 3 {
+		return 0, false
+	}
+
+	var returns []float64
+	for i := 1; i < len(closes); i++ {
+		if closes[i-1] > 0 && closes[i] > 0 {
+			returns = append(returns, math.Log(closes[i]/closes[i-1]))
+		}
+	}
+	if len(returns) < 2 {
+		return 0, false
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r
+	}
+	mean /= float64(len(returns))
+
+	var variance float64
+	for _, r := range returns {
+		variance += (r - mean) * (r - mean)
+	}
+	variance /= float64(len(returns) - 1)
+	return math.Sqrt(variance*252) * 100, true
+}
+
+// writeJSON responde con un valor JSON y el código de estado indicado
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// handleSymbolStats atiende GET /api/stats/{symbol}?range=1mo
+func handleSymbolStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "método no permitido"})
+		return
+	}
+	symbol := strings.ToUpper(strings.TrimPrefix(r.URL.Path, "/api/stats/"))
+	if symbol == "" || strings.Contains(symbol, "/") {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "uso: /api/stats/{símbolo}?range=1mo"})
+		return
+	}
+	rangeName := r.URL.Query().Get("range")
+	if rangeName == "" {
+		rangeName = "1mo"
+	}
+
+	stats, err := symbolStats(symbol, rangeName, time.Now())
+	if err != nil {
+		status := http.StatusNotFound
+		if _, ok := statsRanges[rangeName]; !ok {
+			status = http.StatusBadRequest
+		}
+		writeJSON(w, status, map[string]string{"error": err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// startAPI expone la API HTTP de consulta (por ejemplo en 127.0.0.1:7071); el dashboard muestra lo último que
+// difundió el hub
+func startAPI(addr string, hub *snapshotHub) error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", handleDashboard(hub))
+	mux.HandleFunc("/m", handleMobileDashboard(hub))
+	mux.HandleFunc("/api/stats/", handleSymbolStats)
+	mux.HandleFunc("/metrics", handleMetrics)
+	registerUserAPI(mux)
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("no se pudo iniciar la API en %s: %v", addr, err)
+	}
+
+	fmt.Printf("API disponible en http://%s/api/stats/{símbolo}?range=1mo /api/me/* con API key y /metrics\n", listener.Addr())
+	fmt.Printf("Dashboard en http://%s/ y vista para el celular en http://%s/m\n", listener.Addr(), listener.Addr())
+	go func() {
+		if err := http.Serve(listener, mux); err != nil {
+			fmt.Printf("API detenida: %v\n", err)
+		}
+	}()
+	return nil
+}
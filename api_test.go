@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDayPriceCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewDayPriceCache(3)
+	for i := 1; i <= 3; i++ {
+		cache.Put(fmt.Sprintf("2025-01-0%d", i), map[string][]pricePoint{"GGAL": {{Price: float64(i)}}})
+	}
+	// El 1 se usa de nuevo: al agregar el 4 se descarta el 2, el usado hace más tiempo
+	if _, ok := cache.Get("2025-01-01"); !ok {
+		t.Fatal("falta el 2025-01-01 en la caché")
+	}
+	cache.Put("2025-01-04", map[string][]pricePoint{})
+
+	if cache.Len() != 3 {
+		t.Errorf("la caché tiene %d días, se esperaban 3", cache.Len())
+	}
+	for day, want := range map[string]bool{"2025-01-01": true, "2025-01-02": false, "2025-01-03": true, "2025-01-04": true} {
+		if _, ok := cache.Get(day); ok != want {
+			t.Errorf("%s en la caché = %v, se esperaba %v", day, ok, want)
+		}
+	}
+}
+
+func TestDayPriceCacheCoversWidestRange(t *testing.T) {
+	cache := NewDayPriceCache(maxStatsDays())
+	for i := 0; i < 3*maxStatsDays(); i++ {
+		cache.Put(fmt.Sprintf("día %d", i), nil)
+	}
+	if cache.Len() != maxStatsDays() {
+		t.Errorf("la caché tiene %d días, se esperaban %d", cache.Len(), maxStatsDays())
+	}
+	if _, ok := cache.Get(fmt.Sprintf("día %d", 3*maxStatsDays()-1)); !ok {
+		t.Error("falta el último día agregado")
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Regla con la que se registran y rutean las alertas de licitaciones del Tesoro
+const auctionRule = "licitacion"
+
+const auctionsFile = "auctions.json"
+
+// Los resultados se alertan solo si la licitación es reciente: una primera sincronización no repite el historial
+const auctionResultWindow = 3 * 24 * time.Hour
+
+// TreasuryAuction es una licitación de deuda en pesos de la Secretaría de Finanzas
+type TreasuryAuction struct {
+	Date        string             `json:"date"` // Día de la licitación (AAAA-MM-DD)
+	Instruments []string           `json:"instruments"`
+	Results     map[string]float64 `json:"results,omitempty"` // Instrumento → tasa de corte en %, como la publica Finanzas
+	CallLink    string             `json:"callLink,omitempty"`
+	ResultLink  string             `json:"resultLink,omitempty"`
+
+	DayAlerted    bool `json:"dayAlerted,omitempty"`
+	ResultAlerted bool `json:"resultAlerted,omitempty"`
+}
+
+var auctionsMu sync.Mutex
+
+// loadAuctions lee las licitaciones registradas, ordenadas por fecha
+func loadAuctions() ([]TreasuryAuction, error) {
+	path, err := appFile(auctionsFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var auctions []TreasuryAuction
+	if err := json.Unmarshal(data, &auctions); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return auctions, nil
+}
+
+// saveAuctions escribe las licitaciones ordenadas por fecha
+func saveAuctions(auctions []TreasuryAuction) error {
+	sort.Slice(auctions, func(i, j int) bool { return auctions[i].Date < auctions[j].Date })
+	path, err := appFile(auctionsFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(auctions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// findAuction devuelve la licitación de un día, creándola si no estaba
+func findAuction(auctions *[]TreasuryAuction, date string) *TreasuryAuction {
+	for i := range *auctions {
+		if (*auctions)[i].Date == date {
+			return &(*auctions)[i]
+		}
+	}
+	*auctions = append(*auctions, TreasuryAuction{Date: date})
+	return &(*auctions)[len(*auctions)-1]
+}
+
+// addInstruments agrega instrumentos sin repetir
+func (a *TreasuryAuction) addInstruments(instruments []string) {
+	for _, instrument := range instruments {
+		if !contains(a.Instruments, instrument) {
+			a.Instruments = append(a.Instruments, instrument)
+		}
+	}
+}
+
+var (
+	// Tickers de LECAP, BONCAP, bonos CER, dollar linked y duales: S31O6, T15E7, TZX26, TZXD6, D31O6, TTM26
+	auctionTickerPattern = regexp.MustCompile(`\b[A-Z]{1,4}\d{1,2}(?:[A-Z]\d)?\b`)
+	auctionRatePattern   = regexp.MustCompile(`(\d{1,3}(?:[.,]\d+)?)\s*%`)
+	spanishDatePattern   = regexp.MustCompile(`(\d{1,2}) de (enero|febrero|marzo|abril|mayo|junio|julio|agosto|septiembre|setiembre|octubre|noviembre|diciembre)(?: de(?:l)? (\d{4}))?`)
+)
+
+var spanishMonths = map[string]time.Month{
+	"enero": time.January, "febrero": time.February, "marzo": time.March, "abril": time.April,
+	"mayo": time.May, "junio": time.June, "julio": time.July, "agosto": time.August,
+	"septiembre": time.September, "setiembre": time.September, "octubre": time.October,
+	"noviembre": time.November, "diciembre": time.December,
+}
+
+// parseSpanishDate busca la primera fecha escrita como "28 de octubre [de 2026]"; sin año toma la más cercana a ref
+func parseSpanishDate(text string, ref time.Time) (time.Time, bool) {
+	m := spanishDatePattern.FindStringSubmatch(foldAccents(text))
+	if m == nil {
+		return time.Time{}, false
+	}
+	day, _ := strconv.Atoi(m[1])
+	year := ref.Year()
+	if m[3] != "" {
+		year, _ = strconv.Atoi(m[3])
+	}
+	date := time.Date(year, spanishMonths[m[2]], day, 0, 0, 0, 0, argentinaLocation)
+	if m[3] == "" && ref.Sub(date) > 180*24*time.Hour {
+		date = date.AddDate(1, 0, 0) // Llamado de diciembre para una licitación de enero
+	}
+	return date, true
+}
+
+// auctionRates extrae la tasa de corte que sigue a cada ticker en el texto de un resultado
+func auctionRates(text string) map[string]float64 {
+	rates := make(map[string]float64)
+	tickers := auctionTickerPattern.FindAllStringIndex(text, -1)
+	for i, loc := range tickers {
+		end := len(text)
+		if i+1 < len(tickers) {
+			end = tickers[i+1][0]
+		}
+		if end-loc[1] > 160 {
+			end = loc[1] + 160
+		}
+		if m := auctionRatePattern.FindStringSubmatch(text[loc[1]:end]); m != nil {
+			if rate, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64); err == nil {
+				rates[text[loc[0]:loc[1]]] = rate
+			}
+		}
+	}
+	return rates
+}
+
+// applyAuctionNews incorpora un comunicado de Finanzas (llamado o resultado) a las licitaciones registradas
+func applyAuctionNews(auctions *[]TreasuryAuction, a Announcement, now time.Time) bool {
+	text := a.Title + " " + a.Summary
+	folded := foldAccents(text)
+	if !strings.Contains(folded, "licitacion") {
+		return false
+	}
+	ref := a.Published
+	if ref.IsZero() {
+		ref = now
+	}
+	tickers := auctionTickerPattern.FindAllString(text, -1)
+
+	// El título decide el tipo: un llamado suele anunciar cuándo se publicarán "los resultados"
+	title := foldAccents(a.Title)
+	isCall := strings.Contains(title, "llamado") || strings.Contains(title, "convoca")
+	if !isCall && strings.Contains(folded, "resultado") {
+		date := ref.In(argentinaLocation)
+		if d, ok := parseSpanishDate(text, ref); ok {
+			date = d
+		}
+		rates := auctionRates(text)
+		auction := findAuction(auctions, date.Format("2006-01-02"))
+		auction.addInstruments(tickers)
+		if auction.Results == nil {
+			auction.Results = make(map[string]float64)
+		}
+		for instrument, rate := range rates {
+			auction.Results[instrument] = rate
+		}
+		auction.ResultLink = a.Link
+		return true
+	}
+
+	if isCall || strings.Contains(folded, "llamado") {
+		date, ok := parseSpanishDate(text, ref)
+		if !ok {
+			return false
+		}
+		auction := findAuction(auctions, date.Format("2006-01-02"))
+		auction.addInstruments(tickers)
+		auction.CallLink = a.Link
+		return true
+	}
+	return false
+}
+
+// syncAuctions incorpora los llamados y resultados publicados en el feed de la Secretaría de Finanzas
+func syncAuctions(feed string, client QuoteFetcher, now time.Time) (int, error) {
+	news, err := fetchAnnouncements(feed, client)
+	if err != nil {
+		return 0, err
+	}
+	auctions, err := loadAuctions()
+	if err != nil {
+		return 0, err
+	}
+	applied := 0
+	for _, a := range news {
+		if applyAuctionNews(&auctions, a, now) {
+			applied++
+		}
+	}
+	if applied == 0 {
+		return 0, nil
+	}
+	return applied, saveAuctions(auctions)
+}
+
+// formatAuctionResults lista las tasas de corte en el orden de los instrumentos
+func formatAuctionResults(a TreasuryAuction) string {
+	var parts []string
+	for _, instrument := range a.Instruments {
+		if rate, ok := a.Results[instrument]; ok {
+			parts = append(parts, fmt.Sprintf("%s %.2f%%", instrument, rate))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AuctionWatcher sincroniza las licitaciones y alerta el día de cada una y cuando se publican sus resultados
+type AuctionWatcher struct {
+	mu   sync.Mutex
+	last time.Time
+}
+
+var auctionWatcher = &AuctionWatcher{}
+
+// Check sincroniza el feed si pasó el intervalo y alerta las licitaciones de hoy y los resultados nuevos
+func (w *AuctionWatcher) Check(snapshot *Snapshot, notifiers []Notifier, client QuoteFetcher) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(w.last) < announcementInterval {
+		return
+	}
+	w.last = now
+
+	auctionsMu.Lock()
+	defer auctionsMu.Unlock()
+
+	if feed := appConfig().AuctionsFeed; feed != "" {
+		if _, err := syncAuctions(feed, client, now); err != nil {
+			fmt.Printf("%sNo se pudieron consultar las licitaciones del Tesoro: %v%s\n", Yellow, err, Reset)
+		}
+	}
+	auctions, err := loadAuctions()
+	if err != nil || len(auctions) == 0 {
+		return
+	}
+
+	today := now.In(argentinaLocation).Format("2006-01-02")
+	var alerts []Alert
+	changed := false
+	for i := range auctions {
+		a := &auctions[i]
+		if a.Date == today && !a.DayAlerted {
+			a.DayAlerted, changed = true, true
+			msg := "Hoy licita el Tesoro"
+			if len(a.Instruments) > 0 {
+				msg += ": " + strings.Join(a.Instruments, ", ")
+			}
+			if a.CallLink != "" {
+				msg += "\n    " + a.CallLink
+			}
+			alerts = append(alerts, Alert{Rule: auctionRule, Severity: SeverityInfo, Message: msg, Time: now})
+		}
+		if len(a.Results) > 0 && !a.ResultAlerted {
+			a.ResultAlerted, changed = true, true
+			date, err := time.ParseInLocation("2006-01-02", a.Date, argentinaLocation)
+			if err != nil || now.Sub(date) > auctionResultWindow {
+				continue
+			}
+			msg := fmt.Sprintf("Resultado de la licitación del %s: %s", a.Date, formatAuctionResults(*a))
+			if a.ResultLink != "" {
+				msg += "\n    " + a.ResultLink
+			}
+			alerts = append(alerts, Alert{Rule: auctionRule, Severity: SeverityInfo, Message: msg, Time: now})
+		}
+	}
+	if !changed {
+		return
+	}
+	if err := saveAuctions(auctions); err != nil {
+		fmt.Printf("Error al guardar las licitaciones: %v\n", err)
+	}
+	if len(alerts) > 0 && alertAllowed(auctionRule, now) {
+		dispatchAlerts(notifiers, "Licitaciones del Tesoro", alerts, snapshot)
+	}
+}
+
+// auctionEvents devuelve las licitaciones registradas como eventos del calendario
+func auctionEvents() ([]MarketEvent, error) {
+	auctionsMu.Lock()
+	defer auctionsMu.Unlock()
+	auctions, err := loadAuctions()
+	if err != nil {
+		return nil, err
+	}
+	var events []MarketEvent
+	for _, a := range auctions {
+		date, err := time.ParseInLocation("2006-01-02", a.Date, argentinaLocation)
+		if err != nil {
+			continue
+		}
+		events = append(events, MarketEvent{
+			Date: date, Kind: "licitación", Title: "Licitación del Tesoro en pesos",
+			Description: strings.Join(a.Instruments, ", "),
+		})
+	}
+	return events, nil
+}
+
+// runAuctions implementa `bolsa auctions list|sync|add|result`
+func runAuctions(args []string) error {
+	auctionsMu.Lock()
+	defer auctionsMu.Unlock()
+
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+	switch args[0] {
+	case "list":
+		auctions, err := loadAuctions()
+		if err != nil {
+			return err
+		}
+		if len(auctions) == 0 {
+			fmt.Println("No hay licitaciones registradas: usá bolsa auctions sync (con \"auctionsFeed\" en config.json) o bolsa auctions add.")
+			return nil
+		}
+		fmt.Printf("\n%s=== LICITACIONES DEL TESORO EN PESOS ===%s\n\n", Cyan, Reset)
+		today := time.Now().In(argentinaLocation).Format("2006-01-02")
+		for _, a := range auctions {
+			dateColor := White
+			if a.Date == today {
+				dateColor = Yellow
+			}
+			fmt.Printf("%s%s%s  %s\n", dateColor, a.Date, Reset, strings.Join(a.Instruments, ", "))
+			if results := formatAuctionResults(a); results != "" {
+				fmt.Printf("            %sCorte:%s %s\n", Green, Reset, results)
+			} else if a.Date < today {
+				fmt.Printf("            %sSin resultados registrados%s\n", Yellow, Reset)
+			}
+		}
+		return nil
+
+	case "sync":
+		feed := appConfig().AuctionsFeed
+		if len(args) > 1 {
+			feed = args[1]
+		}
+		if feed == "" {
+			return fmt.Errorf("no hay feed de licitaciones: agregar \"auctionsFeed\" en config.json o usar bolsa auctions sync URL")
+		}
+		applied, err := syncAuctions(feed, NewHTTPClient(), time.Now())
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%d comunicados de licitaciones incorporados\n", applied)
+		return nil
+
+	case "add":
+		if len(args) < 3 {
+			return fmt.Errorf("uso: bolsa auctions add AAAA-MM-DD INSTRUMENTO...")
+		}
+		if _, err := time.Parse("2006-01-02", args[1]); err != nil {
+			return fmt.Errorf("fecha inválida %q", args[1])
+		}
+		auctions, err := loadAuctions()
+		if err != nil {
+			return err
+		}
+		var instruments []string
+		for _, instrument := range args[2:] {
+			instruments = append(instruments, strings.ToUpper(instrument))
+		}
+		findAuction(&auctions, args[1]).addInstruments(instruments)
+		if err := saveAuctions(auctions); err != nil {
+			return err
+		}
+		fmt.Printf("Licitación del %s registrada: %s\n", args[1], strings.Join(instruments, ", "))
+		return nil
+
+	case "result":
+		if len(args) < 3 {
+			return fmt.Errorf("uso: bolsa auctions result AAAA-MM-DD INSTRUMENTO=TASA...")
+		}
+		if _, err := time.Parse("2006-01-02", args[1]); err != nil {
+			return fmt.Errorf("fecha inválida %q", args[1])
+		}
+		auctions, err := loadAuctions()
+		if err != nil {
+			return err
+		}
+		auction := findAuction(&auctions, args[1])
+		if auction.Results == nil {
+			auction.Results = make(map[string]float64)
+		}
+		for _, arg := range args[2:] {
+			instrument, value, ok := strings.Cut(arg, "=")
+			rate, err := strconv.ParseFloat(strings.TrimSuffix(strings.Replace(value, ",", ".", 1), "%"), 64)
+			if !ok || err != nil {
+				return fmt.Errorf("resultado inválido %q (usar INSTRUMENTO=TASA, por ejemplo S31O6=2.45)", arg)
+			}
+			instrument = strings.ToUpper(instrument)
+			auction.addInstruments([]string{instrument})
+			auction.Results[instrument] = rate
+		}
+		auction.ResultAlerted = true // Cargado a mano: no hace falta alertarlo
+		if err := saveAuctions(auctions); err != nil {
+			return err
+		}
+		fmt.Printf("Resultado del %s: %s\n", args[1], formatAuctionResults(*auction))
+		return nil
+	}
+
+	return fmt.Errorf("subcomando desconocido de auctions: %s", args[0])
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Manifiesto que encabeza cada backup
+const backupManifestFile = "backup.json"
+
+// Directorios del estado que no se respaldan: son diagnósticos descartables
+var backupSkipDirs = map[string]bool{"diagnostics": true}
+
+// BackupManifest describe el contenido de un backup
+type BackupManifest struct {
+	Version string    `json:"version"` // Versión de bolsa que lo generó
+	Created time.Time `json:"created"`
+	Host    string    `json:"host"`
+	Files   int       `json:"files"`
+	Bytes   int64     `json:"bytes"`
+}
+
+// backupFiles lista los archivos del directorio de estado a respaldar, con rutas relativas
+func backupFiles(dir string, skipHistory bool) ([]string, int64, error) {
+	var files []string
+	var total int64
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if backupSkipDirs[rel] || (skipHistory && rel == historyDir) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		// Archivos a medio escribir por writeFileAtomic
+		if !d.Type().IsRegular() || strings.HasSuffix(rel, ".tmp") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, rel)
+		total += info.Size()
+		return nil
+	})
+	return files, total, err
+}
+
+// writeBackup empaqueta el directorio de estado en un .tar.gz con el manifiesto al principio
+func writeBackup(path, dir string, skipHistory bool) (*BackupManifest, error) {
+	files, total, err := backupFiles(dir, skipHistory)
+	if err != nil {
+		return nil, err
+	}
+	host, _ := os.Hostname()
+	manifest := &BackupManifest{Version: version, Created: time.Now(), Host: host, Files: len(files), Bytes: total}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	data, _ := json.MarshalIndent(manifest, "", "  ")
+	if err := tw.WriteHeader(&tar.Header{Name: backupManifestFile, Mode: 0o644, Size: int64(len(data)), ModTime: manifest.Created}); err != nil {
+		return nil, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return nil, err
+	}
+
+	for _, rel := range files {
+		if err := addBackupFile(tw, dir, rel); err != nil {
+			return nil, fmt.Errorf("%s: %v", rel, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gz.Close(); err != nil {
+		return nil, err
+	}
+	return manifest, out.Close()
+}
+
+// addBackupFile agrega un archivo del estado al tar, bajo state/ y con separadores de Unix
+func addBackupFile(tw *tar.Writer, dir, rel string) error {
+	f, err := os.Open(filepath.Join(dir, rel))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	header.Name = "state/" + filepath.ToSlash(rel)
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// restoreEntry es un archivo del backup a escribir en el directorio de estado
+type restoreEntry struct {
+	rel  string
+	mode fs.FileMode
+	size int64
+}
+
+// walkBackup recorre un backup y pasa cada archivo del estado a visit a medida que lo lee, sin cargarlo en
+// memoria; rechaza rutas fuera del directorio de estado
+func walkBackup(path string, visit func(entry restoreEntry, r io.Reader) error) (*BackupManifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s no es un backup de bolsa: %v", path, err)
+	}
+	tr := tar.NewReader(gz)
+
+	var manifest *BackupManifest
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("backup dañado: %v", err)
+		}
+		if header.Name == backupManifestFile {
+			manifest = &BackupManifest{}
+			if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+				return nil, fmt.Errorf("manifiesto inválido: %v", err)
+			}
+			continue
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		rel, ok := strings.CutPrefix(header.Name, "state/")
+		if !ok || !filepath.IsLocal(filepath.FromSlash(rel)) {
+			return nil, fmt.Errorf("ruta no permitida en el backup: %s", header.Name)
+		}
+		entry := restoreEntry{rel: filepath.FromSlash(rel), mode: header.FileInfo().Mode().Perm(), size: header.Size}
+		if err := visit(entry, tr); err != nil {
+			return nil, err
+		}
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("%s no es un backup de bolsa: falta %s", path, backupManifestFile)
+	}
+	return manifest, nil
+}
+
+// readBackup lee el manifiesto y lista los archivos de un backup sin extraerlos
+func readBackup(path string) (*BackupManifest, []restoreEntry, error) {
+	var entries []restoreEntry
+	manifest, err := walkBackup(path, func(entry restoreEntry, _ io.Reader) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return manifest, entries, nil
+}
+
+// extractBackup vuelca los archivos de un backup ya validado con readBackup en el directorio de estado
+func extractBackup(path, dir string) error {
+	_, err := walkBackup(path, func(entry restoreEntry, r io.Reader) error {
+		target := filepath.Join(dir, entry.rel)
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return err
+		}
+		if err := restoreFile(target, r, entry.mode); err != nil {
+			return fmt.Errorf("%s: %v", entry.rel, err)
+		}
+		return nil
+	})
+	return err
+}
+
+// restoreFile copia r a un temporal en el directorio destino y recién completo lo renombra sobre path, así un
+// restore interrumpido no deja archivos a medias
+func restoreFile(path string, r io.Reader, mode fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// backupName es el nombre por defecto de un backup generado ahora
+func backupName(prefix string) string {
+	return fmt.Sprintf("%s-%s.tar.gz", prefix, time.Now().Format("20060102-150405"))
+}
+
+// runBackup implementa `bolsa backup`: config, históricos, carteras y estado de alertas en un único .tar.gz
+func runBackup(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := fs.String("out", "", "archivo de salida (por defecto bolsa-backup-FECHA.tar.gz en el directorio actual)")
+	skipHistory := fs.Bool("skip-history", false, "no incluir los snapshots intradiarios de bolsa replay")
+	fs.Parse(args)
+
+	dir, err := appDir()
+	if err != nil {
+		return err
+	}
+	path := *out
+	if path == "" {
+		path = backupName("bolsa-backup")
+	}
+	manifest, err := writeBackup(path, dir, *skipHistory)
+	if err != nil {
+		os.Remove(path)
+		return err
+	}
+	fmt.Printf("%sBackup guardado en %s%s: %d archivos, %.1f MB sin comprimir\n",
+		Green, path, Reset, manifest.Files, float64(manifest.Bytes)/(1<<20))
+	fmt.Println("Puede incluir los secretos cifrados y la clave de firma de bolsa export: guardalo en un lugar seguro.")
+	return nil
+}
+
+// runRestore implementa `bolsa restore`: vuelca un backup en el directorio de estado de esta máquina
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	force := fs.Bool("force", false, "sobrescribir el estado existente (antes se guarda un backup de lo actual)")
+	dryRun := fs.Bool("dry-run", false, "solo listar lo que se restauraría")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: bolsa restore [--force] [--dry-run] ARCHIVO.tar.gz")
+	}
+	manifest, entries, err := readBackup(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	dir, err := appDir()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup de %s del %s (bolsa %s): %d archivos\n",
+		manifest.Host, manifest.Created.In(argentinaLocation).Format("02/01/2006 15:04"), manifest.Version, len(entries))
+	var existing []string
+	for _, entry := range entries {
+		if _, err := os.Stat(filepath.Join(dir, entry.rel)); err == nil {
+			existing = append(existing, entry.rel)
+		}
+	}
+	if *dryRun {
+		for _, entry := range entries {
+			mark := ""
+			if _, err := os.Stat(filepath.Join(dir, entry.rel)); err == nil {
+				mark = " (reemplaza al actual)"
+			}
+			fmt.Printf("  %s%s\n", entry.rel, mark)
+		}
+		return nil
+	}
+
+	if len(existing) > 0 {
+		if !*force {
+			return fmt.Errorf("%d archivos del backup ya existen en %s (por ejemplo %s): usar --force para reemplazarlos",
+				len(existing), dir, existing[0])
+		}
+		// Resguardo de lo actual por si el backup no era el esperado
+		previous := backupName("bolsa-pre-restore")
+		if _, err := writeBackup(previous, dir, false); err != nil {
+			os.Remove(previous)
+			return fmt.Errorf("no se pudo resguardar el estado actual: %v", err)
+		}
+		fmt.Printf("Estado actual resguardado en %s\n", previous)
+	}
+
+	if err := extractBackup(fs.Arg(0), dir); err != nil {
+		return err
+	}
+	fmt.Printf("%sRestaurados %d archivos en %s%s\n", Green, len(entries), dir, Reset)
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// craftBackup escribe un .tar.gz con el manifiesto y los archivos dados, tal cual, sin validar las rutas
+func craftBackup(t *testing.T, files map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	out, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+	files[backupManifestFile] = `{"version": "test", "files": 1}`
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadBackupRejectsPathTraversal(t *testing.T) {
+	tests := []string{
+		"state/../config.json",
+		"state/../../etc/passwd",
+		"state/sub/../../fuera.json",
+		"state//etc/passwd",
+		"/etc/passwd",
+		"../state/alerts.json",
+		"otro/alerts.json",
+	}
+	for _, name := range tests {
+		path := craftBackup(t, map[string]string{name: "malicioso"})
+		_, entries, err := readBackup(path)
+		if err == nil || !strings.Contains(err.Error(), "ruta no permitida") {
+			t.Errorf("%q: err = %v, entradas %v; se esperaba ruta no permitida", name, err, entries)
+		}
+	}
+}
+
+func TestBackupRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"alerts.json":                     `[]`,
+		filepath.Join("users", "a.json"):  `{"name": "a"}`,
+		filepath.Join(historyDir, "x.gz"): "historia",
+		filepath.Join("diagnostics", "d"): "descartable",
+		"watchlist.json.tmp":              "a medio escribir",
+	}
+	for rel, content := range files {
+		if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(rel)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, rel), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "backup.tar.gz")
+	if _, err := writeBackup(path, dir, true); err != nil {
+		t.Fatal(err)
+	}
+	manifest, entries, err := readBackup(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest.Files != 2 || len(entries) != 2 {
+		t.Fatalf("manifiesto con %d archivos y %d entradas, se esperaban 2", manifest.Files, len(entries))
+	}
+	for _, entry := range entries {
+		if entry.size != int64(len(files[entry.rel])) {
+			t.Errorf("%s de %d bytes, se esperaban %d", entry.rel, entry.size, len(files[entry.rel]))
+		}
+		if entry.mode != 0o600 {
+			t.Errorf("%s con permisos %v, se esperaba 0600", entry.rel, entry.mode)
+		}
+	}
+
+	// Se restaura sobre un estado que ya tiene uno de los archivos
+	target := t.TempDir()
+	if err := os.WriteFile(filepath.Join(target, "alerts.json"), []byte(`["viejo"]`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := extractBackup(path, target); err != nil {
+		t.Fatal(err)
+	}
+	restored, _, err := backupFiles(target, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 2 {
+		t.Errorf("archivos restaurados %v, se esperaban 2", restored)
+	}
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(target, entry.rel))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != files[entry.rel] {
+			t.Errorf("%s restaurado = %q, se esperaba %q", entry.rel, data, files[entry.rel])
+		}
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(target, "*.tmp"))
+	if len(leftovers) > 0 {
+		t.Errorf("quedaron temporales del restore: %v", leftovers)
+	}
+}
+
+func TestReadBackupWithoutManifest(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "vacio.tar.gz")
+	if err := os.WriteFile(path, []byte("no es gzip"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := readBackup(path); err == nil {
+		t.Error("se esperaba un error con un archivo que no es backup")
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"time"
+)
+
+// Variables de la API de estadísticas monetarias del BCRA
+const (
+	bcraVariableReservas  = 1  // Reservas internacionales del BCRA (millones de dólares)
+	bcraVariableBase      = 15 // Base monetaria total (millones de pesos)
+	bcraVariablePlazoFijo = 12 // Tasa de depósitos a plazo fijo a 30 días (% TNA)
+	bcraVariableCER       = 30 // Coeficiente de Estabilización de Referencia (base 2/2/2002 = 1)
+	bcraVariableUVA       = 31 // Unidad de Valor Adquisitivo en pesos
+)
+
+// RatePoint representa un valor diario de una serie del BCRA
+type RatePoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// getBCRASeries obtiene una serie de la API de estadísticas del BCRA entre dos fechas
+func getBCRASeries(variable int, from, to time.Time, client QuoteFetcher) ([]RatePoint, error) {
+	seriesURL := fmt.Sprintf("https://api.bcra.gob.ar/estadisticas/v3.0/monetarias/%d?desde=%s&hasta=%s",
+		variable, from.Format("2006-01-02"), to.Format("2006-01-02"))
+
+	resp, err := client.GetWithRetry(seriesURL, map[string]string{"Accept": "application/json"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "para la variable %d del BCRA", variable)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var seriesResp struct {
+		Results []struct {
+			Fecha string  `json:"fecha"`
+			Valor float64 `json:"valor"`
+		} `json:"results"`
+	}
+	if err := json.Unmarshal(body, &seriesResp); err != nil {
+		return nil, fmt.Errorf("error al decodificar la variable %d del BCRA: %v", variable, err)
+	}
+
+	var points []RatePoint
+	for _, r := range seriesResp.Results {
+		date, err := time.Parse("2006-01-02", r.Fecha)
+		if err != nil {
+			continue
+		}
+		points = append(points, RatePoint{Date: date, Value: r.Valor})
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("la variable %d del BCRA no tiene datos en el período", variable)
+	}
+
+	sort.Slice(points, func(i, j int) bool { return points[i].Date.Before(points[j].Date) })
+	return points, nil
+}
+
+// rateAt devuelve el último valor publicado en o antes de la fecha indicada
+func rateAt(points []RatePoint, t time.Time) float64 {
+	value := points[0].Value
+	for _, p := range points {
+		if p.Date.After(t) {
+			break
+		}
+		value = p.Value
+	}
+	return value
+}
@@ -0,0 +1,298 @@
+package bolsa
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// CashFlow representa un pago de un bono cada 100 nominales originales
+type CashFlow struct {
+	Date         time.Time `json:"date"`
+	Amortization float64   `json:"amortization"` // Capital devuelto cada 100 VN originales
+	CouponRate   float64   `json:"couponRate"`   // Tasa anual vigente para el período que termina en esta fecha (0.0075 = 0,75%)
+}
+
+// Bond representa un bono con su cronograma de pagos
+type Bond struct {
+	Symbol      string     `json:"symbol"`      // Especie (GD30, AL30...)
+	QuoteSymbol string     `json:"quoteSymbol"` // Símbolo a consultar en Yahoo Finance
+	Name        string     `json:"name"`
+	Currency    string     `json:"currency"`  // Moneda de pago: USD o ARS
+	Frequency   int        `json:"frequency"` // Pagos por año
+	CashFlows   []CashFlow `json:"cashFlows"`
+}
+
+// BondQuote representa la valuación de un bono a un precio y fecha dados (todo cada 100 VN originales)
+type BondQuote struct {
+	Bond            *Bond
+	Date            time.Time
+	CleanPrice      float64
+	DirtyPrice      float64
+	Residual        float64
+	AccruedInterest float64
+	TechnicalValue  float64
+	Parity          float64 // Precio sucio sobre valor técnico, en porcentaje
+}
+
+// SemiannualSchedule arma un cronograma semestral a partir de los tramos de cupón y las amortizaciones,
+// ambos indexados por fecha en formato 2006-01-02
+func SemiannualSchedule(first, maturity time.Time, coupons map[string]float64, amortizations map[string]float64) []CashFlow {
+	// Las fechas de cambio de cupón están ordenadas para aplicar el último tramo vigente
+	var steps []string
+	for date := range coupons {
+		steps = append(steps, date)
+	}
+	sort.Strings(steps)
+
+	var flows []CashFlow
+	for date := first; !date.After(maturity); date = date.AddDate(0, 6, 0) {
+		key := date.Format("2006-01-02")
+		rate := 0.0
+		for _, step := range steps {
+			if key >= step {
+				rate = coupons[step]
+			}
+		}
+		flows = append(flows, CashFlow{
+			Date:         date,
+			Amortization: amortizations[key],
+			CouponRate:   rate,
+		})
+	}
+	return flows
+}
+
+// AmortizeEvenly reparte el 100% del capital en cuotas semestrales iguales
+func AmortizeEvenly(first time.Time, installments int) map[string]float64 {
+	schedule := make(map[string]float64)
+	for i := 0; i < installments; i++ {
+		schedule[first.AddDate(0, 6*i, 0).Format("2006-01-02")] = 100.0 / float64(installments)
+	}
+	return schedule
+}
+
+// utcDate arma una fecha en UTC sin hora
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// DefaultBonds devuelve el catálogo embebido de bonos soberanos en dólares (reestructuración 2020)
+func DefaultBonds() []*Bond {
+	// Bonos 2029: cupón fijo del 1%, 10 amortizaciones semestrales desde enero 2025
+	coupons29 := map[string]float64{"2021-01-09": 0.01}
+	amort29 := AmortizeEvenly(utcDate(2025, time.January, 9), 10)
+
+	// Bonos 2030: cupón step-up, una cuota del 4% en julio 2024 y 12 del 8%
+	coupons30 := map[string]float64{
+		"2021-01-09": 0.00125,
+		"2022-01-09": 0.005,
+		"2024-01-09": 0.0075,
+		"2028-01-09": 0.0175,
+	}
+	amort30 := map[string]float64{"2024-07-09": 4}
+	for i := 0; i < 12; i++ {
+		amort30[utcDate(2025, time.January, 9).AddDate(0, 6*i, 0).Format("2006-01-02")] = 8
+	}
+
+	// Bonos 2035: cupón step-up, 10 amortizaciones semestrales desde enero 2031
+	coupons35 := map[string]float64{
+		"2021-01-09": 0.00125,
+		"2022-01-09": 0.01125,
+		"2023-01-09": 0.015,
+		"2024-01-09": 0.03625,
+		"2025-01-09": 0.04125,
+		"2028-01-09": 0.0475,
+		"2029-01-09": 0.05,
+	}
+	amort35 := AmortizeEvenly(utcDate(2031, time.January, 9), 10)
+
+	first := utcDate(2021, time.January, 9)
+	flows29 := SemiannualSchedule(first, utcDate(2029, time.July, 9), coupons29, amort29)
+	flows30 := SemiannualSchedule(first, utcDate(2030, time.July, 9), coupons30, amort30)
+	flows35 := SemiannualSchedule(first, utcDate(2035, time.July, 9), coupons35, amort35)
+
+	return []*Bond{
+		{Symbol: "GD29", QuoteSymbol: "GD29D.BA", Name: "Global 2029 (ley NY)", Currency: "USD", Frequency: 2, CashFlows: flows29},
+		{Symbol: "AL29", QuoteSymbol: "AL29D.BA", Name: "Bonar 2029 (ley AR)", Currency: "USD", Frequency: 2, CashFlows: flows29},
+		{Symbol: "GD30", QuoteSymbol: "GD30D.BA", Name: "Global 2030 (ley NY)", Currency: "USD", Frequency: 2, CashFlows: flows30},
+		{Symbol: "AL30", QuoteSymbol: "AL30D.BA", Name: "Bonar 2030 (ley AR)", Currency: "USD", Frequency: 2, CashFlows: flows30},
+		{Symbol: "GD35", QuoteSymbol: "GD35D.BA", Name: "Global 2035 (ley NY)", Currency: "USD", Frequency: 2, CashFlows: flows35},
+		{Symbol: "AL35", QuoteSymbol: "AL35D.BA", Name: "Bonar 2035 (ley AR)", Currency: "USD", Frequency: 2, CashFlows: flows35},
+	}
+}
+
+// FindBond busca un bono del catálogo por especie o símbolo de cotización
+func FindBond(bonds []*Bond, symbol string) *Bond {
+	for _, bond := range bonds {
+		if bond.Symbol == symbol || bond.QuoteSymbol == symbol {
+			return bond
+		}
+	}
+	return nil
+}
+
+// Residual devuelve el valor residual cada 100 VN originales a una fecha (capital aún no amortizado)
+func (b *Bond) Residual(at time.Time) float64 {
+	residual := 100.0
+	for _, flow := range b.CashFlows {
+		if !flow.Date.After(at) {
+			residual -= flow.Amortization
+		}
+	}
+	if residual < 0 {
+		residual = 0
+	}
+	return residual
+}
+
+// period devuelve las fechas del período de cupón en curso y la tasa vigente
+func (b *Bond) period(at time.Time) (start, end time.Time, rate float64, ok bool) {
+	for i, flow := range b.CashFlows {
+		if flow.Date.After(at) {
+			if i > 0 {
+				start = b.CashFlows[i-1].Date
+			} else {
+				start = flow.Date.AddDate(0, -12/b.Frequency, 0)
+			}
+			return start, flow.Date, flow.CouponRate, true
+		}
+	}
+	return time.Time{}, time.Time{}, 0, false
+}
+
+// days360 cuenta días entre dos fechas con la convención 30/360
+func days360(from, to time.Time) float64 {
+	d1, d2 := from.Day(), to.Day()
+	if d1 == 31 {
+		d1 = 30
+	}
+	if d2 == 31 && d1 == 30 {
+		d2 = 30
+	}
+	return float64((to.Year()-from.Year())*360 + (int(to.Month())-int(from.Month()))*30 + (d2 - d1))
+}
+
+// AccruedInterest devuelve los intereses corridos cada 100 VN originales a una fecha
+func (b *Bond) AccruedInterest(at time.Time) float64 {
+	start, _, rate, ok := b.period(at)
+	if !ok {
+		return 0
+	}
+	return b.Residual(at) * rate * days360(start, at) / 360
+}
+
+// TechnicalValue devuelve el valor técnico (residual más intereses corridos) cada 100 VN originales
+func (b *Bond) TechnicalValue(at time.Time) float64 {
+	return b.Residual(at) + b.AccruedInterest(at)
+}
+
+// Quote valúa el bono a partir de un precio limpio cada 100 VN originales, como cotiza en BYMA
+func (b *Bond) Quote(cleanPrice float64, at time.Time) BondQuote {
+	accrued := b.AccruedInterest(at)
+	technical := b.Residual(at) + accrued
+
+	quote := BondQuote{
+		Bond:            b,
+		Date:            at,
+		CleanPrice:      cleanPrice,
+		DirtyPrice:      cleanPrice + accrued,
+		Residual:        b.Residual(at),
+		AccruedInterest: accrued,
+		TechnicalValue:  technical,
+	}
+	if technical > 0 {
+		quote.Parity = quote.DirtyPrice / technical * 100
+	}
+	return quote
+}
+
+// CleanFromDirty convierte un precio sucio en limpio descontando los intereses corridos
+func (b *Bond) CleanFromDirty(dirtyPrice float64, at time.Time) float64 {
+	return dirtyPrice - b.AccruedInterest(at)
+}
+
+// Value devuelve el monto a cobrar por una tenencia: nominales por precio sucio cada 100 VN
+func (q BondQuote) Value(nominal float64) float64 {
+	return nominal * q.DirtyPrice / 100
+}
+
+// bondPayment representa un pago futuro con su plazo en años desde la fecha de valuación
+type bondPayment struct {
+	Years  float64
+	Amount float64
+}
+
+// payments devuelve los pagos futuros (renta más amortización) cada 100 VN originales
+func (b *Bond) payments(at time.Time) []bondPayment {
+	var result []bondPayment
+	residual := 100.0
+	for _, flow := range b.CashFlows {
+		interest := residual * flow.CouponRate / float64(b.Frequency)
+		residual -= flow.Amortization
+		if !flow.Date.After(at) {
+			continue
+		}
+		result = append(result, bondPayment{
+			Years:  flow.Date.Sub(at).Hours() / 24 / 365,
+			Amount: interest + flow.Amortization,
+		})
+	}
+	return result
+}
+
+// presentValue descuenta los pagos a una tasa efectiva anual
+func presentValue(payments []bondPayment, rate float64) float64 {
+	pv := 0.0
+	for _, p := range payments {
+		pv += p.Amount / math.Pow(1+rate, p.Years)
+	}
+	return pv
+}
+
+// YTM calcula la TIR efectiva anual que iguala el valor presente de los pagos al precio sucio
+func (q BondQuote) YTM() (float64, bool) {
+	payments := q.Bond.payments(q.Date)
+	if len(payments) == 0 || q.DirtyPrice <= 0 {
+		return 0, false
+	}
+
+	// Bisección: el valor presente es decreciente en la tasa
+	low, high := -0.99, 10.0
+	if presentValue(payments, low) < q.DirtyPrice || presentValue(payments, high) > q.DirtyPrice {
+		return 0, false
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		if presentValue(payments, mid) > q.DirtyPrice {
+			low = mid
+		} else {
+			high = mid
+		}
+	}
+	return (low + high) / 2, true
+}
+
+// Duration devuelve la duration de Macaulay y la modificada (en años) a una TIR dada
+func (q BondQuote) Duration(ytm float64) (float64, float64) {
+	payments := q.Bond.payments(q.Date)
+	pv := presentValue(payments, ytm)
+	if pv == 0 {
+		return 0, 0
+	}
+
+	weighted := 0.0
+	for _, p := range payments {
+		weighted += p.Years * p.Amount / math.Pow(1+ytm, p.Years)
+	}
+	macaulay := weighted / pv
+	return macaulay, macaulay / (1 + ytm)
+}
+
+// Maturity devuelve la fecha del último pago del bono
+func (b *Bond) Maturity() time.Time {
+	if len(b.CashFlows) == 0 {
+		return time.Time{}
+	}
+	return b.CashFlows[len(b.CashFlows)-1].Date
+}
@@ -0,0 +1,161 @@
+package bolsa
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+// near compara con tolerancia absoluta
+func near(got, want, tolerance float64) bool {
+	return math.Abs(got-want) <= tolerance
+}
+
+func TestDays360(t *testing.T) {
+	tests := []struct {
+		from, to time.Time
+		want     float64
+	}{
+		{utcDate(2025, time.January, 9), utcDate(2025, time.March, 10), 61},
+		{utcDate(2025, time.January, 9), utcDate(2025, time.July, 9), 180},
+		{utcDate(2025, time.January, 31), utcDate(2025, time.March, 1), 31},   // El 31 cuenta como 30
+		{utcDate(2025, time.January, 30), utcDate(2025, time.March, 31), 60},  // 31 final con inicio 30 también
+		{utcDate(2025, time.February, 28), utcDate(2025, time.March, 31), 33}, // Inicio 28: el 31 final se mantiene
+		{utcDate(2024, time.July, 9), utcDate(2025, time.January, 9), 180},
+	}
+	for _, tt := range tests {
+		if got := days360(tt.from, tt.to); got != tt.want {
+			t.Errorf("days360(%s, %s) = %v, se esperaba %v", tt.from.Format("2006-01-02"), tt.to.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestAmortizationSchedules(t *testing.T) {
+	for _, bond := range DefaultBonds() {
+		total := 0.0
+		for _, flow := range bond.CashFlows {
+			total += flow.Amortization
+		}
+		if !near(total, 100, 1e-9) {
+			t.Errorf("%s amortiza %.4f, se esperaba 100", bond.Symbol, total)
+		}
+	}
+
+	bonds := DefaultBonds()
+	tests := []struct {
+		symbol string
+		at     time.Time
+		want   float64
+	}{
+		{"AL30", utcDate(2024, time.July, 8), 100},
+		{"AL30", utcDate(2024, time.July, 9), 96}, // Cuota del 4%
+		{"AL30", utcDate(2025, time.March, 10), 88},
+		{"AL30", utcDate(2030, time.July, 9), 0},
+		{"GD29", utcDate(2025, time.October, 9), 80},
+		{"GD35", utcDate(2030, time.December, 31), 100},
+		{"GD35", utcDate(2031, time.January, 9), 90},
+	}
+	for _, tt := range tests {
+		if got := FindBond(bonds, tt.symbol).Residual(tt.at); !near(got, tt.want, 1e-9) {
+			t.Errorf("residual de %s al %s = %.4f, se esperaba %.4f", tt.symbol, tt.at.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestAccruedInterest(t *testing.T) {
+	bonds := DefaultBonds()
+	tests := []struct {
+		symbol string
+		at     time.Time
+		want   float64
+	}{
+		{"AL30", utcDate(2025, time.March, 10), 88 * 0.0075 * 61 / 360},
+		{"GD30", utcDate(2025, time.March, 10), 88 * 0.0075 * 61 / 360}, // Mismo cronograma que AL30
+		{"AL30", utcDate(2025, time.January, 9), 0},                     // Día de pago: arranca un período nuevo
+		{"GD35", utcDate(2025, time.March, 10), 100 * 0.04125 * 61 / 360},
+		{"GD29", utcDate(2025, time.October, 9), 80 * 0.01 * 90 / 360},
+		{"GD30", utcDate(2031, time.January, 1), 0}, // Vencido
+	}
+	for _, tt := range tests {
+		if got := FindBond(bonds, tt.symbol).AccruedInterest(tt.at); !near(got, tt.want, 1e-9) {
+			t.Errorf("intereses corridos de %s al %s = %.6f, se esperaba %.6f", tt.symbol, tt.at.Format("2006-01-02"), got, tt.want)
+		}
+	}
+}
+
+func TestQuoteParityAndDirtyPrice(t *testing.T) {
+	al30 := FindBond(DefaultBonds(), "AL30")
+	at := utcDate(2025, time.March, 10)
+	q := al30.Quote(70, at)
+
+	accrued := 88 * 0.0075 * 61 / 360
+	if !near(q.DirtyPrice, 70+accrued, 1e-9) || !near(q.TechnicalValue, 88+accrued, 1e-9) {
+		t.Errorf("sucio %.4f y técnico %.4f, se esperaban %.4f y %.4f", q.DirtyPrice, q.TechnicalValue, 70+accrued, 88+accrued)
+	}
+	if !near(q.Parity, (70+accrued)/(88+accrued)*100, 1e-9) {
+		t.Errorf("paridad %.4f", q.Parity)
+	}
+	if !near(al30.CleanFromDirty(q.DirtyPrice, at), 70, 1e-9) {
+		t.Errorf("CleanFromDirty no invierte Quote")
+	}
+	if !near(q.Value(1000), 10*(70+accrued), 1e-9) {
+		t.Errorf("valor de 1000 VN = %.4f", q.Value(1000))
+	}
+}
+
+func TestYTMAndDuration(t *testing.T) {
+	start := utcDate(2025, time.January, 1)
+	// 365 días exactos entre pagos: los plazos en años quedan enteros
+	oneYear, twoYears := start.AddDate(1, 0, 0), start.AddDate(2, 0, 0)
+	zero := &Bond{Symbol: "CERO", Frequency: 1, CashFlows: []CashFlow{{Date: oneYear, Amortization: 100}}}
+	annual := &Bond{Symbol: "BONO10", Frequency: 1, CashFlows: []CashFlow{
+		{Date: oneYear, CouponRate: 0.10},
+		{Date: twoYears, CouponRate: 0.10, Amortization: 100},
+	}}
+	al30 := FindBond(DefaultBonds(), "AL30")
+
+	tests := []struct {
+		name               string
+		quote              BondQuote
+		ytm, macaulay, mod float64
+	}{
+		{"cupón cero a un año", zero.Quote(100/1.1, start), 0.10, 1, 1 / 1.1},
+		{"bullet 10% anual a la par", annual.Quote(100, start), 0.10, (10/1.1 + 2*110/1.21) / 100, (10/1.1 + 2*110/1.21) / 100 / 1.1},
+		{"bullet 10% anual bajo la par", annual.Quote(100*(0.10/1.12+1.10/1.12/1.12), start), 0.12, 0, 0},
+		// AL30 a 70 de precio limpio el 10/03/2025, calculado aparte con el mismo cronograma
+		{"AL30 a 70", al30.Quote(70, utcDate(2025, time.March, 10)), 0.100386, 2.586929, 2.350928},
+	}
+	for _, tt := range tests {
+		ytm, ok := tt.quote.YTM()
+		if !ok {
+			t.Errorf("%s: no se pudo calcular la TIR", tt.name)
+			continue
+		}
+		if !near(ytm, tt.ytm, 1e-5) {
+			t.Errorf("%s: TIR = %.6f, se esperaba %.6f", tt.name, ytm, tt.ytm)
+		}
+		if tt.macaulay == 0 {
+			continue
+		}
+		macaulay, modified := tt.quote.Duration(ytm)
+		if !near(macaulay, tt.macaulay, 1e-5) || !near(modified, tt.mod, 1e-5) {
+			t.Errorf("%s: duration = %.6f / %.6f, se esperaba %.6f / %.6f", tt.name, macaulay, modified, tt.macaulay, tt.mod)
+		}
+	}
+}
+
+func TestYTMWithoutSolution(t *testing.T) {
+	gd30 := FindBond(DefaultBonds(), "GD30")
+	tests := []struct {
+		name  string
+		quote BondQuote
+	}{
+		{"precio cero", gd30.Quote(0, utcDate(2025, time.March, 10))},
+		{"bono vencido", gd30.Quote(50, utcDate(2031, time.January, 1))},
+	}
+	for _, tt := range tests {
+		if ytm, ok := tt.quote.YTM(); ok {
+			t.Errorf("%s: TIR %.4f, se esperaba sin solución", tt.name, ytm)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+// Package bolsa expone la lógica reutilizable de bolsa-valores-argentina-GO: cotizaciones de Yahoo Finance,
+// watchlists con suscripción a actualizaciones y la valuación de bonos soberanos (valor técnico, paridad,
+// TIR y duration).
+//
+//	import "github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+//
+// El módulo sigue versionado semántico: las versiones se publican como tags vX.Y.Z y dentro de una misma
+// versión mayor no se rompe la API exportada de este paquete.
+package bolsa
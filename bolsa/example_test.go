@@ -0,0 +1,92 @@
+package bolsa_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+)
+
+// fakeYahoo responde como la API chart de Yahoo Finance con precios fijos, para que los ejemplos no dependan de la red
+var fakeYahoo = bolsa.FetcherFunc(func(url string, headers map[string]string) (*http.Response, error) {
+	prices := map[string]string{
+		"GGAL.BA": `{"regularMarketPrice": 5120, "previousClose": 5000, "regularMarketVolume": 1250000, "shortName": "GRUPO FIN GALICIA"}`,
+		"YPF":     `{"regularMarketPrice": 34.5, "chartPreviousClose": 35, "regularMarketVolume": 3100000, "shortName": "YPF Sociedad Anonima"}`,
+	}
+	symbol := url[strings.LastIndex(url, "/")+1:]
+	meta, ok := prices[symbol]
+	if !ok {
+		body := `{"chart": {"result": null, "error": {"code": "Not Found", "description": "No data found, symbol may be delisted"}}}`
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	body := `{"chart": {"result": [{"meta": ` + meta + `}], "error": null}}`
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+})
+
+// Obtener una cotización. Con la red real se usa bolsa.HTTPFetcher{} en lugar del proveedor falso
+func ExampleGetQuote() {
+	quote, err := bolsa.GetQuote(fakeYahoo, "GGAL.BA")
+	if err != nil {
+		fmt.Println("error:", err)
+		return
+	}
+	fmt.Printf("%s %s %.2f (%+.2f%%)\n", quote.Symbol, quote.Name, quote.Price, quote.ChangePercent)
+	// Output: GGAL.BA GRUPO FIN GALICIA 5120.00 (+2.40%)
+}
+
+// Armar una watchlist y cotizarla entera: los símbolos que fallan vuelven con Error
+func ExampleWatchlist() {
+	watchlist := bolsa.NewWatchlist("ggal.ba", "YPF", "AAPL")
+	watchlist.Add("YPF") // Repetido: no se agrega
+	watchlist.Remove("AAPL")
+	watchlist.Add("XXXX")
+
+	for _, quote := range bolsa.FetchQuotes(fakeYahoo, watchlist.Symbols()) {
+		if quote.Error != "" {
+			fmt.Printf("%-8s sin datos: %s\n", quote.Symbol, quote.Error)
+			continue
+		}
+		fmt.Printf("%-8s %10.2f %+6.2f%%\n", quote.Symbol, quote.Price, quote.ChangePercent)
+	}
+	// Output:
+	// GGAL.BA     5120.00  +2.40%
+	// YPF           34.50  -1.43%
+	// XXXX     sin datos: Not Found: No data found, symbol may be delisted
+}
+
+// Suscribirse a las actualizaciones de una watchlist hasta cancelar el contexto
+func ExampleSubscribe() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	watchlist := bolsa.NewWatchlist("GGAL.BA", "YPF")
+	updates := bolsa.Subscribe(ctx, fakeYahoo, watchlist, time.Minute)
+
+	// La primera ronda llega enseguida; después, una por minuto
+	for i := 0; i < 2; i++ {
+		quote := <-updates
+		fmt.Printf("%s %.2f\n", quote.Symbol, quote.Price)
+	}
+	// Output:
+	// GGAL.BA 5120.00
+	// YPF 34.50
+}
+
+// Valuar un bono del catálogo a un precio limpio y calcular su TIR y duration
+func ExampleBond_Quote() {
+	al30 := bolsa.FindBond(bolsa.DefaultBonds(), "AL30")
+	settlement := time.Date(2025, time.March, 10, 0, 0, 0, 0, time.UTC)
+
+	quote := al30.Quote(70, settlement)
+	ytm, _ := quote.YTM()
+	_, modified := quote.Duration(ytm)
+	fmt.Printf("residual %.0f, intereses corridos %.4f, paridad %.2f%%\n", quote.Residual, quote.AccruedInterest, quote.Parity)
+	fmt.Printf("TIR %.2f%%, duration modificada %.2f años\n", ytm*100, modified)
+	// Output:
+	// residual 88, intereses corridos 0.1118, paridad 79.57%
+	// TIR 10.04%, duration modificada 2.35 años
+}
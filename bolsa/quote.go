@@ -0,0 +1,240 @@
+package bolsa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Quote es la cotización de un símbolo
+type Quote struct {
+	Symbol        string  `json:"symbol"`
+	Name          string  `json:"name"`
+	Price         float64 `json:"price"`
+	PreviousClose float64 `json:"previous_close"`
+	Change        float64 `json:"change"`
+	ChangePercent float64 `json:"change_percent"`
+	Volume        int64   `json:"volume"`
+	MarketTime    int64   `json:"market_time,omitempty"` // Hora Unix de la última operación, si el proveedor la informa
+	Error         string  `json:"error,omitempty"`
+}
+
+// QuoteFetcher es lo que necesitan las funciones de consulta: un GET con los reintentos del proveedor.
+// En pruebas alcanza con un FetcherFunc que devuelva respuestas armadas a mano
+type QuoteFetcher interface {
+	GetWithRetry(url string, headers map[string]string) (*http.Response, error)
+}
+
+// FetcherFunc adapta una función a QuoteFetcher, como http.HandlerFunc
+type FetcherFunc func(url string, headers map[string]string) (*http.Response, error)
+
+// GetWithRetry llama a la función
+func (f FetcherFunc) GetWithRetry(url string, headers map[string]string) (*http.Response, error) {
+	return f(url, headers)
+}
+
+// HTTPFetcher es un QuoteFetcher mínimo sobre un *http.Client, sin reintentos
+type HTTPFetcher struct {
+	Client *http.Client
+}
+
+// GetWithRetry hace un único GET con los headers pedidos
+func (h HTTPFetcher) GetWithRetry(url string, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	client := h.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// Endpoints de Yahoo Finance: chart (v8) trae la cotización en meta y suele ser más estable; quoteSummary (v10)
+// queda como alternativa cuando chart no responde
+const (
+	chartURL        = "https://query2.finance.yahoo.com/v8/finance/chart/%s"
+	quoteSummaryURL = "https://query1.finance.yahoo.com/v10/finance/quoteSummary/%s?modules=price"
+)
+
+// yahooHeaders son los headers de un navegador: sin ellos Yahoo responde 401 o un desafío de bot
+var yahooHeaders = map[string]string{
+	"User-Agent":                "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+	"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
+	"Accept-Language":           "en-US,en;q=0.5",
+	"DNT":                       "1",
+	"Connection":                "keep-alive",
+	"Sec-Fetch-Dest":            "document",
+	"Sec-Fetch-Mode":            "navigate",
+	"Sec-Fetch-Site":            "none",
+	"Cache-Control":             "no-cache",
+	"Pragma":                    "no-cache",
+	"Sec-Fetch-User":            "?1",
+	"Upgrade-Insecure-Requests": "1",
+	"Referer":                   "https://finance.yahoo.com/",
+}
+
+// NewQuote arma una cotización calculando la variación respecto del cierre anterior
+func NewQuote(symbol, name string, price, previousClose float64, volume int64) Quote {
+	quote := Quote{Symbol: symbol, Name: name, Price: price, PreviousClose: previousClose, Volume: volume}
+	quote.Change = price - previousClose
+	if previousClose != 0 {
+		quote.ChangePercent = quote.Change / previousClose * 100
+	}
+	return quote
+}
+
+// GetQuote cotiza un símbolo con la API chart de Yahoo Finance y, si chart no responde, con quoteSummary.
+// Los errores son *ChartError, *SchemaError, *StatusError, ErrNoData o el del transporte
+func GetQuote(client QuoteFetcher, symbol string) (Quote, error) {
+	url, parse := fmt.Sprintf(chartURL, symbol), ParseChart
+	resp, err := client.GetWithRetry(url, yahooHeaders)
+	if err != nil {
+		url, parse = fmt.Sprintf(quoteSummaryURL, symbol), ParseQuoteSummary
+		if resp, err = client.GetWithRetry(url, yahooHeaders); err != nil {
+			return Quote{}, err
+		}
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return Quote{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		// El 404 de chart explica en el cuerpo si el símbolo no existe o dejó de cotizar
+		var chartErr *ChartError
+		if _, err := parse(body, symbol); resp.StatusCode == http.StatusNotFound && errors.As(err, &chartErr) {
+			return Quote{}, err
+		}
+		return Quote{}, &StatusError{Symbol: symbol, Status: resp.StatusCode}
+	}
+	return parse(body, symbol)
+}
+
+// ParseChart decodifica la respuesta de la API chart (v8) de Yahoo Finance
+func ParseChart(body []byte, symbol string) (Quote, error) {
+	var chart struct {
+		Chart struct {
+			Result []struct {
+				Meta struct {
+					RegularMarketPrice  FlexFloat `json:"regularMarketPrice"`
+					PreviousClose       FlexFloat `json:"previousClose"`
+					ChartPreviousClose  FlexFloat `json:"chartPreviousClose"`
+					RegularMarketVolume FlexFloat `json:"regularMarketVolume"`
+					ShortName           string    `json:"shortName"`
+					RegularMarketTime   int64     `json:"regularMarketTime"`
+				} `json:"meta"`
+			} `json:"result"`
+			Error *struct {
+				Code        string `json:"code"`
+				Description string `json:"description"`
+			} `json:"error"`
+		} `json:"chart"`
+	}
+	if err := json.Unmarshal(body, &chart); err != nil {
+		return Quote{}, &SchemaError{Source: "v8", Symbol: symbol, Reason: err.Error(), Body: body}
+	}
+	if chart.Chart.Error != nil {
+		return Quote{}, &ChartError{Symbol: symbol, Code: chart.Chart.Error.Code, Description: chart.Chart.Error.Description}
+	}
+	if len(chart.Chart.Result) == 0 {
+		return Quote{}, noDataError{symbol}
+	}
+
+	meta := chart.Chart.Result[0].Meta
+	if !meta.RegularMarketPrice.Valid {
+		reason := "falta meta.regularMarketPrice"
+		if missing := MissingPaths(body, []string{"chart.result.0.meta"}); len(missing) > 0 {
+			reason = "falta " + strings.Join(missing, ", ")
+		}
+		return Quote{}, &SchemaError{Source: "v8", Symbol: symbol, Reason: reason, Body: body}
+	}
+
+	// Algunas respuestas solo traen chartPreviousClose
+	previousClose := meta.PreviousClose
+	if !previousClose.Valid {
+		previousClose = meta.ChartPreviousClose
+	}
+	name := meta.ShortName
+	if name == "" {
+		name = symbol
+	}
+	quote := NewQuote(symbol, name, meta.RegularMarketPrice.Value, previousClose.Value, meta.RegularMarketVolume.Int())
+	quote.MarketTime = meta.RegularMarketTime
+	return quote, nil
+}
+
+// ParseQuoteSummary decodifica la respuesta de la API quoteSummary (v10) con el módulo price
+func ParseQuoteSummary(body []byte, symbol string) (Quote, error) {
+	var summary struct {
+		QuoteSummary struct {
+			Result []struct {
+				Price struct {
+					// Yahoo envía estos campos como {raw, fmt} o como número según la versión
+					RegularMarketPrice         FlexFloat `json:"regularMarketPrice"`
+					RegularMarketPreviousClose FlexFloat `json:"regularMarketPreviousClose"`
+					RegularMarketVolume        FlexFloat `json:"regularMarketVolume"`
+					ShortName                  string    `json:"shortName"`
+					LongName                   string    `json:"longName"`
+				} `json:"price"`
+			} `json:"result"`
+		} `json:"quoteSummary"`
+	}
+	if err := json.Unmarshal(body, &summary); err != nil {
+		return Quote{}, &SchemaError{Source: "v10", Symbol: symbol, Reason: err.Error(), Body: body}
+	}
+	if len(summary.QuoteSummary.Result) == 0 {
+		return Quote{}, noDataError{symbol}
+	}
+
+	price := summary.QuoteSummary.Result[0].Price
+	if !price.RegularMarketPrice.Valid {
+		reason := "falta price.regularMarketPrice"
+		if missing := MissingPaths(body, []string{"quoteSummary.result.0.price"}); len(missing) > 0 {
+			reason = "falta " + strings.Join(missing, ", ")
+		}
+		return Quote{}, &SchemaError{Source: "v10", Symbol: symbol, Reason: reason, Body: body}
+	}
+
+	name := price.ShortName
+	if name == "" {
+		name = price.LongName
+	}
+	return NewQuote(symbol, name, price.RegularMarketPrice.Value, price.RegularMarketPreviousClose.Value, price.RegularMarketVolume.Int()), nil
+}
+
+// FetchQuotes cotiza los símbolos en paralelo y devuelve los resultados en el orden pedido;
+// los que fallan vuelven con Error
+func FetchQuotes(client QuoteFetcher, symbols []string) []Quote {
+	return FetchQuotesWith(symbols, func(symbol string) (Quote, error) {
+		return GetQuote(client, symbol)
+	})
+}
+
+// FetchQuotesWith es FetchQuotes con otra forma de cotizar cada símbolo (una caché, otro proveedor)
+func FetchQuotesWith(symbols []string, get func(symbol string) (Quote, error)) []Quote {
+	quotes := make([]Quote, len(symbols))
+	var wg sync.WaitGroup
+	for i, symbol := range symbols {
+		wg.Add(1)
+		go func(i int, symbol string) {
+			defer wg.Done()
+			quote, err := get(symbol)
+			if err != nil {
+				quote = Quote{Symbol: symbol, Error: err.Error()}
+			}
+			quotes[i] = quote
+		}(i, symbol)
+	}
+	wg.Wait()
+	return quotes
+}
@@ -0,0 +1,73 @@
+package bolsa
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseChartSchemas(t *testing.T) {
+	tests := []struct {
+		name                string
+		body                string
+		price, previous     float64
+		volume              int64
+		wantName            string
+		wantMarketTime      int64
+		wantChange, wantPct float64
+	}{
+		{"números", `{"chart": {"result": [{"meta": {"regularMarketPrice": 110, "previousClose": 100, "regularMarketVolume": 500, "shortName": "YPF", "regularMarketTime": 1760000000}}]}}`,
+			110, 100, 500, "YPF", 1760000000, 10, 10},
+		{"objetos raw/fmt y texto", `{"chart": {"result": [{"meta": {"regularMarketPrice": {"raw": 5120, "fmt": "5,120.00"}, "previousClose": "5,000", "regularMarketVolume": {"fmt": "1,250,000"}}}]}}`,
+			5120, 5000, 1250000, "GGAL.BA", 0, 120, 2.4},
+		{"solo chartPreviousClose", `{"chart": {"result": [{"meta": {"regularMarketPrice": 90, "previousClose": null, "chartPreviousClose": 100}}]}}`,
+			90, 100, 0, "GGAL.BA", 0, -10, -10},
+	}
+	for _, tt := range tests {
+		q, err := ParseChart([]byte(tt.body), "GGAL.BA")
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if q.Price != tt.price || q.PreviousClose != tt.previous || q.Volume != tt.volume || q.Name != tt.wantName || q.MarketTime != tt.wantMarketTime {
+			t.Errorf("%s: cotización %+v", tt.name, q)
+		}
+		if !near(q.Change, tt.wantChange, 1e-9) || !near(q.ChangePercent, tt.wantPct, 1e-9) {
+			t.Errorf("%s: variación %.4f (%.4f%%), se esperaba %.4f (%.4f%%)", tt.name, q.Change, q.ChangePercent, tt.wantChange, tt.wantPct)
+		}
+	}
+}
+
+func TestParseChartErrors(t *testing.T) {
+	var chartErr *ChartError
+	_, err := ParseChart([]byte(`{"chart": {"result": null, "error": {"code": "Not Found", "description": "No data found, symbol may be delisted"}}}`), "XXXX")
+	if !errors.As(err, &chartErr) || chartErr.Code != "Not Found" {
+		t.Errorf("error de Yahoo = %v, se esperaba *ChartError", err)
+	}
+
+	if _, err := ParseChart([]byte(`{"chart": {"result": []}}`), "XXXX"); !errors.Is(err, ErrNoData) {
+		t.Errorf("sin resultados = %v, se esperaba ErrNoData", err)
+	}
+
+	var schemaErr *SchemaError
+	for _, body := range []string{`{"chart": `, `{"chart": {"result": [{"meta": {"previousClose": 100}}]}}`, `{"chart": {"result": [{}]}}`} {
+		if _, err := ParseChart([]byte(body), "GGAL.BA"); !errors.As(err, &schemaErr) || schemaErr.Source != "v8" || string(schemaErr.Body) != body {
+			t.Errorf("%s: error = %v, se esperaba *SchemaError con el cuerpo", body, err)
+		}
+	}
+}
+
+func TestParseQuoteSummary(t *testing.T) {
+	body := `{"quoteSummary": {"result": [{"price": {"regularMarketPrice": {"raw": 34.5, "fmt": "34.50"}, "regularMarketPreviousClose": {"raw": 35}, "regularMarketVolume": {"raw": 3100000}, "longName": "YPF Sociedad Anonima"}}]}}`
+	q, err := ParseQuoteSummary([]byte(body), "YPF")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Price != 34.5 || q.PreviousClose != 35 || q.Volume != 3100000 || q.Name != "YPF Sociedad Anonima" {
+		t.Errorf("cotización %+v", q)
+	}
+
+	var schemaErr *SchemaError
+	if _, err := ParseQuoteSummary([]byte(`{"quoteSummary": {"result": [{"price": {}}]}}`), "YPF"); !errors.As(err, &schemaErr) || schemaErr.Source != "v10" {
+		t.Errorf("sin precio = %v, se esperaba *SchemaError", err)
+	}
+}
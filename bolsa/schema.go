@@ -0,0 +1,153 @@
+package bolsa
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FlexFloat decodifica un número que Yahoo puede enviar como número, texto, null u objeto {raw, fmt}
+type FlexFloat struct {
+	Value float64
+	Valid bool // false si el campo vino null, vacío o no vino
+}
+
+// UnmarshalJSON acepta 12.5, "12.5", {"raw": 12.5, "fmt": "12.50"} y null
+func (f *FlexFloat) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || string(data) == "null" {
+		*f = FlexFloat{}
+		return nil
+	}
+
+	switch data[0] {
+	case '{':
+		var obj struct {
+			Raw json.RawMessage `json:"raw"`
+			Fmt string          `json:"fmt"`
+		}
+		if err := json.Unmarshal(data, &obj); err != nil {
+			return err
+		}
+		if len(obj.Raw) > 0 {
+			return f.UnmarshalJSON(obj.Raw)
+		}
+		// Sin raw intentamos con el texto formateado (acepta "1,234.50"; sufijos como "2.5M" quedan inválidos)
+		return f.parseText(obj.Fmt)
+	case '"':
+		var text string
+		if err := json.Unmarshal(data, &text); err != nil {
+			return err
+		}
+		return f.parseText(text)
+	default:
+		value, err := strconv.ParseFloat(string(data), 64)
+		if err != nil {
+			return fmt.Errorf("número inválido %s", data)
+		}
+		*f = FlexFloat{Value: value, Valid: true}
+		return nil
+	}
+}
+
+// parseText interpreta un número enviado como texto, ignorando separadores de miles
+func (f *FlexFloat) parseText(text string) error {
+	text = strings.ReplaceAll(strings.TrimSpace(text), ",", "")
+	if text == "" {
+		*f = FlexFloat{}
+		return nil
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		// Un texto no numérico no rompe el parseo del resto de la respuesta
+		*f = FlexFloat{}
+		return nil
+	}
+	*f = FlexFloat{Value: value, Valid: true}
+	return nil
+}
+
+// Int devuelve el valor como entero (para volúmenes)
+func (f FlexFloat) Int() int64 {
+	return int64(f.Value)
+}
+
+// MissingPaths devuelve las rutas esperadas ("chart.result.0.meta.regularMarketPrice") que no están en el JSON
+func MissingPaths(body []byte, paths []string) []string {
+	var root interface{}
+	if err := json.Unmarshal(body, &root); err != nil {
+		return paths
+	}
+
+	var missing []string
+	for _, path := range paths {
+		node := root
+		for _, key := range strings.Split(path, ".") {
+			switch n := node.(type) {
+			case map[string]interface{}:
+				node = n[key]
+			case []interface{}:
+				index, err := strconv.Atoi(key)
+				if err != nil || index >= len(n) {
+					node = nil
+				} else {
+					node = n[index]
+				}
+			default:
+				node = nil
+			}
+			if node == nil {
+				break
+			}
+		}
+		if node == nil {
+			missing = append(missing, path)
+		}
+	}
+	return missing
+}
+
+// ErrNoData indica que el proveedor respondió sin resultados para el símbolo
+var ErrNoData = errors.New("sin datos")
+
+// noDataError es ErrNoData con el símbolo en el mensaje
+type noDataError struct{ symbol string }
+
+func (e noDataError) Error() string        { return "sin datos para " + e.symbol }
+func (e noDataError) Is(target error) bool { return target == ErrNoData }
+
+// ChartError es el error que Yahoo informa dentro de la respuesta (símbolo inexistente, deslistado...)
+type ChartError struct {
+	Symbol      string
+	Code        string // "Not Found", "Bad Request"...
+	Description string
+}
+
+func (e *ChartError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// SchemaError es una respuesta que no tiene la forma esperada; Body conserva el cuerpo para diagnosticarla
+type SchemaError struct {
+	Source string // "v8" (chart) o "v10" (quoteSummary)
+	Symbol string
+	Reason string
+	Body   []byte
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("esquema desconocido en la respuesta %s de %s: %s", e.Source, e.Symbol, e.Reason)
+}
+
+// StatusError es una respuesta HTTP con un código distinto de 200
+type StatusError struct {
+	Symbol string
+	Status int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("código de estado HTTP inesperado: %d para %s", e.Status, e.Symbol)
+}
@@ -0,0 +1,87 @@
+package bolsa
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Watchlist es una lista de símbolos a seguir; se puede modificar mientras hay una suscripción activa
+type Watchlist struct {
+	mu      sync.Mutex
+	symbols []string
+}
+
+// NewWatchlist arma una watchlist con los símbolos dados, sin repetidos
+func NewWatchlist(symbols ...string) *Watchlist {
+	w := &Watchlist{}
+	for _, symbol := range symbols {
+		w.Add(symbol)
+	}
+	return w
+}
+
+// Add agrega un símbolo al final; devuelve false si ya estaba o está vacío
+func (w *Watchlist) Add(symbol string) bool {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if symbol == "" {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, existing := range w.symbols {
+		if existing == symbol {
+			return false
+		}
+	}
+	w.symbols = append(w.symbols, symbol)
+	return true
+}
+
+// Remove saca un símbolo; devuelve false si no estaba
+func (w *Watchlist) Remove(symbol string) bool {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for i, existing := range w.symbols {
+		if existing == symbol {
+			w.symbols = append(w.symbols[:i], w.symbols[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Symbols devuelve una copia de los símbolos en el orden en que se agregaron
+func (w *Watchlist) Symbols() []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]string(nil), w.symbols...)
+}
+
+// Subscribe cotiza la watchlist enseguida y después cada interval, y manda cada cotización por el canal.
+// Cada ronda usa los símbolos vigentes en ese momento; el canal se cierra cuando se cancela ctx
+func Subscribe(ctx context.Context, client QuoteFetcher, w *Watchlist, interval time.Duration) <-chan Quote {
+	updates := make(chan Quote)
+	go func() {
+		defer close(updates)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			for _, quote := range FetchQuotes(client, w.Symbols()) {
+				select {
+				case updates <- quote:
+				case <-ctx.Done():
+					return
+				}
+			}
+			select {
+			case <-ticker.C:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return updates
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+)
+
+// Los tipos y la matemática de los bonos viven en el paquete bolsa para que se puedan importar
+type (
+	CashFlow  = bolsa.CashFlow
+	Bond      = bolsa.Bond
+	BondQuote = bolsa.BondQuote
+)
+
+// BondHolding representa una tenencia de bonos en valor nominal
+type BondHolding struct {
+	Symbol  string  `json:"symbol"`
+	Nominal float64 `json:"nominal"`
+}
+
+// Tenencias de bonos del usuario, cargadas desde bond_holdings.json
+var bondHoldings []BondHolding
+
+// loadBonds devuelve el catálogo embebido más los bonos definidos por el usuario en bonds.json
+func loadBonds() ([]*Bond, error) {
+	bonds := bolsa.DefaultBonds()
+
+	path, err := appFile("bonds.json")
+	if err != nil {
+		return bonds, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return bonds, nil
+	}
+	if err != nil {
+		return bonds, err
+	}
+
+	var custom []*Bond
+	if err := json.Unmarshal(data, &custom); err != nil {
+		return bonds, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+
+	// Los bonos del usuario reemplazan a los embebidos con la misma especie
+	for _, bond := range custom {
+		if bond.Frequency == 0 {
+			bond.Frequency = 2
+		}
+		sort.Slice(bond.CashFlows, func(i, j int) bool {
+			return bond.CashFlows[i].Date.Before(bond.CashFlows[j].Date)
+		})
+
+		replaced := false
+		for i, existing := range bonds {
+			if existing.Symbol == bond.Symbol {
+				bonds[i] = bond
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			bonds = append(bonds, bond)
+		}
+	}
+
+	return bonds, nil
+}
+
+// loadBondHoldings lee las tenencias de bonos del usuario desde bond_holdings.json
+func loadBondHoldings() ([]BondHolding, error) {
+	path, err := appFile("bond_holdings.json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var holdings []BondHolding
+	if err := json.Unmarshal(data, &holdings); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return holdings, nil
+}
+
+// findBond busca un bono del catálogo por especie o símbolo de cotización
+func findBond(bonds []*Bond, symbol string) *Bond {
+	return bolsa.FindBond(bonds, symbol)
+}
+
+// getBondData obtiene el precio de cada bono del catálogo y lo valúa a la fecha actual
+func getBondData(bonds []*Bond, client QuoteFetcher, errs *fetchErrors) []BondQuote {
+	var quotes []BondQuote
+	now := time.Now().UTC()
+
+	for _, bond := range bonds {
+		price, _, _, _, err := getTickerData(bond.QuoteSymbol, client)
+		if err != nil {
+			errs.add("Bonos", bond.Symbol, err)
+			continue
+		}
+		if bond.Residual(now) == 0 {
+			continue
+		}
+		quotes = append(quotes, bond.Quote(price, now))
+	}
+
+	return quotes
+}
+
+// displayBonds muestra la valuación de los bonos con su valor técnico y paridad
+func displayBonds(quotes []BondQuote) {
+	if len(quotes) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s=== BONOS (cada 100 VN) ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-8s%10s%10s%10s%10s%10s%10s\n", "Especie", "Limpio", "Sucio", "Residual", "Int.Corr", "V.Técnico", "Paridad")
+	for _, q := range quotes {
+		fmt.Printf("%s%-8s%s%10.2f%10.2f%10.2f%10.4f%10.4f%9.2f%%\n",
+			Yellow, q.Bond.Symbol, Reset,
+			q.CleanPrice, q.DirtyPrice, q.Residual, q.AccruedInterest, q.TechnicalValue, q.Parity)
+	}
+
+	if len(bondHoldings) == 0 {
+		return
+	}
+
+	// Valuación de las tenencias al precio sucio
+	fmt.Printf("\n%sTenencias de bonos%s\n", Yellow, Reset)
+	total := make(map[string]float64)
+	for _, holding := range bondHoldings {
+		for _, q := range quotes {
+			if q.Bond.Symbol != holding.Symbol {
+				continue
+			}
+			value := q.Value(holding.Nominal)
+			total[q.Bond.Currency] += value
+			fmt.Printf("%-8s%12.0f VN  %s %.2f\n", holding.Symbol, holding.Nominal, q.Bond.Currency, value)
+		}
+	}
+	for currency, value := range total {
+		fmt.Printf("%sTotal %s: %.2f%s\n", White, currency, value, Reset)
+	}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Fracción del presupuesto diario a partir de la cual solo se consultan los símbolos prioritarios
+const budgetReserve = 0.8
+
+// budgetWarned registra el último día en que se avisó que un proveedor entró en la reserva
+var (
+	budgetWarnMu sync.Mutex
+	budgetWarned = make(map[string]string)
+)
+
+// budgetDay es el día (hora de Buenos Aires) al que se imputan los requests
+func budgetDay(t time.Time) string {
+	return t.In(argentinaLocation).Format("2006-01-02")
+}
+
+// budgetUsage devuelve los requests de hoy a un proveedor y su presupuesto diario (0 = sin límite)
+func budgetUsage(provider string) (used, budget int64) {
+	budget = int64(appConfig().provider(provider).DailyBudget)
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if p, ok := stats.Providers[provider]; ok && p.Day == budgetDay(time.Now()) {
+		used = p.DayRequests
+	}
+	return used, budget
+}
+
+// budgetExhausted indica si el proveedor ya consumió todo su presupuesto del día
+func budgetExhausted(provider string) bool {
+	used, budget := budgetUsage(provider)
+	return budget > 0 && used >= budget
+}
+
+// budgetTight indica si el proveedor entró en la reserva del presupuesto: solo quedan requests para lo prioritario
+func budgetTight(provider string) bool {
+	used, budget := budgetUsage(provider)
+	if budget <= 0 || float64(used) < float64(budget)*budgetReserve {
+		return false
+	}
+
+	budgetWarnMu.Lock()
+	defer budgetWarnMu.Unlock()
+	if today := budgetDay(time.Now()); budgetWarned[provider] != today {
+		budgetWarned[provider] = today
+		fmt.Printf("%s⚠️ %s consumió %d de %d requests de hoy: se consultan solo los símbolos prioritarios%s\n",
+			Yellow, provider, used, budget, Reset)
+	}
+	return true
+}
+
+// budgetError es el error de una consulta omitida para no exceder el presupuesto
+func budgetError(provider string) error {
+	used, budget := budgetUsage(provider)
+	return &QuoteError{Kind: ErrRateLimited, Err: fmt.Errorf("presupuesto diario de %s: %d de %d requests usados", provider, used, budget)}
+}
+
+// prioritySymbols devuelve los símbolos que se siguen consultando con el presupuesto en reserva:
+// los fijados y las tenencias de la cartera
+func prioritySymbols() map[string]bool {
+	priority := make(map[string]bool)
+	pinsMu.Lock()
+	loadPins()
+	for _, symbol := range pinned {
+		priority[symbol] = true
+	}
+	pinsMu.Unlock()
+
+	if portfolio, err := loadPortfolio(); err == nil {
+		for _, holding := range portfolio.Holdings {
+			priority[holding.Symbol] = true
+		}
+	}
+	return priority
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Paneles de la API abierta de BYMA (datos con demora, sin credenciales); cada uno devuelve todos sus papeles
+const bymaBaseURL = "https://open.bymadata.com.ar/vanoms-be-core/rest/api/bymadata/free"
+
+var bymaPanels = []string{"leading-equity", "general-equity", "cedears"}
+
+// bymaPanelQuote es una fila de un panel de BYMA
+type bymaPanelQuote struct {
+	Symbol        string    `json:"symbol"`
+	Trade         FlexFloat `json:"trade"`
+	PreviousClose FlexFloat `json:"previousClosingPrice"`
+	Volume        FlexFloat `json:"volume"`
+	Description   string    `json:"securityDesc"`
+}
+
+// Los paneles se descargan una vez por intervalo y se comparten entre todos los símbolos
+var (
+	bymaMu        sync.Mutex
+	bymaQuotes    map[string]bymaPanelQuote
+	bymaFetchedAt time.Time
+	bymaErr       error
+)
+
+// fetchBymaPanel descarga un panel de BYMA; la API acepta solo POST con los plazos de liquidación a incluir
+func fetchBymaPanel(panel string, client *HTTPClient) ([]bymaPanelQuote, error) {
+	payload := []byte(`{"excludeZeroPxAndQty":true,"T0":false,"T1":true,"T2":false,"Content-Type":"application/json"}`)
+	req, err := http.NewRequest(http.MethodPost, bymaBaseURL+"/"+panel, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.doer.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "para el panel %s de BYMA", panel)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// Según la versión de la API, el panel viene como lista o dentro de "data"
+	var wrapped struct {
+		Data []bymaPanelQuote `json:"data"`
+	}
+	if err := json.Unmarshal(body, &wrapped); err == nil && wrapped.Data != nil {
+		return wrapped.Data, nil
+	}
+	var quotes []bymaPanelQuote
+	if err := json.Unmarshal(body, &quotes); err != nil {
+		return nil, schemaError("byma", panel, body, err.Error())
+	}
+	return quotes, nil
+}
+
+// bymaPanelSnapshot devuelve los papeles de todos los paneles, descargándolos si pasó el intervalo
+func bymaPanelSnapshot() (map[string]bymaPanelQuote, error) {
+	bymaMu.Lock()
+	defer bymaMu.Unlock()
+	if !bymaFetchedAt.IsZero() && time.Since(bymaFetchedAt) < time.Duration(appConfig().Interval) {
+		return bymaQuotes, bymaErr
+	}
+
+	client := NewProviderClient("byma")
+	quotes := make(map[string]bymaPanelQuote)
+	var errs []string
+	for _, panel := range bymaPanels {
+		rows, err := fetchBymaPanel(panel, client)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", panel, err))
+			continue
+		}
+		for _, q := range rows {
+			// Un papel puede aparecer en más de un plazo: vale el primero con precio
+			symbol := strings.ToUpper(strings.TrimSpace(q.Symbol)) + ".BA"
+			if _, ok := quotes[symbol]; !ok && q.Trade.Value > 0 {
+				quotes[symbol] = q
+			}
+		}
+	}
+	bymaQuotes, bymaFetchedAt, bymaErr = quotes, time.Now(), nil
+	if len(quotes) == 0 && len(errs) > 0 {
+		bymaErr = fmt.Errorf("BYMA: %s", strings.Join(errs, "; "))
+	}
+	return bymaQuotes, bymaErr
+}
+
+// bymaQuote busca la cotización de un papel de BYMA en los paneles de la API abierta
+func bymaQuote(symbol string, market Market) tickerResult {
+	if market != MarketBYMA {
+		return tickerResult{err: fmt.Errorf("BYMA solo cotiza papeles locales")}
+	}
+	return tickerCache.Do("byma:"+symbol, func() tickerResult {
+		quotes, err := bymaPanelSnapshot()
+		if err != nil {
+			return tickerResult{err: err}
+		}
+		q, ok := quotes[symbol]
+		if !ok {
+			return tickerResult{err: symbolError(symbol, fmt.Errorf("%s no figura en los paneles de BYMA", symbol))}
+		}
+		return tickerResult{
+			price:         q.Trade.Value,
+			previousClose: q.PreviousClose.Value,
+			volume:        q.Volume.Int(),
+			name:          strings.TrimSpace(q.Description),
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"sync"
+)
+
+// tickerResult representa el resultado de consultar un ticker, para compartirlo entre llamadas
+type tickerResult struct {
+	price         float64
+	previousClose float64
+	name          string
+	volume        int64
+	err           error
+}
+
+// tickerCall es una consulta en curso o terminada dentro del ciclo actual
+type tickerCall struct {
+	wg     sync.WaitGroup
+	result tickerResult
+}
+
+// TickerCache deduplica las consultas por símbolo dentro de un ciclo de actualización:
+// las llamadas concurrentes por el mismo símbolo esperan a la primera (singleflight)
+// y las posteriores reutilizan su resultado hasta que se reinicia el ciclo
+type TickerCache struct {
+	mu    sync.Mutex
+	calls map[string]*tickerCall
+	hits  int
+}
+
+// tickerCache es la caché compartida por todas las secciones del ciclo
+var tickerCache = NewTickerCache()
+
+// NewTickerCache crea una caché vacía
+func NewTickerCache() *TickerCache {
+	return &TickerCache{calls: make(map[string]*tickerCall)}
+}
+
+// Reset descarta los resultados del ciclo anterior y devuelve cuántas consultas se ahorraron
+func (c *TickerCache) Reset() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	hits := c.hits
+	c.calls = make(map[string]*tickerCall)
+	c.hits = 0
+	return hits
+}
+
+// Do ejecuta fetch una sola vez por símbolo en el ciclo y comparte el resultado
+func (c *TickerCache) Do(symbol string, fetch func() tickerResult) tickerResult {
+	c.mu.Lock()
+	if call, ok := c.calls[symbol]; ok {
+		c.hits++
+		c.mu.Unlock()
+		call.wg.Wait()
+		return call.result
+	}
+
+	call := &tickerCall{}
+	call.wg.Add(1)
+	c.calls[symbol] = call
+	c.mu.Unlock()
+
+	call.result = fetch()
+	call.wg.Done()
+
+	// Los errores no se guardan: la próxima sección puede reintentar
+	if call.result.err != nil {
+		c.mu.Lock()
+		if c.calls[symbol] == call {
+			delete(c.calls, symbol)
+		}
+		c.mu.Unlock()
+	}
+	return call.result
+}
@@ -0,0 +1,276 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MarketEvent es un evento de mercado para exportar al calendario
+type MarketEvent struct {
+	Date        time.Time `json:"date"`
+	Kind        string    `json:"kind"` // dividendo, balance, pago de bono, licitación...
+	Symbol      string    `json:"symbol"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+}
+
+// loadUserEvents lee eventos cargados a mano en events.json (por ejemplo licitaciones del Tesoro)
+func loadUserEvents() ([]MarketEvent, error) {
+	path, err := appFile("events.json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var events []MarketEvent
+	if err := json.Unmarshal(data, &events); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return events, nil
+}
+
+// bondEvents devuelve los pagos de renta y amortización futuros de los bonos
+func bondEvents(bonds []*Bond, from time.Time) []MarketEvent {
+	var events []MarketEvent
+	for _, bond := range bonds {
+		for _, cf := range bond.CashFlows {
+			if cf.Date.Before(from) {
+				continue
+			}
+			var parts []string
+			if cf.CouponRate > 0 {
+				parts = append(parts, fmt.Sprintf("renta %.2f%% anual", cf.CouponRate*100))
+			}
+			if cf.Amortization > 0 {
+				parts = append(parts, fmt.Sprintf("amortización %.2f cada 100 VN", cf.Amortization))
+			}
+			events = append(events, MarketEvent{
+				Date:        cf.Date,
+				Kind:        "pago de bono",
+				Symbol:      bond.Symbol,
+				Title:       fmt.Sprintf("Pago %s", bond.Symbol),
+				Description: fmt.Sprintf("%s: %s", bond.Name, strings.Join(parts, ", ")),
+			})
+		}
+	}
+	return events
+}
+
+// corporateEvents consulta en Yahoo las próximas fechas de dividendos y balances de un símbolo
+func corporateEvents(symbol string, client QuoteFetcher) ([]MarketEvent, error) {
+	eventsURL := fmt.Sprintf("https://query2.finance.yahoo.com/v10/finance/quoteSummary/%s?modules=calendarEvents",
+		url.PathEscape(symbol))
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Accept":     "application/json",
+		"Referer":    "https://finance.yahoo.com/",
+	}
+
+	resp, err := client.GetWithRetry(eventsURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "para los eventos de %s", symbol)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var calendarResp struct {
+		QuoteSummary struct {
+			Result []struct {
+				CalendarEvents struct {
+					Earnings struct {
+						EarningsDate []FlexFloat `json:"earningsDate"`
+					} `json:"earnings"`
+					ExDividendDate FlexFloat `json:"exDividendDate"`
+					DividendDate   FlexFloat `json:"dividendDate"`
+				} `json:"calendarEvents"`
+			} `json:"result"`
+		} `json:"quoteSummary"`
+	}
+	if err := json.Unmarshal(body, &calendarResp); err != nil {
+		return nil, schemaError("calendarEvents", symbol, body, err.Error())
+	}
+	if len(calendarResp.QuoteSummary.Result) == 0 {
+		return nil, nil
+	}
+
+	cal := calendarResp.QuoteSummary.Result[0].CalendarEvents
+	var events []MarketEvent
+	if cal.ExDividendDate.Valid {
+		events = append(events, MarketEvent{
+			Date: time.Unix(cal.ExDividendDate.Int(), 0).UTC(), Kind: "dividendo", Symbol: symbol,
+			Title: fmt.Sprintf("%s ex-dividendo", symbol), Description: "Último día para comprar con derecho al dividendo: la rueda anterior",
+		})
+	}
+	if cal.DividendDate.Valid {
+		events = append(events, MarketEvent{
+			Date: time.Unix(cal.DividendDate.Int(), 0).UTC(), Kind: "dividendo", Symbol: symbol,
+			Title: fmt.Sprintf("%s pago de dividendo", symbol),
+		})
+	}
+	if len(cal.Earnings.EarningsDate) > 0 && cal.Earnings.EarningsDate[0].Valid {
+		events = append(events, MarketEvent{
+			Date: time.Unix(cal.Earnings.EarningsDate[0].Int(), 0).UTC(), Kind: "balance", Symbol: symbol,
+			Title: fmt.Sprintf("%s presenta balance", symbol),
+		})
+	}
+	return events, nil
+}
+
+// icsEscape escapa un texto para un campo de iCalendar (RFC 5545)
+func icsEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace(s)
+}
+
+// icsLine escribe una línea de iCalendar partiéndola cada 75 bytes como exige el estándar
+func icsLine(b *strings.Builder, line string) {
+	for len(line) > 75 {
+		cut := 75
+		// No partir un carácter UTF-8 al medio
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// writeICS genera el calendario con un recordatorio el día anterior a cada evento
+func writeICS(w io.Writer, events []MarketEvent) error {
+	var b strings.Builder
+	stamp := time.Now().UTC().Format("20060102T150405Z")
+
+	icsLine(&b, "BEGIN:VCALENDAR")
+	icsLine(&b, "VERSION:2.0")
+	icsLine(&b, "PRODID:-//bolsa-valores-argentina//eventos de mercado//ES")
+	icsLine(&b, "CALSCALE:GREGORIAN")
+	icsLine(&b, "X-WR-CALNAME:Eventos de mercado")
+	for _, e := range events {
+		day := e.Date.Format("20060102")
+		uid := strings.ToLower(strings.NewReplacer(" ", "-", ".", "-").Replace(fmt.Sprintf("%s-%s-%s", day, e.Kind, e.Symbol)))
+
+		icsLine(&b, "BEGIN:VEVENT")
+		icsLine(&b, "UID:"+uid+"@bolsa")
+		icsLine(&b, "DTSTAMP:"+stamp)
+		icsLine(&b, "DTSTART;VALUE=DATE:"+day)
+		icsLine(&b, "DTEND;VALUE=DATE:"+e.Date.AddDate(0, 0, 1).Format("20060102"))
+		icsLine(&b, "SUMMARY:"+icsEscape(e.Title))
+		if e.Description != "" {
+			icsLine(&b, "DESCRIPTION:"+icsEscape(e.Description))
+		}
+		icsLine(&b, "CATEGORIES:"+icsEscape(e.Kind))
+		icsLine(&b, "BEGIN:VALARM")
+		icsLine(&b, "ACTION:DISPLAY")
+		icsLine(&b, "DESCRIPTION:"+icsEscape(e.Title))
+		icsLine(&b, "TRIGGER:-PT12H")
+		icsLine(&b, "END:VALARM")
+		icsLine(&b, "END:VEVENT")
+	}
+	icsLine(&b, "END:VCALENDAR")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// runCalendar implementa `bolsa calendar -o eventos.ics`: dividendos, balances, pagos de bonos y
+// eventos propios, para importar o suscribirse desde Google Calendar u otra agenda
+func runCalendar(args []string) error {
+	fs := flag.NewFlagSet("calendar", flag.ExitOnError)
+	output := fs.String("o", "", "archivo .ics de salida (sin -o se listan los eventos)")
+	months := fs.Int("months", 12, "meses hacia adelante a incluir")
+	holdingsOnly := fs.Bool("holdings", false, "incluir solo los bonos de bond_holdings.json")
+	fs.Parse(args)
+
+	from := time.Now().Truncate(24 * time.Hour)
+	to := from.AddDate(0, *months, 0)
+
+	bonds, err := loadBonds()
+	if err != nil {
+		fmt.Printf("Error al cargar bonos personalizados: %v\n", err)
+	}
+	if *holdingsOnly {
+		holdings, err := loadBondHoldings()
+		if err != nil {
+			return err
+		}
+		var held []*Bond
+		for _, h := range holdings {
+			if bond := findBond(bonds, h.Symbol); bond != nil {
+				held = append(held, bond)
+			}
+		}
+		bonds = held
+	}
+	events := bondEvents(bonds, from)
+
+	client := NewHTTPClient()
+	for _, stock := range watchlistSnapshot() {
+		corporate, err := corporateEvents(stock.Symbol, client)
+		if err != nil {
+			fmt.Printf("%sNo se pudieron obtener los eventos de %s: %v%s\n", Yellow, stock.Symbol, err, Reset)
+			continue
+		}
+		events = append(events, corporate...)
+	}
+
+	userEvents, err := loadUserEvents()
+	if err != nil {
+		return err
+	}
+	events = append(events, userEvents...)
+
+	auctions, err := auctionEvents()
+	if err != nil {
+		return err
+	}
+	events = append(events, auctions...)
+
+	var upcoming []MarketEvent
+	for _, e := range events {
+		if !e.Date.Before(from) && e.Date.Before(to) {
+			upcoming = append(upcoming, e)
+		}
+	}
+	sort.Slice(upcoming, func(i, j int) bool { return upcoming[i].Date.Before(upcoming[j].Date) })
+
+	if *output == "" {
+		for _, e := range upcoming {
+			fmt.Printf("%s  %-14s %s\n", e.Date.Format("2006-01-02"), e.Kind, e.Title)
+		}
+		return nil
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := writeICS(file, upcoming); err != nil {
+		return err
+	}
+	fmt.Printf("%d eventos exportados a %s (importalo en Google Calendar desde Configuración > Importar)\n", len(upcoming), *output)
+	return nil
+}
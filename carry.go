@@ -0,0 +1,226 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CarryConfig define las patas del carry trade: la tasa de caución y los futuros de dólar a comparar
+type CarryConfig struct {
+	CaucionRate float64        `json:"caucionRate"` // TNA de la caución en %, si no se obtiene en vivo de IOL
+	Futures     []DollarFuture `json:"futures"`
+	futures     []parsedFuture // Vencimientos ya interpretados
+}
+
+// DollarFuture es un futuro de dólar (A3/Matba-Rofex) con su vencimiento; sin símbolo se usa el precio fijo
+type DollarFuture struct {
+	Name     string  `json:"name"`     // DLR/DIC26
+	Maturity string  `json:"maturity"` // Fecha de vencimiento (2026-12-31)
+	Symbol   string  `json:"symbol"`   // Símbolo a consultar en cada ciclo, si el proveedor lo publica
+	Price    float64 `json:"price"`    // Precio a usar si no hay símbolo o falla la consulta
+}
+
+type parsedFuture struct {
+	DollarFuture
+	maturity time.Time
+}
+
+// validate interpreta los vencimientos de los futuros configurados
+func (c *CarryConfig) validate() error {
+	c.futures = nil
+	for _, f := range c.Futures {
+		maturity, err := time.ParseInLocation("2006-01-02", f.Maturity, argentinaLocation)
+		if err != nil {
+			return fmt.Errorf("futuro %q: vencimiento inválido %q (usar AAAA-MM-DD)", f.Name, f.Maturity)
+		}
+		if f.Symbol == "" && f.Price <= 0 {
+			return fmt.Errorf("futuro %q: falta \"symbol\" o \"price\"", f.Name)
+		}
+		c.futures = append(c.futures, parsedFuture{DollarFuture: f, maturity: maturity})
+	}
+	return nil
+}
+
+// CarryQuote es el carry implícito hasta el vencimiento de un futuro
+type CarryQuote struct {
+	Future      string    `json:"future"`
+	Maturity    time.Time `json:"maturity"`
+	Days        int       `json:"days"`
+	Price       float64   `json:"price"`        // Precio del futuro
+	ImpliedRate float64   `json:"implied_rate"` // TNA implícita del futuro respecto del oficial, en %
+	CarryUSD    float64   `json:"carry_usd"`    // TNA en dólares de hacer caución cubierta con el futuro, en %
+	ImpliedMEP  float64   `json:"implied_mep"`  // MEP al vencimiento si la brecha se mantiene
+	RateSpread  float64   `json:"rate_spread"`  // Caución menos tasa implícita, en puntos
+	LivePrice   bool      `json:"live_price"`   // El precio se obtuvo en el ciclo (no es el fijo de config.json)
+}
+
+// CarryPanel combina caución, futuros y MEP en un ciclo
+type CarryPanel struct {
+	Official    float64      `json:"official"`
+	MEP         float64      `json:"mep"`
+	CaucionRate float64      `json:"caucion_rate"`
+	CaucionLive bool         `json:"caucion_live"`
+	Quotes      []CarryQuote `json:"quotes"`
+}
+
+// computeCarry calcula, para un futuro, la tasa implícita y el rendimiento en dólares de la caución cubierta:
+// se venden dólares al oficial, se colocan los pesos a la tasa de caución y se recompran al precio del futuro
+func computeCarry(official, mep, caucionRate, price float64, days int) (implied, carryUSD, impliedMEP float64) {
+	if official <= 0 || price <= 0 || days <= 0 {
+		return 0, 0, 0
+	}
+	years := float64(days) / 365
+	implied = (price/official - 1) / years * 100
+	pesos := official * (1 + caucionRate/100*years)
+	carryUSD = (pesos/price - 1) / years * 100
+	if mep > 0 {
+		impliedMEP = mep * price / official
+	}
+	return implied, carryUSD, impliedMEP
+}
+
+// fetchCaucionRate consulta en IOL la tasa de la caución en pesos más corta
+func fetchCaucionRate(cfg *IOLConfig) (float64, error) {
+	token, err := iol.accessToken(cfg)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := iol.client.GetWithRetry(iolBaseURL+"/api/v2/Cotizaciones/cauciones/argentina/Todos",
+		map[string]string{"Authorization": "Bearer " + token, "Accept": "application/json"})
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError(resp.StatusCode, "para las cauciones de IOL")
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, err
+	}
+
+	var panel struct {
+		Titulos []struct {
+			Simbolo      string    `json:"simbolo"`
+			Moneda       string    `json:"moneda"`
+			Plazo        FlexFloat `json:"plazo"`
+			UltimoPrecio FlexFloat `json:"ultimoPrecio"` // En cauciones, la TNA
+		} `json:"titulos"`
+	}
+	if err := json.Unmarshal(body, &panel); err != nil {
+		return 0, &QuoteError{Kind: ErrParse, Err: fmt.Errorf("respuesta inválida de las cauciones de IOL: %v", err)}
+	}
+
+	best, bestDays := 0.0, 0.0
+	for _, t := range panel.Titulos {
+		if strings.Contains(strings.ToLower(t.Moneda), "dolar") || !t.UltimoPrecio.Valid || t.UltimoPrecio.Value <= 0 {
+			continue
+		}
+		if bestDays == 0 || t.Plazo.Value < bestDays {
+			best, bestDays = t.UltimoPrecio.Value, t.Plazo.Value
+		}
+	}
+	if best == 0 {
+		return 0, &QuoteError{Kind: ErrParse, Err: fmt.Errorf("IOL no informa cauciones en pesos")}
+	}
+	return best, nil
+}
+
+// getCarryPanel arma el panel de carry del ciclo; sin futuros configurados no hace nada
+func getCarryPanel(forexData []ForexInfo, client QuoteFetcher, errs *fetchErrors) *CarryPanel {
+	cfg := appConfig().Carry
+	if cfg == nil || len(cfg.futures) == 0 {
+		return nil
+	}
+
+	panel := &CarryPanel{CaucionRate: cfg.CaucionRate}
+	for _, forex := range forexData {
+		if forex.Symbol == "ARS=X" {
+			panel.Official = forex.Price
+		}
+	}
+	if panel.Official == 0 {
+		errs.add("Carry", "ARS=X", fmt.Errorf("sin dólar oficial no se puede calcular el carry"))
+		return nil
+	}
+
+	mep, err := liveMEP(client)
+	if err != nil {
+		errs.add("Carry", "MEP", err)
+	}
+	panel.MEP = mep
+
+	if iolCfg := appConfig().IOL; iolCfg != nil && iolCfg.Username != "" {
+		if rate, err := fetchCaucionRate(iolCfg); err != nil {
+			errs.add("Carry", "caución", err)
+		} else {
+			panel.CaucionRate, panel.CaucionLive = rate, true
+		}
+	}
+
+	now := time.Now()
+	for _, f := range cfg.futures {
+		days := int(f.maturity.Sub(now).Hours() / 24)
+		if days <= 0 {
+			continue // Vencido: se omite hasta que se actualice config.json
+		}
+		price, live := f.Price, false
+		if f.Symbol != "" {
+			if p, _, _, _, err := getTickerData(f.Symbol, client); err != nil {
+				errs.add("Carry", f.Symbol, err)
+			} else {
+				price, live = p, true
+			}
+		}
+		if price <= 0 {
+			continue
+		}
+
+		implied, carryUSD, impliedMEP := computeCarry(panel.Official, panel.MEP, panel.CaucionRate, price, days)
+		panel.Quotes = append(panel.Quotes, CarryQuote{
+			Future: f.Name, Maturity: f.maturity, Days: days, Price: price,
+			ImpliedRate: implied, CarryUSD: carryUSD, ImpliedMEP: impliedMEP,
+			RateSpread: panel.CaucionRate - implied, LivePrice: live,
+		})
+	}
+	sort.Slice(panel.Quotes, func(i, j int) bool { return panel.Quotes[i].Days < panel.Quotes[j].Days })
+	return panel
+}
+
+// displayCarry muestra el panel de carry trade
+func displayCarry(panel *CarryPanel) {
+	if panel == nil || len(panel.Quotes) == 0 {
+		return
+	}
+
+	source := "config.json"
+	if panel.CaucionLive {
+		source = "IOL"
+	}
+	note := ""
+	for _, q := range panel.Quotes {
+		if !q.LivePrice {
+			note = "; * precio fijo de config.json"
+		}
+	}
+	fmt.Printf("\n%s=== CARRY TRADE (caución %.2f%% TNA de %s, oficial %.2f, MEP %.2f%s) ===%s\n\n",
+		Cyan, panel.CaucionRate, source, panel.Official, panel.MEP, note, Reset)
+	fmt.Printf("%-14s %6s %12s %10s %12s %10s %12s\n", "Futuro", "Días", "Precio", "TNA impl.", "Carry USD", "Dif. tasa", "MEP impl.")
+	for _, q := range panel.Quotes {
+		carryColor := Green
+		if q.CarryUSD < 0 {
+			carryColor = Red
+		}
+		fixed := ""
+		if !q.LivePrice {
+			fixed = "*"
+		}
+		fmt.Printf("%-14s %6d %11.2f%1s %9.2f%% %s%11.2f%%%s %+9.2f %12.2f\n",
+			q.Future, q.Days, q.Price, fixed, q.ImpliedRate, carryColor, q.CarryUSD, Reset, q.RateSpread, q.ImpliedMEP)
+	}
+}
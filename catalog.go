@@ -0,0 +1,176 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CatalogEntry representa un papel del catálogo embebido con su sector
+type CatalogEntry struct {
+	Symbol string
+	Market Market
+	Name   string
+	Sector string
+}
+
+// Catálogo embebido de papeles argentinos: ADRs en Nueva York y panel líder de BYMA (en pesos)
+var catalog = []CatalogEntry{
+	// ADRs
+	{"GGAL", MarketNYSE, "Grupo Financiero Galicia", "Bancos"},
+	{"BMA", MarketNYSE, "Banco Macro", "Bancos"},
+	{"BBAR", MarketNYSE, "BBVA Argentina", "Bancos"},
+	{"SUPV", MarketNYSE, "Grupo Supervielle", "Bancos"},
+	{"YPF", MarketNYSE, "YPF", "Energía"},
+	{"PAM", MarketNYSE, "Pampa Energía", "Energía"},
+	{"VIST", MarketNYSE, "Vista Energy", "Energía"},
+	{"EDN", MarketNYSE, "Edenor", "Utilities"},
+	{"CEPU", MarketNYSE, "Central Puerto", "Utilities"},
+	{"TGS", MarketNYSE, "Transportadora de Gas del Sur", "Utilities"},
+	{"TEO", MarketNYSE, "Telecom Argentina", "Telecomunicaciones"},
+	{"GLOB", MarketNYSE, "Globant", "Tecnología"},
+	{"MELI", MarketNYSE, "MercadoLibre", "Tecnología"},
+	{"DESP", MarketNYSE, "Despegar", "Tecnología"},
+	{"TS", MarketNYSE, "Tenaris", "Materiales"},
+	{"TX", MarketNYSE, "Ternium", "Materiales"},
+	{"LOMA", MarketNYSE, "Loma Negra", "Materiales"},
+	{"IRS", MarketNYSE, "IRSA", "Real Estate"},
+	{"CRESY", MarketNYSE, "Cresud", "Agro"},
+	{"BIOX", MarketNYSE, "Bioceres Crop Solutions", "Agro"},
+	{"CAAP", MarketNYSE, "Corporación América Airports", "Infraestructura"},
+
+	// Panel líder BYMA
+	{"GGAL.BA", MarketBYMA, "Grupo Financiero Galicia", "Bancos"},
+	{"BMA.BA", MarketBYMA, "Banco Macro", "Bancos"},
+	{"BBAR.BA", MarketBYMA, "BBVA Argentina", "Bancos"},
+	{"SUPV.BA", MarketBYMA, "Grupo Supervielle", "Bancos"},
+	{"VALO.BA", MarketBYMA, "Grupo Financiero Valores", "Bancos"},
+	{"BYMA.BA", MarketBYMA, "Bolsas y Mercados Argentinos", "Bancos"},
+	{"YPFD.BA", MarketBYMA, "YPF", "Energía"},
+	{"PAMP.BA", MarketBYMA, "Pampa Energía", "Energía"},
+	{"EDN.BA", MarketBYMA, "Edenor", "Utilities"},
+	{"CEPU.BA", MarketBYMA, "Central Puerto", "Utilities"},
+	{"TRAN.BA", MarketBYMA, "Transener", "Utilities"},
+	{"TGSU2.BA", MarketBYMA, "Transportadora de Gas del Sur", "Utilities"},
+	{"TGNO4.BA", MarketBYMA, "Transportadora de Gas del Norte", "Utilities"},
+	{"METR.BA", MarketBYMA, "Metrogas", "Utilities"},
+	{"TECO2.BA", MarketBYMA, "Telecom Argentina", "Telecomunicaciones"},
+	{"CVH.BA", MarketBYMA, "Cablevisión Holding", "Telecomunicaciones"},
+	{"TXAR.BA", MarketBYMA, "Ternium Argentina", "Materiales"},
+	{"ALUA.BA", MarketBYMA, "Aluar", "Materiales"},
+	{"LOMA.BA", MarketBYMA, "Loma Negra", "Materiales"},
+	{"IRSA.BA", MarketBYMA, "IRSA", "Real Estate"},
+	{"CRES.BA", MarketBYMA, "Cresud", "Agro"},
+	{"COME.BA", MarketBYMA, "Sociedad Comercial del Plata", "Holdings"},
+	{"MIRG.BA", MarketBYMA, "Mirgor", "Industria"},
+}
+
+// foldAccents normaliza texto para comparar sin distinguir mayúsculas ni tildes
+func foldAccents(s string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	return replacer.Replace(strings.ToLower(strings.TrimSpace(s)))
+}
+
+// catalogSectors devuelve los sectores del catálogo ordenados alfabéticamente
+func catalogSectors() []string {
+	seen := make(map[string]bool)
+	var sectors []string
+	for _, entry := range catalog {
+		if !seen[entry.Sector] {
+			seen[entry.Sector] = true
+			sectors = append(sectors, entry.Sector)
+		}
+	}
+	sort.Strings(sectors)
+	return sectors
+}
+
+// catalogBySector devuelve los papeles de un sector, opcionalmente filtrados por mercado
+func catalogBySector(sector string, market Market) []CatalogEntry {
+	var entries []CatalogEntry
+	for _, entry := range catalog {
+		if foldAccents(entry.Sector) != foldAccents(sector) {
+			continue
+		}
+		if market != MarketOther && entry.Market != market {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// catalogLookup busca un papel del catálogo por símbolo
+func catalogLookup(symbol string) (CatalogEntry, bool) {
+	for _, entry := range catalog {
+		if strings.EqualFold(entry.Symbol, symbol) {
+			return entry, true
+		}
+	}
+	return CatalogEntry{}, false
+}
+
+// addSectorToActiveWatchlist agrega en caliente todos los papeles de un sector a la watchlist del monitor
+func addSectorToActiveWatchlist(sector string, market Market) (int, error) {
+	entries := catalogBySector(sector, market)
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("sector desconocido %q (sectores: %s)", sector, strings.Join(catalogSectors(), ", "))
+	}
+
+	added := 0
+	for _, entry := range entries {
+		if addStock(entry.Symbol, entry.Market) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// runCatalog implementa `bolsa catalog`: lista sectores y papeles, y los agrega en bloque a una watchlist
+func runCatalog(args []string) error {
+	fs := flag.NewFlagSet("catalog", flag.ExitOnError)
+	sector := fs.String("sector", "", "sector a listar (bancos, energía, utilities...)")
+	marketName := fs.String("market", "", "filtrar por mercado (NYSE o BYMA)")
+	watchlist := fs.String("add-to", "", "agregar los papeles del sector a esta watchlist guardada")
+	fs.Parse(args)
+
+	market := MarketOther
+	if *marketName != "" {
+		parsed, err := ParseMarket(*marketName)
+		if err != nil {
+			return err
+		}
+		market = parsed
+	}
+
+	if *sector == "" {
+		fmt.Println("Sectores disponibles:")
+		for _, s := range catalogSectors() {
+			fmt.Printf("  %-20s %d papeles\n", s, len(catalogBySector(s, market)))
+		}
+		fmt.Println("\nUsá: bolsa catalog --sector energía [--market BYMA] [--add-to mi-lista]")
+		return nil
+	}
+
+	entries := catalogBySector(*sector, market)
+	if len(entries) == 0 {
+		return fmt.Errorf("sector desconocido %q (sectores: %s)", *sector, strings.Join(catalogSectors(), ", "))
+	}
+
+	fmt.Printf("%s%s%s\n", Cyan, entries[0].Sector, Reset)
+	for _, entry := range entries {
+		fmt.Printf("  %-10s %-6s %s\n", entry.Symbol, entry.Market, entry.Name)
+	}
+
+	if *watchlist == "" {
+		return nil
+	}
+
+	added, err := addToWatchlist(*watchlist, entries)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\n%d papeles agregados a la watchlist %q (iniciar con: bolsa --watchlist %s)\n", added, *watchlist, *watchlist)
+	return nil
+}
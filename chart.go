@@ -0,0 +1,243 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Símbolos para calcular el dólar MEP implícito en los gráficos de brecha
+const (
+	mepPesosSymbol  = "AL30.BA"
+	mepDollarSymbol = "AL30D.BA"
+)
+
+// priceChart arma el gráfico de la serie histórica de un símbolo; con un índice (cer o ipc) la serie va en pesos constantes
+func priceChart(symbol, rangeStr, interval, index string, client QuoteFetcher) (*Chart, error) {
+	points, err := getHistory(symbol, rangeStr, interval, client)
+	if err != nil {
+		return nil, err
+	}
+
+	title, yLabel := fmt.Sprintf("%s - %s", symbol, rangeStr), "Precio"
+	if index != "" && len(points) > 0 {
+		if !quotedInPesos(symbol) {
+			return nil, fmt.Errorf("%s no cotiza en pesos: --real solo ajusta series en pesos", symbol)
+		}
+		deflator, err := deflatorFor(index, points, NewProviderClient("bcra"))
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo obtener el %s: %v", strings.ToUpper(index), err)
+		}
+		yLabel = "Precio en " + deflator.Label(points)
+		title += " (" + deflator.Label(points) + ")"
+		points = deflator.Apply(points)
+	}
+
+	series := Series{Name: symbol}
+	for _, p := range points {
+		series.X = append(series.X, float64(p.Time.Unix()))
+		series.Y = append(series.Y, p.Close)
+	}
+
+	return &Chart{
+		Title:  title,
+		XLabel: "Fecha",
+		YLabel: yLabel,
+		Series: []Series{series},
+		XTime:  true,
+	}, nil
+}
+
+// gapChart arma el gráfico de la brecha entre el dólar MEP (AL30/AL30D) y el oficial
+func gapChart(rangeStr string, client QuoteFetcher) (*Chart, error) {
+	histories := make(map[string]map[string]float64)
+	for _, symbol := range []string{"ARS=X", mepPesosSymbol, mepDollarSymbol} {
+		points, err := getHistory(symbol, rangeStr, "1d", client)
+		if err != nil {
+			return nil, fmt.Errorf("error al obtener el histórico de %s: %v", symbol, err)
+		}
+		histories[symbol] = historyByDay(points)
+	}
+
+	var days []string
+	for day := range histories[mepPesosSymbol] {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	series := Series{Name: "Brecha MEP / oficial %"}
+	for _, day := range days {
+		official := histories["ARS=X"][day]
+		dollar := histories[mepDollarSymbol][day]
+		if official == 0 || dollar == 0 {
+			continue
+		}
+		mep := histories[mepPesosSymbol][day] / dollar
+		t, _ := time.Parse("2006-01-02", day)
+		series.X = append(series.X, float64(t.Unix()))
+		series.Y = append(series.Y, (mep/official-1)*100)
+	}
+
+	return &Chart{
+		Title:  "Brecha cambiaria - " + rangeStr,
+		XLabel: "Fecha",
+		YLabel: "Brecha %",
+		Series: []Series{series},
+		XTime:  true,
+	}, nil
+}
+
+// portfolioHistory valúa día a día las tenencias de bonos: suma de nominales por precio cada 100 VN
+func portfolioHistory(rangeStr string, client QuoteFetcher) ([]HistoryPoint, error) {
+	bonds, err := loadBonds()
+	if err != nil {
+		fmt.Printf("Error al cargar bonos personalizados: %v\n", err)
+	}
+	holdings, err := loadBondHoldings()
+	if err != nil {
+		return nil, err
+	}
+	if len(holdings) == 0 {
+		return nil, fmt.Errorf("no hay tenencias cargadas en bond_holdings.json")
+	}
+
+	var prices []map[string]float64
+	var nominals []float64
+	for _, holding := range holdings {
+		bond := findBond(bonds, holding.Symbol)
+		if bond == nil {
+			return nil, fmt.Errorf("bono desconocido en las tenencias: %s", holding.Symbol)
+		}
+		points, err := getHistory(bond.QuoteSymbol, rangeStr, "1d", client)
+		if err != nil {
+			return nil, fmt.Errorf("error al obtener el histórico de %s: %v", bond.Symbol, err)
+		}
+		prices = append(prices, historyByDay(points))
+		nominals = append(nominals, holding.Nominal/100)
+	}
+	return carriedForwardSum(prices, nominals), nil
+}
+
+// carriedForwardSum suma día a día los precios ponderados de varias series. Un día sin rueda en una especie
+// usa su último cierre en lugar de contarla en cero; los días anteriores al primer cierre de alguna especie
+// se omiten, porque la suma todavía no incluye toda la cartera
+func carriedForwardSum(prices []map[string]float64, weights []float64) []HistoryPoint {
+	seen := make(map[string]bool)
+	var days []string
+	for _, series := range prices {
+		for day := range series {
+			if !seen[day] {
+				seen[day] = true
+				days = append(days, day)
+			}
+		}
+	}
+	sort.Strings(days)
+
+	last := make([]float64, len(prices))
+	started := make([]bool, len(prices))
+	var points []HistoryPoint
+	for _, day := range days {
+		complete := true
+		for i, series := range prices {
+			if price, ok := series[day]; ok {
+				last[i], started[i] = price, true
+			}
+			complete = complete && started[i]
+		}
+		if !complete {
+			continue
+		}
+		total := 0.0
+		for i := range prices {
+			total += weights[i] * last[i]
+		}
+		t, _ := time.Parse("2006-01-02", day)
+		points = append(points, HistoryPoint{Time: t, Close: total})
+	}
+	return points
+}
+
+// portfolioChart arma el gráfico de la valuación histórica de las tenencias de bonos
+func portfolioChart(rangeStr string, client QuoteFetcher) (*Chart, error) {
+	points, err := portfolioHistory(rangeStr, client)
+	if err != nil {
+		return nil, err
+	}
+
+	series := Series{Name: "Cartera de bonos"}
+	for _, p := range points {
+		series.X = append(series.X, float64(p.Time.Unix()))
+		series.Y = append(series.Y, p.Close)
+	}
+
+	return &Chart{
+		Title:  "Performance de cartera - " + rangeStr,
+		XLabel: "Fecha",
+		YLabel: "Valuación",
+		Series: []Series{series},
+		XTime:  true,
+	}, nil
+}
+
+// runChart implementa `bolsa chart SIMBOLO --range 6mo -o archivo.png`
+// Además de símbolos acepta BRECHA (brecha cambiaria) y CARTERA (tenencias de bonos)
+func runChart(args []string) error {
+	fs := flag.NewFlagSet("chart", flag.ExitOnError)
+	rangeStr := fs.String("range", "6mo", "rango del histórico (1mo, 6mo, 1y, 5y...)")
+	interval := fs.String("interval", "1d", "intervalo de las velas (1d, 1wk...)")
+	output := fs.String("o", "", "archivo de salida .png o .svg (sin -o se grafica en la terminal)")
+	real := fs.Bool("real", false, "deflactar la serie en pesos y expresarla en pesos constantes de la última fecha")
+	index := fs.String("index", "cer", "índice para --real: cer (diario) o ipc (mensual)")
+
+	// Permitir el símbolo antes o después de las opciones
+	var symbol string
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		symbol, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if symbol == "" {
+		symbol = fs.Arg(0)
+	}
+	if symbol == "" {
+		return fmt.Errorf("uso: bolsa chart SIMBOLO|BRECHA|CARTERA [--range 6mo] [--real [--index cer|ipc]] [-o archivo.png]")
+	}
+	// La brecha es un porcentaje y la cartera de bonos mezcla especies en pesos y en dólares
+	deflate := ""
+	if *real {
+		if upper := strings.ToUpper(symbol); upper == "BRECHA" || upper == "CARTERA" {
+			return fmt.Errorf("--real solo se aplica a símbolos que cotizan en pesos")
+		}
+		deflate = *index
+	}
+
+	client := NewHTTPClient()
+
+	var chart *Chart
+	var err error
+	switch strings.ToUpper(symbol) {
+	case "BRECHA":
+		chart, err = gapChart(*rangeStr, client)
+	case "CARTERA":
+		chart, err = portfolioChart(*rangeStr, client)
+	default:
+		chart, err = priceChart(strings.ToUpper(symbol), *rangeStr, *interval, deflate, client)
+	}
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		chart.RenderASCII(os.Stdout, 70, 20)
+		return nil
+	}
+
+	if err := saveChart(chart, *output); err != nil {
+		return err
+	}
+	fmt.Printf("Gráfico exportado a %s\n", *output)
+	return nil
+}
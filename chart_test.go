@@ -0,0 +1,29 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCarriedForwardSum(t *testing.T) {
+	al30 := map[string]float64{"2026-03-02": 70, "2026-03-03": 71, "2026-03-05": 72}
+	gd30 := map[string]float64{"2026-03-03": 75, "2026-03-04": 76, "2026-03-05": 77}
+	points := carriedForwardSum([]map[string]float64{al30, gd30}, []float64{10, 20})
+
+	var days []string
+	var values []float64
+	for _, p := range points {
+		days = append(days, p.Time.Format("2006-01-02"))
+		values = append(values, p.Close)
+	}
+	// El 02/03 falta GD30 y se omite; el 04/03 falta AL30 y se usa su cierre del 03/03
+	wantDays := []string{"2026-03-03", "2026-03-04", "2026-03-05"}
+	wantValues := []float64{10*71 + 20*75, 10*71 + 20*76, 10*72 + 20*77}
+	if !reflect.DeepEqual(days, wantDays) || !reflect.DeepEqual(values, wantValues) {
+		t.Errorf("carriedForwardSum = %v %v, se esperaba %v %v", days, values, wantDays, wantValues)
+	}
+
+	if points := carriedForwardSum([]map[string]float64{al30, {}}, []float64{1, 1}); len(points) != 0 {
+		t.Errorf("una especie sin histórico no puede dar puntos: %v", points)
+	}
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Series intradiarias que acompañan el resumen de cierre
+var closeSummarySymbols = []struct {
+	Symbol string
+	Name   string
+}{
+	{"^MERV", "MERVAL"},
+	{"ARS=X", "Dólar Oficial"},
+	{"EURARS=X", "Euro"},
+}
+
+const closeSummaryFile = "last_close_summary"
+
+// intradayChart arma el gráfico de la variación intradiaria (% desde la apertura) del MERVAL y los tipos de cambio
+func intradayChart(client QuoteFetcher) (*Chart, error) {
+	chart := &Chart{
+		Title:      "Rueda del " + time.Now().In(argentinaLocation).Format("02/01/2006"),
+		XLabel:     "Hora",
+		YLabel:     "Variación %",
+		XTime:      true,
+		TimeLayout: "15:04",
+	}
+
+	for _, s := range closeSummarySymbols {
+		points, err := getHistory(s.Symbol, "1d", "5m", client)
+		if err != nil {
+			fmt.Printf("Error al obtener el intradiario de %s: %v\n", s.Symbol, err)
+			continue
+		}
+		if len(points) == 0 || points[0].Close == 0 {
+			continue
+		}
+
+		// Normalizamos a variación porcentual para comparar series de escalas distintas
+		base := points[0].Close
+		series := Series{Name: s.Name}
+		for _, p := range points {
+			series.X = append(series.X, float64(p.Time.Unix()))
+			series.Y = append(series.Y, (p.Close/base-1)*100)
+		}
+		chart.Series = append(chart.Series, series)
+	}
+
+	if len(chart.Series) == 0 {
+		return nil, fmt.Errorf("no se pudo obtener ninguna serie intradiaria")
+	}
+	return chart, nil
+}
+
+// closeSummaryText arma el texto del resumen de cierre con los tipos de cambio y los mayores movimientos
+func closeSummaryText(forexData []ForexInfo, stocksData []StockInfo) string {
+	var b strings.Builder
+
+	b.WriteString("Tipos de cambio:\n")
+	for _, forex := range forexData {
+		fmt.Fprintf(&b, "  %s: $%.2f (%+.2f%%)\n", forex.Name, forex.Price, forex.ChangePercent)
+	}
+
+	gainers, losers := topMovers(stocksData, 3)
+	b.WriteString("\nMayores subas:\n")
+	for _, stock := range gainers {
+		fmt.Fprintf(&b, "  %s %+.2f%%\n", stock.Symbol, stock.ChangePercent)
+	}
+	b.WriteString("\nMayores bajas:\n")
+	for _, stock := range losers {
+		fmt.Fprintf(&b, "  %s %+.2f%%\n", stock.Symbol, stock.ChangePercent)
+	}
+
+	return b.String()
+}
+
+// closeSummaryDue indica si corresponde enviar el resumen de hoy (BYMA cerró y no se envió todavía)
+func closeSummaryDue(now time.Time) bool {
+	if !bymaHours.IsClosedForDay(now) {
+		return false
+	}
+
+	path, err := appFile(closeSummaryFile)
+	if err != nil {
+		return false
+	}
+	last, _ := os.ReadFile(path)
+	return strings.TrimSpace(string(last)) != now.In(argentinaLocation).Format("2006-01-02")
+}
+
+// markCloseSummarySent registra que el resumen de hoy ya se envió, para no repetirlo tras un reinicio
+func markCloseSummarySent(now time.Time) {
+	path, err := appFile(closeSummaryFile)
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(path, []byte(now.In(argentinaLocation).Format("2006-01-02")), 0o644); err != nil {
+		fmt.Printf("Error al registrar el envío del resumen de cierre: %v\n", err)
+	}
+}
+
+// maybeSendCloseSummary envía una vez por día, tras el cierre de BYMA, el resumen con el gráfico intradiario
+func maybeSendCloseSummary(snapshot *Snapshot, notifiers []Notifier, client QuoteFetcher) {
+	now := time.Now()
+	if !closeSummaryDue(now) {
+		return
+	}
+
+	title := "Resumen de cierre " + now.In(argentinaLocation).Format("02/01/2006")
+	text := closeSummaryText(snapshot.Forex, snapshot.Stocks) + resolveTodayPredictions(snapshot, now)
+	notification := Notification{
+		Title: title,
+		Text: renderMessage(TemplateCloseSummary, CloseSummaryMessage{
+			Title:    title,
+			Date:     now.In(argentinaLocation).Format("2006-01-02"),
+			Snapshot: snapshot,
+			Default:  text,
+		}, text),
+	}
+
+	chart, err := intradayChart(client)
+	if err != nil {
+		fmt.Printf("No se pudo generar el gráfico del día: %v\n", err)
+	} else {
+		var img bytes.Buffer
+		if err := chart.RenderPNG(&img, 900, 500); err != nil {
+			fmt.Printf("No se pudo generar el gráfico del día: %v\n", err)
+		} else {
+			notification.Attachments = append(notification.Attachments, Attachment{
+				Name: "cierre-" + now.In(argentinaLocation).Format("20060102") + ".png",
+				Data: img.Bytes(),
+			})
+		}
+	}
+
+	// Si fallaron todos los canales no se marca como enviado: se reintenta en el próximo ciclo
+	if deliveries := notifyAll(notifiers, notification); len(deliveries) > 0 && !anyDelivered(deliveries) {
+		fmt.Printf("%sNo se pudo enviar el resumen de cierre por ningún canal; se reintenta en el próximo ciclo%s\n", Yellow, Reset)
+		return
+	}
+	markCloseSummarySent(now)
+
+	runHooks(EventMarketClose, struct {
+		Market string      `json:"market"`
+		Date   string      `json:"date"`
+		Text   string      `json:"text"`
+		Forex  []ForexInfo `json:"forex"`
+		Stocks []StockInfo `json:"stocks"`
+	}{bymaHours.Name, now.In(argentinaLocation).Format("2006-01-02"), notification.Text, snapshot.Forex, snapshot.Stocks})
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// Command representa un subcomando de la línea de comandos (bolsa <comando> ...)
+type Command struct {
+	Name        string
+	Description string
+	Run         func(args []string) error
+}
+
+// Subcomandos disponibles; sin subcomando el programa corre el monitor en vivo
+var commands = []Command{
+	{Name: "alerts", Description: "Historial y gestión de alertas (history, list, ack, snooze, disable, enable)", Run: runAlerts},
+	{Name: "announcements", Description: "Últimos hechos relevantes (CNV/BYMA) que mencionan papeles de la watchlist", Run: runAnnouncements},
+	{Name: "attach", Description: "Conectar la terminal a un servidor `bolsa serve` y mostrar una vista", Run: runAttach},
+	{Name: "auctions", Description: "Licitaciones del Tesoro en pesos: fechas, instrumentos y tasas de corte (list, sync, add, result)", Run: runAuctions},
+	{Name: "backup", Description: "Empaquetar config, históricos, carteras y estado de alertas en un .tar.gz", Run: runBackup},
+	{Name: "calendar", Description: "Exportar dividendos, balances y pagos de bonos a un archivo .ics", Run: runCalendar},
+	{Name: "catalog", Description: "Catálogo de papeles por sector y alta en bloque a una watchlist", Run: runCatalog},
+	{Name: "chart", Description: "Gráfico histórico de un símbolo, la brecha o la cartera (terminal, PNG o SVG)", Run: runChart},
+	{Name: "config", Description: "Validar config.json y los archivos de datos (config check)", Run: runConfig},
+	{Name: "curve", Description: "Curva de rendimientos de bonos (TIR vs. duration) en ASCII, PNG o SVG", Run: runCurve},
+	{Name: "disabled", Description: "Símbolos desactivados por la política de errores de Yahoo (list, enable, clear)", Run: runDisabled},
+	{Name: "dividends", Description: "Proyección anual de dividendos de la cartera en USD y pesos, con calendario de cobros", Run: runDividends},
+	{Name: "doctor", Description: "Diagnóstico de DNS, proveedores, crumb de Yahoo, reloj y permisos de escritura", Run: runDoctor},
+	{Name: "drawdown", Description: "Drawdown actual y máximo de cada activo y de la cartera de bonos", Run: runDrawdown},
+	{Name: "dump", Description: "Descarga masiva de históricos a CSV para investigación, reanudable y verificada", Run: runDump},
+	{Name: "export", Description: "Exportar los snapshots de una rueda a JSON o CSV, con hash y firma opcional", Run: runExport},
+	{Name: "holidays", Description: "Feriados y días sin rueda de BYMA y NYSE; update descarga los de Argentina", Run: runHolidays},
+	{Name: "hourly", Description: "En qué franja horaria se mueve más cada símbolo, con el intradiario acumulado", Run: runHourly},
+	{Name: "init", Description: "Asistente interactivo que genera el config.json inicial", Run: runInit},
+	{Name: "names", Description: "Nombres y descripciones localizados de las empresas (list, set, unset)", Run: runNames},
+	{Name: "networth", Description: "Evolución patrimonial mensual de la cartera en pesos, dólares MEP y pesos constantes", Run: runNetWorth},
+	{Name: "news", Description: "Titulares recientes con las palabras clave configuradas, por papel o macro", Run: runNews},
+	{Name: "official", Description: "Aperturas y cierres oficiales registrados por día de un símbolo", Run: runOfficial},
+	{Name: "portfolio", Description: "Composición de la cartera por clase, sector, moneda y tenencia en torta ASCII, con concentraciones", Run: runPortfolio},
+	{Name: "predict", Description: "Registrar predicciones de cierre y ver su hit rate (add, list, resolve, stats)", Run: runPredict},
+	{Name: "publish", Description: "Publicar la tabla del día como página HTML estática (directorio, S3 o GitHub Pages)", Run: runPublish},
+	{Name: "quote", Description: "Cotizar símbolos por argumento o desde stdin, en tabla, JSON o CSV", Run: runQuote},
+	{Name: "rates", Description: "Tasa de plazo fijo, CER y UVA, y comparación de plazo fijo tradicional, UVA y dólar", Run: runRates},
+	{Name: "rebalance", Description: "Órdenes sugeridas para llevar la cartera a sus pesos objetivo", Run: runRebalance},
+	{Name: "replay", Description: "Reproducir una rueda pasada a partir de los snapshots intradiarios guardados", Run: runReplay},
+	{Name: "restore", Description: "Restaurar un backup de bolsa backup en esta máquina", Run: runRestore},
+	{Name: "risk", Description: "Score de riesgo por activo (volatilidad, liquidez, drawdown) y concentración de la cartera", Run: runRisk},
+	{Name: "screen", Description: "Filtrar todos los símbolos conocidos con una condición del DSL de alertas", Run: runScreen},
+	{Name: "secrets", Description: "Secretos cifrados con passphrase o en el keyring, para referenciar desde config.json", Run: runSecrets},
+	{Name: "self-update", Description: "Descargar el binario del último release para esta plataforma y reemplazar el actual", Run: runSelfUpdate},
+	{Name: "serve", Description: "Hacer el fetch en un único proceso y servirlo a varias terminales", Run: runServe},
+	{Name: "short", Description: "Posiciones cortas (short interest de FINRA) de los ADRs y presión bajista", Run: runShort},
+	{Name: "social", Description: "Ver o publicar en Mastodon o X el resumen de cierre (dólares, MERVAL y top movers)", Run: runSocial},
+	{Name: "sources", Description: "Comparar el precio de cada papel según Yahoo, IOL y BYMA, con el desvío entre fuentes", Run: runSources},
+	{Name: "stats", Description: "Uptime, ciclos y uso de proveedores del daemon, acumulados entre reinicios", Run: runStats},
+	{Name: "users", Description: "Usuarios del modo servidor con API key propia (list, add, remove, rotate)", Run: runUsers},
+	{Name: "verify", Description: "Verificar el hash y la firma de un archivo exportado", Run: runVerify},
+	{Name: "vs", Description: "Comparar el retorno de un papel contra plazo fijo y dólar MEP", Run: runVs},
+}
+
+// runCommand ejecuta el subcomando indicado y devuelve el código de salida del proceso
+func runCommand(name string, args []string) int {
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return 0
+	}
+
+	for _, cmd := range commands {
+		if cmd.Name != name {
+			continue
+		}
+		if err := cmd.Run(args); err != nil {
+			fmt.Fprintf(os.Stderr, "%sError: %v%s\n", Red, err, Reset)
+			return 1
+		}
+		return 0
+	}
+
+	fmt.Fprintf(os.Stderr, "Comando desconocido: %s\n\n", name)
+	printUsage()
+	return 2
+}
+
+// printUsage muestra los subcomandos disponibles
+func printUsage() {
+	fmt.Printf("bolsa %s\n\n", version)
+	fmt.Println("Uso: bolsa [comando] [opciones]")
+	fmt.Println("\nSin comando se inicia el monitor en vivo del mercado.")
+	fmt.Println("\nComandos:")
+	for _, cmd := range commands {
+		fmt.Printf("  %-14s %s\n", cmd.Name, cmd.Description)
+	}
+}
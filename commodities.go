@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// Kilos por bushel de cada grano de CBOT, para pasar de centavos por bushel a dólares por tonelada
+const (
+	bushelKgSoy   = 27.2155
+	bushelKgCorn  = 25.4012
+	bushelKgWheat = 27.2155
+)
+
+// CommoditySpec es una commodity a seguir. Los granos cotizan en centavos de dólar por bushel en CBOT:
+// con BushelKg se pasan a dólares por tonelada, y con Basis y ExportTax al FOB y al precio local teórico
+type CommoditySpec struct {
+	Symbol    string  `json:"symbol"`    // Símbolo de Yahoo (ZS=F, BZ=F...)
+	Name      string  `json:"name"`      // Nombre a mostrar
+	Unit      string  `json:"unit"`      // Unidad de la cotización, para la pantalla
+	BushelKg  float64 `json:"bushelKg"`  // Kilos por bushel; 0 si no es un grano de CBOT
+	Basis     float64 `json:"basis"`     // Diferencia del FOB Up-River contra CBOT, en USD/t (suele ser negativa)
+	ExportTax float64 `json:"exportTax"` // Derechos de exportación en %, para el precio local teórico en pesos
+}
+
+// defaultCommodities son las que mueven a las agroexportadoras, a YPF y a la macro. Los derechos de exportación
+// y las primas FOB cambian seguido: conviene ajustarlos en config.json
+var defaultCommodities = []CommoditySpec{
+	{Symbol: "ZS=F", Name: "Soja", Unit: "¢/bu", BushelKg: bushelKgSoy, ExportTax: 26},
+	{Symbol: "ZC=F", Name: "Maíz", Unit: "¢/bu", BushelKg: bushelKgCorn, ExportTax: 9.5},
+	{Symbol: "ZW=F", Name: "Trigo", Unit: "¢/bu", BushelKg: bushelKgWheat, ExportTax: 9.5},
+	{Symbol: "BZ=F", Name: "Brent", Unit: "USD/bbl"},
+	{Symbol: "CL=F", Name: "WTI", Unit: "USD/bbl"},
+	{Symbol: "GC=F", Name: "Oro", Unit: "USD/oz"},
+	// El litio no tiene un futuro líquido en Yahoo: se sigue el ETF de productores
+	{Symbol: "LIT", Name: "Litio (ETF)", Unit: "USD"},
+}
+
+// CommodityQuote es la cotización de una commodity con sus conversiones
+type CommodityQuote struct {
+	Symbol        string  `json:"symbol"`
+	Name          string  `json:"name"`
+	Unit          string  `json:"unit"`
+	Price         float64 `json:"price"`
+	ChangePercent float64 `json:"change_percent"`
+	PerTon        float64 `json:"per_ton,omitempty"` // USD/t en CBOT, solo granos
+	FOB           float64 `json:"fob,omitempty"`     // USD/t con la prima local
+	Local         float64 `json:"local,omitempty"`   // Pesos por tonelada: FOB neto de derechos al oficial
+}
+
+// commoditySpecs devuelve las commodities configuradas, o las de siempre si config.json no define ninguna
+func commoditySpecs() []CommoditySpec {
+	cfg := appConfig()
+	if cfg.DisableCommodities {
+		return nil
+	}
+	if len(cfg.Commodities) > 0 {
+		return cfg.Commodities
+	}
+	return defaultCommodities
+}
+
+// quoteCommodity arma la cotización con las conversiones a tonelada, FOB y pesos
+func quoteCommodity(spec CommoditySpec, price, previousClose, official float64) CommodityQuote {
+	_, changePercent := priceChange(price, previousClose)
+	q := CommodityQuote{Symbol: spec.Symbol, Name: spec.Name, Unit: spec.Unit, Price: price, ChangePercent: changePercent}
+	if spec.BushelKg > 0 {
+		q.PerTon = price / 100 * 1000 / spec.BushelKg
+		q.FOB = q.PerTon + spec.Basis
+		if official > 0 {
+			q.Local = q.FOB * (1 - spec.ExportTax/100) * official
+		}
+	}
+	return q
+}
+
+// getCommodities consulta las commodities del ciclo; las que fallan se registran en errs
+func getCommodities(ctx context.Context, forexData []ForexInfo, client QuoteFetcher, errs *fetchErrors) []CommodityQuote {
+	specs := commoditySpecs()
+	var official float64
+	for _, forex := range forexData {
+		if forex.Symbol == "ARS=X" {
+			official = forex.Price
+		}
+	}
+
+	results := make([]*CommodityQuote, len(specs))
+	group := newWorkGroup(ctx, fetchConcurrency)
+	for i, spec := range specs {
+		i, spec := i, spec
+		group.Go(func(ctx context.Context) error {
+			price, previousClose, _, _, err := getTickerData(spec.Symbol, client)
+			if err != nil {
+				errs.add("Commodities", spec.Symbol, err)
+				return err
+			}
+			q := quoteCommodity(spec, price, previousClose, official)
+			results[i] = &q
+			return nil
+		})
+	}
+	group.Wait()
+
+	var quotes []CommodityQuote
+	for _, q := range results {
+		if q != nil {
+			quotes = append(quotes, *q)
+		}
+	}
+	return quotes
+}
+
+// displayCommodities muestra la sección de commodities, con USD/t, FOB y precio local de los granos
+func displayCommodities(quotes []CommodityQuote) {
+	if len(quotes) == 0 {
+		return
+	}
+	fmt.Printf("\n%s=== COMMODITIES ===%s\n\n", Cyan, Reset)
+	if plainMode {
+		fmt.Println(plainRow(fmt.Sprintf("%-14s", "Commodity"), fmt.Sprintf("%12s", "Precio"), fmt.Sprintf("%8s", "Var"),
+			fmt.Sprintf("%9s", "USD/t"), fmt.Sprintf("%9s", "FOB"), fmt.Sprintf("%12s", "$/t local")))
+	} else {
+		fmt.Printf("%-14s %12s %-8s %8s %9s %9s %12s\n", "Commodity", "Precio", "Unidad", "Var", "USD/t", "FOB", "$/t local")
+	}
+
+	for _, q := range quotes {
+		perTon, fob, local := "", "", ""
+		if q.PerTon > 0 {
+			perTon, fob = fmt.Sprintf("%.1f", q.PerTon), fmt.Sprintf("%.1f", q.FOB)
+		}
+		if q.Local > 0 {
+			local = fmt.Sprintf("%.0f", q.Local)
+		}
+		if plainMode {
+			fmt.Println(plainRow(fmt.Sprintf("%-14s", q.Name), fmt.Sprintf("%12.2f", q.Price), fmt.Sprintf("%+7.2f%%", q.ChangePercent),
+				fmt.Sprintf("%9s", perTon), fmt.Sprintf("%9s", fob), fmt.Sprintf("%12s", local)))
+			continue
+		}
+		fmt.Printf("%s%-14s%s %12.2f %-8s %s%+7.2f%%%s %9s %9s %12s\n",
+			White, q.Name, Reset, q.Price, q.Unit, variationColor(q.ChangePercent), q.ChangePercent, Reset, perTon, fob, local)
+	}
+	if !plainMode {
+		fmt.Println("FOB = CBOT + prima local; $/t local = FOB neto de derechos de exportación al dólar oficial")
+	}
+}
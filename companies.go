@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// CompanyListing relaciona la cotización de una empresa en el exterior con la local y el ratio de conversión:
+// un ADR (o la acción que respalda un CEDEAR) equivale a Ratio papeles locales
+type CompanyListing struct {
+	Company string  `json:"company"`
+	Foreign string  `json:"foreign"` // ADR o acción del subyacente, en dólares (GGAL, AAPL)
+	Local   string  `json:"local"`   // Acción local o CEDEAR, en pesos (GGAL.BA, AAPL.BA)
+	Ratio   float64 `json:"ratio"`
+}
+
+// Ratios de los ADRs argentinos y de los CEDEARs más operados
+var defaultCompanyListings = []CompanyListing{
+	{"Galicia", "GGAL", "GGAL.BA", 10},
+	{"YPF", "YPF", "YPFD.BA", 1},
+	{"Macro", "BMA", "BMA.BA", 10},
+	{"Pampa Energía", "PAM", "PAMP.BA", 25},
+	{"TGS", "TGS", "TGSU2.BA", 5},
+	{"Central Puerto", "CEPU", "CEPU.BA", 10},
+	{"Loma Negra", "LOMA", "LOMA.BA", 5},
+	{"Supervielle", "SUPV", "SUPV.BA", 5},
+	{"Cresud", "CRESY", "CRES.BA", 10},
+	{"Edenor", "EDN", "EDN.BA", 20},
+	{"Telecom", "TEO", "TECO2.BA", 5},
+	{"BBVA Argentina", "BBAR", "BBAR.BA", 3},
+	{"IRSA", "IRS", "IRSA.BA", 10},
+	{"Apple (CEDEAR)", "AAPL", "AAPL.BA", 20},
+	{"Mercado Libre (CEDEAR)", "MELI", "MELI.BA", 120},
+	{"Microsoft (CEDEAR)", "MSFT", "MSFT.BA", 30},
+}
+
+// companyListings devuelve las equivalencias vigentes: las de config.json reemplazan a las por defecto del mismo ADR
+func companyListings() []CompanyListing {
+	listings := append([]CompanyListing(nil), appConfig().Companies...)
+	for _, def := range defaultCompanyListings {
+		overridden := false
+		for _, l := range listings {
+			overridden = overridden || strings.EqualFold(l.Foreign, def.Foreign)
+		}
+		if !overridden {
+			listings = append(listings, def)
+		}
+	}
+	return listings
+}
+
+// CompanyGroup es una empresa con sus dos cotizaciones y el CCL que surge de compararlas
+type CompanyGroup struct {
+	Listing     CompanyListing
+	Foreign     StockInfo
+	Local       StockInfo
+	ForeignUSD  float64 // Precio del ADR en dólares (la tabla lo muestra convertido a pesos)
+	ImpliedCCL  float64 // Pesos por dólar implícitos: local × ratio / ADR
+	CCLDeviance float64 // Desvío del CCL implícito respecto de la mediana de todas las empresas, en %
+	ChangeGap   float64 // Diferencia entre la variación local y la del ADR, en puntos
+}
+
+// groupByCompany agrupa las cotizaciones de la misma empresa presentes en el snapshot y devuelve el resto sueltas
+func groupByCompany(stocks []StockInfo) ([]CompanyGroup, []StockInfo) {
+	bySymbol := make(map[string]StockInfo)
+	for _, stock := range stocks {
+		bySymbol[stock.Symbol] = stock
+	}
+
+	var groups []CompanyGroup
+	grouped := make(map[string]bool)
+	for _, l := range companyListings() {
+		foreign, okForeign := bySymbol[l.Foreign]
+		local, okLocal := bySymbol[l.Local]
+		if !okForeign || !okLocal || l.Ratio <= 0 {
+			continue
+		}
+		g := CompanyGroup{Listing: l, Foreign: foreign, Local: local, ChangeGap: local.ChangePercent - foreign.ChangePercent}
+		// El precio en dólares sale del cierre anterior y la variación, que no se convierten a pesos
+		g.ForeignUSD = foreign.PreviousClose * (1 + foreign.ChangePercent/100)
+		if g.ForeignUSD > 0 {
+			g.ImpliedCCL = local.Price * l.Ratio / g.ForeignUSD
+		}
+		groups = append(groups, g)
+		grouped[l.Foreign], grouped[l.Local] = true, true
+	}
+
+	// La mediana de los CCL implícitos es la referencia para detectar papeles desarbitrados
+	var ccls []float64
+	for _, g := range groups {
+		if g.ImpliedCCL > 0 {
+			ccls = append(ccls, g.ImpliedCCL)
+		}
+	}
+	if len(ccls) > 0 {
+		sort.Float64s(ccls)
+		median := ccls[len(ccls)/2]
+		if len(ccls)%2 == 0 {
+			median = (ccls[len(ccls)/2-1] + ccls[len(ccls)/2]) / 2
+		}
+		for i := range groups {
+			if groups[i].ImpliedCCL > 0 {
+				groups[i].CCLDeviance = (groups[i].ImpliedCCL/median - 1) * 100
+			}
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Listing.Company < groups[j].Listing.Company })
+
+	var rest []StockInfo
+	for _, stock := range stocks {
+		if !grouped[stock.Symbol] {
+			rest = append(rest, stock)
+		}
+	}
+	return groups, rest
+}
+
+// Estado de la vista por empresa: si está activa y qué empresas muestran sus dos filas
+var (
+	companyViewMu sync.Mutex
+	companyView   bool
+	expanded      = make(map[string]bool)
+)
+
+// companyViewEnabled indica si la tabla agrupa las cotizaciones por empresa
+func companyViewEnabled() bool {
+	companyViewMu.Lock()
+	defer companyViewMu.Unlock()
+	return companyView
+}
+
+// toggleCompanyView alterna la vista por empresa (:empresas)
+func toggleCompanyView() bool {
+	companyViewMu.Lock()
+	defer companyViewMu.Unlock()
+	companyView = !companyView
+	return companyView
+}
+
+// toggleCompanyExpanded despliega o pliega las filas de una empresa (:expand GGAL); acepta el ADR o el papel local
+func toggleCompanyExpanded(symbol string) (string, bool, error) {
+	symbol = strings.ToUpper(symbol)
+	for _, l := range companyListings() {
+		if strings.EqualFold(l.Foreign, symbol) || strings.EqualFold(l.Local, symbol) {
+			companyViewMu.Lock()
+			defer companyViewMu.Unlock()
+			expanded[l.Foreign] = !expanded[l.Foreign]
+			return l.Company, expanded[l.Foreign], nil
+		}
+	}
+	return "", false, fmt.Errorf("%s no tiene una equivalencia ADR/local conocida (agregarla en \"companies\" de config.json)", symbol)
+}
+
+// isExpanded indica si la empresa muestra sus dos filas
+func isExpanded(foreign string) bool {
+	companyViewMu.Lock()
+	defer companyViewMu.Unlock()
+	return expanded[foreign]
+}
+
+// companyLines estima las líneas que ocupa la sección por empresa
+func companyLines(groups []CompanyGroup) int {
+	if len(groups) == 0 {
+		return 0
+	}
+	lines := len(groups) + 4
+	for _, g := range groups {
+		if isExpanded(g.Listing.Foreign) {
+			lines += 2
+		}
+	}
+	return lines
+}
+
+// displayCompanies muestra una fila por empresa con ratio, CCL implícito y desvíos; las desplegadas agregan sus dos cotizaciones
+func displayCompanies(groups []CompanyGroup, layout StockLayout) {
+	if len(groups) == 0 {
+		return
+	}
+	fmt.Printf("\n%sPor empresa (:expand SIMBOLO despliega, :empresas vuelve a las filas sueltas)%s\n\n", Yellow, Reset)
+	fmt.Printf("  %-24s %12s %10s %6s %10s %8s %9s\n", "Empresa", "Local $", "ADR US$", "Ratio", "CCL impl.", "Desvío", "Dif. var")
+	for _, g := range groups {
+		deviance := White
+		if math.Abs(g.CCLDeviance) >= 2 {
+			deviance = Red
+		}
+		marker := "▸"
+		if isExpanded(g.Listing.Foreign) {
+			marker = "▾"
+		}
+		fmt.Printf("%s %-24.24s %12.2f %10.2f %6g %10.2f %s%+7.2f%%%s %s%+8.2f%s\n",
+			marker, g.Listing.Company, g.Local.Price, g.ForeignUSD, g.Listing.Ratio, g.ImpliedCCL,
+			deviance, g.CCLDeviance, Reset, variationColor(g.ChangeGap), g.ChangeGap, Reset)
+		if isExpanded(g.Listing.Foreign) {
+			fmt.Print("    ")
+			displayStockRow(g.Foreign, layout)
+			fmt.Print("    ")
+			displayStockRow(g.Local, layout)
+		}
+	}
+}
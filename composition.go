@@ -0,0 +1,184 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Radio de la torta en renglones; cada renglón lleva el doble de columnas para que se vea redonda
+const pieRadius = 6
+
+// Relleno y color de cada porción, en orden de tamaño
+var (
+	pieGlyphs = []string{"█", "▓", "▒", "░", "#", "*", "+", "o", "x", "="}
+	pieColors = []string{Cyan, Yellow, Green, Magenta, Red, White}
+)
+
+// Slice es una porción de la cartera: una clase, un sector, una moneda o una tenencia
+type Slice struct {
+	Name  string
+	Value float64 // En pesos
+	Share float64 // Sobre el total, en %
+}
+
+// groupHoldings suma el valor de las tenencias por la clave indicada, de mayor a menor
+func groupHoldings(holdings []pricedHolding, key func(pricedHolding) string) []Slice {
+	var total float64
+	values := make(map[string]float64)
+	for _, h := range holdings {
+		values[key(h)] += h.Value
+		total += h.Value
+	}
+	var slices []Slice
+	for name, value := range values {
+		s := Slice{Name: name, Value: value}
+		if total > 0 {
+			s.Share = value / total * 100
+		}
+		slices = append(slices, s)
+	}
+	sort.Slice(slices, func(i, j int) bool {
+		if slices[i].Value != slices[j].Value {
+			return slices[i].Value > slices[j].Value
+		}
+		return slices[i].Name < slices[j].Name
+	})
+	return slices
+}
+
+// holdingSector devuelve el sector del catálogo; los bonos van a renta fija
+func holdingSector(h pricedHolding) string {
+	if h.Class == bondAssetClass {
+		return "Renta fija"
+	}
+	if entry, ok := catalogLookup(h.Symbol); ok && entry.Sector != "" {
+		return entry.Sector
+	}
+	return "Sin clasificar"
+}
+
+// pieRows dibuja la torta: cada celda toma la porción que corresponde a su ángulo, en sentido horario desde arriba
+func pieRows(slices []Slice) []string {
+	var rows []string
+	for y := -pieRadius; y <= pieRadius; y++ {
+		var b strings.Builder
+		for x := -2 * pieRadius; x <= 2*pieRadius; x++ {
+			dx, dy := float64(x)/2, float64(y)
+			if dx*dx+dy*dy > pieRadius*pieRadius+pieRadius/2 {
+				b.WriteString(" ")
+				continue
+			}
+			angle := math.Atan2(dx, -dy)
+			if angle < 0 {
+				angle += 2 * math.Pi
+			}
+			at := angle / (2 * math.Pi) * 100
+			i, cum := 0, 0.0
+			for i = range slices {
+				cum += slices[i].Share
+				if at < cum {
+					break
+				}
+			}
+			b.WriteString(pieColors[i%len(pieColors)] + pieGlyphs[i%len(pieGlyphs)] + Reset)
+		}
+		rows = append(rows, b.String())
+	}
+	return rows
+}
+
+// displayComposition muestra la torta con la leyenda al costado y marca las porciones que superan el umbral
+func displayComposition(title string, slices []Slice, threshold float64) []string {
+	fmt.Printf("\n%s=== %s ===%s\n\n", Cyan, title, Reset)
+
+	var legend []string
+	var warnings []string
+	for i, s := range slices {
+		bar := strings.Repeat("■", int(math.Round(s.Share/5)))
+		color := pieColors[i%len(pieColors)]
+		mark := ""
+		if threshold > 0 && s.Share > threshold {
+			mark = Red + " ⚠" + Reset
+			warnings = append(warnings, fmt.Sprintf("%s: %s concentra el %.1f%% (umbral %.0f%%)", strings.ToLower(title), s.Name, s.Share, threshold))
+		}
+		legend = append(legend, fmt.Sprintf("%s%s%s %-18.18s %s%-20s%s %5.1f%% $%14.2f%s",
+			color, pieGlyphs[i%len(pieGlyphs)], Reset, s.Name, color, bar, Reset, s.Share, s.Value, mark))
+	}
+
+	// La leyenda se centra verticalmente junto a la torta
+	rows := pieRows(slices)
+	offset := max((len(rows)-len(legend))/2, 0)
+	for i := 0; i < max(len(rows), len(legend)+offset); i++ {
+		row := strings.Repeat(" ", 4*pieRadius+1)
+		if i < len(rows) {
+			row = rows[i]
+		}
+		line := ""
+		if j := i - offset; j >= 0 && j < len(legend) {
+			line = legend[j]
+		}
+		fmt.Printf("%s   %s\n", row, line)
+	}
+	return warnings
+}
+
+// runPortfolio implementa `bolsa portfolio`: composición de la cartera por clase, sector, moneda y tenencia
+func runPortfolio(args []string) error {
+	fs := flag.NewFlagSet("portfolio", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 30, "advertir las porciones que superan este porcentaje de la cartera (0 desactiva)")
+	by := fs.String("by", "all", "agrupación a mostrar: class, sector, currency, holding o all")
+	fs.Parse(args)
+
+	views := []struct {
+		name  string
+		title string
+		key   func(pricedHolding) string
+	}{
+		{"class", "POR CLASE DE ACTIVO", func(h pricedHolding) string { return h.Class }},
+		{"sector", "POR SECTOR", holdingSector},
+		{"currency", "POR MONEDA", func(h pricedHolding) string { return h.Currency }},
+		{"holding", "POR TENENCIA", func(h pricedHolding) string { return h.Symbol }},
+	}
+	known := *by == "all"
+	for _, v := range views {
+		known = known || v.name == *by
+	}
+	if !known {
+		return fmt.Errorf("agrupación desconocida %q (usar class, sector, currency, holding o all)", *by)
+	}
+
+	portfolio, err := loadPortfolio()
+	if err != nil {
+		return err
+	}
+	holdings, err := pricePortfolio(portfolio, NewHTTPClient())
+	if err != nil {
+		return err
+	}
+	var total float64
+	for _, h := range holdings {
+		total += h.Value
+	}
+	if total <= 0 {
+		return fmt.Errorf("la cartera no tiene tenencias valuadas")
+	}
+	fmt.Printf("Cartera valuada en $%.2f (dólares al MEP)\n", total)
+
+	var warnings []string
+	for _, v := range views {
+		if *by == "all" || *by == v.name {
+			warnings = append(warnings, displayComposition(v.title, groupHoldings(holdings, v.key), *threshold)...)
+		}
+	}
+
+	if len(warnings) > 0 {
+		fmt.Printf("\n%s⚠️ Concentraciones:%s\n", Red, Reset)
+		for _, w := range warnings {
+			fmt.Printf("  %s\n", w)
+		}
+	}
+	return nil
+}
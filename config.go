@@ -0,0 +1,455 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Duration es un time.Duration que se lee y escribe en JSON como texto ("15s", "2m")
+type Duration time.Duration
+
+// MarshalJSON codifica la duración como texto
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON acepta texto ("15s") o un número de segundos
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("duración inválida %q: %v", text, err)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return fmt.Errorf("duración inválida %s", data)
+	}
+	*d = Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// ProviderConfig agrupa los timeouts y la política de reintentos de un proveedor de datos
+type ProviderConfig struct {
+	ConnectTimeout Duration `json:"connectTimeout"` // Tiempo máximo para establecer la conexión
+	Timeout        Duration `json:"timeout"`        // Tiempo máximo total de cada request
+	MaxRetries     int      `json:"maxRetries"`     // Intentos totales por request
+	Backoff        Duration `json:"backoff"`        // Espera inicial entre reintentos, se duplica en cada uno
+	MaxBackoff     Duration `json:"maxBackoff"`     // Tope de la espera entre reintentos
+	DailyBudget    int      `json:"dailyBudget"`    // Requests por día para proveedores con cuota; 0 sin límite
+}
+
+// Config representa el archivo de configuración del programa (config.json)
+type Config struct {
+	Providers map[string]ProviderConfig `json:"providers"`
+
+	Alerts      []AlertRule `json:"alerts"`      // Reglas de alerta definidas por el usuario
+	AlertRepeat Duration    `json:"alertRepeat"` // Cada cuánto se repite una alerta activa no reconocida
+
+	MervalWeights  map[string]float64 `json:"mervalWeights"`  // Ponderaciones para estimar el MERVAL a partir de sus componentes
+	MervalMaxDelay Duration           `json:"mervalMaxDelay"` // Antigüedad de ^MERV a partir de la cual se muestra el estimado
+
+	WatchdogTimeout Duration `json:"watchdogTimeout"` // Duración máxima de un ciclo antes de que el watchdog lo reinicie
+
+	Hooks []Hook `json:"hooks"` // Comandos externos que se ejecutan en eventos
+
+	Templates map[string]string `json:"templates"` // Templates (text/template) de los mensajes de notificación
+	templates map[string]*template.Template
+
+	Notify NotifyConfig `json:"notify"` // Canales extra (chats de Telegram, email) y ruteo de alertas
+
+	DrawdownAlert float64 `json:"drawdownAlert"` // Caída desde el máximo anual (%) que dispara una alerta; 0 desactiva
+
+	RVOLAlert float64 `json:"rvolAlert"` // Volumen relativo (vs. promedio de 20 ruedas a la misma hora) que dispara una alerta; 0 desactiva
+
+	FCIs []FCIConfig `json:"fcis"` // Fondos comunes de inversión a seguir (API de CAFCI)
+
+	Commodities        []CommoditySpec `json:"commodities"`        // Reemplaza las commodities por defecto (granos, petróleo, oro, litio)
+	DisableCommodities bool            `json:"disableCommodities"` // No seguir commodities
+
+	Interval     Duration         `json:"interval"`     // Espera entre ciclos de actualización
+	Stocks       []WatchlistEntry `json:"stocks"`       // Reemplaza la lista de ADRs por defecto
+	Forex        []ForexSymbol    `json:"forex"`        // Reemplaza los tipos de cambio por defecto (acepta cripto, BTC-USD)
+	DisableBonds bool             `json:"disableBonds"` // No seguir bonos
+
+	Pinned []string `json:"pinned"` // Símbolos (o nombres de tipos de cambio) siempre visibles arriba de su tabla
+
+	Important []string `json:"important"` // Símbolos que nunca se postergan cuando consultar la watchlist excede el intervalo (además de los fijados y las tenencias)
+
+	symbolWarnings []SymbolWarning // Símbolos normalizados o fusionados al cargar, para advertirlos una vez
+	secretWarnings []string        // Referencias a secretos que no se pudieron resolver al cargar
+
+	Companies []CompanyListing `json:"companies"` // Equivalencias ADR/CEDEAR ↔ papel local para la vista por empresa
+
+	ColorThresholds []float64 `json:"colorThresholds"` // Variaciones (%) que intensifican el verde o el rojo; vacío = binario
+
+	HistoryInterval Duration `json:"historyInterval"` // Cada cuánto se guarda un snapshot para bolsa replay; 0 desactiva
+
+	RiskMaxHigh float64 `json:"riskMaxHigh"` // Porcentaje máximo de la cartera en activos de riesgo alto antes de advertir; 0 desactiva
+
+	Language string `json:"language"` // Idioma de los nombres de empresas: "es" (catálogo localizado) o "en" (Yahoo)
+
+	AnnouncementFeeds []string `json:"announcementFeeds"` // Feeds RSS/Atom de hechos relevantes (CNV, BYMA) a vigilar
+
+	AuctionsFeed string `json:"auctionsFeed"` // Feed RSS/Atom de comunicados de la Secretaría de Finanzas (llamados y resultados de licitaciones)
+
+	NewsFeeds      []string `json:"newsFeeds"`      // Feeds RSS/Atom de noticias cuyos titulares se vigilan
+	NewsKeywords   []string `json:"newsKeywords"`   // Palabras clave ("default", "cepo", "canje") que disparan alertas en los titulares
+	NewsMacroTerms []string `json:"newsMacroTerms"` // Términos que asocian un titular a la macro argentina; vacío usa los por defecto
+
+	OffHoursInterval Duration `json:"offHoursInterval"` // Espera entre ciclos con los mercados cerrados (solo forex y cripto); 0 desactiva
+
+	IOL *IOLConfig `json:"iol"` // Credenciales de InvertirOnline para las puntas de BYMA y la alerta de spread
+
+	Carry *CarryConfig `json:"carry"` // Caución y futuros de dólar para el panel de carry trade
+
+	Equilibrium *EquilibriumConfig `json:"equilibrium"` // Variables del BCRA del tipo de cambio de equilibrio; nil usa base monetaria / reservas
+
+	ExportPrograms []ExportProgram `json:"exportPrograms"` // Programas cambiarios para exportadores (dólar soja, 80/20...) con su vigencia
+
+	Merge map[string][]string `json:"merge"` // Proveedores por campo de la cotización, en orden de prioridad
+
+	Publish *PublishConfig `json:"publish"` // Destinos de la página estática con la tabla del día (directorio, S3, GitHub Pages)
+
+	DashboardRefresh Duration `json:"dashboardRefresh"` // Auto-refresh de las páginas web de bolsa serve --http (/ y /m); 0 desactiva
+
+	Social *SocialConfig `json:"social"` // Cuentas de Mastodon o X donde se postea el resumen de cierre
+
+	Failover *FailoverConfig `json:"failover"` // Lock compartido para correr varias instancias con una sola activa
+
+	Redis *RedisConfig `json:"redis"` // Caché de cotizaciones y pub/sub de snapshots compartidos entre procesos
+
+	YahooPolicies map[string]string `json:"yahooPolicies"` // Política por error de Yahoo (delisted, not_found, 429, 999, 5xx → disable, pause, retry, backoff, fail)
+	YahooPause    Duration          `json:"yahooPause"`    // Pausa global tras un 429 o 999 con política pause; por defecto 5 minutos
+}
+
+// ForexSymbol es un tipo de cambio (o cripto) a seguir en la sección de tipos de cambio
+type ForexSymbol struct {
+	Symbol string `json:"symbol"`
+	Name   string `json:"name"`
+}
+
+// defaultProviderConfig se usa para proveedores sin configuración propia
+var defaultProviderConfig = ProviderConfig{
+	ConnectTimeout: Duration(5 * time.Second),
+	Timeout:        Duration(15 * time.Second),
+	MaxRetries:     3,
+	Backoff:        Duration(1 * time.Second),
+	MaxBackoff:     Duration(30 * time.Second),
+}
+
+// defaultConfig devuelve la configuración por defecto, con valores ajustados por proveedor
+func defaultConfig() *Config {
+	return &Config{
+		AlertRepeat:      Duration(30 * time.Minute),
+		MervalWeights:    defaultMervalWeights,
+		MervalMaxDelay:   Duration(20 * time.Minute),
+		WatchdogTimeout:  Duration(3 * time.Minute),
+		Interval:         Duration(5 * time.Second),
+		ColorThresholds:  defaultColorThresholds,
+		HistoryInterval:  Duration(time.Minute),
+		RiskMaxHigh:      40,
+		Language:         LanguageSpanish,
+		OffHoursInterval: Duration(15 * time.Minute),
+		RVOLAlert:        2,
+		DashboardRefresh: Duration(30 * time.Second),
+		Providers: map[string]ProviderConfig{
+			"yahoo": defaultProviderConfig,
+			"telegram": {
+				ConnectTimeout: Duration(5 * time.Second),
+				Timeout:        Duration(30 * time.Second),
+				MaxRetries:     2,
+				Backoff:        Duration(2 * time.Second),
+				MaxBackoff:     Duration(10 * time.Second),
+			},
+			// Los binarios de los releases pesan varios MB: el timeout cubre la descarga completa
+			"github": {
+				ConnectTimeout: Duration(10 * time.Second),
+				Timeout:        Duration(5 * time.Minute),
+				MaxRetries:     2,
+				Backoff:        Duration(2 * time.Second),
+				MaxBackoff:     Duration(10 * time.Second),
+			},
+		},
+	}
+}
+
+var (
+	configOnce   sync.Once
+	loadedConfig *Config
+)
+
+// configPath devuelve la ruta del archivo de configuración (BOLSA_CONFIG la reemplaza)
+func configPath() (string, error) {
+	if path := os.Getenv("BOLSA_CONFIG"); path != "" {
+		return path, nil
+	}
+	return appFile("config.json")
+}
+
+// loadConfig lee config.json y completa los valores faltantes con los valores por defecto. Ante cualquier error
+// devuelve la configuración por defecto entera, nunca una mezcla con la parte del archivo que se llegó a aplicar
+func loadConfig() (*Config, error) {
+	cfg, err := readConfigFile()
+	if err != nil {
+		return defaultConfig(), err
+	}
+	return cfg, nil
+}
+
+// readConfigFile aplica config.json sobre la configuración por defecto; si devuelve un error, la configuración
+// puede haber quedado a medio completar
+func readConfigFile() (*Config, error) {
+	cfg := defaultConfig()
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	// Los campos donde 0 desactiva arrancan con su valor por defecto: Unmarshal solo pisa los presentes
+	fileCfg := Config{HistoryInterval: cfg.HistoryInterval, RiskMaxHigh: cfg.RiskMaxHigh, OffHoursInterval: cfg.OffHoursInterval, RVOLAlert: cfg.RVOLAlert, DashboardRefresh: cfg.DashboardRefresh}
+	if err := json.Unmarshal(data, &fileCfg); err != nil {
+		return cfg, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+
+	// Cada proveedor del archivo pisa solo los campos que define
+	for name, provider := range fileCfg.Providers {
+		cfg.Providers[name] = mergeProviderConfig(cfg.provider(name), provider)
+	}
+
+	for i := range fileCfg.Alerts {
+		rule := &fileCfg.Alerts[i]
+		cond, err := parseCondition(rule.Condition)
+		if err != nil {
+			return cfg, fmt.Errorf("regla de alerta %q inválida: %v", rule.Name, err)
+		}
+		rule.parsed = cond
+		if !validSeverity(rule.Severity) {
+			return cfg, fmt.Errorf("regla de alerta %q: severidad desconocida %q (info, warning o critical)", rule.Name, rule.Severity)
+		}
+	}
+	cfg.Alerts = fileCfg.Alerts
+	if fileCfg.AlertRepeat > 0 {
+		cfg.AlertRepeat = fileCfg.AlertRepeat
+	}
+
+	// Las ponderaciones del archivo reemplazan a las por defecto completas: la canasta se define entera
+	if len(fileCfg.MervalWeights) > 0 {
+		cfg.MervalWeights = fileCfg.MervalWeights
+	}
+	if fileCfg.MervalMaxDelay > 0 {
+		cfg.MervalMaxDelay = fileCfg.MervalMaxDelay
+	}
+	if fileCfg.WatchdogTimeout > 0 {
+		cfg.WatchdogTimeout = fileCfg.WatchdogTimeout
+	}
+
+	for _, hook := range fileCfg.Hooks {
+		if !validHookEvent(hook.Event) {
+			return cfg, fmt.Errorf("hook con evento desconocido %q (eventos: %s, %s, %s)", hook.Event, EventAlert, EventMarketClose, EventProviderError)
+		}
+		if hook.Command == "" {
+			return cfg, fmt.Errorf("hook %s sin comando", hook.Event)
+		}
+	}
+	cfg.Hooks = fileCfg.Hooks
+
+	templates, err := parseMessageTemplates(fileCfg.Templates)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Templates, cfg.templates = fileCfg.Templates, templates
+
+	if err := validateRoutes(fileCfg.Notify.Routes); err != nil {
+		return cfg, err
+	}
+	cfg.Notify = fileCfg.Notify
+	cfg.DrawdownAlert = fileCfg.DrawdownAlert
+	cfg.RVOLAlert = fileCfg.RVOLAlert
+	cfg.FCIs = fileCfg.FCIs
+	for _, c := range fileCfg.Commodities {
+		if c.Symbol == "" || c.Name == "" {
+			return cfg, fmt.Errorf("commodities: cada commodity necesita symbol y name")
+		}
+		if c.ExportTax < 0 || c.ExportTax >= 100 {
+			return cfg, fmt.Errorf("commodities: %s: exportTax debe estar entre 0 y 100", c.Symbol)
+		}
+	}
+	cfg.Commodities, cfg.DisableCommodities = fileCfg.Commodities, fileCfg.DisableCommodities
+
+	if fileCfg.Interval > 0 {
+		cfg.Interval = fileCfg.Interval
+	}
+	cfg.Stocks, cfg.Forex, cfg.DisableBonds = fileCfg.Stocks, fileCfg.Forex, fileCfg.DisableBonds
+	cfg.Pinned = fileCfg.Pinned
+	cfg.Important = fileCfg.Important
+	for _, l := range fileCfg.Companies {
+		if l.Foreign == "" || l.Local == "" || l.Ratio <= 0 {
+			return cfg, fmt.Errorf("companies: %q necesita foreign, local y un ratio positivo", l.Company)
+		}
+	}
+	cfg.Companies = fileCfg.Companies
+
+	cfg.HistoryInterval = fileCfg.HistoryInterval
+	cfg.RiskMaxHigh = fileCfg.RiskMaxHigh
+	cfg.AnnouncementFeeds = fileCfg.AnnouncementFeeds
+	cfg.AuctionsFeed = fileCfg.AuctionsFeed
+	cfg.NewsFeeds, cfg.NewsKeywords, cfg.NewsMacroTerms = fileCfg.NewsFeeds, fileCfg.NewsKeywords, fileCfg.NewsMacroTerms
+	cfg.OffHoursInterval = fileCfg.OffHoursInterval
+	cfg.DashboardRefresh = fileCfg.DashboardRefresh
+	cfg.IOL = fileCfg.IOL
+	if fileCfg.Carry != nil {
+		if err := fileCfg.Carry.validate(); err != nil {
+			return cfg, fmt.Errorf("carry: %v", err)
+		}
+	}
+	cfg.Carry = fileCfg.Carry
+	if fileCfg.Equilibrium != nil {
+		if err := fileCfg.Equilibrium.validate(); err != nil {
+			return cfg, fmt.Errorf("equilibrium: %v", err)
+		}
+	}
+	cfg.Equilibrium = fileCfg.Equilibrium
+	for i := range fileCfg.ExportPrograms {
+		if err := fileCfg.ExportPrograms[i].validate(); err != nil {
+			return cfg, fmt.Errorf("exportPrograms: %v", err)
+		}
+	}
+	cfg.ExportPrograms = fileCfg.ExportPrograms
+	if err := validateMergeRules(fileCfg.Merge); err != nil {
+		return cfg, fmt.Errorf("merge: %v", err)
+	}
+	cfg.Merge = fileCfg.Merge
+	if fileCfg.Publish != nil {
+		if err := fileCfg.Publish.validate(); err != nil {
+			return cfg, fmt.Errorf("publish: %v", err)
+		}
+	}
+	cfg.Publish = fileCfg.Publish
+	if fileCfg.Social != nil {
+		if err := fileCfg.Social.validate(); err != nil {
+			return cfg, fmt.Errorf("social: %v", err)
+		}
+	}
+	cfg.Social = fileCfg.Social
+	if fileCfg.Failover != nil {
+		if err := fileCfg.Failover.validate(); err != nil {
+			return cfg, fmt.Errorf("failover: %v", err)
+		}
+	}
+	cfg.Failover = fileCfg.Failover
+	if fileCfg.Redis != nil {
+		if err := fileCfg.Redis.validate(); err != nil {
+			return cfg, fmt.Errorf("redis: %v", err)
+		}
+	}
+	cfg.Redis = fileCfg.Redis
+	if err := validateYahooPolicies(fileCfg.YahooPolicies); err != nil {
+		return cfg, fmt.Errorf("yahooPolicies: %v", err)
+	}
+	cfg.YahooPolicies, cfg.YahooPause = fileCfg.YahooPolicies, fileCfg.YahooPause
+
+	switch fileCfg.Language {
+	case "":
+	case LanguageSpanish, LanguageEnglish:
+		cfg.Language = fileCfg.Language
+	default:
+		return cfg, fmt.Errorf("idioma desconocido %q (es o en)", fileCfg.Language)
+	}
+
+	if fileCfg.ColorThresholds != nil {
+		if err := validateColorThresholds(fileCfg.ColorThresholds); err != nil {
+			return cfg, err
+		}
+		cfg.ColorThresholds = fileCfg.ColorThresholds
+	}
+
+	// "ggal", " GGAL " y "GGAL" son el mismo símbolo: se normalizan y los repetidos se fusionan
+	cfg.symbolWarnings = normalizeConfigSymbols(cfg)
+
+	cfg.secretWarnings = resolveConfigSecrets(cfg)
+
+	return cfg, nil
+}
+
+// applyWatchedSymbols reemplaza las listas por defecto de acciones y tipos de cambio por las de config.json
+func applyWatchedSymbols() {
+	cfg := appConfig()
+	if len(cfg.Stocks) > 0 {
+		stocksMu.Lock()
+		stocks = append([]WatchlistEntry(nil), cfg.Stocks...)
+		stocksMu.Unlock()
+	}
+	if len(cfg.Forex) > 0 {
+		forexSymbols = nil
+		for _, fx := range cfg.Forex {
+			forexSymbols = append(forexSymbols, map[string]string{"symbol": fx.Symbol, "name": fx.Name})
+		}
+	}
+}
+
+// appConfig devuelve la configuración cargada una única vez por proceso
+func appConfig() *Config {
+	configOnce.Do(func() {
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Printf("%sError al cargar la configuración, se usan valores por defecto: %v%s\n", Red, err, Reset)
+		}
+		for _, warning := range cfg.symbolWarnings {
+			fmt.Printf("%s⚠️ config.json: %s%s\n", Yellow, warning, Reset)
+		}
+		for _, warning := range cfg.secretWarnings {
+			fmt.Printf("%s⚠️ config.json: %s%s\n", Yellow, warning, Reset)
+		}
+		loadedConfig = cfg
+	})
+	return loadedConfig
+}
+
+// provider devuelve la configuración de un proveedor, o la configuración por defecto
+func (c *Config) provider(name string) ProviderConfig {
+	if p, ok := c.Providers[name]; ok {
+		return p
+	}
+	return defaultProviderConfig
+}
+
+// mergeProviderConfig completa los campos en cero de override con los de base
+func mergeProviderConfig(base, override ProviderConfig) ProviderConfig {
+	if override.ConnectTimeout == 0 {
+		override.ConnectTimeout = base.ConnectTimeout
+	}
+	if override.Timeout == 0 {
+		override.Timeout = base.Timeout
+	}
+	if override.MaxRetries == 0 {
+		override.MaxRetries = base.MaxRetries
+	}
+	if override.Backoff == 0 {
+		override.Backoff = base.Backoff
+	}
+	if override.MaxBackoff == 0 {
+		override.MaxBackoff = base.MaxBackoff
+	}
+	if override.DailyBudget == 0 {
+		override.DailyBudget = base.DailyBudget
+	}
+	return override
+}
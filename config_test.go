@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig deja un config.json temporal y apunta BOLSA_CONFIG a él
+func writeConfig(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("BOLSA_CONFIG", path)
+}
+
+func TestLoadConfigMissingFileUsesDefaults(t *testing.T) {
+	t.Setenv("BOLSA_CONFIG", filepath.Join(t.TempDir(), "no-existe.json"))
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Providers, defaultConfig().Providers) {
+		t.Errorf("proveedores = %+v, se esperaban los por defecto", cfg.Providers)
+	}
+	if cfg.Interval != Duration(5*time.Second) {
+		t.Errorf("interval = %v, se esperaba 5s", time.Duration(cfg.Interval))
+	}
+}
+
+func TestLoadConfigMergesFile(t *testing.T) {
+	writeConfig(t, `{
+		"providers": {"yahoo": {"timeout": "20s"}, "iol": {"maxRetries": 5}},
+		"interval": "10s",
+		"historyInterval": 0,
+		"alerts": [{"name": "ggal-baja", "symbol": "GGAL.BA", "condition": "changePercent < -3", "severity": "critical"}]
+	}`)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	yahoo := cfg.provider("yahoo")
+	if yahoo.Timeout != Duration(20*time.Second) {
+		t.Errorf("yahoo.timeout = %v, se esperaba 20s", time.Duration(yahoo.Timeout))
+	}
+	if yahoo.MaxRetries != defaultProviderConfig.MaxRetries || yahoo.ConnectTimeout != defaultProviderConfig.ConnectTimeout {
+		t.Errorf("yahoo perdió los campos que el archivo no define: %+v", yahoo)
+	}
+	if iol := cfg.provider("iol"); iol.MaxRetries != 5 || iol.Timeout != defaultProviderConfig.Timeout {
+		t.Errorf("iol = %+v, se esperaba maxRetries 5 sobre los valores por defecto", iol)
+	}
+	if cfg.Interval != Duration(10*time.Second) {
+		t.Errorf("interval = %v, se esperaba 10s", time.Duration(cfg.Interval))
+	}
+	// 0 explícito desactiva; ausente conserva el valor por defecto
+	if cfg.HistoryInterval != 0 {
+		t.Errorf("historyInterval = %v, se esperaba 0 (desactivado)", time.Duration(cfg.HistoryInterval))
+	}
+	if cfg.OffHoursInterval != defaultConfig().OffHoursInterval {
+		t.Errorf("offHoursInterval = %v, se esperaba el valor por defecto", time.Duration(cfg.OffHoursInterval))
+	}
+	if len(cfg.Alerts) != 1 || cfg.Alerts[0].parsed == nil {
+		t.Fatalf("alertas = %+v, se esperaba una regla con la condición ya parseada", cfg.Alerts)
+	}
+}
+
+func TestLoadConfigErrorsReturnDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"json inválido", `{"providers": `, "error al decodificar"},
+		{"condición inválida", `{"providers": {"yahoo": {"timeout": "1s"}}, "alerts": [{"name": "mala", "condition": "price >"}]}`, `regla de alerta "mala" inválida`},
+		{"severidad desconocida", `{"providers": {"yahoo": {"timeout": "1s"}}, "alerts": [{"name": "x", "condition": "price > 1", "severity": "urgente"}]}`, "severidad desconocida"},
+		{"hook sin comando", `{"providers": {"yahoo": {"timeout": "1s"}}, "interval": "1m", "hooks": [{"event": "alert"}]}`, "sin comando"},
+		{"idioma", `{"providers": {"yahoo": {"timeout": "1s"}}, "interval": "1m", "language": "pt"}`, "idioma desconocido"},
+	}
+
+	defaults, err := json.Marshal(defaultConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeConfig(t, tt.content)
+
+			cfg, err := loadConfig()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("error = %v, se esperaba uno con %q", err, tt.wantErr)
+			}
+			got, err := json.Marshal(cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != string(defaults) {
+				t.Errorf("ante un error se esperaba la configuración por defecto, se obtuvo:\n%s", got)
+			}
+		})
+	}
+}
+
+func TestLoadConfigUnresolvedSecretKeepsConfig(t *testing.T) {
+	os.Unsetenv("BOLSA_TEST_TOKEN_INEXISTENTE")
+	writeConfig(t, `{
+		"interval": "30s",
+		"stocks": [{"symbol": "GGAL.BA", "market": "BYMA"}],
+		"notify": {"telegramToken": "env:BOLSA_TEST_TOKEN_INEXISTENTE"}
+	}`)
+
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatalf("un secreto sin resolver no debería invalidar config.json: %v", err)
+	}
+	if cfg.Interval != Duration(30*time.Second) || len(cfg.Stocks) != 1 || cfg.Stocks[0].Symbol != "GGAL.BA" {
+		t.Errorf("se perdió el resto de la configuración: interval %v, stocks %+v", time.Duration(cfg.Interval), cfg.Stocks)
+	}
+	if cfg.Notify.TelegramToken != "" {
+		t.Errorf("telegramToken = %q, se esperaba vacío", cfg.Notify.TelegramToken)
+	}
+	if len(cfg.secretWarnings) != 1 || !strings.Contains(cfg.secretWarnings[0], "notify.telegramToken") {
+		t.Errorf("advertencias = %v, se esperaba una para notify.telegramToken", cfg.secretWarnings)
+	}
+}
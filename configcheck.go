@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigIssue es un problema encontrado por `bolsa config check`
+type ConfigIssue struct {
+	File       string
+	Line       int // 0 si no se pudo ubicar
+	Message    string
+	Suggestion string
+	Warning    bool // Las advertencias no hacen fallar el chequeo
+}
+
+func (i ConfigIssue) String() string {
+	location := i.File
+	if i.Line > 0 {
+		location = fmt.Sprintf("%s:%d", i.File, i.Line)
+	}
+	s := fmt.Sprintf("%s: %s", location, i.Message)
+	if i.Suggestion != "" {
+		s += " (" + i.Suggestion + ")"
+	}
+	return s
+}
+
+// configChecker acumula los problemas de todos los archivos en lugar de frenar en el primero
+type configChecker struct {
+	issues []ConfigIssue
+}
+
+func (c *configChecker) add(file string, line int, message, suggestion string) {
+	c.issues = append(c.issues, ConfigIssue{File: file, Line: line, Message: message, Suggestion: suggestion})
+}
+
+func (c *configChecker) warn(file string, line int, message, suggestion string) {
+	c.issues = append(c.issues, ConfigIssue{File: file, Line: line, Message: message, Suggestion: suggestion, Warning: true})
+}
+
+// lineAt convierte un offset en bytes en número de línea
+func lineAt(data []byte, offset int64) int {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	return bytes.Count(data[:offset], []byte("\n")) + 1
+}
+
+// lineOf devuelve la línea de la primera aparición de un texto como string JSON, o 0
+func lineOf(data []byte, text string) int {
+	var quoted bytes.Buffer
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false) // Las condiciones usan < y >
+	encoder.Encode(text)
+	if i := bytes.Index(data, bytes.TrimSpace(quoted.Bytes())); i >= 0 {
+		return lineAt(data, int64(i))
+	}
+	return 0
+}
+
+// levenshtein calcula la distancia de edición entre dos textos
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// suggest devuelve "¿quisiste decir X?" con la opción más parecida, si hay una razonablemente cerca
+func suggest(word string, options []string) string {
+	best, bestDist := "", len(word)/2+2
+	for _, option := range options {
+		if d := levenshtein(strings.ToLower(word), strings.ToLower(option)); d < bestDist {
+			best, bestDist = option, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf("¿quisiste decir %q?", best)
+}
+
+// jsonFieldNames devuelve los nombres JSON de los campos de un struct
+func jsonFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag != "" && tag != "-" {
+			names = append(names, tag)
+		}
+	}
+	return names
+}
+
+// decodeStrict decodifica rechazando campos desconocidos y traduce los errores a línea y sugerencia
+func (c *configChecker) decodeStrict(file string, data []byte, v interface{}, fields []string) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(v)
+	if err == nil {
+		return true
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		c.add(file, lineAt(data, syntaxErr.Offset), "JSON inválido: "+syntaxErr.Error(), "revisá comas, comillas y llaves cerca de esa línea")
+	case errors.As(err, &typeErr):
+		c.add(file, lineAt(data, typeErr.Offset), fmt.Sprintf("el campo %q debe ser %s, no %s", typeErr.Field, typeErr.Type, typeErr.Value), "")
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		c.add(file, lineOf(data, field), fmt.Sprintf("campo desconocido %q", field), suggest(field, fields))
+	default:
+		c.add(file, 0, err.Error(), "")
+	}
+	return false
+}
+
+// readConfigFile lee un archivo de estado; ok es false si no existe
+func (c *configChecker) readConfigFile(path string) ([]byte, bool) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, false
+	}
+	if err != nil {
+		c.add(filepath.Base(path), 0, err.Error(), "")
+		return nil, false
+	}
+	return data, true
+}
+
+// knownSymbols devuelve los símbolos que el programa conoce: watchlist, tipos de cambio y catálogo
+func knownSymbols() map[string]bool {
+	known := map[string]bool{"*": true, mervalSymbol: true, spFuturesSymbol: true}
+	for _, stock := range watchlistSnapshot() {
+		known[stock.Symbol] = true
+	}
+	for _, forex := range forexSymbols {
+		known[forex["symbol"]] = true
+	}
+	for _, entry := range catalog {
+		known[entry.Symbol] = true
+	}
+	if watchlists, err := loadWatchlists(); err == nil {
+		for _, entries := range watchlists {
+			for _, entry := range entries {
+				known[entry.Symbol] = true
+			}
+		}
+	}
+	return known
+}
+
+// checkConfigFile valida config.json completo
+func (c *configChecker) checkConfigFile(path string) {
+	file := filepath.Base(path)
+	data, ok := c.readConfigFile(path)
+	if !ok {
+		c.warn(file, 0, "no existe, se usan los valores por defecto", "bolsa init genera uno")
+		return
+	}
+
+	var cfg Config
+	if !c.decodeStrict(file, data, &cfg, jsonFieldNames(Config{})) {
+		return
+	}
+
+	// Reglas de alerta
+	known := knownSymbols()
+	var symbols []string
+	for symbol := range known {
+		symbols = append(symbols, symbol)
+	}
+	var fieldNames []string
+	for _, name := range conditionFields {
+		fieldNames = append(fieldNames, name)
+	}
+	sort.Strings(fieldNames)
+
+	seen := make(map[string]bool)
+	for _, rule := range cfg.Alerts {
+		line := lineOf(data, rule.Name)
+		if rule.Name == "" {
+			c.add(file, lineOf(data, rule.Condition), "regla de alerta sin nombre", `agregá "name"`)
+		} else if seen[rule.Name] {
+			c.add(file, lineOf(data, rule.Condition), fmt.Sprintf("regla de alerta %q duplicada", rule.Name), "los nombres se usan en ack/snooze y deben ser únicos")
+		}
+		seen[rule.Name] = true
+
+		if _, err := parseCondition(rule.Condition); err != nil {
+			hint := ""
+			for _, token := range strings.FieldsFunc(rule.Condition, func(r rune) bool { return !(r == '_' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') }) {
+				if _, ok := conditionFields[strings.ToLower(token)]; !ok && !strings.EqualFold(token, "AND") && !strings.EqualFold(token, "OR") {
+					hint = suggest(token, fieldNames)
+					break
+				}
+			}
+			c.add(file, lineOf(data, rule.Condition), fmt.Sprintf("regla %q: %v", rule.Name, err), hint)
+		}
+		if rule.Symbol == "" {
+			c.add(file, line, fmt.Sprintf("regla %q sin símbolo", rule.Name), `usá "*" para todos los símbolos`)
+		} else if !known[rule.Symbol] {
+			c.warn(file, lineOf(data, rule.Symbol), fmt.Sprintf("regla %q: símbolo desconocido %q", rule.Name, rule.Symbol), suggest(rule.Symbol, symbols))
+		}
+		if !validSeverity(rule.Severity) {
+			c.add(file, lineOf(data, rule.Severity), fmt.Sprintf("regla %q: severidad desconocida %q", rule.Name, rule.Severity),
+				suggest(rule.Severity, []string{SeverityInfo, SeverityWarning, SeverityCritical}))
+		}
+	}
+
+	// Hooks, templates y ruteo
+	events := []string{EventAlert, EventMarketClose, EventProviderError}
+	for _, hook := range cfg.Hooks {
+		if !validHookEvent(hook.Event) {
+			c.add(file, lineOf(data, hook.Event), fmt.Sprintf("hook con evento desconocido %q", hook.Event), suggest(hook.Event, events))
+		}
+		if hook.Command == "" {
+			c.add(file, lineOf(data, hook.Event), fmt.Sprintf("hook %s sin comando", hook.Event), "")
+		}
+	}
+	for name, source := range cfg.Templates {
+		if _, err := parseMessageTemplates(map[string]string{name: source}); err != nil {
+			c.add(file, lineOf(data, name), err.Error(), suggest(name, []string{TemplateAlerts, TemplateCloseSummary, TemplateSocialPost}))
+		}
+	}
+	channels := []string{"console", "log", "telegram", "email"}
+	usesChannel := make(map[string]bool)
+	for _, route := range cfg.Notify.Routes {
+		if !validSeverity(route.Severity) {
+			c.add(file, lineOf(data, route.Severity), fmt.Sprintf("ruta con severidad desconocida %q", route.Severity),
+				suggest(route.Severity, []string{SeverityInfo, SeverityWarning, SeverityCritical}))
+		}
+		for _, channel := range route.Channels {
+			usesChannel[channel] = true
+			if !contains(channels, channel) {
+				c.add(file, lineOf(data, channel), fmt.Sprintf("ruta con canal desconocido %q", channel), suggest(channel, channels))
+			}
+		}
+	}
+
+	// Credenciales: mejor referenciadas que en texto plano
+	for name, field := range secretFields(&cfg) {
+		switch {
+		case *field == "":
+		case !isSecretRef(*field):
+			c.warn(file, lineOf(data, *field), fmt.Sprintf("%s está en texto plano", name),
+				"guardalo con bolsa secrets set y usá secret:NOMBRE, keyring:NOMBRE o env:VARIABLE")
+		default:
+			if _, err := resolveSecret(*field); err != nil {
+				c.add(file, lineOf(data, *field), fmt.Sprintf("%s: %v", name, err), "")
+			}
+		}
+	}
+
+	token := os.Getenv("TELEGRAM_BOT_TOKEN")
+	if token == "" {
+		token = cfg.Notify.TelegramToken
+	}
+	chats := os.Getenv("TELEGRAM_CHAT_ID") != "" || len(cfg.Notify.TelegramChats) > 0
+	if usesChannel["telegram"] && token == "" {
+		c.add(file, lineOf(data, "telegram"), "las rutas usan telegram pero falta TELEGRAM_BOT_TOKEN", "exportá la variable de entorno con el token del bot")
+	}
+	if token != "" && !chats {
+		c.add("entorno", 0, "TELEGRAM_BOT_TOKEN está definido pero no hay chats", "definí TELEGRAM_CHAT_ID o notify.telegramChats")
+	}
+	if cfg.Notify.TelegramCommands && token == "" {
+		c.add(file, lineOf(data, "telegramCommands"), "telegramCommands necesita el token del bot", "exportá TELEGRAM_BOT_TOKEN")
+	}
+	if email := cfg.Notify.Email; email != nil {
+		if email.Host == "" || email.From == "" || len(email.To) == 0 {
+			c.add(file, lineOf(data, "email"), "la configuración de email necesita host, from y to", "")
+		}
+		if email.Username != "" && email.Password == "" && os.Getenv("BOLSA_SMTP_PASSWORD") == "" {
+			c.add(file, lineOf(data, "email"), "email con usuario pero sin contraseña", "exportá BOLSA_SMTP_PASSWORD")
+		}
+	} else if usesChannel["email"] {
+		c.add(file, lineOf(data, "email"), "las rutas usan email pero no hay notify.email", "")
+	}
+
+	// Símbolos con espacios, en minúsculas o repetidos entre listas
+	for _, w := range normalizeConfigSymbols(&cfg) {
+		c.warn(file, lineOf(data, w.Raw), w.Message, w.Suggestion)
+	}
+
+	for name := range cfg.Providers {
+		if !contains([]string{"yahoo", "telegram", "bcra", "cafci"}, name) {
+			c.warn(file, lineOf(data, name), fmt.Sprintf("proveedor desconocido %q", name), suggest(name, []string{"yahoo", "telegram", "bcra", "cafci"}))
+		}
+	}
+}
+
+// checkStateFiles valida los demás archivos de datos que el usuario puede editar a mano
+func (c *configChecker) checkStateFiles() {
+	dir, err := appDir()
+	if err != nil {
+		c.add("estado", 0, err.Error(), "")
+		return
+	}
+
+	files := []struct {
+		name   string
+		target interface{}
+		fields []string
+	}{
+		{watchlistsFile, &map[string][]WatchlistEntry{}, jsonFieldNames(WatchlistEntry{})},
+		{"bonds.json", &[]*Bond{}, jsonFieldNames(Bond{})},
+		{"bond_holdings.json", &[]BondHolding{}, jsonFieldNames(BondHolding{})},
+		{"portfolio.json", &Portfolio{}, jsonFieldNames(Portfolio{})},
+		{"events.json", &[]MarketEvent{}, jsonFieldNames(MarketEvent{})},
+	}
+	for _, f := range files {
+		data, ok := c.readConfigFile(filepath.Join(dir, f.name))
+		if !ok {
+			continue
+		}
+		if !c.decodeStrict(f.name, data, f.target, f.fields) {
+			continue
+		}
+		if f.name == "bond_holdings.json" {
+			bonds, _ := loadBonds()
+			var names []string
+			for _, bond := range bonds {
+				names = append(names, bond.Symbol)
+			}
+			for _, h := range *f.target.(*[]BondHolding) {
+				if findBond(bonds, h.Symbol) == nil {
+					c.add(f.name, lineOf(data, h.Symbol), fmt.Sprintf("bono desconocido %q", h.Symbol), suggest(h.Symbol, names))
+				}
+			}
+		}
+	}
+}
+
+// contains indica si el texto está en la lista
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// runConfig implementa `bolsa config check`
+func runConfig(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return fmt.Errorf("uso: bolsa config check")
+	}
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	checker := &configChecker{}
+	checker.checkConfigFile(path)
+	checker.checkStateFiles()
+
+	errorsFound := 0
+	for _, issue := range checker.issues {
+		if issue.Warning {
+			fmt.Printf("%s⚠️ %s%s\n", Yellow, issue, Reset)
+		} else {
+			errorsFound++
+			fmt.Printf("%s✗ %s%s\n", Red, issue, Reset)
+		}
+	}
+	if errorsFound > 0 {
+		return fmt.Errorf("%d errores en la configuración", errorsFound)
+	}
+	fmt.Printf("%s✓ Configuración válida (%s)%s\n", Green, path, Reset)
+	return nil
+}
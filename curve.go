@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// CurvePoint representa un bono ubicado en la curva de rendimientos
+type CurvePoint struct {
+	Quote    BondQuote
+	YTM      float64
+	Duration float64 // Duration modificada en años
+	Years    float64 // Años al vencimiento
+}
+
+// buildCurve calcula TIR y duration de cada bono valuado, agrupados por moneda de pago
+func buildCurve(quotes []BondQuote) map[string][]CurvePoint {
+	curves := make(map[string][]CurvePoint)
+	for _, q := range quotes {
+		ytm, ok := q.YTM()
+		if !ok {
+			fmt.Printf("No se pudo calcular la TIR de %s a precio %.2f\n", q.Bond.Symbol, q.CleanPrice)
+			continue
+		}
+		_, modified := q.Duration(ytm)
+		curves[q.Bond.Currency] = append(curves[q.Bond.Currency], CurvePoint{
+			Quote:    q,
+			YTM:      ytm,
+			Duration: modified,
+			Years:    q.Bond.Maturity().Sub(q.Date).Hours() / 24 / 365,
+		})
+	}
+
+	for currency := range curves {
+		points := curves[currency]
+		sort.Slice(points, func(i, j int) bool { return points[i].Duration < points[j].Duration })
+	}
+	return curves
+}
+
+// curveChart arma el gráfico de la curva con una serie por moneda
+func curveChart(curves map[string][]CurvePoint, byMaturity bool) *Chart {
+	chart := &Chart{Title: "Curva de rendimientos", XLabel: "Duration (años)", YLabel: "TIR %"}
+	if byMaturity {
+		chart.XLabel = "Vencimiento (años)"
+	}
+
+	var currencies []string
+	for currency := range curves {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	for _, currency := range currencies {
+		series := Series{Name: "Bonos en " + currency, Scatter: true}
+		for _, p := range curves[currency] {
+			x := p.Duration
+			if byMaturity {
+				x = p.Years
+			}
+			series.X = append(series.X, x)
+			series.Y = append(series.Y, p.YTM*100)
+			series.Labels = append(series.Labels, p.Quote.Bond.Symbol)
+		}
+		chart.Series = append(chart.Series, series)
+	}
+	return chart
+}
+
+// runCurve implementa `bolsa curve`: muestra una curva por moneda de pago. El catálogo embebido solo trae
+// soberanos en dólares; la curva en pesos aparece con los bonos en ARS que el usuario cargue en bonds.json
+func runCurve(args []string) error {
+	fs := flag.NewFlagSet("curve", flag.ExitOnError)
+	output := fs.String("o", "", "exportar la curva a un archivo .png o .svg")
+	byMaturity := fs.Bool("maturity", false, "usar años al vencimiento en el eje X en lugar de duration")
+	fs.Parse(args)
+
+	bonds, err := loadBonds()
+	if err != nil {
+		fmt.Printf("Error al cargar bonos personalizados: %v\n", err)
+	}
+
+	client := NewHTTPClient()
+	curves := buildCurve(getBondData(bonds, client, nil))
+	if len(curves) == 0 {
+		return fmt.Errorf("no se pudo valuar ningún bono")
+	}
+
+	var currencies []string
+	for currency := range curves {
+		currencies = append(currencies, currency)
+	}
+	// USD primero y después el resto en orden alfabético
+	sort.Slice(currencies, func(i, j int) bool {
+		if (currencies[i] == "USD") != (currencies[j] == "USD") {
+			return currencies[i] == "USD"
+		}
+		return currencies[i] < currencies[j]
+	})
+
+	for _, currency := range currencies {
+		points := curves[currency]
+
+		fmt.Printf("\n%s=== CURVA EN %s ===%s\n\n", Cyan, currency, Reset)
+		fmt.Printf("%-8s%10s%10s%10s%12s\n", "Especie", "Precio", "TIR", "Duration", "Vencimiento")
+		for _, p := range points {
+			fmt.Printf("%-8s%10.2f%9.2f%%%10.2f%12s\n",
+				p.Quote.Bond.Symbol, p.Quote.CleanPrice, p.YTM*100, p.Duration,
+				p.Quote.Bond.Maturity().Format("2006-01-02"))
+		}
+		fmt.Println()
+
+		chart := curveChart(map[string][]CurvePoint{currency: points}, *byMaturity)
+		chart.Title = fmt.Sprintf("Curva de bonos en %s", currency)
+		chart.RenderASCII(os.Stdout, 60, 15)
+	}
+
+	if len(curves["ARS"]) == 0 {
+		fmt.Printf("%sSin curva en pesos: el catálogo embebido solo trae bonos en dólares. Las LECAPs, BONCAPs y bonos CER\n"+
+			"se agregan en bonds.json con currency \"ARS\" (una LECAP es un único pago con amortization igual al pago final).%s\n", Yellow, Reset)
+	}
+
+	if *output == "" {
+		return nil
+	}
+
+	if err := saveChart(curveChart(curves, *byMaturity), *output); err != nil {
+		return err
+	}
+	fmt.Printf("\nCurva exportada a %s\n", *output)
+	return nil
+}
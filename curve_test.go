@@ -0,0 +1,32 @@
+package main
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+)
+
+func TestBuildCurveGroupsPesoBondsFromBondsFile(t *testing.T) {
+	settlement := time.Date(2026, time.January, 15, 0, 0, 0, 0, time.UTC)
+	maturity := settlement.AddDate(1, 0, 0)
+
+	// LECAP como la define bonds.json: un único pago con amortization igual al pago final
+	lecap := &Bond{Symbol: "S15E7", Currency: "ARS", Frequency: 2, CashFlows: []CashFlow{{Date: maturity, Amortization: 130}}}
+	gd30 := findBond(bolsa.DefaultBonds(), "GD30")
+
+	curves := buildCurve([]BondQuote{lecap.Quote(100, settlement), gd30.Quote(70, settlement)})
+	if len(curves["USD"]) != 1 || len(curves["ARS"]) != 1 {
+		t.Fatalf("curvas = %v, se esperaba un bono en USD y uno en ARS", curves)
+	}
+
+	// 130 al año por 100 hoy: 30% efectivo anual y duration de un año (modificada: 1 / 1,30)
+	point := curves["ARS"][0]
+	if math.Abs(point.YTM-0.30) > 1e-3 {
+		t.Errorf("TIR de la LECAP = %.4f, se esperaba 0,30", point.YTM)
+	}
+	if math.Abs(point.Duration-1/1.30) > 1e-2 {
+		t.Errorf("duration modificada = %.4f, se esperaba %.4f", point.Duration, 1/1.30)
+	}
+}
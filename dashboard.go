@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/http"
+	"time"
+)
+
+// Vista móvil del dashboard: tarjetas de dólares arriba y una lista compacta de acciones,
+// pensada para el ancho de un teléfono; se recarga sola cada dashboardRefresh
+var mobilePageTemplate = template.Must(template.New("mobile").Funcs(template.FuncMap{
+	"signed": func(v float64) string { return fmt.Sprintf("%+.2f", v) },
+	"price":  func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"class": func(v float64) string {
+		switch {
+		case v > 0:
+			return "up"
+		case v < 0:
+			return "down"
+		}
+		return ""
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+<title>Bolsa</title>
+<style>
+* { box-sizing: border-box; }
+body { font-family: system-ui, sans-serif; margin: 0; padding: .75em; color: #222; background: #f6f6f6; font-variant-numeric: tabular-nums; }
+header { display: flex; justify-content: space-between; align-items: baseline; margin-bottom: .75em; }
+header h1 { font-size: 1.2em; margin: 0; }
+header small, footer { color: #777; font-size: .8em; }
+.markets span { margin-left: .5em; }
+.cards { display: grid; grid-template-columns: repeat(auto-fill, minmax(9em, 1fr)); gap: .5em; margin-bottom: 1em; }
+.card { background: #fff; border-radius: .5em; padding: .6em .7em; box-shadow: 0 1px 2px rgba(0,0,0,.08); }
+.card .name { font-size: .8em; color: #555; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; }
+.card .value { font-size: 1.25em; font-weight: 600; }
+ul { list-style: none; margin: 0; padding: 0; background: #fff; border-radius: .5em; box-shadow: 0 1px 2px rgba(0,0,0,.08); }
+li { display: flex; align-items: center; padding: .55em .7em; border-bottom: 1px solid #eee; }
+li:last-child { border-bottom: none; }
+li .symbol { flex: 1; font-weight: 600; }
+li .symbol small { display: block; font-weight: normal; color: #777; font-size: .75em; white-space: nowrap; overflow: hidden; text-overflow: ellipsis; max-width: 12em; }
+li .last { margin-right: .6em; }
+.badge { min-width: 4.8em; text-align: right; padding: .15em .4em; border-radius: .3em; font-size: .9em; color: #fff; background: #888; }
+.badge.up { background: #0a7d28; } .badge.down { background: #c0152f; }
+.up { color: #0a7d28; } .down { color: #c0152f; }
+footer { margin-top: 1em; text-align: center; }
+footer a { color: inherit; }
+@media (min-width: 40em) { body { max-width: 40em; margin: 0 auto; } }
+</style>
+</head>
+<body>
+<header>
+<h1>Bolsa</h1>
+<small>{{.Updated}}<span class="markets">{{range .Markets}}<span>{{.Name}} {{if .Open}}●{{else if .Holiday}}feriado{{else}}○{{end}}</span>{{end}}</span></small>
+</header>
+{{if .Forex}}<section class="cards">
+{{range .Forex}}<div class="card"><div class="name">{{.Name}}</div><div class="value">{{price .Price}}</div><div class="{{class .ChangePercent}}">{{signed .ChangePercent}}%</div></div>
+{{end}}</section>{{end}}
+{{if .Stocks}}<ul>
+{{range .Stocks}}<li><span class="symbol">{{.Symbol}}<small>{{.Name}}</small></span><span class="last">{{price .Price}}</span><span class="badge {{class .ChangePercent}}">{{signed .ChangePercent}}%</span></li>
+{{end}}</ul>{{end}}
+<footer>{{if .Refresh}}Se actualiza cada {{.Refresh}} s · {{end}}<a href="/">tabla completa</a></footer>
+</body>
+</html>
+`))
+
+// dashboardSnapshot devuelve el último snapshot que difundió el hub, o el guardado si todavía no hubo ninguno
+func dashboardSnapshot(hub *snapshotHub) *Snapshot {
+	if snapshot := hub.latest(); snapshot != nil {
+		return snapshot
+	}
+	snapshot, _ := loadLastSnapshot()
+	return snapshot
+}
+
+// dashboardRefresh devuelve cada cuántos segundos se recarga la página: ?refresh=15s reemplaza el de config.json
+func dashboardRefresh(r *http.Request) (int, error) {
+	refresh := time.Duration(appConfig().DashboardRefresh)
+	if value := r.URL.Query().Get("refresh"); value != "" {
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return 0, fmt.Errorf("refresh inválido %q (usar por ejemplo 30s, o 0 para desactivarlo)", value)
+		}
+		refresh = d
+	}
+	if refresh > 0 && refresh < time.Second {
+		refresh = time.Second
+	}
+	return int(refresh / time.Second), nil
+}
+
+// renderMobilePage genera la vista móvil del snapshot
+func renderMobilePage(snapshot *Snapshot, refresh int) ([]byte, error) {
+	var page bytes.Buffer
+	err := mobilePageTemplate.Execute(&page, struct {
+		Updated string
+		Refresh int
+		Markets []MarketState
+		Forex   []ForexInfo
+		Stocks  []StockInfo
+	}{snapshot.Time.In(argentinaLocation).Format("02/01 15:04"), refresh, snapshot.Status.Markets, snapshot.Forex, snapshot.Stocks})
+	return page.Bytes(), err
+}
+
+// handleDashboard atiende GET /: la tabla completa, la misma de la página publicada al cierre, que se
+// recarga con el mismo intervalo que la vista móvil
+func handleDashboard(hub *snapshotHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		serveDashboardPage(w, r, hub, renderSnapshotPage)
+	}
+}
+
+// handleMobileDashboard atiende GET /m: la vista compacta para el celular
+func handleMobileDashboard(hub *snapshotHub) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		serveDashboardPage(w, r, hub, renderMobilePage)
+	}
+}
+
+// serveDashboardPage responde una página HTML del último snapshot
+func serveDashboardPage(w http.ResponseWriter, r *http.Request, hub *snapshotHub, render func(*Snapshot, int) ([]byte, error)) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "método no permitido", http.StatusMethodNotAllowed)
+		return
+	}
+	refresh, err := dashboardRefresh(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	snapshot := dashboardSnapshot(hub)
+	if snapshot == nil {
+		http.Error(w, "todavía no hay datos del primer ciclo", http.StatusServiceUnavailable)
+		return
+	}
+	page, err := render(snapshot, refresh)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(page)
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDashboardServesHubSnapshotWithRefresh(t *testing.T) {
+	hub := newSnapshotHub()
+	hub.broadcast(&Snapshot{
+		Time:   time.Date(2025, 3, 14, 15, 30, 0, 0, argentinaLocation),
+		Stocks: []StockInfo{{Symbol: "ZZHUB", Name: "Del hub", Price: 100}},
+	})
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		target  string
+		refresh string
+	}{
+		{"tabla completa", handleDashboard(hub), "/", `content="30"`},
+		{"tabla completa con ?refresh", handleDashboard(hub), "/?refresh=15s", `content="15"`},
+		{"vista móvil", handleMobileDashboard(hub), "/m", `content="30"`},
+		{"vista móvil con ?refresh", handleMobileDashboard(hub), "/m?refresh=15s", `content="15"`},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		tt.handler(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+		body := rec.Body.String()
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: código %d (%s)", tt.name, rec.Code, body)
+			continue
+		}
+		if !strings.Contains(body, "ZZHUB") {
+			t.Errorf("%s: la página no muestra el snapshot del hub", tt.name)
+		}
+		if !strings.Contains(body, `http-equiv="refresh" `+tt.refresh) {
+			t.Errorf("%s: se esperaba el refresh %s", tt.name, tt.refresh)
+		}
+	}
+
+	// Con ?refresh=0 la tabla completa no se recarga
+	rec := httptest.NewRecorder()
+	handleDashboard(hub)(rec, httptest.NewRequest(http.MethodGet, "/?refresh=0", nil))
+	if strings.Contains(rec.Body.String(), `http-equiv="refresh"`) {
+		t.Error("con ?refresh=0 la página no debería recargarse")
+	}
+}
+
+func TestPublishedPageDoesNotRefresh(t *testing.T) {
+	page, err := renderSnapshotPage(&Snapshot{Time: time.Now()}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(page), `http-equiv="refresh"`) {
+		t.Error("la página publicada al cierre no debería recargarse")
+	}
+}
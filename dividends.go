@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DividendPayment es un dividendo por acción pagado por un símbolo, en la moneda en que cotiza
+type DividendPayment struct {
+	Date   time.Time
+	Amount float64
+}
+
+// getDividends obtiene de Yahoo los dividendos pagados por un símbolo en el rango indicado
+func getDividends(symbol, rangeStr string, client QuoteFetcher) ([]DividendPayment, error) {
+	dividendsURL := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?range=%s&interval=1mo&events=div",
+		url.PathEscape(symbol), url.QueryEscape(rangeStr))
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Accept":     "application/json",
+		"Referer":    "https://finance.yahoo.com/",
+	}
+
+	resp, err := client.GetWithRetry(dividendsURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "para los dividendos de %s", symbol)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var chartResp struct {
+		Chart struct {
+			Result []struct {
+				Events struct {
+					Dividends map[string]struct {
+						Amount float64 `json:"amount"`
+						Date   int64   `json:"date"`
+					} `json:"dividends"`
+				} `json:"events"`
+			} `json:"result"`
+		} `json:"chart"`
+	}
+	if err := json.Unmarshal(body, &chartResp); err != nil {
+		return nil, schemaError("dividends", symbol, body, err.Error())
+	}
+	if len(chartResp.Chart.Result) == 0 {
+		return nil, nil
+	}
+
+	var payments []DividendPayment
+	for _, d := range chartResp.Chart.Result[0].Events.Dividends {
+		if d.Amount > 0 {
+			payments = append(payments, DividendPayment{Date: time.Unix(d.Date, 0).UTC(), Amount: d.Amount})
+		}
+	}
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Date.Before(payments[j].Date) })
+	return payments, nil
+}
+
+// ProjectedDividend es el cobro estimado de una tenencia en un mes de los próximos doce
+type ProjectedDividend struct {
+	Symbol   string
+	Month    time.Time // Primer día del mes estimado de cobro
+	Currency string    // Moneda en que paga: ARS para BYMA, USD para el resto
+	Amount   float64   // Total a cobrar por la tenencia, en su moneda
+}
+
+// projectDividends repite en los próximos doce meses los dividendos pagados en los últimos doce,
+// en el mismo mes y por el mismo monto por acción
+func projectDividends(h pricedHolding, payments []DividendPayment, now time.Time) []ProjectedDividend {
+	var projected []ProjectedDividend
+	from := now.AddDate(-1, 0, 0)
+	for _, p := range payments {
+		if !p.Date.After(from) || p.Date.After(now) {
+			continue
+		}
+		next := p.Date.AddDate(1, 0, 0)
+		month := time.Date(next.Year(), next.Month(), 1, 0, 0, 0, 0, time.UTC)
+		projected = append(projected, ProjectedDividend{
+			Symbol: h.Symbol, Month: month, Currency: h.Currency, Amount: p.Amount * h.Quantity,
+		})
+	}
+	return projected
+}
+
+// displayDividendProjection muestra el resumen por tenencia y el calendario mes a mes, en USD y en pesos al MEP
+func displayDividendProjection(holdings []pricedHolding, projected []ProjectedDividend, mep float64, now time.Time) {
+	toARS := func(amount float64, currency string) float64 {
+		if currency == "USD" {
+			return amount * mep
+		}
+		return amount
+	}
+
+	bySymbol := make(map[string]float64)
+	for _, d := range projected {
+		bySymbol[d.Symbol] += d.Amount
+	}
+
+	fmt.Printf("\n%s=== DIVIDENDOS PROYECTADOS (12 MESES) ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-10s %14s %7s %10s %16s\n", "Símbolo", "Por año", "Moneda", "Yield", "En pesos")
+	var totalARS float64
+	for _, h := range holdings {
+		amount, ok := bySymbol[h.Symbol]
+		if !ok {
+			continue
+		}
+		ars := toARS(amount, h.Currency)
+		totalARS += ars
+		yield := 0.0
+		if h.Value > 0 {
+			yield = ars / h.Value * 100
+		}
+		fmt.Printf("%-10s %14.2f %7s %s%9.2f%%%s %16.2f\n", h.Symbol, amount, h.Currency, Green, yield, Reset, ars)
+	}
+	var portfolioValue float64
+	for _, h := range holdings {
+		portfolioValue += h.Value
+	}
+	fmt.Printf("\n%sTotal anual: $%.2f  (USD %.2f al MEP de $%.2f)%s\n", Green, totalARS, totalARS/mep, mep, Reset)
+	if portfolioValue > 0 {
+		fmt.Printf("Yield de la cartera: %.2f%%\n", totalARS/portfolioValue*100)
+	}
+
+	fmt.Printf("\n%s=== CALENDARIO DE COBROS ===%s\n\n", Cyan, Reset)
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	var maxMonth float64
+	months := make(map[string]float64)
+	symbols := make(map[string][]string)
+	for _, d := range projected {
+		key := d.Month.Format("2006-01")
+		months[key] += toARS(d.Amount, d.Currency)
+		maxMonth = max(maxMonth, months[key])
+		if !contains(symbols[key], d.Symbol) {
+			symbols[key] = append(symbols[key], d.Symbol)
+		}
+	}
+	// Un pago de este mes ya cobrado se repite dentro de un año: el calendario suma ese mes solo si tiene cobros
+	for i := 0; i <= 12; i++ {
+		month := thisMonth.AddDate(0, i, 0)
+		key := month.Format("2006-01")
+		amount := months[key]
+		if i == 12 && amount == 0 {
+			break
+		}
+		bar := ""
+		if maxMonth > 0 {
+			bar = strings.Repeat("■", int(amount/maxMonth*20+0.5))
+		}
+		fmt.Printf("%-8s %s%-20s%s %14.2f %10.2f  %s\n", month.Format("01/2006"), Green, bar, Reset, amount, amount/mep, strings.Join(symbols[key], " "))
+	}
+	fmt.Println("\nMontos en pesos y en USD al MEP; los meses y montos repiten los pagos de los últimos doce meses.")
+}
+
+// runDividends implementa `bolsa dividends`: proyección anual de dividendos de la cartera y calendario de cobros
+func runDividends(args []string) error {
+	fs := flag.NewFlagSet("dividends", flag.ExitOnError)
+	fs.Parse(args)
+
+	portfolio, err := loadPortfolio()
+	if err != nil {
+		return err
+	}
+	client := NewHTTPClient()
+	mep, err := liveMEP(client)
+	if err != nil {
+		return fmt.Errorf("no se pudo calcular el dólar MEP: %v", err)
+	}
+	holdings, err := pricePortfolio(portfolio, client)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	var projected []ProjectedDividend
+	for _, h := range holdings {
+		// Los bonos pagan renta y amortización, no dividendos: su cronograma está en bolsa calendar
+		if h.Class == bondAssetClass {
+			continue
+		}
+		payments, err := getDividends(h.Symbol, "2y", client)
+		if err != nil {
+			fmt.Printf("%s%s: %v%s\n", Red, h.Symbol, err, Reset)
+			continue
+		}
+		projected = append(projected, projectDividends(h, payments, now)...)
+	}
+	if len(projected) == 0 {
+		fmt.Println("Ninguna tenencia pagó dividendos en los últimos doce meses.")
+		return nil
+	}
+	displayDividendProjection(holdings, projected, mep, now)
+	return nil
+}
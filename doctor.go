@@ -0,0 +1,238 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Diferencia con la hora de los servidores a partir de la cual las marcas de tiempo dejan de ser confiables
+const maxClockSkew = 2 * time.Minute
+
+// doctorProvider es un proveedor de datos a diagnosticar con la URL que se prueba
+type doctorProvider struct {
+	Name     string // Proveedor en config.json (timeouts y reintentos)
+	Label    string
+	URL      string
+	Optional bool // Su falla es una advertencia: solo se usa en algunas funciones
+}
+
+// Proveedores que usa el programa; la URL de prueba es un endpoint liviano de cada uno
+var doctorProviders = []doctorProvider{
+	{Name: "yahoo", Label: "Yahoo Finance (cotizaciones)", URL: "https://query2.finance.yahoo.com/v8/finance/chart/ARS=X?range=1d&interval=1d"},
+	{Name: "yahoo", Label: "Yahoo Finance (quoteSummary)", URL: "https://query1.finance.yahoo.com/v10/finance/quoteSummary/YPF?modules=price", Optional: true},
+	{Name: "bcra", Label: "BCRA (tasas, CER, UVA, base monetaria y reservas)", URL: "https://api.bcra.gob.ar/estadisticas/v3.0/monetarias", Optional: true},
+	{Name: "cafci", Label: "CAFCI (fondos comunes)", URL: "https://api.cafci.org.ar/", Optional: true},
+	{Name: "iol", Label: "InvertirOnline (puntas BYMA)", URL: "https://api.invertironline.com/", Optional: true},
+	{Name: "byma", Label: "BYMA datos abiertos (comparación de fuentes)", URL: "https://open.bymadata.com.ar/", Optional: true},
+	{Name: "telegram", Label: "Telegram (notificaciones)", URL: "https://api.telegram.org/", Optional: true},
+	{Name: "github", Label: "GitHub (actualizaciones)", URL: "https://api.github.com/", Optional: true},
+}
+
+// DoctorCheck es el resultado de una verificación de `bolsa doctor`
+type DoctorCheck struct {
+	Name   string
+	OK     bool
+	Warn   bool // Falla no bloqueante
+	Detail string
+}
+
+// doctorReport acumula los resultados y los muestra a medida que se obtienen
+type doctorReport struct {
+	checks []DoctorCheck
+}
+
+func (r *doctorReport) add(check DoctorCheck) {
+	r.checks = append(r.checks, check)
+	mark, color := "✅", Green
+	switch {
+	case check.Warn:
+		mark, color = "⚠️ ", Yellow
+	case !check.OK:
+		mark, color = "❌", Red
+	}
+	fmt.Printf("%s %-32s %s%s%s\n", mark, check.Name, color, check.Detail, Reset)
+}
+
+func (r *doctorReport) pass(name, format string, args ...interface{}) {
+	r.add(DoctorCheck{Name: name, OK: true, Detail: fmt.Sprintf(format, args...)})
+}
+
+func (r *doctorReport) fail(name string, optional bool, format string, args ...interface{}) {
+	r.add(DoctorCheck{Name: name, Warn: optional, Detail: fmt.Sprintf(format, args...)})
+}
+
+// failures cuenta las verificaciones fallidas que no son advertencias
+func (r *doctorReport) failures() int {
+	n := 0
+	for _, c := range r.checks {
+		if !c.OK && !c.Warn {
+			n++
+		}
+	}
+	return n
+}
+
+// checkDNS resuelve el host de cada proveedor una sola vez
+func checkDNS(r *doctorReport) map[string]bool {
+	resolved := make(map[string]bool)
+	for _, p := range doctorProviders {
+		u, err := url.Parse(p.URL)
+		if err != nil {
+			continue
+		}
+		host := u.Hostname()
+		if _, done := resolved[host]; done {
+			continue
+		}
+		start := time.Now()
+		addrs, err := net.LookupHost(host)
+		if err != nil {
+			resolved[host] = false
+			r.fail("DNS "+host, p.Optional, "no resuelve: %v", err)
+			continue
+		}
+		resolved[host] = true
+		r.pass("DNS "+host, "%s (%v)", addrs[0], time.Since(start).Round(time.Millisecond))
+	}
+	return resolved
+}
+
+// checkProvider hace un request real al proveedor y devuelve la hora informada por el servidor
+func checkProvider(r *doctorReport, p doctorProvider) (time.Time, bool) {
+	client := NewProviderClient(p.Name)
+	start := time.Now()
+	resp, err := client.GetWithRetry(p.URL, map[string]string{"User-Agent": "Mozilla/5.0", "Accept": "application/json"})
+	if err != nil {
+		r.fail(p.Label, p.Optional, "%v", err)
+		return time.Time{}, false
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	elapsed := time.Since(start).Round(time.Millisecond)
+	serverTime, _ := http.ParseTime(resp.Header.Get("Date"))
+
+	// Cualquier respuesta del servidor prueba la conectividad; para Yahoo además hace falta un 200
+	if p.Name == "yahoo" && resp.StatusCode != http.StatusOK {
+		r.fail(p.Label, p.Optional, "HTTP %d en %v", resp.StatusCode, elapsed)
+		return serverTime, false
+	}
+	r.pass(p.Label, "HTTP %d en %v", resp.StatusCode, elapsed)
+	return serverTime, true
+}
+
+// checkCrumb verifica que Yahoo entregue un crumb con la cookie del programa: sin él algunos endpoints devuelven 401
+func checkCrumb(r *doctorReport) {
+	client := NewHTTPClient()
+	resp, err := client.GetWithRetry("https://query2.finance.yahoo.com/v1/test/getcrumb", map[string]string{"User-Agent": "Mozilla/5.0"})
+	if err != nil {
+		r.fail("Crumb de Yahoo", true, "%v (las cotizaciones usan el endpoint v8, que no lo requiere)", err)
+		return
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	crumb := strings.TrimSpace(string(body))
+
+	if resp.StatusCode != http.StatusOK || crumb == "" || strings.HasPrefix(crumb, "{") {
+		r.fail("Crumb de Yahoo", true, "inválido (HTTP %d): los endpoints v10 pueden fallar y se usa v8", resp.StatusCode)
+		return
+	}
+	r.pass("Crumb de Yahoo", "válido (%d caracteres)", len(crumb))
+}
+
+// checkClock compara el reloj local con la hora informada por los proveedores
+func checkClock(r *doctorReport, serverTimes []time.Time) {
+	if len(serverTimes) == 0 {
+		r.fail("Reloj del sistema", true, "no se pudo comparar: ningún proveedor informó la hora")
+		return
+	}
+	// El header Date tiene resolución de un segundo y llega con la latencia del request
+	skew := time.Since(serverTimes[0]).Round(time.Second)
+	if skew < 0 {
+		skew = -skew
+	}
+	if skew > maxClockSkew {
+		r.fail("Reloj del sistema", false, "desfasado %v respecto de los servidores: sincronizar con NTP", skew)
+		return
+	}
+	r.pass("Reloj del sistema", "diferencia de %v con los servidores (%s)", skew, time.Now().In(argentinaLocation).Format("15:04:05 MST"))
+}
+
+// checkWritable verifica que se puedan escribir el estado, el historial y la configuración
+func checkWritable(r *doctorReport) {
+	dir, err := appDir()
+	if err != nil {
+		r.fail("Directorio de datos", false, "%v", err)
+		return
+	}
+
+	for _, d := range []string{dir, filepath.Join(dir, historyDir)} {
+		if err := os.MkdirAll(d, 0o755); err != nil {
+			r.fail("Escritura "+d, false, "%v", err)
+			continue
+		}
+		probe, err := os.CreateTemp(d, ".doctor-*")
+		if err != nil {
+			r.fail("Escritura "+d, false, "sin permisos de escritura: %v", err)
+			continue
+		}
+		probe.Close()
+		os.Remove(probe.Name())
+		r.pass("Escritura "+filepath.Base(d), "%s", d)
+	}
+
+	if path, err := configPath(); err == nil {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			r.pass("Configuración", "sin %s: se usan los valores por defecto (crear con bolsa init)", path)
+		} else if _, err := loadConfig(); err != nil {
+			r.fail("Configuración", false, "%v (detalle con bolsa config check)", err)
+		} else {
+			r.pass("Configuración", "%s", path)
+		}
+	}
+}
+
+// runDoctor implementa `bolsa doctor`: diagnóstico de DNS, proveedores, crumb, reloj y permisos de escritura
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	offline := fs.Bool("offline", false, "solo verificar permisos y configuración, sin usar la red")
+	fs.Parse(args)
+
+	r := &doctorReport{}
+	fmt.Printf("%s=== DIAGNÓSTICO (bolsa %s, %s) ===%s\n\n", Cyan, version, time.Now().Format("2006-01-02 15:04:05"), Reset)
+
+	checkWritable(r)
+	if !*offline {
+		resolved := checkDNS(r)
+
+		var serverTimes []time.Time
+		for _, p := range doctorProviders {
+			u, _ := url.Parse(p.URL)
+			if !resolved[u.Hostname()] {
+				r.fail(p.Label, p.Optional, "omitido: el host no resuelve")
+				continue
+			}
+			if t, _ := checkProvider(r, p); !t.IsZero() {
+				serverTimes = append(serverTimes, t)
+			}
+		}
+		if resolved["query2.finance.yahoo.com"] {
+			checkCrumb(r)
+		}
+		checkClock(r, serverTimes)
+	}
+
+	if n := r.failures(); n > 0 {
+		return fmt.Errorf("%d verificaciones fallidas", n)
+	}
+	fmt.Printf("\n%sTodo en orden.%s\n", Green, Reset)
+	return nil
+}
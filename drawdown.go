@@ -0,0 +1,220 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// portfolioSymbol identifica a la cartera de bonos en los reportes y alertas de drawdown
+const portfolioSymbol = "CARTERA"
+
+// Drawdown resume las caídas desde máximos de una serie
+type Drawdown struct {
+	Symbol      string
+	Peak        float64   // Máximo histórico de la serie
+	PeakTime    time.Time // Fecha del máximo histórico
+	Current     float64   // Último valor
+	CurrentDD   float64   // Caída actual desde el máximo, en % (negativo o cero)
+	MaxDD       float64   // Peor caída de la serie, en %
+	MaxDDPeak   time.Time // Máximo previo a la peor caída
+	MaxDDTrough time.Time // Piso de la peor caída
+}
+
+// computeDrawdown calcula el drawdown actual y el máximo de una serie histórica
+func computeDrawdown(symbol string, points []HistoryPoint) (Drawdown, bool) {
+	if len(points) == 0 {
+		return Drawdown{}, false
+	}
+
+	dd := Drawdown{Symbol: symbol, Peak: points[0].Close, PeakTime: points[0].Time}
+	for _, p := range points {
+		if p.Close > dd.Peak {
+			dd.Peak, dd.PeakTime = p.Close, p.Time
+		}
+		if dd.Peak <= 0 {
+			continue
+		}
+		if drop := (p.Close/dd.Peak - 1) * 100; drop < dd.MaxDD {
+			dd.MaxDD, dd.MaxDDPeak, dd.MaxDDTrough = drop, dd.PeakTime, p.Time
+		}
+	}
+
+	last := points[len(points)-1]
+	dd.Current = last.Close
+	if dd.Peak > 0 {
+		dd.CurrentDD = (last.Close/dd.Peak - 1) * 100
+	}
+	return dd, true
+}
+
+// runDrawdown implementa `bolsa drawdown [SIMBOLOS...]`: drawdown actual y máximo de cada activo y de la cartera
+func runDrawdown(args []string) error {
+	fs := flag.NewFlagSet("drawdown", flag.ExitOnError)
+	rangeStr := fs.String("range", "5y", "rango del histórico (1y, 5y, max...)")
+	threshold := fs.Float64("threshold", 20, "resaltar los activos con drawdown actual mayor a este porcentaje")
+	fs.Parse(args)
+
+	// Sin símbolos se usa la watchlist activa más la cartera de bonos
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		for _, stock := range watchlistSnapshot() {
+			symbols = append(symbols, stock.Symbol)
+		}
+		symbols = append(symbols, portfolioSymbol)
+	}
+
+	client := NewHTTPClient()
+	var results []Drawdown
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+
+		var points []HistoryPoint
+		var err error
+		if symbol == portfolioSymbol {
+			points, err = portfolioHistory(*rangeStr, client)
+		} else {
+			points, err = getHistory(symbol, *rangeStr, "1d", client)
+		}
+		if err != nil {
+			fmt.Printf("%s%s: %v%s\n", Red, symbol, err, Reset)
+			continue
+		}
+		if dd, ok := computeDrawdown(symbol, points); ok {
+			results = append(results, dd)
+		}
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("no se pudo calcular el drawdown de ningún activo")
+	}
+
+	fmt.Printf("\n%s=== DRAWDOWN (%s) ===%s\n\n", Cyan, *rangeStr, Reset)
+	fmt.Printf("%-10s %12s %-11s %10s %10s  %s\n", "Símbolo", "Máximo", "Fecha", "Actual", "DD actual", "DD máximo")
+	for _, dd := range results {
+		color := White
+		if -dd.CurrentDD >= *threshold {
+			color = Red
+		}
+		fmt.Printf("%-10s %12.2f %-11s %10.2f %s%9.1f%%%s  %.1f%% (%s → %s)\n",
+			dd.Symbol, dd.Peak, dd.PeakTime.Format("2006-01-02"), dd.Current,
+			color, dd.CurrentDD, Reset,
+			dd.MaxDD, dd.MaxDDPeak.Format("2006-01-02"), dd.MaxDDTrough.Format("2006-01-02"))
+	}
+	return nil
+}
+
+// DrawdownWatcher alerta cuando un activo o la cartera cae más del umbral desde su máximo del último año
+type DrawdownWatcher struct {
+	Threshold float64 // En %; 0 desactiva
+
+	mu       sync.Mutex
+	peaks    map[string]float64 // Máximo del último año por símbolo, en la moneda de origen
+	peaksDay string             // Día en que se calcularon los máximos
+	alerted  map[string]string  // Último día alertado por símbolo
+}
+
+// NewDrawdownWatcher crea el vigilante de drawdown
+func NewDrawdownWatcher(threshold float64) *DrawdownWatcher {
+	return &DrawdownWatcher{
+		Threshold: threshold,
+		peaks:     make(map[string]float64),
+		alerted:   make(map[string]string),
+	}
+}
+
+// peak devuelve el máximo del último año de un símbolo, consultándolo una vez por día
+func (w *DrawdownWatcher) peak(symbol string, client QuoteFetcher) (float64, bool) {
+	if p, ok := w.peaks[symbol]; ok {
+		return p, p > 0
+	}
+
+	var points []HistoryPoint
+	var err error
+	if symbol == portfolioSymbol {
+		points, err = portfolioHistory("1y", client)
+	} else {
+		points, err = getHistory(symbol, "1y", "1d", client)
+	}
+
+	// Un error se guarda como 0 para no reintentar en cada ciclo
+	w.peaks[symbol] = 0
+	if err != nil {
+		return 0, false
+	}
+	for _, p := range points {
+		if p.Close > w.peaks[symbol] {
+			w.peaks[symbol] = p.Close
+		}
+	}
+	return w.peaks[symbol], w.peaks[symbol] > 0
+}
+
+// Check compara los precios del snapshot contra los máximos y alerta una vez por día y símbolo
+func (w *DrawdownWatcher) Check(snapshot *Snapshot, notifiers []Notifier, client QuoteFetcher) {
+	if w == nil || w.Threshold <= 0 {
+		return
+	}
+
+	now := time.Now()
+	if !alertAllowed("drawdown", now) {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	today := now.Format("2006-01-02")
+	if w.peaksDay != today {
+		w.peaks = make(map[string]float64)
+		w.peaksDay = today
+	}
+
+	// Las acciones de NYSE se muestran en pesos: el precio de origen sale del cierre anterior y la variación
+	current := make(map[string]float64)
+	for _, stock := range snapshot.Stocks {
+		if stock.PreviousClose > 0 {
+			current[stock.Symbol] = stock.PreviousClose * (1 + stock.ChangePercent/100)
+		}
+	}
+	var portfolio float64
+	for _, holding := range bondHoldings {
+		for _, q := range snapshot.Bonds {
+			if q.Bond.Symbol == holding.Symbol {
+				portfolio += holding.Nominal * q.CleanPrice / 100
+			}
+		}
+	}
+	if portfolio > 0 {
+		current[portfolioSymbol] = portfolio
+	}
+
+	var alerts []Alert
+	for symbol, price := range current {
+		if w.alerted[symbol] == today {
+			continue
+		}
+		peak, ok := w.peak(symbol, client)
+		if !ok || price >= peak {
+			continue
+		}
+		drop := (price/peak - 1) * 100
+		if -drop < w.Threshold {
+			continue
+		}
+
+		w.alerted[symbol] = today
+		alerts = append(alerts, Alert{
+			Rule:     "drawdown",
+			Severity: SeverityWarning,
+			Symbol:   symbol,
+			Value:    drop,
+			Message:  fmt.Sprintf("%s está %.1f%% debajo de su máximo del último año (%.2f)", symbol, -drop, peak),
+			Time:     now,
+		})
+	}
+
+	dispatchAlerts(notifiers, fmt.Sprintf("Drawdown mayor a %.0f%%", w.Threshold), alerts, snapshot)
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Manifiesto de una descarga masiva: qué símbolos quedaron completos, para reanudar después de un corte
+const dumpManifestFile = "manifest.json"
+
+// DumpEntry es el resultado de la descarga de un símbolo
+type DumpEntry struct {
+	Range      string    `json:"range"`
+	Interval   string    `json:"interval"`
+	Rows       int       `json:"rows"`
+	First      string    `json:"first,omitempty"`
+	Last       string    `json:"last,omitempty"`
+	Warnings   []string  `json:"warnings,omitempty"` // Huecos y bordes faltantes detectados al verificar la serie
+	Real       string    `json:"real,omitempty"`     // Índice con el que se deflactó la serie en pesos (cer o ipc)
+	Downloaded time.Time `json:"downloaded"`
+}
+
+// Duración nominal de cada vela diaria o mayor; las intradiarias no se verifican (tienen noches y fines de semana)
+var dumpIntervalSpans = map[string]time.Duration{
+	"1d":  24 * time.Hour,
+	"5d":  5 * 24 * time.Hour,
+	"1wk": 7 * 24 * time.Hour,
+	"1mo": 31 * 24 * time.Hour,
+	"3mo": 92 * 24 * time.Hour,
+}
+
+// loadDumpManifest lee el manifiesto de un directorio de descarga; vacío si todavía no existe
+func loadDumpManifest(dir string) (map[string]DumpEntry, error) {
+	manifest := make(map[string]DumpEntry)
+	path := filepath.Join(dir, dumpManifestFile)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return manifest, nil
+	}
+	if err != nil {
+		return manifest, err
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return manifest, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return manifest, nil
+}
+
+// writeFileAtomic escribe un archivo completo o nada: un corte a mitad de camino no deja datos truncados
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// saveDumpManifest guarda el manifiesto después de cada símbolo
+func saveDumpManifest(dir string, manifest map[string]DumpEntry) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(filepath.Join(dir, dumpManifestFile), data)
+}
+
+// dumpFile es el CSV de un símbolo dentro del directorio de descarga
+func dumpFile(dir, symbol string) string {
+	return filepath.Join(dir, strings.NewReplacer("/", "_", "\\", "_").Replace(symbol)+".csv")
+}
+
+// dumpSymbols resuelve --symbols: all (catálogo, watchlist y tipos de cambio), watchlist o una lista separada por comas
+func dumpSymbols(spec string) []string {
+	seen := make(map[string]bool)
+	var symbols []string
+	add := func(symbol string) {
+		symbol = strings.ToUpper(strings.TrimSpace(symbol))
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	switch strings.ToLower(spec) {
+	case "all", "watchlist":
+		applyWatchedSymbols()
+		for _, stock := range watchlistSnapshot() {
+			add(stock.Symbol)
+		}
+		if strings.ToLower(spec) == "all" {
+			for _, entry := range catalog {
+				add(entry.Symbol)
+			}
+			for _, forex := range forexSymbols {
+				add(forex["symbol"])
+			}
+		}
+	default:
+		for _, symbol := range strings.Split(spec, ",") {
+			add(symbol)
+		}
+	}
+	return symbols
+}
+
+// rangeStart devuelve desde cuándo debería arrancar una serie pedida con un rango de Yahoo (5d, 6mo, 5y, ytd)
+func rangeStart(now time.Time, rangeStr string) (time.Time, bool) {
+	if rangeStr == "ytd" {
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location()), true
+	}
+	for _, unit := range []string{"mo", "d", "y"} {
+		n, err := strconv.Atoi(strings.TrimSuffix(rangeStr, unit))
+		if !strings.HasSuffix(rangeStr, unit) || err != nil || n <= 0 {
+			continue
+		}
+		switch unit {
+		case "d":
+			return now.AddDate(0, 0, -n), true
+		case "mo":
+			return now.AddDate(0, -n, 0), true
+		default:
+			return now.AddDate(-n, 0, 0), true
+		}
+	}
+	return time.Time{}, false // max u otro rango sin inicio conocido
+}
+
+// verifyDump revisa que una serie cubra el rango pedido y no tenga huecos mayores a tres velas
+func verifyDump(points []HistoryPoint, rangeStr, interval string, now time.Time) []string {
+	if len(points) == 0 {
+		return []string{"la serie está vacía"}
+	}
+	span, ok := dumpIntervalSpans[interval]
+	if !ok {
+		return nil
+	}
+	maxGap := 3 * span
+	if maxGap < 7*24*time.Hour {
+		maxGap = 7 * 24 * time.Hour // Feriados largos y fines de semana no cuentan como huecos
+	}
+
+	var warnings []string
+	first, last := points[0].Time, points[len(points)-1].Time
+	if start, ok := rangeStart(now, rangeStr); ok && first.Sub(start) > maxGap {
+		warnings = append(warnings, fmt.Sprintf("arranca el %s: cotiza desde entonces o Yahoo no tiene datos anteriores", first.Format("2006-01-02")))
+	}
+	for i := 1; i < len(points); i++ {
+		if gap := points[i].Time.Sub(points[i-1].Time); gap > maxGap {
+			warnings = append(warnings, fmt.Sprintf("hueco de %d días entre %s y %s", int(gap.Hours()/24),
+				points[i-1].Time.Format("2006-01-02"), points[i].Time.Format("2006-01-02")))
+		}
+	}
+	if now.Sub(last) > maxGap {
+		warnings = append(warnings, fmt.Sprintf("termina el %s", last.Format("2006-01-02")))
+	}
+	return warnings
+}
+
+// writeDumpCSV escribe la serie de un símbolo: una fila por vela
+func writeDumpCSV(path string, points []HistoryPoint) error {
+	var b strings.Builder
+	out := csv.NewWriter(&b)
+	out.Write([]string{"time", "open", "close", "volume"})
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, p := range points {
+		out.Write([]string{p.Time.In(argentinaLocation).Format(time.RFC3339), format(p.Open), format(p.Close), strconv.FormatInt(p.Volume, 10)})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	return writeFileAtomic(path, []byte(b.String()))
+}
+
+// runDump implementa `bolsa dump`: descarga masiva de históricos a CSV, reanudable y verificada
+func runDump(args []string) error {
+	fs := flag.NewFlagSet("dump", flag.ExitOnError)
+	symbolsSpec := fs.String("symbols", "watchlist", "símbolos a descargar: all, watchlist o una lista separada por comas")
+	rangeStr := fs.String("range", "5y", "rango de Yahoo (1mo, 6mo, 1y, 5y, ytd, max...)")
+	interval := fs.String("interval", "1d", "intervalo de las velas (1d, 1wk, 1mo...)")
+	out := fs.String("out", "", "directorio de salida: un CSV por símbolo y manifest.json")
+	delay := fs.Duration("delay", 2*time.Second, "espera entre símbolos para no saturar a Yahoo")
+	force := fs.Bool("force", false, "volver a descargar también los símbolos ya completos en el manifiesto")
+	real := fs.Bool("real", false, "deflactar las series en pesos a pesos constantes de su última fecha (las de dólares quedan nominales)")
+	index := fs.String("index", "cer", "índice para --real: cer (diario) o ipc (mensual)")
+	fs.Parse(args)
+
+	if *out == "" {
+		return fmt.Errorf("uso: bolsa dump [--symbols all|watchlist|SIM1,SIM2] [--range 5y] [--interval 1d] [--delay 2s] [--force] [--real [--index cer|ipc]] --out DIR")
+	}
+	symbols := dumpSymbols(*symbolsSpec)
+	if len(symbols) == 0 {
+		return fmt.Errorf("no hay símbolos para descargar")
+	}
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		return err
+	}
+	manifest, err := loadDumpManifest(*out)
+	if err != nil {
+		return err
+	}
+
+	if *real && *index != "cer" && *index != "ipc" {
+		return fmt.Errorf("índice desconocido %q (usar cer o ipc)", *index)
+	}
+	// Índice con el que se ajusta cada símbolo: solo los que cotizan en pesos
+	realFor := func(symbol string) string {
+		if *real && quotedInPesos(symbol) {
+			return *index
+		}
+		return ""
+	}
+
+	client := NewHTTPClient()
+	bcraClient := NewProviderClient("bcra")
+	var deflator *Deflator
+	var deflatorFrom time.Time
+	var failed []string
+	downloaded, skipped := 0, 0
+	requested := false
+	for i, symbol := range symbols {
+		prefix := fmt.Sprintf("[%d/%d] %s", i+1, len(symbols), symbol)
+
+		// Reanudación: lo que ya quedó completo con los mismos parámetros no se vuelve a pedir
+		if entry, ok := manifest[symbol]; ok && !*force && entry.Range == *rangeStr && entry.Interval == *interval && entry.Real == realFor(symbol) {
+			if _, err := os.Stat(dumpFile(*out, symbol)); err == nil {
+				skipped++
+				continue
+			}
+		}
+
+		if requested {
+			time.Sleep(*delay)
+		}
+		requested = true
+
+		now := time.Now()
+		points, err := getHistory(symbol, *rangeStr, *interval, client)
+		if errors.Is(err, ErrRateLimited) {
+			// Seguir solo consumiría reintentos: lo pendiente se retoma corriendo el mismo comando más tarde
+			fmt.Printf("%s%s: %v%s\n", Red, prefix, err, Reset)
+			failed = append(failed, symbols[i:]...)
+			break
+		}
+		if err == nil && realFor(symbol) != "" && len(points) > 0 {
+			// El índice se descarga una vez y solo se vuelve a pedir si una serie empieza antes
+			if deflator == nil || points[0].Time.Before(deflatorFrom) {
+				deflator, err = newDeflator(*index, points[0].Time, now, bcraClient)
+				deflatorFrom = points[0].Time
+			}
+			if err == nil {
+				points = deflator.Apply(points)
+			}
+		}
+		if err == nil {
+			err = writeDumpCSV(dumpFile(*out, symbol), points)
+		}
+		if err != nil {
+			fmt.Printf("%s%s: %v%s\n", Red, prefix, err, Reset)
+			failed = append(failed, symbol)
+			continue
+		}
+
+		entry := DumpEntry{Range: *rangeStr, Interval: *interval, Rows: len(points), Real: realFor(symbol), Downloaded: now}
+		if len(points) > 0 {
+			entry.First = points[0].Time.In(argentinaLocation).Format("2006-01-02")
+			entry.Last = points[len(points)-1].Time.In(argentinaLocation).Format("2006-01-02")
+		}
+		entry.Warnings = verifyDump(points, *rangeStr, *interval, now)
+		manifest[symbol] = entry
+		if err := saveDumpManifest(*out, manifest); err != nil {
+			return err
+		}
+		downloaded++
+
+		fmt.Printf("%s: %d filas (%s → %s)\n", prefix, entry.Rows, entry.First, entry.Last)
+		for _, warning := range entry.Warnings {
+			fmt.Printf("  %s⚠️ %s%s\n", Yellow, warning, Reset)
+		}
+	}
+
+	fmt.Printf("\n%d símbolos descargados, %d ya completos en %s\n", downloaded, skipped, *out)
+	incomplete := 0
+	for _, entry := range manifest {
+		if len(entry.Warnings) > 0 {
+			incomplete++
+		}
+	}
+	if incomplete > 0 {
+		fmt.Printf("%s%d series con huecos o bordes faltantes: ver warnings en %s%s\n", Yellow, incomplete, filepath.Join(*out, dumpManifestFile), Reset)
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("%d símbolos sin descargar (%s): volvé a correr el mismo comando para reanudar", len(failed), strings.Join(failed, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Margen antes de la apertura y después del cierre en que se sigue consultando todo al ritmo normal
+const activeMargin = 30 * time.Minute
+
+// Mercados cuyo horario define el modo económico
+var trackedMarkets = []MarketHours{nyseHours, bymaHours}
+
+// marketsActive indica si algún mercado está en rueda o dentro del margen previo a la apertura o posterior al cierre
+func marketsActive(t time.Time) bool {
+	for _, m := range trackedMarkets {
+		if !m.IsTradingDay(t) {
+			continue
+		}
+		open, close := m.sessionTimes(t)
+		if !t.Before(open.Add(-activeMargin)) && t.Before(close.Add(activeMargin)) {
+			return true
+		}
+	}
+	return false
+}
+
+// nextActiveStart devuelve cuándo empieza la próxima ventana activa de algún mercado
+func nextActiveStart(t time.Time) time.Time {
+	var next time.Time
+	// Dos semanas alcanzan para saltar un fin de semana largo con feriados y puentes
+	for days := 0; days <= 14; days++ {
+		day := t.AddDate(0, 0, days)
+		for _, m := range trackedMarkets {
+			if !m.IsTradingDay(day) {
+				continue
+			}
+			open, _ := m.sessionTimes(day)
+			start := open.Add(-activeMargin)
+			if start.After(t) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+		if !next.IsZero() {
+			return next
+		}
+	}
+	return next
+}
+
+// offHoursWait devuelve la espera fuera de horario, recortada para no perderse el inicio de la próxima rueda
+func offHoursWait(now time.Time, interval time.Duration) time.Duration {
+	if next := nextActiveStart(now); !next.IsZero() && next.Sub(now) < interval {
+		return next.Sub(now)
+	}
+	return interval
+}
+
+// economyMode indica si el ciclo debe consultar solo lo que opera 24 h
+func economyMode(now time.Time) bool {
+	return appConfig().OffHoursInterval > 0 && !marketsActive(now)
+}
+
+// refreshOffHours actualiza solo tipos de cambio, cripto y el estado de los mercados;
+// acciones, bonos y fondos se mantienen del snapshot anterior, que ya tiene los precios de cierre
+func refreshOffHours(client QuoteFetcher, previous *Snapshot) (*Snapshot, error) {
+	fmt.Println("\n=== CICLO FUERA DE HORARIO (solo tipos de cambio y cripto) ===")
+	tickerCache.Reset()
+	errs := &fetchErrors{}
+	resetCycleLog()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(appConfig().WatchdogTimeout))
+	defer cancel()
+	forexData, err := getForexData(ctx, client, errs)
+	fmt.Printf("%s, %d consultas fallidas\n", cycleLogSummary(), len(errs.all()))
+	if err != nil {
+		return nil, err
+	}
+	if len(forexData) == 0 {
+		return nil, fmt.Errorf("no hay tipos de cambio configurados")
+	}
+
+	now := time.Now()
+	snapshot := *previous
+	snapshot.Time = now
+	snapshot.Forex = forexData
+	snapshot.Status = currentMarketStatus(now, client)
+	snapshot.Errors = errs.all()
+	snapshot.OrderBooks = nil // Las puntas solo tienen sentido con la rueda abierta
+	snapshot.Cached = false
+	snapshot.Quality = computeQuality(&Snapshot{Forex: forexData}, len(forexSymbols), now)
+	return &snapshot, nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailConfig es la configuración SMTP del canal de email
+type EmailConfig struct {
+	Host     string   `json:"host"`
+	Port     int      `json:"port"`
+	Username string   `json:"username"`
+	Password string   `json:"password"` // Si está vacío se usa BOLSA_SMTP_PASSWORD
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// EmailNotifier envía notificaciones por email a uno o más destinatarios
+type EmailNotifier struct {
+	config EmailConfig
+}
+
+// NewEmailNotifier crea el canal de email
+func NewEmailNotifier(config EmailConfig) *EmailNotifier {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	if config.Password == "" {
+		config.Password = os.Getenv("BOLSA_SMTP_PASSWORD")
+	}
+	return &EmailNotifier{config: config}
+}
+
+// Name devuelve el nombre del canal
+func (e *EmailNotifier) Name() string { return "email" }
+
+// Send envía la notificación como texto plano; los adjuntos se mencionan pero no se envían
+func (e *EmailNotifier) Send(n Notification) error {
+	var body strings.Builder
+	body.WriteString(n.Text)
+	for _, a := range n.Attachments {
+		fmt.Fprintf(&body, "\n(adjunto omitido: %s, %d bytes)", a.Name, len(a.Data))
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", n.Title)
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(body.String(), "\n", "\r\n"))
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+	addr := net.JoinHostPort(e.config.Host, strconv.Itoa(e.config.Port))
+	if err := smtp.SendMail(addr, auth, e.config.From, e.config.To, []byte(msg.String())); err != nil {
+		return fmt.Errorf("error al enviar email por %s: %v", addr, err)
+	}
+	return nil
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Las series del BCRA son diarias: se vuelven a pedir cada tantas horas, y antes si la última consulta falló
+const (
+	equilibriumRefresh = 6 * time.Hour
+	equilibriumRetry   = 30 * time.Minute
+)
+
+// EquilibriumConfig define el tipo de cambio de equilibrio: la suma de unas variables del BCRA sobre la de otras
+type EquilibriumConfig struct {
+	Disabled    bool    `json:"disabled"`
+	Label       string  `json:"label"`       // Descripción de la fórmula, para la pantalla
+	Numerator   []int   `json:"numerator"`   // Variables en millones de pesos (por defecto la base monetaria)
+	Denominator []int   `json:"denominator"` // Variables en millones de dólares (por defecto las reservas)
+	Coverage    float64 `json:"coverage"`    // Fracción de los pesos a respaldar; 1 es convertibilidad plena
+}
+
+// defaultEquilibrium es la convertibilidad teórica clásica: toda la base monetaria respaldada por las reservas
+var defaultEquilibrium = EquilibriumConfig{
+	Label:       "base monetaria / reservas",
+	Numerator:   []int{bcraVariableBase},
+	Denominator: []int{bcraVariableReservas},
+	Coverage:    1,
+}
+
+// validate completa con los valores por defecto lo que no define config.json
+func (c *EquilibriumConfig) validate() error {
+	if c.Coverage < 0 {
+		return fmt.Errorf("coverage debe ser positivo")
+	}
+	if c.Coverage == 0 {
+		c.Coverage = defaultEquilibrium.Coverage
+	}
+	if len(c.Numerator) == 0 {
+		c.Numerator = defaultEquilibrium.Numerator
+	}
+	if len(c.Denominator) == 0 {
+		c.Denominator = defaultEquilibrium.Denominator
+	}
+	if c.Label == "" {
+		c.Label = defaultEquilibrium.Label
+	}
+	return nil
+}
+
+// EquilibriumRate es el tipo de cambio de equilibrio del ciclo, comparado con el oficial
+type EquilibriumRate struct {
+	Label    string    `json:"label"`
+	Value    float64   `json:"value"`    // Pesos por dólar
+	Date     time.Time `json:"date"`     // Fecha del dato más viejo usado
+	Official float64   `json:"official"` // Dólar oficial del ciclo
+	Gap      float64   `json:"gap"`      // Equilibrio sobre el oficial, en %
+}
+
+// Último cálculo, reutilizado entre consultas al BCRA
+var (
+	equilibriumMu      sync.Mutex
+	equilibriumCache   *EquilibriumRate
+	equilibriumFetched time.Time
+	equilibriumFailed  bool
+)
+
+// sumBCRALatest suma el último valor publicado de cada variable y devuelve la fecha del más viejo
+func sumBCRALatest(variables []int, now time.Time, client QuoteFetcher) (float64, time.Time, error) {
+	var total float64
+	var oldest time.Time
+	for _, variable := range variables {
+		points, err := getBCRASeries(variable, now.AddDate(0, 0, -30), now, client)
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		last := points[len(points)-1]
+		total += last.Value
+		if oldest.IsZero() || last.Date.Before(oldest) {
+			oldest = last.Date
+		}
+	}
+	return total, oldest, nil
+}
+
+// computeEquilibrium calcula el tipo de cambio de equilibrio con las series del BCRA
+func computeEquilibrium(cfg EquilibriumConfig, now time.Time, client QuoteFetcher) (*EquilibriumRate, error) {
+	pesos, pesosDate, err := sumBCRALatest(cfg.Numerator, now, client)
+	if err != nil {
+		return nil, err
+	}
+	dollars, dollarsDate, err := sumBCRALatest(cfg.Denominator, now, client)
+	if err != nil {
+		return nil, err
+	}
+	if dollars <= 0 {
+		return nil, fmt.Errorf("el denominador del tipo de cambio de equilibrio es cero")
+	}
+	date := pesosDate
+	if dollarsDate.Before(date) {
+		date = dollarsDate
+	}
+	return &EquilibriumRate{Label: cfg.Label, Value: pesos * cfg.Coverage / dollars, Date: date}, nil
+}
+
+// getEquilibrium devuelve el tipo de cambio de equilibrio del ciclo; entre consultas al BCRA usa el último cálculo
+func getEquilibrium(forexData []ForexInfo, errs *fetchErrors) *EquilibriumRate {
+	cfg := defaultEquilibrium
+	if c := appConfig().Equilibrium; c != nil {
+		cfg = *c
+	}
+	if cfg.Disabled {
+		return nil
+	}
+
+	equilibriumMu.Lock()
+	defer equilibriumMu.Unlock()
+
+	now := time.Now()
+	wait := equilibriumRefresh
+	if equilibriumFailed {
+		wait = equilibriumRetry
+	}
+	if now.Sub(equilibriumFetched) >= wait {
+		equilibriumFetched = now
+		rate, err := computeEquilibrium(cfg, now, NewProviderClient("bcra"))
+		equilibriumFailed = err != nil
+		if err != nil {
+			errs.add("Equilibrio", "BCRA", err)
+		} else {
+			equilibriumCache = rate
+		}
+	}
+	if equilibriumCache == nil {
+		return nil
+	}
+
+	rate := *equilibriumCache
+	for _, forex := range forexData {
+		if forex.Symbol == "ARS=X" && forex.Price > 0 {
+			rate.Official = forex.Price
+			rate.Gap = (rate.Value/forex.Price - 1) * 100
+		}
+	}
+	return &rate
+}
+
+// displayEquilibrium muestra el tipo de cambio de equilibrio debajo de los dólares
+func displayEquilibrium(rate *EquilibriumRate) {
+	if rate == nil {
+		return
+	}
+	fmt.Printf("\n%sEquilibrio%s  $%.2f (%s, BCRA al %s)", Yellow, Reset, rate.Value, rate.Label, rate.Date.Format("02/01"))
+	if rate.Official > 0 {
+		fmt.Printf(" %+.1f%% sobre el oficial", rate.Gap)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Categorías de error de las consultas; se comparan con errors.Is a través de los envoltorios
+var (
+	ErrNetwork       = errors.New("error de red")
+	ErrRateLimited   = errors.New("límite de requests del proveedor")
+	ErrParse         = errors.New("respuesta ilegible")
+	ErrInvalidSymbol = errors.New("símbolo inválido")
+)
+
+// QuoteError es una consulta fallida con su categoría y la causa original
+type QuoteError struct {
+	Kind   error  // ErrNetwork, ErrRateLimited, ErrParse o ErrInvalidSymbol
+	Symbol string // Vacío si el error no corresponde a un símbolo
+	Status int    // Código HTTP, si hubo respuesta
+	Code   string // Error de Yahoo con política propia (delisted, not_found, 429, 999, 5xx)
+	Err    error
+}
+
+func (e *QuoteError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap expone la categoría y la causa, para que funcionen tanto errors.Is(err, ErrParse) como errors.As(err, &netErr)
+func (e *QuoteError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// kindForStatus clasifica una respuesta HTTP fallida
+func kindForStatus(status int) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusNotFound || status == http.StatusBadRequest:
+		return ErrInvalidSymbol
+	default:
+		return ErrNetwork
+	}
+}
+
+// statusError arma el error de una respuesta con código inesperado; detail describe la consulta
+func statusError(status int, detail string, args ...interface{}) error {
+	msg := fmt.Sprintf("código de estado HTTP inesperado: %d", status)
+	if detail != "" {
+		msg += " " + fmt.Sprintf(detail, args...)
+	}
+	return &QuoteError{Kind: kindForStatus(status), Status: status, Err: errors.New(msg)}
+}
+
+// symbolError marca un error como propio del símbolo (inexistente o sin datos en el proveedor)
+func symbolError(symbol string, err error) error {
+	return &QuoteError{Kind: ErrInvalidSymbol, Symbol: symbol, Err: err}
+}
+
+// errorKindName devuelve el nombre corto de la categoría de un error, tal como se guarda en FetchError.Kind
+func errorKindName(err error) string {
+	switch {
+	case errors.Is(err, ErrRateLimited):
+		return "rate-limit"
+	case errors.Is(err, ErrInvalidSymbol):
+		return "simbolo"
+	case errors.Is(err, ErrParse):
+		return "parseo"
+	case errors.Is(err, ErrNetwork):
+		return "red"
+	default:
+		return ""
+	}
+}
+
+// errorKindStyle devuelve la etiqueta, el color y la sugerencia con que la UI muestra cada categoría
+func errorKindStyle(kind string) (label, color, hint string) {
+	switch kind {
+	case "rate-limit":
+		return "límite", Magenta, "el proveedor limitó las consultas: aumentar \"interval\" en config.json"
+	case "simbolo":
+		return "símbolo", Yellow, "revisar la watchlist: el símbolo no existe o no tiene datos"
+	case "parseo":
+		return "formato", Cyan, "el proveedor cambió el formato de la respuesta"
+	case "red":
+		return "red", Red, "sin conexión con el proveedor: diagnosticar con bolsa doctor"
+	default:
+		return "error", White, ""
+	}
+}
+
+// cycleErrorHint sugiere qué hacer cuando falla un ciclo completo, según la causa
+func cycleErrorHint(err error) string {
+	_, _, hint := errorKindStyle(errorKindName(err))
+	if hint == "" {
+		hint = "para diagnosticar la conexión: bolsa doctor"
+	}
+	return hint
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clave Ed25519 para firmar exports, en el directorio de estado (solo la lee el usuario)
+const signingKeyFile = "signing.key"
+
+// ExportSignature es el archivo .sig que acompaña a un export: hash y, si hay clave, firma Ed25519
+type ExportSignature struct {
+	File      string    `json:"file"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+	Created   time.Time `json:"created"`
+	PublicKey string    `json:"publicKey,omitempty"` // Base64
+	Signature string    `json:"signature,omitempty"` // Base64, firma del hash SHA-256
+}
+
+// loadSigningKey lee la clave privada de firma; devuelve nil si todavía no se generó
+func loadSigningKey() (ed25519.PrivateKey, error) {
+	path, err := appFile(signingKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return nil, fmt.Errorf("clave de firma inválida en %s", path)
+	}
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
+// keyFingerprint identifica una clave pública con los primeros bytes de su hash
+func keyFingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return hex.EncodeToString(sum[:8])
+}
+
+// fileDigest calcula el SHA-256 y el tamaño de un archivo
+func fileDigest(path string) ([]byte, int64, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer file.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, file)
+	if err != nil {
+		return nil, 0, err
+	}
+	return h.Sum(nil), size, nil
+}
+
+// signFile escribe path.sig con el hash del archivo, firmado si hay clave generada
+func signFile(path string) (*ExportSignature, error) {
+	digest, size, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &ExportSignature{
+		File:    path,
+		Size:    size,
+		SHA256:  hex.EncodeToString(digest),
+		Created: time.Now(),
+	}
+
+	key, err := loadSigningKey()
+	if err != nil {
+		return nil, err
+	}
+	if key != nil {
+		sig.PublicKey = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+		sig.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(key, digest))
+	}
+
+	data, err := json.MarshalIndent(sig, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return sig, os.WriteFile(path+".sig", append(data, '\n'), 0o644)
+}
+
+// writeSnapshotsCSV exporta una fila por símbolo y snapshot
+func writeSnapshotsCSV(w io.Writer, snapshots []Snapshot) error {
+	out := csv.NewWriter(w)
+	out.Write([]string{"time", "section", "symbol", "name", "price", "change_percent", "volume"})
+
+	format := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+	for _, snapshot := range snapshots {
+		t := snapshot.Time.Format(time.RFC3339)
+		for _, fx := range snapshot.Forex {
+			out.Write([]string{t, "forex", fx.Symbol, fx.Name, format(fx.Price), format(fx.ChangePercent), ""})
+		}
+		for _, stock := range snapshot.Stocks {
+			out.Write([]string{t, "stocks", stock.Symbol, stock.Name, format(stock.Price), format(stock.ChangePercent), strconv.FormatInt(stock.Volume, 10)})
+		}
+		for _, bond := range snapshot.Bonds {
+			if bond.Bond != nil {
+				out.Write([]string{t, "bonds", bond.Bond.Symbol, bond.Bond.Name, format(bond.CleanPrice), "", ""})
+			}
+		}
+	}
+	out.Flush()
+	return out.Error()
+}
+
+// runExport implementa `bolsa export`: exporta los snapshots guardados de un día, opcionalmente firmados
+func runExport(args []string) error {
+	if len(args) > 0 {
+		switch args[0] {
+		case "keygen":
+			return runExportKeygen(args[1:])
+		case "sign":
+			if len(args) != 2 {
+				return fmt.Errorf("uso: bolsa export sign ARCHIVO")
+			}
+			sig, err := signFile(args[1])
+			if err != nil {
+				return err
+			}
+			printSignature(sig)
+			return nil
+		}
+	}
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	day := fs.String("date", time.Now().In(argentinaLocation).Format("2006-01-02"), "rueda a exportar (AAAA-MM-DD)")
+	format := fs.String("format", "json", "formato del export: json o csv")
+	output := fs.String("o", "", "archivo de salida")
+	sign := fs.Bool("sign", false, "escribir ARCHIVO.sig con el hash SHA-256 y la firma (ver bolsa export keygen)")
+	fs.Parse(args)
+
+	if *output == "" {
+		return fmt.Errorf("uso: bolsa export [--date AAAA-MM-DD] [--format json|csv] -o ARCHIVO [--sign] | keygen | sign ARCHIVO")
+	}
+	if *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido %q (usar json o csv)", *format)
+	}
+
+	snapshots, err := loadHistory(*day)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if *format == "csv" {
+		err = writeSnapshotsCSV(file, snapshots)
+	} else {
+		encoder := json.NewEncoder(file)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(snapshots)
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%d snapshots del %s exportados a %s\n", len(snapshots), *day, *output)
+
+	if *sign {
+		sig, err := signFile(*output)
+		if err != nil {
+			return err
+		}
+		printSignature(sig)
+	}
+	return nil
+}
+
+// printSignature resume la firma escrita
+func printSignature(sig *ExportSignature) {
+	fmt.Printf("SHA-256: %s\n", sig.SHA256)
+	if sig.Signature == "" {
+		fmt.Printf("%sSolo hash, sin firma: generá una clave con bolsa export keygen%s\n", Yellow, Reset)
+	} else {
+		pub, _ := base64.StdEncoding.DecodeString(sig.PublicKey)
+		fmt.Printf("Firmado con la clave %s\n", keyFingerprint(pub))
+	}
+	fmt.Printf("Verificación: bolsa verify %s\n", sig.File)
+}
+
+// runExportKeygen genera el par de claves Ed25519 para firmar exports
+func runExportKeygen(args []string) error {
+	fs := flag.NewFlagSet("export keygen", flag.ExitOnError)
+	force := fs.Bool("force", false, "reemplazar la clave existente (las firmas anteriores siguen verificando con su clave pública)")
+	fs.Parse(args)
+
+	existing, err := loadSigningKey()
+	if err != nil {
+		return err
+	}
+	if existing != nil && !*force {
+		pub := existing.Public().(ed25519.PublicKey)
+		return fmt.Errorf("ya existe una clave de firma (%s); usar --force para reemplazarla", keyFingerprint(pub))
+	}
+
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return err
+	}
+	path, err := appFile(signingKeyFile)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(base64.StdEncoding.EncodeToString(key.Seed())+"\n"), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Clave de firma guardada en %s\n", path)
+	fmt.Printf("Clave pública (compartila con quien verifique): %s\n", base64.StdEncoding.EncodeToString(pub))
+	fmt.Printf("Huella: %s\n", keyFingerprint(pub))
+	return nil
+}
+
+// runVerify implementa `bolsa verify ARCHIVO`: comprueba el hash y la firma de un export
+func runVerify(args []string) error {
+	fs := flag.NewFlagSet("verify", flag.ExitOnError)
+	pubKey := fs.String("pubkey", "", "clave pública en base64 de quien firmó (por defecto la propia o la incluida en el .sig)")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: bolsa verify [--pubkey CLAVE] ARCHIVO")
+	}
+	path := fs.Arg(0)
+
+	data, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		return fmt.Errorf("no se pudo leer la firma %s.sig: %v", path, err)
+	}
+	var sig ExportSignature
+	if err := json.Unmarshal(data, &sig); err != nil {
+		return fmt.Errorf("firma inválida en %s.sig: %v", path, err)
+	}
+
+	digest, size, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(digest) != sig.SHA256 || size != sig.Size {
+		return fmt.Errorf("el archivo fue modificado: SHA-256 %s, esperado %s", hex.EncodeToString(digest), sig.SHA256)
+	}
+	fmt.Printf("%s✓ Hash SHA-256 correcto (%s, %s)%s\n", Green, sig.SHA256[:16], formatBytes(size), Reset)
+
+	if sig.Signature == "" {
+		fmt.Printf("%sEl export no está firmado: solo se verificó la integridad%s\n", Yellow, Reset)
+		return nil
+	}
+
+	// La clave de confianza es la indicada, o la propia; la incluida en el .sig solo prueba integridad
+	trusted := ""
+	switch {
+	case *pubKey != "":
+		trusted = *pubKey
+	default:
+		if key, err := loadSigningKey(); err == nil && key != nil {
+			trusted = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+		}
+	}
+	keyText := sig.PublicKey
+	if trusted != "" {
+		keyText = trusted
+	}
+
+	pub, err := base64.StdEncoding.DecodeString(keyText)
+	if err != nil || len(pub) != ed25519.PublicKeySize {
+		return fmt.Errorf("clave pública inválida")
+	}
+	signature, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil || !ed25519.Verify(pub, digest, signature) {
+		return fmt.Errorf("la firma no corresponde a la clave %s", keyFingerprint(pub))
+	}
+
+	fmt.Printf("%s✓ Firma válida de la clave %s, del %s%s\n", Green, keyFingerprint(pub), sig.Created.Local().Format("2006-01-02 15:04"), Reset)
+	if trusted == "" {
+		fmt.Printf("%sSe usó la clave incluida en el .sig: confirmá la huella con quien firmó o pasá --pubkey%s\n", Yellow, Reset)
+	}
+	return nil
+}
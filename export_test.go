@@ -0,0 +1,156 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// withSigningKey genera una clave de firma para el test y la borra al terminar
+func withSigningKey(t *testing.T) {
+	t.Helper()
+	if err := runExportKeygen([]string{"--force"}); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if path, err := appFile(signingKeyFile); err == nil {
+			os.Remove(path)
+		}
+	})
+}
+
+// signedExport escribe un export de prueba con su .sig
+func signedExport(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "export.csv")
+	if err := os.WriteFile(path, []byte("time,section,symbol\n2025-03-14T15:30:00-03:00,stocks,GGAL\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := signFile(path); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// rewriteSignature modifica el .sig de un export
+func rewriteSignature(t *testing.T, path string, edit func(sig *ExportSignature)) {
+	t.Helper()
+	data, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sig ExportSignature
+	if err := json.Unmarshal(data, &sig); err != nil {
+		t.Fatal(err)
+	}
+	edit(&sig)
+	if data, err = json.Marshal(sig); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path+".sig", data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExportSignVerifyRoundTrip(t *testing.T) {
+	withSigningKey(t)
+	path := signedExport(t)
+	if err := runVerify([]string{path}); err != nil {
+		t.Fatalf("verify de un export recién firmado: %v", err)
+	}
+
+	// Con la clave pública explícita también verifica
+	key, err := loadSigningKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+	if err := runVerify([]string{"--pubkey", pub, path}); err != nil {
+		t.Errorf("verify con --pubkey: %v", err)
+	}
+}
+
+func TestVerifyDetectsTampering(t *testing.T) {
+	withSigningKey(t)
+	other, otherKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(t *testing.T, path string) []string
+		want   string
+	}{
+		{"mismo tamaño, otro contenido", func(t *testing.T, path string) []string {
+			data, _ := os.ReadFile(path)
+			os.WriteFile(path, []byte(strings.Replace(string(data), "GGAL", "YPFD", 1)), 0o644)
+			return []string{path}
+		}, "fue modificado"},
+		{"fila agregada", func(t *testing.T, path string) []string {
+			data, _ := os.ReadFile(path)
+			os.WriteFile(path, append(data, "2025-03-14T15:31:00-03:00,stocks,YPFD\n"...), 0o644)
+			return []string{path}
+		}, "fue modificado"},
+		{"hash del .sig recalculado sin la clave", func(t *testing.T, path string) []string {
+			os.WriteFile(path, []byte("otro contenido\n"), 0o644)
+			sum := sha256.Sum256([]byte("otro contenido\n"))
+			rewriteSignature(t, path, func(sig *ExportSignature) {
+				sig.SHA256, sig.Size = hex.EncodeToString(sum[:]), int64(len("otro contenido\n"))
+			})
+			return []string{path}
+		}, "la firma no corresponde"},
+		{"refirmado con otra clave incluida en el .sig", func(t *testing.T, path string) []string {
+			os.WriteFile(path, []byte("otro contenido\n"), 0o644)
+			sum := sha256.Sum256([]byte("otro contenido\n"))
+			rewriteSignature(t, path, func(sig *ExportSignature) {
+				sig.SHA256, sig.Size = hex.EncodeToString(sum[:]), int64(len("otro contenido\n"))
+				sig.PublicKey = base64.StdEncoding.EncodeToString(other)
+				sig.Signature = base64.StdEncoding.EncodeToString(ed25519.Sign(otherKey, sum[:]))
+			})
+			return []string{path}
+		}, "la firma no corresponde"},
+		{"--pubkey de otra persona", func(t *testing.T, path string) []string {
+			return []string{"--pubkey", base64.StdEncoding.EncodeToString(other), path}
+		}, "la firma no corresponde"},
+		{"firma ilegible", func(t *testing.T, path string) []string {
+			rewriteSignature(t, path, func(sig *ExportSignature) { sig.Signature = "no es base64!" })
+			return []string{path}
+		}, "la firma no corresponde"},
+	}
+	for _, tt := range tests {
+		path := signedExport(t)
+		args := tt.tamper(t, path)
+		if err := runVerify(args); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: err = %v, se esperaba %q", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestVerifyUnsignedExport(t *testing.T) {
+	// Sin clave generada el .sig lleva solo el hash: verifica la integridad y nada más
+	path := signedExport(t)
+	data, err := os.ReadFile(path + ".sig")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), `"signature"`) {
+		t.Fatalf("sin clave no debería haber firma: %s", data)
+	}
+	if err := runVerify([]string{path}); err != nil {
+		t.Errorf("verify de un export sin firma: %v", err)
+	}
+	if err := os.WriteFile(path, []byte("modificado"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runVerify([]string{path}); err == nil {
+		t.Error("un export sin firma modificado debería fallar")
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// Símbolos para calcular el dólar CCL implícito: GGAL en BYMA contra su ADR, 10 acciones por ADR
+const (
+	cclLocalSymbol   = "GGAL.BA"
+	cclForeignSymbol = "GGAL"
+	cclRatio         = 10
+)
+
+// ExportProgram es un programa cambiario temporal para exportadores (dólar soja, dólar exportador): una parte de
+// las divisas se liquida al CCL y el resto al oficial
+type ExportProgram struct {
+	Name     string  `json:"name"`     // Dólar soja, exportador...
+	Start    string  `json:"start"`    // Primer día de vigencia (AAAA-MM-DD)
+	End      string  `json:"end"`      // Último día de vigencia (AAAA-MM-DD); vacío si no tiene fecha de fin
+	CCLShare float64 `json:"cclShare"` // Porcentaje liquidable al CCL (20 en el esquema 80/20)
+	Rate     float64 `json:"rate"`     // Tipo de cambio fijo del programa, si no es una mezcla con el CCL
+
+	start, end time.Time
+}
+
+// validate interpreta las fechas de vigencia y verifica el porcentaje
+func (p *ExportProgram) validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("falta \"name\"")
+	}
+	var err error
+	if p.start, err = time.ParseInLocation("2006-01-02", p.Start, argentinaLocation); err != nil {
+		return fmt.Errorf("%s: inicio inválido %q (usar AAAA-MM-DD)", p.Name, p.Start)
+	}
+	if p.End != "" {
+		if p.end, err = time.ParseInLocation("2006-01-02", p.End, argentinaLocation); err != nil {
+			return fmt.Errorf("%s: fin inválido %q (usar AAAA-MM-DD)", p.Name, p.End)
+		}
+		if p.end.Before(p.start) {
+			return fmt.Errorf("%s: termina antes de empezar", p.Name)
+		}
+	}
+	if p.CCLShare < 0 || p.CCLShare > 100 {
+		return fmt.Errorf("%s: cclShare debe estar entre 0 y 100", p.Name)
+	}
+	if p.CCLShare == 0 && p.Rate <= 0 {
+		return fmt.Errorf("%s: falta \"cclShare\" o \"rate\"", p.Name)
+	}
+	return nil
+}
+
+// Active indica si el programa está vigente en el día de now (la fecha de fin es inclusive)
+func (p ExportProgram) Active(now time.Time) bool {
+	day := now.In(argentinaLocation)
+	if day.Before(p.start) {
+		return false
+	}
+	return p.end.IsZero() || day.Before(p.end.AddDate(0, 0, 1))
+}
+
+// ExportRate es el tipo de cambio que recibe el exportador con un programa vigente
+type ExportRate struct {
+	Program  string    `json:"program"`
+	Value    float64   `json:"value"`
+	Official float64   `json:"official"`
+	CCL      float64   `json:"ccl,omitempty"`
+	CCLShare float64   `json:"ccl_share,omitempty"`
+	Gap      float64   `json:"gap"` // Sobre el oficial, en %
+	Ends     time.Time `json:"ends,omitempty"`
+}
+
+// liveCCL calcula el dólar CCL implícito en GGAL
+func liveCCL(client QuoteFetcher) (float64, error) {
+	local, _, _, _, err := getTickerData(cclLocalSymbol, client)
+	if err != nil {
+		return 0, err
+	}
+	foreign, _, _, _, err := getTickerData(cclForeignSymbol, client)
+	if err != nil {
+		return 0, err
+	}
+	if foreign == 0 {
+		return 0, fmt.Errorf("cotización inválida de %s", cclForeignSymbol)
+	}
+	return local * cclRatio / foreign, nil
+}
+
+// getExportRates calcula el tipo de cambio de cada programa vigente; el CCL solo se consulta si alguno lo usa
+func getExportRates(forexData []ForexInfo, client QuoteFetcher, errs *fetchErrors) []ExportRate {
+	now := time.Now()
+	var active []ExportProgram
+	for _, p := range appConfig().ExportPrograms {
+		if p.Active(now) {
+			active = append(active, p)
+		}
+	}
+	if len(active) == 0 {
+		return nil
+	}
+
+	var official float64
+	for _, forex := range forexData {
+		if forex.Symbol == "ARS=X" {
+			official = forex.Price
+		}
+	}
+	if official == 0 {
+		errs.add("Exportador", "ARS=X", fmt.Errorf("sin dólar oficial no se puede calcular el tipo de cambio exportador"))
+		return nil
+	}
+
+	var ccl float64
+	var cclErr error
+	cclFetched := false
+	var rates []ExportRate
+	for _, p := range active {
+		rate := ExportRate{Program: p.Name, Official: official, Ends: p.end}
+		if p.Rate > 0 {
+			rate.Value = p.Rate
+		} else {
+			if !cclFetched {
+				ccl, cclErr = liveCCL(client)
+				cclFetched = true
+				if cclErr != nil {
+					errs.add("Exportador", "CCL", cclErr)
+				}
+			}
+			if cclErr != nil {
+				continue
+			}
+			rate.CCL, rate.CCLShare = ccl, p.CCLShare
+			rate.Value = official*(1-p.CCLShare/100) + ccl*p.CCLShare/100
+		}
+		rate.Gap = (rate.Value/official - 1) * 100
+		rates = append(rates, rate)
+	}
+	return rates
+}
+
+// displayExportRates muestra el tipo de cambio de los programas vigentes debajo de los dólares
+func displayExportRates(rates []ExportRate) {
+	for _, rate := range rates {
+		fmt.Printf("\n%s%-12s%s$%.2f", Yellow, rate.Program, Reset, rate.Value)
+		if rate.CCLShare > 0 {
+			fmt.Printf(" (%.0f%% oficial $%.2f + %.0f%% CCL $%.2f)", 100-rate.CCLShare, rate.Official, rate.CCLShare, rate.CCL)
+		}
+		fmt.Printf(" %+.1f%% sobre el oficial", rate.Gap)
+		if !rate.Ends.IsZero() {
+			fmt.Printf(", vigente hasta el %s", rate.Ends.Format("02/01"))
+		}
+	}
+	if len(rates) > 0 {
+		fmt.Println()
+	}
+}
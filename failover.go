@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Espera entre la escritura de un lock tomado y su relectura, para detectar a otra instancia que lo tomó a la vez
+const failoverSettle = 500 * time.Millisecond
+
+// FailoverConfig define la elección de líder entre instancias redundantes del daemon: solo la que tiene el lock
+// consulta proveedores y envía alertas; las demás quedan en standby hasta que el lock venza
+type FailoverConfig struct {
+	LockFile string   `json:"lockFile"` // Archivo en un directorio compartido por todas las instancias (NFS, SMB...)
+	TTL      Duration `json:"ttl"`      // Vigencia del lock sin renovar; por defecto 1 minuto
+	ID       string   `json:"id"`       // Nombre de esta instancia; por defecto host-pid
+}
+
+// validate completa los valores por defecto
+func (c *FailoverConfig) validate() error {
+	if c.LockFile == "" {
+		return fmt.Errorf("falta \"lockFile\"")
+	}
+	if c.TTL < 0 {
+		return fmt.Errorf("ttl debe ser positivo")
+	}
+	if c.TTL == 0 {
+		c.TTL = Duration(time.Minute)
+	}
+	if c.ID == "" {
+		host, _ := os.Hostname()
+		c.ID = fmt.Sprintf("%s-%d", host, os.Getpid())
+	}
+	return nil
+}
+
+// leaderLease es el contenido del archivo de lock
+type leaderLease struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+}
+
+// LeaderLock es un lock con vencimiento sobre un archivo compartido
+type LeaderLock struct {
+	cfg     FailoverConfig
+	mu      sync.Mutex
+	leader  bool
+	standby bool // Ya se avisó que esta instancia está en standby
+}
+
+// Lock de la instancia, nil si no hay failover configurado
+var leaderLock *LeaderLock
+
+// NewLeaderLock crea el lock de la configuración; nil sin failover
+func NewLeaderLock(cfg *FailoverConfig) *LeaderLock {
+	if cfg == nil {
+		return nil
+	}
+	return &LeaderLock{cfg: *cfg}
+}
+
+// read devuelve el lock vigente en el archivo, o nil si no existe o no se puede interpretar
+func (l *LeaderLock) read() *leaderLease {
+	data, err := os.ReadFile(l.cfg.LockFile)
+	if err != nil {
+		return nil
+	}
+	var lease leaderLease
+	if json.Unmarshal(data, &lease) != nil {
+		return nil
+	}
+	return &lease
+}
+
+// write toma o renueva el lock a nombre de esta instancia
+func (l *LeaderLock) write(now time.Time) error {
+	data, err := json.MarshalIndent(leaderLease{Holder: l.cfg.ID, Expires: now.Add(time.Duration(l.cfg.TTL))}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(l.cfg.LockFile), 0o755); err != nil {
+		return err
+	}
+	return writeFileAtomic(l.cfg.LockFile, data)
+}
+
+// Hold toma o renueva el lock; devuelve true si esta instancia es la líder
+func (l *LeaderLock) Hold(now time.Time) bool {
+	if l == nil {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lease := l.read()
+	if lease != nil && lease.Holder != l.cfg.ID && now.Before(lease.Expires) {
+		if !l.standby {
+			fmt.Printf("%sFailover: standby, la instancia %s tiene el lock hasta las %s%s\n",
+				Yellow, lease.Holder, lease.Expires.In(argentinaLocation).Format("15:04:05"), Reset)
+		}
+		l.leader, l.standby = false, true
+		return false
+	}
+
+	if err := l.write(now); err != nil {
+		fmt.Printf("%s⚠️ Failover: no se pudo escribir el lock %s: %v%s\n", Yellow, l.cfg.LockFile, err, Reset)
+		// Sin poder renovar no hay forma de saber si otra instancia lo tomó: se deja de ser líder
+		l.leader = false
+		return false
+	}
+	if !l.leader {
+		// Si otra instancia escribió a la vez, gana la última escritura
+		time.Sleep(failoverSettle)
+		if lease := l.read(); lease == nil || lease.Holder != l.cfg.ID {
+			return false
+		}
+		fmt.Printf("%sFailover: la instancia %s es la líder%s\n", Green, l.cfg.ID, Reset)
+		l.leader, l.standby = true, false
+	}
+	return true
+}
+
+// Sleep espera entre ciclos renovando el lock, para no perderlo en las esperas largas del modo económico
+func (l *LeaderLock) Sleep(wait time.Duration) {
+	if l == nil {
+		time.Sleep(wait)
+		return
+	}
+	step := time.Duration(l.cfg.TTL) / 3
+	for wait > step {
+		time.Sleep(step)
+		wait -= step
+		l.Hold(time.Now())
+	}
+	time.Sleep(wait)
+}
+
+// Release libera el lock al terminar, para que otra instancia tome el relevo sin esperar el vencimiento
+func (l *LeaderLock) Release() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.leader {
+		return
+	}
+	if lease := l.read(); lease != nil && lease.Holder == l.cfg.ID {
+		os.Remove(l.cfg.LockFile)
+	}
+	l.leader = false
+}
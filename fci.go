@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// FCIConfig identifica un fondo común de inversión en la API de CAFCI (fondo y clase de cuotaparte)
+type FCIConfig struct {
+	Name  string `json:"name"`
+	Fund  int    `json:"fund"`
+	Class int    `json:"class"`
+}
+
+// FundQuote es el valor de cuotaparte de un FCI con sus variaciones
+type FundQuote struct {
+	Name          string    `json:"name"`
+	Date          time.Time `json:"date"`
+	Value         float64   `json:"value"`          // Valor de la cuotaparte
+	DailyChange   float64   `json:"daily_change"`   // Variación diaria en %
+	MonthlyChange float64   `json:"monthly_change"` // Variación de los últimos 30 días en %
+}
+
+// La cuotaparte se publica una vez por día: alcanza con consultarla cada hora
+const fundQuoteTTL = time.Hour
+
+var (
+	fundQuotesMu sync.Mutex
+	fundQuotes   = make(map[string]FundQuote)
+	fundFetched  = make(map[string]time.Time)
+
+	cafciOnce   sync.Once
+	cafciClient *HTTPClient
+)
+
+// getFundQuotes devuelve la cotización de los FCIs configurados, usando la caché horaria
+func getFundQuotes(errs *fetchErrors) []FundQuote {
+	cafciOnce.Do(func() { cafciClient = NewProviderClient("cafci") })
+
+	var quotes []FundQuote
+	for _, fund := range appConfig().FCIs {
+		key := fmt.Sprintf("%d/%d", fund.Fund, fund.Class)
+
+		fundQuotesMu.Lock()
+		quote, ok := fundQuotes[key]
+		fresh := ok && time.Since(fundFetched[key]) < fundQuoteTTL
+		fundQuotesMu.Unlock()
+
+		if !fresh {
+			fetched, err := fetchFundQuote(fund, cafciClient)
+			if err != nil {
+				errs.add("FCI", fund.Name, err)
+			} else {
+				quote, ok = fetched, true
+			}
+			// Si falla se reintenta recién en el próximo período, mostrando el último valor conocido
+			fundQuotesMu.Lock()
+			if ok {
+				fundQuotes[key] = quote
+			}
+			fundFetched[key] = time.Now()
+			fundQuotesMu.Unlock()
+		}
+
+		if ok {
+			quotes = append(quotes, quote)
+		}
+	}
+	return quotes
+}
+
+// fetchFundQuote consulta la ficha de un fondo en la API de CAFCI
+func fetchFundQuote(fund FCIConfig, client QuoteFetcher) (FundQuote, error) {
+	fichaURL := fmt.Sprintf("https://api.cafci.org.ar/fondo/%d/clase/%d/ficha", fund.Fund, fund.Class)
+	headers := map[string]string{
+		"Accept":  "application/json",
+		"Referer": "https://www.cafci.org.ar/",
+	}
+
+	resp, err := client.GetWithRetry(fichaURL, headers)
+	if err != nil {
+		return FundQuote{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return FundQuote{}, statusError(resp.StatusCode, "")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return FundQuote{}, err
+	}
+
+	var ficha struct {
+		Data struct {
+			Info struct {
+				Diaria struct {
+					Actual struct {
+						Fecha       string    `json:"fecha"`
+						VcpUnitario FlexFloat `json:"vcpUnitario"`
+					} `json:"actual"`
+					Anterior struct {
+						VcpUnitario FlexFloat `json:"vcpUnitario"`
+					} `json:"anterior"`
+					Rendimientos struct {
+						Day struct {
+							Rendimiento FlexFloat `json:"rendimiento"`
+						} `json:"day"`
+						Month struct {
+							Rendimiento FlexFloat `json:"rendimiento"`
+						} `json:"month"`
+					} `json:"rendimientos"`
+				} `json:"diaria"`
+			} `json:"info"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &ficha); err != nil {
+		return FundQuote{}, schemaError("cafci", fund.Name, body, err.Error())
+	}
+
+	diaria := ficha.Data.Info.Diaria
+	if !diaria.Actual.VcpUnitario.Valid {
+		return FundQuote{}, schemaError("cafci", fund.Name, body, "falta data.info.diaria.actual.vcpUnitario")
+	}
+
+	quote := FundQuote{
+		Name:          fund.Name,
+		Value:         diaria.Actual.VcpUnitario.Value,
+		DailyChange:   diaria.Rendimientos.Day.Rendimiento.Value,
+		MonthlyChange: diaria.Rendimientos.Month.Rendimiento.Value,
+	}
+	quote.Date, _ = time.ParseInLocation("02/01/2006", diaria.Actual.Fecha, argentinaLocation)
+
+	// Sin rendimiento publicado, la variación diaria sale de la cuotaparte anterior
+	if !diaria.Rendimientos.Day.Rendimiento.Valid && diaria.Anterior.VcpUnitario.Value > 0 {
+		quote.DailyChange = (quote.Value/diaria.Anterior.VcpUnitario.Value - 1) * 100
+	}
+	return quote, nil
+}
+
+// displayFunds muestra la sección de fondos comunes de inversión
+func displayFunds(funds []FundQuote) {
+	if len(funds) == 0 {
+		return
+	}
+
+	fmt.Printf("\n%s=== FONDOS COMUNES DE INVERSIÓN ===%s\n\n", Cyan, Reset)
+	for _, fund := range funds {
+		dailyColor, monthlyColor := Green, Green
+		if fund.DailyChange < 0 {
+			dailyColor = Red
+		}
+		if fund.MonthlyChange < 0 {
+			monthlyColor = Red
+		}
+		fmt.Printf("%s%-30s%s $%-12.4f día %s%+.2f%%%s  mes %s%+.2f%%%s  (%s)\n",
+			White, fund.Name, Reset, fund.Value,
+			dailyColor, fund.DailyChange, Reset,
+			monthlyColor, fund.MonthlyChange, Reset,
+			fund.Date.Format("02/01"))
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+)
+
+// Doer ejecuta un request HTTP; lo implementa *http.Client y permite reemplazar el transporte sin tocar los reintentos
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// QuoteFetcher es lo que necesitan las funciones de consulta: un GET con los reintentos del proveedor.
+// Lo implementa *HTTPClient; en pruebas alcanza con un FetcherFunc que devuelva respuestas armadas a mano
+type QuoteFetcher = bolsa.QuoteFetcher
+
+// FetcherFunc adapta una función a QuoteFetcher, como http.HandlerFunc
+type FetcherFunc = bolsa.FetcherFunc
+
+var _ QuoteFetcher = (*HTTPClient)(nil)
+
+// NewHTTPClientWithDoer crea un cliente del proveedor que ejecuta los requests con doer (por ejemplo un transporte falso)
+func NewHTTPClientWithDoer(provider string, doer Doer) *HTTPClient {
+	c := NewProviderClient(provider)
+	c.doer = doer
+	return c
+}
+
+// priceChange calcula la variación absoluta y porcentual respecto del cierre anterior
+func priceChange(price, previousClose float64) (change, changePercent float64) {
+	change = price - previousClose
+	if previousClose != 0 {
+		changePercent = (change / previousClose) * 100
+	}
+	return change, changePercent
+}
+
+// toPesos convierte precio y variación de un papel que cotiza en dólares; sin tasa o en pesos los deja igual
+func toPesos(price, change, dolarRate float64, market Market) (float64, float64) {
+	if dolarRate == 0 || !market.Valid() || market.Currency() != "USD" {
+		return price, change
+	}
+	return price * dolarRate, change * dolarRate
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// doerFunc adapta una función a Doer para reemplazar el transporte del cliente
+type doerFunc func(req *http.Request) (*http.Response, error)
+
+func (f doerFunc) Do(req *http.Request) (*http.Response, error) { return f(req) }
+
+// fakeYahooDoer responde la API chart con cotizaciones fijas por símbolo y 404 para los demás
+func fakeYahooDoer(meta map[string]string) Doer {
+	return doerFunc(func(req *http.Request) (*http.Response, error) {
+		symbol := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+		body, status := `{"chart": {"result": null, "error": {"code": "Not Found", "description": "No data found"}}}`, http.StatusNotFound
+		if m, ok := meta[symbol]; ok {
+			body, status = `{"chart": {"result": [{"meta": `+m+`}], "error": null}}`, http.StatusOK
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}, Request: req}, nil
+	})
+}
+
+// withWatchlist reemplaza las acciones y tipos de cambio seguidos durante la prueba, con la caché del ciclo vacía
+func withWatchlist(t *testing.T, watchlist []WatchlistEntry, forex []map[string]string) {
+	t.Helper()
+	stocksMu.Lock()
+	savedStocks, savedForex := stocks, forexSymbols
+	stocks, forexSymbols = watchlist, forex
+	stocksMu.Unlock()
+	tickerCache.Reset()
+	t.Cleanup(func() {
+		stocksMu.Lock()
+		stocks, forexSymbols = savedStocks, savedForex
+		stocksMu.Unlock()
+		tickerCache.Reset()
+	})
+}
+
+func TestGetStockDataWithFakeTransport(t *testing.T) {
+	withWatchlist(t, []WatchlistEntry{{"GGAL.BA", MarketBYMA}, {"YPF", MarketNYSE}, {"NOEXISTE.BA", MarketBYMA}}, nil)
+	client := NewHTTPClientWithDoer("yahoo", fakeYahooDoer(map[string]string{
+		"GGAL.BA": `{"regularMarketPrice": 5120, "previousClose": 5000, "regularMarketVolume": 1250000, "shortName": "GRUPO FIN GALICIA"}`,
+		"YPF":     `{"regularMarketPrice": {"raw": 34.5}, "chartPreviousClose": 35, "regularMarketVolume": 3100000}`,
+	}))
+
+	errs := &fetchErrors{}
+	data, err := getStockData(context.Background(), 1000, client, errs)
+	if err == nil {
+		t.Error("se esperaba el error del símbolo inexistente")
+	}
+	var quoteErr *QuoteError
+	if !errors.As(err, &quoteErr) || !errors.Is(err, ErrInvalidSymbol) {
+		t.Errorf("error = %v, se esperaba un QuoteError de símbolo inválido", err)
+	}
+
+	got := make(map[string]StockInfo)
+	for _, stock := range data {
+		got[stock.Symbol] = stock
+	}
+	if len(got) != 2 {
+		t.Fatalf("se obtuvieron %d acciones, se esperaban 2: %+v", len(got), data)
+	}
+
+	// En pesos: la variación es la de Yahoo, sin conversión
+	ggal := got["GGAL.BA"]
+	if ggal.Price != 5120 || ggal.Change != 120 || math.Abs(ggal.ChangePercent-2.4) > 1e-9 || ggal.Volume != 1250000 {
+		t.Errorf("GGAL.BA = %+v", ggal)
+	}
+	// En dólares: precio y variación pasan a pesos; el porcentaje y el cierre anterior quedan en la moneda de origen
+	ypf := got["YPF"]
+	if ypf.Price != 34500 || ypf.Change != -500 || math.Abs(ypf.ChangePercent+0.5/35*100) > 1e-9 || ypf.PreviousClose != 35 {
+		t.Errorf("YPF = %+v", ypf)
+	}
+	if ypf.Name != "YPF" {
+		t.Errorf("sin shortName el nombre debería ser el símbolo, fue %q", ypf.Name)
+	}
+}
+
+func TestGetForexDataWithFakeTransport(t *testing.T) {
+	withWatchlist(t, nil, []map[string]string{
+		{"symbol": "ARS=X", "name": "Dólar Oficial"},
+		{"symbol": "NOEXISTE=X", "name": "Inexistente"},
+		{"symbol": "EURUSD=X", "name": "Euro/USD"},
+	})
+	client := NewHTTPClientWithDoer("yahoo", fakeYahooDoer(map[string]string{
+		"ARS=X":    `{"regularMarketPrice": 1450, "previousClose": 1400}`,
+		"EURUSD=X": `{"regularMarketPrice": "1.1", "previousClose": "1.1"}`,
+	}))
+
+	errs := &fetchErrors{}
+	data, err := getForexData(context.Background(), client, errs)
+	if err != nil {
+		t.Fatalf("con algún tipo de cambio obtenido no se esperaba error: %v", err)
+	}
+	if len(data) != 2 || data[0].Symbol != "ARS=X" || data[1].Symbol != "EURUSD=X" {
+		t.Fatalf("tipos de cambio = %+v, se esperaban ARS=X y EURUSD=X en el orden configurado", data)
+	}
+	if data[0].Change != 50 || math.Abs(data[0].ChangePercent-50.0/1400*100) > 1e-9 {
+		t.Errorf("ARS=X = %+v", data[0])
+	}
+	if len(errs.list) != 1 || errs.list[0].Symbol != "NOEXISTE=X" || errs.list[0].Kind != "simbolo" {
+		t.Errorf("errores = %+v, se esperaba uno de símbolo para NOEXISTE=X", errs.list)
+	}
+}
+
+func TestGetWithRetryKeepsTransportError(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	attempts := 0
+	client := NewHTTPClientWithDoer("prueba", doerFunc(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return nil, refused
+	}))
+	client.config.MaxRetries, client.config.Backoff = 2, Duration(time.Millisecond)
+
+	_, err := client.GetWithRetry("https://example.invalid/", nil)
+	if attempts != 2 {
+		t.Errorf("se hicieron %d intentos, se esperaban 2", attempts)
+	}
+	var opErr *net.OpError
+	if !errors.Is(err, ErrNetwork) || !errors.As(err, &opErr) {
+		t.Errorf("error = %v, se esperaba ErrNetwork con el error del transporte", err)
+	}
+}
+
+func TestGetWithRetryYahooOnlyTweaks(t *testing.T) {
+	for _, provider := range []string{"yahoo", "iol", "github"} {
+		var urls []string
+		var cookies []string
+		client := NewHTTPClientWithDoer(provider, doerFunc(func(req *http.Request) (*http.Response, error) {
+			urls = append(urls, req.URL.String())
+			cookies = append(cookies, req.Header.Get("Cookie"))
+			return &http.Response{StatusCode: http.StatusUnauthorized, Body: io.NopCloser(strings.NewReader("")), Header: http.Header{}}, nil
+		}))
+		client.config.MaxRetries, client.config.Backoff = 2, Duration(time.Millisecond)
+
+		url := "https://api.example.com/api/v10/cotizacion"
+		client.GetWithRetry(url, nil)
+		if len(urls) != 2 {
+			t.Fatalf("%s: %d intentos, se esperaban 2", provider, len(urls))
+		}
+
+		yahoo := provider == "yahoo"
+		if rewritten := urls[1] != url; rewritten != yahoo {
+			t.Errorf("%s: segundo intento a %s; solo Yahoo cambia de v10 a v8", provider, urls[1])
+		}
+		if sent := strings.Contains(cookies[0], "B="); sent != yahoo {
+			t.Errorf("%s: cookie %q; solo a Yahoo se le manda la cookie B", provider, cookies[0])
+		}
+	}
+}
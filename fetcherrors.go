@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FetchError es una consulta que falló durante un ciclo de actualización
+type FetchError struct {
+	Section string `json:"section"`
+	Symbol  string `json:"symbol,omitempty"`
+	Cause   string `json:"cause"`
+	Kind    string `json:"kind,omitempty"` // Categoría: red, rate-limit, parseo o simbolo
+}
+
+// fetchErrors junta los errores de las consultas concurrentes de un ciclo;
+// un valor nil solo los muestra en la consola (comandos que no arman un snapshot)
+type fetchErrors struct {
+	mu   sync.Mutex
+	list []FetchError
+	errs []error // Errores originales, para inspeccionarlos con errors.Is/As
+}
+
+// add registra una consulta fallida de la sección indicada
+func (f *fetchErrors) add(section, symbol string, err error) {
+	// Sin snapshot no hay franja de errores: se muestran enseguida; en el monitor solo en nivel debug
+	if f == nil || debugLogging {
+		if symbol != "" {
+			fmt.Printf("error al obtener datos para %s: %v\n", symbol, err)
+		} else {
+			fmt.Printf("error al obtener %s: %v\n", section, err)
+		}
+	}
+	if f == nil {
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.list = append(f.list, FetchError{Section: section, Symbol: symbol, Cause: err.Error(), Kind: errorKindName(err)})
+	f.errs = append(f.errs, err)
+}
+
+// err devuelve los errores del ciclo unidos, o nil si no hubo
+func (f *fetchErrors) err() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return errors.Join(f.errs...)
+}
+
+// all devuelve los errores ordenados por sección y símbolo
+func (f *fetchErrors) all() []FetchError {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	list := append([]FetchError(nil), f.list...)
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Section != list[j].Section {
+			return list[i].Section < list[j].Section
+		}
+		return list[i].Symbol < list[j].Symbol
+	})
+	return list
+}
+
+// displayFetchErrors muestra la franja inferior con las consultas fallidas del ciclo
+func displayFetchErrors(errs []FetchError) {
+	if len(errs) == 0 {
+		return
+	}
+
+	// El encabezado resume las causas y sugiere qué hacer con la más frecuente
+	counts := make(map[string]int)
+	top := ""
+	for _, e := range errs {
+		counts[e.Kind]++
+		if counts[e.Kind] > counts[top] || top == "" {
+			top = e.Kind
+		}
+	}
+	var kinds []string
+	for kind, n := range counts {
+		label, _, _ := errorKindStyle(kind)
+		kinds = append(kinds, fmt.Sprintf("%d de %s", n, label))
+	}
+	sort.Strings(kinds)
+	_, _, hint := errorKindStyle(top)
+	if hint != "" {
+		hint = ": " + hint
+	}
+	fmt.Printf("\n%s--- Consultas fallidas en este ciclo (%s)%s ---%s\n", Red, strings.Join(kinds, ", "), hint, Reset)
+
+	for _, e := range errs {
+		target := e.Section
+		if e.Symbol != "" {
+			target += " " + e.Symbol
+		}
+		cause := e.Cause
+		if len(cause) > 80 {
+			cause = cause[:77] + "..."
+		}
+		label, color, _ := errorKindStyle(e.Kind)
+		fmt.Printf("%s%-22s%s %s%-8s%s %s\n", Yellow, target, Reset, color, label, Reset, cause)
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Ventana tras la apertura en la que el primer precio visto se considera precio de apertura
+const gapOpeningWindow = 15 * time.Minute
+
+// GapWatcher detecta los papeles que abren con un gap significativo respecto al cierre anterior
+type GapWatcher struct {
+	Threshold float64 // Gap mínimo en porcentaje (valor absoluto)
+
+	mu      sync.Mutex
+	checked map[string]string // Mercado -> fecha de la última apertura revisada
+}
+
+// NewGapWatcher crea un detector de gaps con el umbral indicado en porcentaje
+func NewGapWatcher(threshold float64) *GapWatcher {
+	return &GapWatcher{Threshold: threshold, checked: make(map[string]string)}
+}
+
+// Check revisa, una vez por apertura de cada mercado, los gaps del primer precio del día
+func (g *GapWatcher) Check(snapshot *Snapshot, notifiers []Notifier) {
+	g.check(snapshot, notifiers, time.Now())
+}
+
+// check hace la revisión de Check en el instante now
+func (g *GapWatcher) check(snapshot *Snapshot, notifiers []Notifier, now time.Time) {
+	if g == nil || g.Threshold <= 0 {
+		return
+	}
+	if !alertAllowed("gap_apertura", now) {
+		return
+	}
+
+	for _, hours := range []MarketHours{nyseHours, bymaHours} {
+		if !hours.IsOpen(now) {
+			continue
+		}
+
+		today := now.In(hours.Location).Format("2006-01-02")
+		g.mu.Lock()
+		done := g.checked[hours.Name] == today
+		g.mu.Unlock()
+		if done {
+			continue
+		}
+
+		// Si arrancamos con la rueda avanzada, el primer precio visto ya no es el de apertura
+		open, _ := hours.sessionTimes(now)
+		if now.Sub(open) > gapOpeningWindow {
+			g.markChecked(hours.Name, today)
+			fmt.Printf("Apertura de %s fuera de la ventana de detección de gaps; se omite hoy\n", hours.Name)
+			continue
+		}
+
+		// El cambio porcentual se calcula en la moneda de origen, antes de convertir a pesos.
+		// Las cotizaciones postergadas son de un ciclo anterior y no dicen nada de la apertura
+		var gaps []StockInfo
+		quoted := 0
+		for _, stock := range snapshot.Stocks {
+			if stock.Market.Hours().Name != hours.Name || stock.PreviousClose == 0 || stock.Price == 0 || stock.Deferred {
+				continue
+			}
+			quoted++
+			if math.Abs(stock.ChangePercent) >= g.Threshold {
+				gaps = append(gaps, stock)
+			}
+		}
+		// Sin precios del día todavía: se reintenta en el próximo ciclo dentro de la ventana
+		if quoted == 0 {
+			continue
+		}
+		g.markChecked(hours.Name, today)
+		if len(gaps) == 0 {
+			continue
+		}
+
+		sort.Slice(gaps, func(i, j int) bool {
+			return math.Abs(gaps[i].ChangePercent) > math.Abs(gaps[j].ChangePercent)
+		})
+
+		var alerts []Alert
+		for _, stock := range gaps {
+			direction := "alza"
+			if stock.ChangePercent < 0 {
+				direction = "baja"
+			}
+			alerts = append(alerts, Alert{
+				Rule:     "gap_apertura",
+				Severity: SeverityInfo,
+				Symbol:   stock.Symbol,
+				Value:    stock.ChangePercent,
+				Message:  fmt.Sprintf("%s abre con gap a la %s de %+.2f%%", stock.Symbol, direction, stock.ChangePercent),
+				Time:     now,
+			})
+		}
+
+		dispatchAlerts(notifiers, fmt.Sprintf("Gaps de apertura en %s (> %.1f%%)", hours.Name, g.Threshold), alerts, snapshot)
+	}
+}
+
+// markChecked registra que la apertura del día del mercado ya se revisó
+func (g *GapWatcher) markChecked(market, day string) {
+	g.mu.Lock()
+	g.checked[market] = day
+	g.mu.Unlock()
+}
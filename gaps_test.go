@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGapWatcherWaitsForPrices(t *testing.T) {
+	g := NewGapWatcher(3)
+	// Lunes 19/10/2026, cinco minutos después de la apertura de BYMA
+	now := time.Date(2026, time.October, 19, 11, 5, 0, 0, argentinaLocation)
+
+	// Sin cierre anterior todavía: no se puede calcular el gap y la apertura queda pendiente
+	g.check(&Snapshot{Stocks: []StockInfo{{Symbol: "GGAL.BA", Market: MarketBYMA, Price: 5120}}}, nil, now)
+	if day := g.checked[bymaHours.Name]; day != "" {
+		t.Fatalf("apertura marcada como revisada (%s) sin datos de cierre anterior", day)
+	}
+
+	// Una cotización postergada es de otro ciclo: tampoco cuenta
+	deferred := StockInfo{Symbol: "GGAL.BA", Market: MarketBYMA, Price: 5120, PreviousClose: 5000, ChangePercent: 2.4, Deferred: true}
+	g.check(&Snapshot{Stocks: []StockInfo{deferred}}, nil, now)
+	if day := g.checked[bymaHours.Name]; day != "" {
+		t.Fatalf("apertura marcada como revisada (%s) con una cotización postergada", day)
+	}
+
+	// Con precio y cierre anterior el cálculo corre y la apertura del día queda revisada
+	deferred.Deferred = false
+	g.check(&Snapshot{Stocks: []StockInfo{deferred}}, nil, now.Add(time.Minute))
+	if day := g.checked[bymaHours.Name]; day != "2026-10-19" {
+		t.Errorf("apertura revisada el %q, se esperaba 2026-10-19", day)
+	}
+}
+
+func TestGapWatcherSkipsLateStart(t *testing.T) {
+	g := NewGapWatcher(3)
+	// Arranque a media rueda: la apertura del día se da por perdida aunque no haya datos
+	now := time.Date(2026, time.October, 19, 14, 0, 0, 0, argentinaLocation)
+	g.check(&Snapshot{}, nil, now)
+	if day := g.checked[bymaHours.Name]; day != "2026-10-19" {
+		t.Errorf("apertura revisada el %q, se esperaba 2026-10-19", day)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Tonos de verde y rojo de menor a mayor intensidad (colores de 256 de la terminal)
+var (
+	gainShades = []string{"\033[38;5;151m", "\033[38;5;77m", "\033[38;5;34m", "\033[1;38;5;46m"}
+	lossShades = []string{"\033[38;5;217m", "\033[38;5;203m", "\033[38;5;160m", "\033[1;38;5;196m"}
+)
+
+// Umbrales de variación (%) por defecto: suave por debajo de 1%, fuerte por encima de 3%
+var defaultColorThresholds = []float64{1, 3}
+
+// validateColorThresholds exige umbrales positivos, crecientes y no más que tonos disponibles
+func validateColorThresholds(thresholds []float64) error {
+	if len(thresholds) >= len(gainShades) {
+		return fmt.Errorf("colorThresholds admite hasta %d umbrales", len(gainShades)-1)
+	}
+	for i, t := range thresholds {
+		if t <= 0 {
+			return fmt.Errorf("colorThresholds: el umbral %v debe ser positivo", t)
+		}
+		if i > 0 && t <= thresholds[i-1] {
+			return fmt.Errorf("colorThresholds: los umbrales deben ser crecientes (%v después de %v)", t, thresholds[i-1])
+		}
+	}
+	return nil
+}
+
+// variationColor devuelve el color de una variación porcentual: verde o rojo más intenso cuanto
+// más umbrales de config.json (colorThresholds) supera en valor absoluto
+func variationColor(changePercent float64) string {
+	shades := gainShades
+	if changePercent < 0 {
+		shades = lossShades
+	}
+
+	thresholds := appConfig().ColorThresholds
+	if len(thresholds) == 0 {
+		if changePercent < 0 {
+			return Red
+		}
+		return Green
+	}
+
+	// Cantidad de umbrales superados, repartida sobre los tonos para usar siempre el más intenso arriba
+	level := sort.SearchFloat64s(thresholds, math.Abs(changePercent))
+	if level < len(thresholds) && thresholds[level] == math.Abs(changePercent) {
+		level++
+	}
+	return shades[level*(len(shades)-1)/len(thresholds)]
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// heatmapCell es un bloque del mapa de calor: un papel con su peso (capitalización) y variación
+type heatmapCell struct {
+	Symbol        string
+	Weight        float64
+	ChangePercent float64
+}
+
+// heatmapRect es un rectángulo en celdas de la terminal
+type heatmapRect struct {
+	X, Y, W, H int
+}
+
+// Paletas ANSI 256 de menor a mayor intensidad
+var (
+	heatmapGreens = []int{22, 28, 34, 40, 46}
+	heatmapReds   = []int{52, 88, 124, 160, 196}
+)
+
+// heatmapColor elige el color de fondo según la variación: cada tono representa un punto porcentual
+func heatmapColor(changePercent float64) int {
+	if math.Abs(changePercent) < 0.1 {
+		return 240
+	}
+	level := int(math.Min(math.Abs(changePercent), float64(len(heatmapGreens))))
+	if level >= len(heatmapGreens) {
+		level = len(heatmapGreens) - 1
+	}
+	if changePercent > 0 {
+		return heatmapGreens[level]
+	}
+	return heatmapReds[level]
+}
+
+// layoutTreemap reparte el rectángulo entre las celdas en proporción a su peso, partiendo
+// recursivamente en dos grupos de peso similar a lo largo del lado más largo
+func layoutTreemap(cells []heatmapCell, rect heatmapRect, out map[string]heatmapRect) {
+	if len(cells) == 0 || rect.W <= 0 || rect.H <= 0 {
+		return
+	}
+	if len(cells) == 1 {
+		out[cells[0].Symbol] = rect
+		return
+	}
+
+	var total float64
+	for _, c := range cells {
+		total += c.Weight
+	}
+
+	// Primer grupo: los papeles (ordenados de mayor a menor) hasta cubrir la mitad del peso
+	split, acc := 0, 0.0
+	for split < len(cells)-1 && acc+cells[split].Weight/2 < total/2 {
+		acc += cells[split].Weight
+		split++
+	}
+	if split == 0 {
+		acc, split = cells[0].Weight, 1
+	}
+	ratio := acc / total
+
+	// Los caracteres de la terminal son el doble de altos que de anchos
+	if rect.W >= rect.H*2 {
+		w := int(math.Round(float64(rect.W) * ratio))
+		layoutTreemap(cells[:split], heatmapRect{rect.X, rect.Y, w, rect.H}, out)
+		layoutTreemap(cells[split:], heatmapRect{rect.X + w, rect.Y, rect.W - w, rect.H}, out)
+	} else {
+		h := int(math.Round(float64(rect.H) * ratio))
+		layoutTreemap(cells[:split], heatmapRect{rect.X, rect.Y, rect.W, h}, out)
+		layoutTreemap(cells[split:], heatmapRect{rect.X, rect.Y + h, rect.W, rect.H - h}, out)
+	}
+}
+
+// renderHeatmap dibuja el mapa de calor: bloques Unicode coloreados con el símbolo y la variación
+func renderHeatmap(cells []heatmapCell, width, height int) string {
+	sort.Slice(cells, func(i, j int) bool { return cells[i].Weight > cells[j].Weight })
+
+	rects := make(map[string]heatmapRect)
+	layoutTreemap(cells, heatmapRect{0, 0, width, height}, rects)
+
+	// Cada posición de la grilla guarda a qué papel pertenece
+	owner := make([][]int, height)
+	for y := range owner {
+		owner[y] = make([]int, width)
+		for x := range owner[y] {
+			owner[y][x] = -1
+		}
+	}
+	labels := make(map[[2]int]rune)
+	for i, c := range cells {
+		r, ok := rects[c.Symbol]
+		if !ok {
+			continue
+		}
+		for y := r.Y; y < r.Y+r.H && y < height; y++ {
+			for x := r.X; x < r.X+r.W && x < width; x++ {
+				owner[y][x] = i
+			}
+		}
+
+		// Etiqueta centrada: símbolo y, si entra, la variación debajo
+		lines := []string{strings.TrimSuffix(c.Symbol, ".BA"), fmt.Sprintf("%+.1f%%", c.ChangePercent)}
+		if r.H < 2 {
+			lines = lines[:1]
+		}
+		top := r.Y + (r.H-len(lines))/2
+		for li, line := range lines {
+			if len(line) > r.W-1 {
+				if li > 0 || r.W < 2 {
+					continue
+				}
+				line = line[:r.W-1]
+			}
+			left := r.X + (r.W-len(line))/2
+			for k, ch := range line {
+				labels[[2]int{left + k, top + li}] = ch
+			}
+		}
+	}
+
+	var b strings.Builder
+	for y := 0; y < height; y++ {
+		current := -2
+		for x := 0; x < width; x++ {
+			i := owner[y][x]
+			if i != current {
+				if i >= 0 {
+					color := heatmapColor(cells[i].ChangePercent)
+					fmt.Fprintf(&b, "\033[38;5;%dm\033[48;5;%dm", color, color)
+				} else {
+					b.WriteString(Reset)
+				}
+				current = i
+			}
+			if ch, ok := labels[[2]int{x, y}]; ok && i >= 0 {
+				fmt.Fprintf(&b, "\033[97m%c\033[38;5;%dm", ch, heatmapColor(cells[i].ChangePercent))
+				continue
+			}
+
+			// El borde derecho e inferior de cada bloque los separa visualmente
+			r := rects[safeSymbol(cells, i)]
+			switch {
+			case i < 0:
+				b.WriteByte(' ')
+			case x == r.X+r.W-1 && r.W > 1:
+				b.WriteString("▕")
+			case y == r.Y+r.H-1 && r.H > 1:
+				b.WriteString("▁")
+			default:
+				b.WriteString("█")
+			}
+		}
+		b.WriteString(Reset + "\n")
+	}
+	return b.String()
+}
+
+// safeSymbol devuelve el símbolo de la celda i, o "" si la posición no tiene dueño
+func safeSymbol(cells []heatmapCell, i int) string {
+	if i < 0 {
+		return ""
+	}
+	return cells[i].Symbol
+}
+
+// displayHeatmap muestra el mapa de calor del snapshot usando todo el ancho de la terminal
+func displayHeatmap(snapshot *Snapshot) {
+	fmt.Printf("\n%s=== MAPA DE CALOR (tamaño: capitalización, color: variación) ===%s\n\n", Cyan, Reset)
+	if len(snapshot.Stocks) == 0 {
+		fmt.Println("No hay datos de acciones disponibles")
+		return
+	}
+
+	// Los papeles sin capitalización conocida ocupan lo mismo que el más chico conocido
+	minWeight := math.MaxFloat64
+	for _, c := range snapshot.MarketCaps {
+		minWeight = math.Min(minWeight, c)
+	}
+	if minWeight == math.MaxFloat64 {
+		minWeight = 1
+	}
+
+	var cells []heatmapCell
+	for _, stock := range snapshot.Stocks {
+		weight, ok := snapshot.MarketCaps[stock.Symbol]
+		if !ok {
+			weight = minWeight
+		}
+		cells = append(cells, heatmapCell{Symbol: stock.Symbol, Weight: weight, ChangePercent: stock.ChangePercent})
+	}
+
+	cols, rows := terminalSize()
+	height := rows - 8
+	if height < 10 {
+		height = 10
+	}
+	fmt.Print(renderHeatmap(cells, cols, height))
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// HistoryPoint representa un precio de cierre de una serie histórica
+type HistoryPoint struct {
+	Time   time.Time
+	Open   float64 // 0 si Yahoo no la informa
+	Close  float64
+	Volume int64
+}
+
+// getHistory obtiene la serie histórica de un símbolo desde el endpoint chart de Yahoo
+func getHistory(symbol, rangeStr, interval string, client QuoteFetcher) ([]HistoryPoint, error) {
+	historyURL := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?range=%s&interval=%s",
+		url.PathEscape(symbol), url.QueryEscape(rangeStr), url.QueryEscape(interval))
+	return fetchHistory(symbol, historyURL, client)
+}
+
+// getHistoryBetween obtiene la serie histórica de un símbolo entre dos fechas
+func getHistoryBetween(symbol string, from, to time.Time, interval string, client QuoteFetcher) ([]HistoryPoint, error) {
+	historyURL := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s?period1=%d&period2=%d&interval=%s",
+		url.PathEscape(symbol), from.Unix(), to.Unix(), url.QueryEscape(interval))
+	return fetchHistory(symbol, historyURL, client)
+}
+
+// fetchHistory descarga y decodifica una serie histórica del endpoint chart
+func fetchHistory(symbol, historyURL string, client QuoteFetcher) ([]HistoryPoint, error) {
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Accept":     "application/json",
+		"Referer":    "https://finance.yahoo.com/",
+	}
+
+	resp, err := client.GetWithRetry(historyURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "para el histórico de %s", symbol)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var chartResp struct {
+		Chart struct {
+			Result []struct {
+				Timestamp  []int64 `json:"timestamp"`
+				Indicators struct {
+					Quote []struct {
+						Open   []*float64 `json:"open"`
+						Close  []*float64 `json:"close"`
+						Volume []*int64   `json:"volume"`
+					} `json:"quote"`
+				} `json:"indicators"`
+			} `json:"result"`
+			Error *struct {
+				Code        string `json:"code"`
+				Description string `json:"description"`
+			} `json:"error"`
+		} `json:"chart"`
+	}
+
+	if err := json.Unmarshal(body, &chartResp); err != nil {
+		return nil, fmt.Errorf("error al decodificar el histórico de %s: %v", symbol, err)
+	}
+	if chartResp.Chart.Error != nil {
+		return nil, fmt.Errorf("%s: %s", chartResp.Chart.Error.Code, chartResp.Chart.Error.Description)
+	}
+	if len(chartResp.Chart.Result) == 0 || len(chartResp.Chart.Result[0].Indicators.Quote) == 0 {
+		return nil, fmt.Errorf("no hay histórico disponible para %s", symbol)
+	}
+
+	result := chartResp.Chart.Result[0]
+	quote := result.Indicators.Quote[0]
+
+	var points []HistoryPoint
+	for i, ts := range result.Timestamp {
+		// Yahoo devuelve null en las ruedas sin operaciones
+		if i >= len(quote.Close) || quote.Close[i] == nil {
+			continue
+		}
+		point := HistoryPoint{Time: time.Unix(ts, 0), Close: *quote.Close[i]}
+		if i < len(quote.Open) && quote.Open[i] != nil {
+			point.Open = *quote.Open[i]
+		}
+		if i < len(quote.Volume) && quote.Volume[i] != nil {
+			point.Volume = *quote.Volume[i]
+		}
+		points = append(points, point)
+	}
+
+	return points, nil
+}
+
+// priceOnOrAfter devuelve el primer cierre en o después de la fecha indicada
+func priceOnOrAfter(points []HistoryPoint, t time.Time) (HistoryPoint, bool) {
+	for _, p := range points {
+		if !p.Time.Before(t) {
+			return p, true
+		}
+	}
+	return HistoryPoint{}, false
+}
+
+// historyByDay indexa una serie histórica por fecha (AAAA-MM-DD)
+func historyByDay(points []HistoryPoint) map[string]float64 {
+	byDay := make(map[string]float64, len(points))
+	for _, p := range points {
+		byDay[p.Time.Format("2006-01-02")] = p.Close
+	}
+	return byDay
+}
@@ -0,0 +1,491 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Holiday es un día sin rueda de un mercado
+type Holiday struct {
+	Date   string `json:"date"`   // 2006-01-02, en la zona horaria del mercado
+	Market Market `json:"market"` // BYMA o NYSE
+	Name   string `json:"name"`
+}
+
+// Feriados nacionales y días no laborables de Argentina publicados por decreto, con los puentes turísticos.
+// Para los años que no están acá se usan las reglas de la ley 27.399, sin puentes, hasta que se descarguen
+// con `bolsa holidays update`
+var argentinaHolidays = []Holiday{
+	{"2025-01-01", MarketBYMA, "Año Nuevo"},
+	{"2025-03-03", MarketBYMA, "Carnaval"},
+	{"2025-03-04", MarketBYMA, "Carnaval"},
+	{"2025-03-24", MarketBYMA, "Día Nacional de la Memoria por la Verdad y la Justicia"},
+	{"2025-04-02", MarketBYMA, "Día del Veterano y de los Caídos en la Guerra de Malvinas"},
+	{"2025-04-17", MarketBYMA, "Jueves Santo"},
+	{"2025-04-18", MarketBYMA, "Viernes Santo"},
+	{"2025-05-01", MarketBYMA, "Día del Trabajador"},
+	{"2025-05-02", MarketBYMA, "Puente turístico"},
+	{"2025-05-25", MarketBYMA, "Día de la Revolución de Mayo"},
+	{"2025-06-16", MarketBYMA, "Paso a la Inmortalidad del General Güemes"},
+	{"2025-06-20", MarketBYMA, "Paso a la Inmortalidad del General Belgrano"},
+	{"2025-07-09", MarketBYMA, "Día de la Independencia"},
+	{"2025-08-15", MarketBYMA, "Puente turístico"},
+	{"2025-08-17", MarketBYMA, "Paso a la Inmortalidad del General San Martín"},
+	{"2025-10-12", MarketBYMA, "Día del Respeto a la Diversidad Cultural"},
+	{"2025-11-21", MarketBYMA, "Puente turístico"},
+	{"2025-11-24", MarketBYMA, "Día de la Soberanía Nacional"},
+	{"2025-12-08", MarketBYMA, "Inmaculada Concepción de María"},
+	{"2025-12-25", MarketBYMA, "Navidad"},
+
+	{"2026-01-01", MarketBYMA, "Año Nuevo"},
+	{"2026-02-16", MarketBYMA, "Carnaval"},
+	{"2026-02-17", MarketBYMA, "Carnaval"},
+	{"2026-03-23", MarketBYMA, "Puente turístico"},
+	{"2026-03-24", MarketBYMA, "Día Nacional de la Memoria por la Verdad y la Justicia"},
+	{"2026-04-02", MarketBYMA, "Día del Veterano y de los Caídos en la Guerra de Malvinas / Jueves Santo"},
+	{"2026-04-03", MarketBYMA, "Viernes Santo"},
+	{"2026-05-01", MarketBYMA, "Día del Trabajador"},
+	{"2026-05-25", MarketBYMA, "Día de la Revolución de Mayo"},
+	{"2026-06-15", MarketBYMA, "Paso a la Inmortalidad del General Güemes"},
+	{"2026-06-20", MarketBYMA, "Paso a la Inmortalidad del General Belgrano"},
+	{"2026-07-09", MarketBYMA, "Día de la Independencia"},
+	{"2026-07-10", MarketBYMA, "Puente turístico"},
+	{"2026-08-17", MarketBYMA, "Paso a la Inmortalidad del General San Martín"},
+	{"2026-10-12", MarketBYMA, "Día del Respeto a la Diversidad Cultural"},
+	{"2026-11-23", MarketBYMA, "Día de la Soberanía Nacional"},
+	{"2026-12-07", MarketBYMA, "Puente turístico"},
+	{"2026-12-08", MarketBYMA, "Inmaculada Concepción de María"},
+	{"2026-12-25", MarketBYMA, "Navidad"},
+}
+
+// Feriados descargados y cierres agregados a mano, en el directorio de la app
+const holidaysFile = "holidays.json"
+
+// Feriados oficiales de Argentina por año (incluye días no laborables y puentes)
+const argentinaHolidaysURL = "https://api.argentinadatos.com/v1/feriados/%d"
+
+// HolidayFile es el calendario actualizable: los feriados oficiales descargados reemplazan a los embebidos
+// o calculados de su año y mercado; los extra se suman (duelos nacionales, cierres excepcionales)
+type HolidayFile struct {
+	Updated  time.Time `json:"updated"`
+	Official []Holiday `json:"official"`
+	Extra    []Holiday `json:"extra"`
+}
+
+// El calendario se arma una vez por año y mercado y se invalida al actualizar holidays.json
+var (
+	holidaysMu     sync.Mutex
+	holidaysLoaded bool
+	holidaysStored HolidayFile
+	holidaysByYear = make(map[string]map[string]Holiday) // "BYMA 2026" → fecha → feriado
+)
+
+// loadHolidayFile lee holidays.json; si no existe devuelve un calendario vacío
+func loadHolidayFile() (HolidayFile, error) {
+	var file HolidayFile
+	path, err := appFile(holidaysFile)
+	if err != nil {
+		return file, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return file, nil
+	}
+	if err != nil {
+		return file, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return HolidayFile{}, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return file, nil
+}
+
+// saveHolidayFile escribe holidays.json y descarta el calendario armado
+func saveHolidayFile(file HolidayFile) error {
+	path, err := appFile(holidaysFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return err
+	}
+
+	holidaysMu.Lock()
+	holidaysLoaded = false
+	holidaysByYear = make(map[string]map[string]Holiday)
+	holidaysMu.Unlock()
+	return nil
+}
+
+// easter devuelve el domingo de Pascua del año (algoritmo de Meeus/Jones/Butcher)
+func easter(year int, loc *time.Location) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 12, 0, 0, 0, loc)
+}
+
+// movedHoliday aplica la ley 27.399 a un feriado trasladable: martes y miércoles pasan al lunes anterior,
+// jueves y viernes al lunes siguiente
+func movedHoliday(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Tuesday:
+		return t.AddDate(0, 0, -1)
+	case time.Wednesday:
+		return t.AddDate(0, 0, -2)
+	case time.Thursday:
+		return t.AddDate(0, 0, 4)
+	case time.Friday:
+		return t.AddDate(0, 0, 3)
+	}
+	return t
+}
+
+// argentinaRuleHolidays calcula los feriados de un año sin decreto cargado: inamovibles, los que dependen
+// de Pascua y los trasladables. Los puentes turísticos se fijan por decreto y no se pueden anticipar
+func argentinaRuleHolidays(year int) []Holiday {
+	loc := argentinaLocation
+	date := func(month time.Month, day int) time.Time { return time.Date(year, month, day, 12, 0, 0, 0, loc) }
+	pascua := easter(year, loc)
+	// Güemes sigue la misma regla, salvo que si cae martes queda en su día
+	guemes := date(time.June, 17)
+	if guemes.Weekday() != time.Tuesday {
+		guemes = movedHoliday(guemes)
+	}
+
+	days := []struct {
+		t    time.Time
+		name string
+	}{
+		{date(time.January, 1), "Año Nuevo"},
+		{pascua.AddDate(0, 0, -48), "Carnaval"},
+		{pascua.AddDate(0, 0, -47), "Carnaval"},
+		{date(time.March, 24), "Día Nacional de la Memoria por la Verdad y la Justicia"},
+		{date(time.April, 2), "Día del Veterano y de los Caídos en la Guerra de Malvinas"},
+		{pascua.AddDate(0, 0, -3), "Jueves Santo"},
+		{pascua.AddDate(0, 0, -2), "Viernes Santo"},
+		{date(time.May, 1), "Día del Trabajador"},
+		{date(time.May, 25), "Día de la Revolución de Mayo"},
+		{guemes, "Paso a la Inmortalidad del General Güemes"},
+		{date(time.June, 20), "Paso a la Inmortalidad del General Belgrano"},
+		{date(time.July, 9), "Día de la Independencia"},
+		{movedHoliday(date(time.August, 17)), "Paso a la Inmortalidad del General San Martín"},
+		{movedHoliday(date(time.October, 12)), "Día del Respeto a la Diversidad Cultural"},
+		{movedHoliday(date(time.November, 20)), "Día de la Soberanía Nacional"},
+		{date(time.December, 8), "Inmaculada Concepción de María"},
+		{date(time.December, 25), "Navidad"},
+	}
+
+	var holidays []Holiday
+	for _, d := range days {
+		holidays = append(holidays, Holiday{Date: d.t.Format("2006-01-02"), Market: MarketBYMA, Name: d.name})
+	}
+	return holidays
+}
+
+// observedUS corre al viernes o al lunes un feriado de EE.UU. que cae sábado o domingo
+func observedUS(t time.Time) time.Time {
+	switch t.Weekday() {
+	case time.Saturday:
+		return t.AddDate(0, 0, -1)
+	case time.Sunday:
+		return t.AddDate(0, 0, 1)
+	}
+	return t
+}
+
+// nthWeekday devuelve el n-ésimo día de la semana del mes; n negativo cuenta desde el final
+func nthWeekday(year int, month time.Month, weekday time.Weekday, n int, loc *time.Location) time.Time {
+	if n < 0 {
+		last := time.Date(year, month+1, 0, 12, 0, 0, 0, loc)
+		return last.AddDate(0, 0, -((int(last.Weekday())-int(weekday)+7)%7)+7*(n+1))
+	}
+	first := time.Date(year, month, 1, 12, 0, 0, 0, loc)
+	return first.AddDate(0, 0, (int(weekday)-int(first.Weekday())+7)%7+7*(n-1))
+}
+
+// nyseRuleHolidays calcula los cierres de la bolsa de Nueva York, que siguen reglas fijas.
+// Año Nuevo en sábado no se corre al 31 de diciembre: la NYSE no cierra el último día hábil del año
+func nyseRuleHolidays(year int) []Holiday {
+	loc := newYorkLocation
+	date := func(month time.Month, day int) time.Time { return time.Date(year, month, day, 12, 0, 0, 0, loc) }
+
+	days := []struct {
+		t    time.Time
+		name string
+	}{
+		{observedUS(date(time.January, 1)), "New Year's Day"},
+		{nthWeekday(year, time.January, time.Monday, 3, loc), "Martin Luther King Jr. Day"},
+		{nthWeekday(year, time.February, time.Monday, 3, loc), "Washington's Birthday"},
+		{easter(year, loc).AddDate(0, 0, -2), "Good Friday"},
+		{nthWeekday(year, time.May, time.Monday, -1, loc), "Memorial Day"},
+		{observedUS(date(time.June, 19)), "Juneteenth"},
+		{observedUS(date(time.July, 4)), "Independence Day"},
+		{nthWeekday(year, time.September, time.Monday, 1, loc), "Labor Day"},
+		{nthWeekday(year, time.November, time.Thursday, 4, loc), "Thanksgiving Day"},
+		{observedUS(date(time.December, 25)), "Christmas Day"},
+	}
+	var holidays []Holiday
+	for _, d := range days {
+		if d.t.Year() == year {
+			holidays = append(holidays, Holiday{Date: d.t.Format("2006-01-02"), Market: MarketNYSE, Name: d.name})
+		}
+	}
+	return holidays
+}
+
+// yearHolidays arma los feriados de un mercado en un año: los oficiales descargados, si no los embebidos,
+// si no los calculados por reglas; más los cierres extra. Se llama con holidaysMu tomado
+func yearHolidays(market Market, year int) map[string]Holiday {
+	key := fmt.Sprintf("%s %d", market, year)
+	if days, ok := holidaysByYear[key]; ok {
+		return days
+	}
+	if !holidaysLoaded {
+		file, err := loadHolidayFile()
+		if err != nil {
+			fmt.Printf("Error al leer el calendario de feriados: %v\n", err)
+		}
+		holidaysStored, holidaysLoaded = file, true
+	}
+
+	prefix := strconv.Itoa(year) + "-"
+	pick := func(list []Holiday) []Holiday {
+		var picked []Holiday
+		for _, h := range list {
+			if h.Market == market && strings.HasPrefix(h.Date, prefix) {
+				picked = append(picked, h)
+			}
+		}
+		return picked
+	}
+
+	base := pick(holidaysStored.Official)
+	if len(base) == 0 && market == MarketBYMA {
+		base = pick(argentinaHolidays)
+		if len(base) == 0 {
+			base = argentinaRuleHolidays(year)
+		}
+	}
+	if len(base) == 0 && market == MarketNYSE {
+		base = nyseRuleHolidays(year)
+	}
+
+	days := make(map[string]Holiday)
+	for _, h := range append(base, pick(holidaysStored.Extra)...) {
+		days[h.Date] = h
+	}
+	holidaysByYear[key] = days
+	return days
+}
+
+// holidayOn devuelve el feriado del mercado en la fecha de t, si lo hay
+func holidayOn(market Market, t time.Time) (Holiday, bool) {
+	local := t.In(market.Hours().Location)
+	holidaysMu.Lock()
+	defer holidaysMu.Unlock()
+	h, ok := yearHolidays(market, local.Year())[local.Format("2006-01-02")]
+	return h, ok
+}
+
+// holidaysBetween devuelve los feriados de un mercado entre dos fechas inclusive, ordenados
+func holidaysBetween(market Market, from, to time.Time) []Holiday {
+	fromDay, toDay := from.Format("2006-01-02"), to.Format("2006-01-02")
+	holidaysMu.Lock()
+	defer holidaysMu.Unlock()
+
+	var holidays []Holiday
+	for year := from.Year(); year <= to.Year(); year++ {
+		for day, h := range yearHolidays(market, year) {
+			if day >= fromDay && day <= toDay {
+				holidays = append(holidays, h)
+			}
+		}
+	}
+	sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+	return holidays
+}
+
+// tradingDaysBack devuelve la fecha que está n ruedas antes de t (n = 1 es el día de t si hubo rueda)
+func tradingDaysBack(hours MarketHours, t time.Time, n int) time.Time {
+	day := t.In(hours.Location)
+	for count := 0; ; day = day.AddDate(0, 0, -1) {
+		if hours.IsTradingDay(day) {
+			count++
+		}
+		if count >= n {
+			return day
+		}
+	}
+}
+
+// symbolHours devuelve el horario del mercado en que opera un símbolo; false para tipos de cambio y cripto,
+// que cotizan también los días sin rueda
+func symbolHours(symbol string) (MarketHours, bool) {
+	if strings.HasSuffix(symbol, ".BA") {
+		return bymaHours, true
+	}
+	for _, stock := range watchlistSnapshot() {
+		if stock.Symbol == symbol {
+			return stock.Market.Hours(), true
+		}
+	}
+	if entry, ok := catalogLookup(symbol); ok {
+		return entry.Market.Hours(), true
+	}
+	if bonds, err := loadBonds(); err == nil && findBond(bonds, symbol) != nil {
+		return bymaHours, true
+	}
+	return MarketHours{}, false
+}
+
+// fetchArgentinaHolidays descarga los feriados oficiales de un año
+func fetchArgentinaHolidays(year int, client QuoteFetcher) ([]Holiday, error) {
+	resp, err := client.GetWithRetry(fmt.Sprintf(argentinaHolidaysURL, year), map[string]string{"Accept": "application/json"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "para los feriados de %d", year)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []struct {
+		Fecha  string `json:"fecha"`
+		Nombre string `json:"nombre"`
+		Tipo   string `json:"tipo"`
+	}
+	if err := json.Unmarshal(body, &rows); err != nil {
+		return nil, schemaError("argentinadatos", "feriados", body, err.Error())
+	}
+
+	var holidays []Holiday
+	for _, row := range rows {
+		if _, err := time.Parse("2006-01-02", row.Fecha); err != nil {
+			continue
+		}
+		name := row.Nombre
+		if row.Tipo == "puente" {
+			name = "Puente turístico: " + name
+		}
+		holidays = append(holidays, Holiday{Date: row.Fecha, Market: MarketBYMA, Name: name})
+	}
+	if len(holidays) == 0 {
+		return nil, fmt.Errorf("no hay feriados publicados para %d", year)
+	}
+	return holidays, nil
+}
+
+// updateHolidays descarga los feriados de Argentina de los años pedidos y los guarda en holidays.json
+func updateHolidays(years []int) error {
+	file, err := loadHolidayFile()
+	if err != nil {
+		return err
+	}
+	client := NewProviderClient("argentinadatos")
+	for _, year := range years {
+		holidays, err := fetchArgentinaHolidays(year, client)
+		if err != nil {
+			return err
+		}
+		// Reemplaza los feriados de Argentina de ese año y conserva los demás
+		prefix := strconv.Itoa(year) + "-"
+		kept := file.Official[:0]
+		for _, h := range file.Official {
+			if h.Market != MarketBYMA || !strings.HasPrefix(h.Date, prefix) {
+				kept = append(kept, h)
+			}
+		}
+		file.Official = append(kept, holidays...)
+		fmt.Printf("%d: %d feriados de Argentina\n", year, len(holidays))
+	}
+	file.Updated = time.Now()
+	return saveHolidayFile(file)
+}
+
+// Abreviaturas de los días de la semana, empezando por domingo como time.Weekday
+var holidayWeekdays = [...]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"}
+
+// runHolidays implementa `bolsa holidays`: próximos días sin rueda de BYMA y NYSE, y `update` para descargarlos
+func runHolidays(args []string) error {
+	if len(args) > 0 && args[0] == "update" {
+		fs := flag.NewFlagSet("holidays update", flag.ExitOnError)
+		year := fs.Int("year", 0, "año a descargar (por defecto el actual y el siguiente)")
+		fs.Parse(args[1:])
+
+		years := []int{time.Now().Year(), time.Now().Year() + 1}
+		if *year > 0 {
+			years = []int{*year}
+		}
+		if err := updateHolidays(years); err != nil {
+			return fmt.Errorf("no se pudieron actualizar los feriados: %v", err)
+		}
+		path, _ := appFile(holidaysFile)
+		fmt.Printf("Calendario guardado en %s; los cierres excepcionales se agregan a mano en \"extra\"\n", path)
+		return nil
+	}
+
+	fs := flag.NewFlagSet("holidays", flag.ExitOnError)
+	days := fs.Int("days", 90, "mostrar los feriados de los próximos días")
+	year := fs.Int("year", 0, "mostrar los feriados de un año completo")
+	fs.Parse(args)
+
+	from := time.Now().In(argentinaLocation)
+	to := from.AddDate(0, 0, *days)
+	if *year > 0 {
+		from = time.Date(*year, time.January, 1, 12, 0, 0, 0, argentinaLocation)
+		to = time.Date(*year, time.December, 31, 12, 0, 0, 0, argentinaLocation)
+	}
+
+	fmt.Printf("\n%s=== DÍAS SIN RUEDA (%s al %s) ===%s\n\n", Cyan, from.Format("02/01/2006"), to.Format("02/01/2006"), Reset)
+	var all []Holiday
+	for _, market := range []Market{MarketBYMA, MarketNYSE} {
+		all = append(all, holidaysBetween(market, from, to)...)
+	}
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Date < all[j].Date })
+	if len(all) == 0 {
+		fmt.Println("No hay feriados en el período.")
+		return nil
+	}
+
+	for _, h := range all {
+		date, _ := time.ParseInLocation("2006-01-02", h.Date, argentinaLocation)
+		color := Yellow
+		if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+			color = "" // Un feriado en fin de semana no cambia nada
+		}
+		fmt.Printf("%s%-10s %-4s %-5s %s%s\n", color, h.Date, holidayWeekdays[date.Weekday()], h.Market, h.Name, Reset)
+	}
+
+	if file, err := loadHolidayFile(); err == nil && !file.Updated.IsZero() {
+		fmt.Printf("\nFeriados de Argentina actualizados el %s.\n", file.Updated.In(argentinaLocation).Format("02/01/2006"))
+	} else {
+		fmt.Printf("\nFeriados de Argentina embebidos; para años siguientes: bolsa holidays update\n")
+	}
+	return nil
+}
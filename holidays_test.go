@@ -0,0 +1,151 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEaster(t *testing.T) {
+	tests := map[int]string{
+		2019: "2019-04-21",
+		2024: "2024-03-31",
+		2025: "2025-04-20",
+		2026: "2026-04-05",
+		2027: "2027-03-28",
+		2038: "2038-04-25", // La más tardía posible
+	}
+	for year, want := range tests {
+		if got := easter(year, argentinaLocation).Format("2006-01-02"); got != want {
+			t.Errorf("easter(%d) = %s, se esperaba %s", year, got, want)
+		}
+	}
+}
+
+func TestMovedHoliday(t *testing.T) {
+	tests := []struct{ date, want string }{
+		{"2027-08-17", "2027-08-16"}, // Martes → lunes anterior
+		{"2026-06-17", "2026-06-15"}, // Miércoles → lunes anterior
+		{"2025-11-20", "2025-11-24"}, // Jueves → lunes siguiente
+		{"2026-11-20", "2026-11-23"}, // Viernes → lunes siguiente
+		{"2026-10-12", "2026-10-12"}, // Lunes: queda
+		{"2025-08-17", "2025-08-17"}, // Domingo: queda
+	}
+	for _, tt := range tests {
+		date, _ := time.ParseInLocation("2006-01-02", tt.date, argentinaLocation)
+		if got := movedHoliday(date).Format("2006-01-02"); got != tt.want {
+			t.Errorf("movedHoliday(%s) = %s, se esperaba %s", tt.date, got, tt.want)
+		}
+	}
+}
+
+// holidayDates arma el conjunto de fechas de una lista de feriados
+func holidayDates(holidays []Holiday) map[string]bool {
+	dates := make(map[string]bool)
+	for _, h := range holidays {
+		dates[h.Date] = true
+	}
+	return dates
+}
+
+func TestArgentinaRuleHolidays(t *testing.T) {
+	// En 2026 los decretos solo agregaron puentes: las reglas tienen que dar el resto de los feriados embebidos
+	embedded := holidayDates(argentinaHolidays)
+	for _, h := range argentinaRuleHolidays(2026) {
+		if !embedded[h.Date] {
+			t.Errorf("2026: %s (%s) no está entre los feriados publicados", h.Date, h.Name)
+		}
+	}
+
+	rules := holidayDates(argentinaRuleHolidays(2027))
+	for _, date := range []string{
+		"2027-02-08", "2027-02-09", // Carnaval
+		"2027-03-25", "2027-03-26", // Semana Santa
+		"2027-06-21", // Güemes en jueves → lunes siguiente
+		"2027-08-16", // San Martín en martes → lunes anterior
+		"2027-10-11", // 12 de octubre en martes → lunes anterior
+		"2027-11-20", // Soberanía en sábado: queda en su día
+	} {
+		if !rules[date] {
+			t.Errorf("2027: falta el feriado del %s", date)
+		}
+	}
+	for _, date := range []string{"2027-06-17", "2027-08-17", "2027-10-12"} {
+		if rules[date] {
+			t.Errorf("2027: el feriado del %s se tenía que trasladar", date)
+		}
+	}
+}
+
+func TestNYSERuleHolidays(t *testing.T) {
+	tests := []struct {
+		year int
+		want []string
+	}{
+		{2026, []string{
+			"2026-01-01", "2026-01-19", "2026-02-16", "2026-04-03", "2026-05-25",
+			"2026-06-19", "2026-07-03", "2026-09-07", "2026-11-26", "2026-12-25",
+		}},
+		// Año Nuevo en sábado no se corre al 31/12/2021; Juneteenth en domingo pasa al lunes
+		{2022, []string{
+			"2022-01-17", "2022-02-21", "2022-04-15", "2022-05-30",
+			"2022-06-20", "2022-07-04", "2022-09-05", "2022-11-24", "2022-12-26",
+		}},
+	}
+	for _, tt := range tests {
+		got := nyseRuleHolidays(tt.year)
+		if len(got) != len(tt.want) {
+			t.Errorf("%d: %d feriados, se esperaban %d: %v", tt.year, len(got), len(tt.want), got)
+			continue
+		}
+		for i, h := range got {
+			if h.Date != tt.want[i] {
+				t.Errorf("%d: %s el %s, se esperaba el %s", tt.year, h.Name, h.Date, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestHolidayOn(t *testing.T) {
+	tests := []struct {
+		market Market
+		at     time.Time
+		want   bool
+	}{
+		{MarketBYMA, time.Date(2026, time.November, 23, 12, 0, 0, 0, argentinaLocation), true},
+		// 22 h del 23 en Buenos Aires ya es el 24 en UTC: cuenta la fecha del mercado
+		{MarketBYMA, time.Date(2026, time.November, 24, 1, 0, 0, 0, time.UTC), true},
+		{MarketBYMA, time.Date(2026, time.November, 24, 12, 0, 0, 0, argentinaLocation), false},
+		{MarketBYMA, time.Date(2026, time.July, 10, 12, 0, 0, 0, argentinaLocation), true}, // Puente turístico
+		{MarketNYSE, time.Date(2026, time.July, 3, 12, 0, 0, 0, newYorkLocation), true},
+		{MarketNYSE, time.Date(2026, time.November, 23, 12, 0, 0, 0, newYorkLocation), false},
+		{MarketBYMA, time.Date(2026, time.November, 26, 12, 0, 0, 0, argentinaLocation), false}, // Thanksgiving no cierra BYMA
+	}
+	for _, tt := range tests {
+		if _, got := holidayOn(tt.market, tt.at); got != tt.want {
+			t.Errorf("holidayOn(%s, %s) = %v, se esperaba %v", tt.market, tt.at.Format(time.RFC3339), got, tt.want)
+		}
+	}
+}
+
+func TestTradingDaysBack(t *testing.T) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 12, 0, 0, 0, argentinaLocation)
+	}
+	tests := []struct {
+		hours MarketHours
+		from  time.Time
+		n     int
+		want  string
+	}{
+		{bymaHours, at(2026, time.November, 24), 1, "2026-11-24"},
+		{bymaHours, at(2026, time.November, 24), 2, "2026-11-20"}, // Saltea el feriado del lunes y el fin de semana
+		{bymaHours, at(2026, time.November, 22), 1, "2026-11-20"}, // Domingo: la última rueda fue el viernes
+		{bymaHours, at(2026, time.April, 6), 2, "2026-04-01"},     // Semana Santa: jueves y viernes sin rueda
+		{nyseHours, at(2026, time.July, 6), 2, "2026-07-02"},      // 4 de julio observado el viernes 3
+	}
+	for _, tt := range tests {
+		if got := tradingDaysBack(tt.hours, tt.from, tt.n).Format("2006-01-02"); got != tt.want {
+			t.Errorf("tradingDaysBack(%s, %s, %d) = %s, se esperaba %s", tt.hours.Name, tt.from.Format("2006-01-02"), tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"time"
+)
+
+// Eventos que disparan hooks del usuario
+const (
+	EventAlert         = "alert"          // Alerta disparada (una por alerta)
+	EventMarketClose   = "market_close"   // Cierre de la rueda de BYMA, con el resumen del día
+	EventProviderError = "provider_error" // Un proveedor de datos pasó de funcionar a fallar
+)
+
+// Hook es un comando externo que se ejecuta en un evento y recibe el JSON del evento por stdin
+type Hook struct {
+	Event   string   `json:"event"`
+	Command string   `json:"command"` // Se ejecuta con sh -c (cmd /C en Windows)
+	Timeout Duration `json:"timeout"`
+}
+
+// HookEvent es el JSON que recibe el comando por stdin
+type HookEvent struct {
+	Event string      `json:"event"`
+	Time  time.Time   `json:"time"`
+	Data  interface{} `json:"data"`
+}
+
+// Tiempo máximo de un hook sin timeout propio
+const defaultHookTimeout = 30 * time.Second
+
+// Espera a la salida del hook tras cortarlo: sin ella, un proceso hijo del shell que hereda stdout (un sleep,
+// un curl) demora el hook hasta que termina aunque el shell ya se haya cortado
+const hookWaitDelay = time.Second
+
+// validHookEvent indica si el evento existe
+func validHookEvent(event string) bool {
+	switch event {
+	case EventAlert, EventMarketClose, EventProviderError:
+		return true
+	}
+	return false
+}
+
+// runHooks ejecuta en segundo plano los hooks configurados para el evento
+func runHooks(event string, data interface{}) {
+	payload, err := json.Marshal(HookEvent{Event: event, Time: time.Now(), Data: data})
+	if err != nil {
+		fmt.Printf("No se pudo serializar el evento %s: %v\n", event, err)
+		return
+	}
+
+	for _, hook := range appConfig().Hooks {
+		if hook.Event == event {
+			go runHook(hook, payload)
+		}
+	}
+}
+
+// runHook ejecuta un hook con el evento por stdin; un hook que falla solo se registra en pantalla
+func runHook(hook Hook, payload []byte) {
+	output, err := execHook(hook, payload)
+	if err != nil {
+		fmt.Printf("%sHook %s (%s) falló: %v%s\n", Red, hook.Event, hook.Command, err, Reset)
+		if len(output) > 0 {
+			fmt.Printf("%s\n", bytes.TrimSpace(output))
+		}
+	}
+}
+
+// execHook ejecuta el comando del hook con el evento por stdin y devuelve su salida combinada
+func execHook(hook Hook, payload []byte) ([]byte, error) {
+	timeout := time.Duration(hook.Timeout)
+	if timeout <= 0 {
+		timeout = defaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", hook.Command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", hook.Command)
+	}
+	cmd.Stdin = bytes.NewReader(payload)
+	cmd.WaitDelay = hookWaitDelay
+
+	output, err := cmd.CombinedOutput()
+	if ctx.Err() == context.DeadlineExceeded {
+		return output, fmt.Errorf("no terminó en %v", timeout)
+	}
+	return output, err
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"encoding/json"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// skipWithoutShell saltea las pruebas que usan sh -c
+func skipWithoutShell(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("los comandos de prueba usan sh")
+	}
+}
+
+func TestExecHookReceivesEventOnStdin(t *testing.T) {
+	skipWithoutShell(t)
+	payload, err := json.Marshal(HookEvent{Event: EventAlert, Time: time.Now(), Data: map[string]string{"symbol": "GGAL"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := execHook(Hook{Event: EventAlert, Command: "cat"}, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var event HookEvent
+	if err := json.Unmarshal(output, &event); err != nil {
+		t.Fatalf("el hook no recibió el JSON del evento: %q", output)
+	}
+	if event.Event != EventAlert || event.Data.(map[string]interface{})["symbol"] != "GGAL" {
+		t.Errorf("evento recibido = %+v", event)
+	}
+}
+
+func TestExecHookFailure(t *testing.T) {
+	skipWithoutShell(t)
+	output, err := execHook(Hook{Event: EventAlert, Command: "echo sin permiso >&2; exit 3"}, nil)
+	if err == nil || !strings.Contains(err.Error(), "exit status 3") {
+		t.Errorf("err = %v, se esperaba exit status 3", err)
+	}
+	if strings.TrimSpace(string(output)) != "sin permiso" {
+		t.Errorf("salida = %q, se esperaba la de stderr", output)
+	}
+}
+
+func TestExecHookTimeout(t *testing.T) {
+	skipWithoutShell(t)
+	start := time.Now()
+	// El sleep es hijo de sh y hereda la salida: el timeout tiene que cortar igual
+	_, err := execHook(Hook{Event: EventMarketClose, Command: "sleep 10; echo tarde", Timeout: Duration(200 * time.Millisecond)}, nil)
+	if err == nil || !strings.Contains(err.Error(), "no terminó") {
+		t.Errorf("err = %v, se esperaba el timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("el hook con timeout de 200ms tardó %v", elapsed)
+	}
+}
+
+func TestLoadConfigValidatesHooks(t *testing.T) {
+	tests := []struct {
+		hooks string
+		want  string
+	}{
+		{`[{"event": "alerta", "command": "true"}]`, "evento desconocido"},
+		{`[{"event": "alert"}]`, "sin comando"},
+		{`[{"event": "alert", "command": "true", "timeout": "5s"}]`, ""},
+	}
+	for _, tt := range tests {
+		writeConfig(t, `{"hooks": `+tt.hooks+`}`)
+		cfg, err := loadConfig()
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: %v", tt.hooks, err)
+		case tt.want == "" && (len(cfg.Hooks) != 1 || time.Duration(cfg.Hooks[0].Timeout) != 5*time.Second):
+			t.Errorf("%s: hooks = %+v", tt.hooks, cfg.Hooks)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: err = %v, se esperaba %q", tt.hooks, err, tt.want)
+		}
+	}
+}
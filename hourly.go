@@ -0,0 +1,207 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+// HourlyProfile es el movimiento promedio de un símbolo en cada hora del día (hora de Buenos Aires)
+type HourlyProfile struct {
+	Symbol string
+	Moves  map[int]float64 // Hora → variación absoluta promedio dentro de la hora, en %
+	Days   map[int]int     // Hora → ruedas con datos en esa hora
+}
+
+// Peak devuelve la hora en la que el símbolo más se mueve
+func (p HourlyProfile) Peak() (int, bool) {
+	peak, found := 0, false
+	for hour, move := range p.Moves {
+		if !found || move > p.Moves[peak] || (move == p.Moves[peak] && hour < peak) {
+			peak, found = hour, true
+		}
+	}
+	return peak, found
+}
+
+// buildHourlyProfile suma, por rueda y por hora, las variaciones absolutas entre precios consecutivos y promedia entre ruedas.
+// La variación de la apertura contra el cierre anterior no cuenta: es el gap, no el movimiento de la hora
+func buildHourlyProfile(symbol string, points []pricePoint) HourlyProfile {
+	sort.Slice(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+
+	totals := make(map[int]float64)
+	days := make(map[int]int)
+	byDay := make(map[string]map[int]float64)
+	for i := 1; i < len(points); i++ {
+		prev, cur := points[i-1], points[i]
+		prevTime, curTime := prev.Time.In(argentinaLocation), cur.Time.In(argentinaLocation)
+		day := curTime.Format("2006-01-02")
+		if prevTime.Format("2006-01-02") != day || prev.Price <= 0 {
+			continue
+		}
+		if byDay[day] == nil {
+			byDay[day] = make(map[int]float64)
+		}
+		byDay[day][curTime.Hour()] += math.Abs(cur.Price/prev.Price-1) * 100
+	}
+	for _, hours := range byDay {
+		for hour, move := range hours {
+			totals[hour] += move
+			days[hour]++
+		}
+	}
+
+	profile := HourlyProfile{Symbol: symbol, Moves: make(map[int]float64), Days: days}
+	for hour, total := range totals {
+		profile.Moves[hour] = total / float64(days[hour])
+	}
+	return profile
+}
+
+// localIntradayPoints junta los precios de los símbolos en los snapshots guardados de los últimos días
+func localIntradayPoints(symbols []string, days int) (map[string][]pricePoint, int, error) {
+	available, err := historyDays()
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(available) > days {
+		available = available[len(available)-days:]
+	}
+
+	today := time.Now().In(argentinaLocation).Format("2006-01-02")
+	points := make(map[string][]pricePoint)
+	for _, day := range available {
+		prices, err := pricesForDay(day, day == today)
+		if err != nil {
+			fmt.Printf("%s: %v\n", day, err)
+			continue
+		}
+		for _, symbol := range symbols {
+			points[symbol] = append(points[symbol], prices[symbol]...)
+		}
+	}
+	return points, len(available), nil
+}
+
+// yahooIntradayPoints trae velas de 5 minutos de los últimos días de Yahoo, para cuando no hay historial propio
+func yahooIntradayPoints(symbols []string, days int) map[string][]pricePoint {
+	rangeStr := fmt.Sprintf("%dd", min(days, 60))
+	client := NewHTTPClient()
+	points := make(map[string][]pricePoint)
+	for _, symbol := range symbols {
+		history, err := getHistory(symbol, rangeStr, "5m", client)
+		if err != nil {
+			fmt.Printf("%s%s: %v%s\n", Red, symbol, err, Reset)
+			continue
+		}
+		for _, p := range history {
+			points[symbol] = append(points[symbol], pricePoint{p.Time, p.Close})
+		}
+	}
+	return points
+}
+
+// displayHourlyProfiles muestra una fila por símbolo con el movimiento promedio de cada hora; resalta la hora pico
+func displayHourlyProfiles(profiles []HourlyProfile, title string) {
+	hourSet := make(map[int]bool)
+	for _, p := range profiles {
+		for hour := range p.Moves {
+			hourSet[hour] = true
+		}
+	}
+	var hours []int
+	for hour := range hourSet {
+		hours = append(hours, hour)
+	}
+	sort.Ints(hours)
+
+	fmt.Printf("\n%s=== MOVIMIENTO PROMEDIO POR HORA (%s) ===%s\n\n", Cyan, title, Reset)
+	fmt.Printf("%-10s", "Símbolo")
+	for _, hour := range hours {
+		fmt.Printf(" %6s", fmt.Sprintf("%02dh", hour))
+	}
+	fmt.Printf(" %8s\n", "Pico")
+
+	for _, p := range profiles {
+		peak, ok := p.Peak()
+		fmt.Printf("%-10s", p.Symbol)
+		for _, hour := range hours {
+			move, has := p.Moves[hour]
+			switch {
+			case !has:
+				fmt.Printf(" %6s", "-")
+			case hour == peak:
+				fmt.Printf(" %s%5.2f%%%s", Yellow, move, Reset)
+			default:
+				fmt.Printf(" %5.2f%%", move)
+			}
+		}
+		if ok {
+			fmt.Printf(" %5s%02dh", "", peak)
+		}
+		fmt.Println()
+	}
+	fmt.Println("\nSuma de variaciones absolutas dentro de cada hora (hora de Buenos Aires), promediada entre ruedas; sin el gap de apertura.")
+}
+
+// runHourly implementa `bolsa hourly`: en qué franja horaria se mueve más cada símbolo
+func runHourly(args []string) error {
+	fs := flag.NewFlagSet("hourly", flag.ExitOnError)
+	days := fs.Int("days", 30, "ruedas a analizar (las más recientes)")
+	source := fs.String("source", "local", "local (snapshots guardados para bolsa replay) o yahoo (velas de 5 minutos, hasta 60 días)")
+	minDays := fs.Int("min-days", 3, "ruedas mínimas con datos en una hora para mostrarla")
+	fs.Parse(args)
+
+	// Sin símbolos se usa la watchlist activa
+	var symbols []string
+	for _, arg := range fs.Args() {
+		symbols = append(symbols, strings.ToUpper(arg))
+	}
+	if len(symbols) == 0 {
+		for _, stock := range watchlistSnapshot() {
+			symbols = append(symbols, stock.Symbol)
+		}
+	}
+
+	var points map[string][]pricePoint
+	var title string
+	switch *source {
+	case "local":
+		var loaded int
+		var err error
+		points, loaded, err = localIntradayPoints(symbols, *days)
+		if err != nil {
+			return err
+		}
+		if loaded == 0 {
+			return fmt.Errorf("no hay intradiario guardado: activar historyInterval en config.json o usar --source yahoo")
+		}
+		title = fmt.Sprintf("%d ruedas guardadas", loaded)
+	case "yahoo":
+		points = yahooIntradayPoints(symbols, *days)
+		title = fmt.Sprintf("Yahoo, %d días", min(*days, 60))
+	default:
+		return fmt.Errorf("fuente desconocida %q (usar local o yahoo)", *source)
+	}
+
+	var profiles []HourlyProfile
+	for _, symbol := range symbols {
+		profile := buildHourlyProfile(symbol, points[symbol])
+		for hour, n := range profile.Days {
+			if n < *minDays {
+				delete(profile.Moves, hour)
+			}
+		}
+		if len(profile.Moves) > 0 {
+			profiles = append(profiles, profile)
+		}
+	}
+	if len(profiles) == 0 {
+		return fmt.Errorf("no hay suficientes datos intradiarios (al menos %d ruedas por hora) para ningún símbolo", *minDays)
+	}
+	displayHourlyProfiles(profiles, title)
+	return nil
+}
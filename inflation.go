@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Inflación mensual publicada por el BCRA (variación del IPC del INDEC, en %)
+const bcraVariableIPC = 27
+
+// La API del BCRA devuelve a lo sumo unos mil valores por consulta: las series largas se piden por año
+const bcraSeriesChunk = 365 * 24 * time.Hour
+
+// Deflator lleva precios en pesos corrientes a pesos constantes según el CER o el IPC
+type Deflator struct {
+	Name  string      // cer o ipc
+	index []RatePoint // Nivel del índice; para el IPC, un valor por mes con fecha el día 1
+}
+
+// newDeflator descarga el índice entre dos fechas; el IPC se encadena a partir de las variaciones mensuales
+func newDeflator(name string, from, to time.Time, client QuoteFetcher) (*Deflator, error) {
+	variable := bcraVariableCER
+	switch name {
+	case "cer":
+	case "ipc":
+		variable = bcraVariableIPC
+		// El IPC de un mes se publica a mediados del siguiente: hace falta el del mes anterior al inicio
+		from = from.AddDate(0, -2, 0)
+	default:
+		return nil, fmt.Errorf("índice desconocido %q (usar cer o ipc)", name)
+	}
+
+	var series []RatePoint
+	for start := from; !start.After(to); start = start.Add(bcraSeriesChunk) {
+		end := start.Add(bcraSeriesChunk - 24*time.Hour)
+		if end.After(to) {
+			end = to
+		}
+		points, err := getBCRASeries(variable, start, end, client)
+		if err != nil {
+			// Un tramo sin datos (por ejemplo, meses sin IPC publicado todavía) no invalida el resto
+			if strings.Contains(err.Error(), "no tiene datos") {
+				continue
+			}
+			return nil, err
+		}
+		series = append(series, points...)
+	}
+	if len(series) == 0 {
+		return nil, fmt.Errorf("el BCRA no publicó %s entre %s y %s", strings.ToUpper(name), from.Format("02/01/2006"), to.Format("02/01/2006"))
+	}
+
+	d := &Deflator{Name: name}
+	if name == "cer" {
+		d.index = series
+		return d, nil
+	}
+	level := 1.0
+	for _, p := range series {
+		level *= 1 + p.Value/100
+		d.index = append(d.index, RatePoint{Date: time.Date(p.Date.Year(), p.Date.Month(), 1, 0, 0, 0, 0, time.UTC), Value: level})
+	}
+	return d, nil
+}
+
+// deflatorFor descarga el índice que cubre una serie histórica
+func deflatorFor(name string, points []HistoryPoint, client QuoteFetcher) (*Deflator, error) {
+	if len(points) == 0 {
+		return nil, fmt.Errorf("serie vacía")
+	}
+	return newDeflator(name, points[0].Time, points[len(points)-1].Time, client)
+}
+
+// Apply expresa la serie en pesos de su última fecha: cada precio se multiplica por índice final / índice del día
+func (d *Deflator) Apply(points []HistoryPoint) []HistoryPoint {
+	if len(points) == 0 {
+		return points
+	}
+	base := rateAt(d.index, points[len(points)-1].Time)
+	real := make([]HistoryPoint, len(points))
+	for i, p := range points {
+		factor := base / rateAt(d.index, p.Time)
+		real[i] = HistoryPoint{Time: p.Time, Open: p.Open * factor, Close: p.Close * factor, Volume: p.Volume}
+	}
+	return real
+}
+
+// Label describe la unidad de la serie ajustada, para títulos y ejes
+func (d *Deflator) Label(points []HistoryPoint) string {
+	return fmt.Sprintf("pesos constantes %s de %s", strings.ToUpper(d.Name), points[len(points)-1].Time.In(argentinaLocation).Format("01/2006"))
+}
+
+// quotedInPesos indica si la serie de un símbolo está en pesos y tiene sentido deflactarla: los de BYMA,
+// salvo las especies D de los bonos en dólares
+func quotedInPesos(symbol string) bool {
+	symbol = strings.ToUpper(symbol)
+	if !strings.HasSuffix(symbol, ".BA") {
+		return false
+	}
+	bonds, _ := loadBonds()
+	if bond := findBond(bonds, symbol); bond != nil && bond.Currency == "USD" {
+		return false
+	}
+	return true
+}
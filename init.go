@@ -0,0 +1,176 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Criptomonedas que ofrece el asistente, cotizadas en dólares
+var initCrypto = []ForexSymbol{
+	{Symbol: "BTC-USD", Name: "Bitcoin"},
+	{Symbol: "ETH-USD", Name: "Ethereum"},
+	{Symbol: "USDT-USD", Name: "Tether"},
+}
+
+// wizard hace preguntas por la terminal con valores por defecto
+type wizard struct {
+	in *bufio.Scanner
+}
+
+// ask muestra una pregunta y devuelve la respuesta o el valor por defecto
+func (w *wizard) ask(question, def string) string {
+	fmt.Printf("%s%s%s [%s]: ", Cyan, question, Reset, def)
+	if !w.in.Scan() {
+		return def
+	}
+	if answer := strings.TrimSpace(w.in.Text()); answer != "" {
+		return answer
+	}
+	return def
+}
+
+// yes hace una pregunta por sí o no
+func (w *wizard) yes(question string, def bool) bool {
+	defText := "s/N"
+	if def {
+		defText = "S/n"
+	}
+	for {
+		switch strings.ToLower(w.ask(question, defText)) {
+		case "s", "si", "sí", "y", "yes":
+			return true
+		case "n", "no":
+			return false
+		case strings.ToLower(defText):
+			return def
+		}
+		fmt.Println("Respondé s o n.")
+	}
+}
+
+// runInit implementa `bolsa init`: un asistente que genera el config.json inicial
+func runInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	force := fs.Bool("force", false, "sobrescribir un config.json existente sin preguntar")
+	fs.Parse(args)
+
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+
+	w := &wizard{in: bufio.NewScanner(os.Stdin)}
+	fmt.Printf("%s=== Configuración inicial de bolsa ===%s\n", Cyan, Reset)
+	fmt.Println("Enter acepta el valor entre corchetes.")
+
+	if _, err := os.Stat(path); err == nil && !*force {
+		if !w.yes(fmt.Sprintf("Ya existe %s. ¿Sobrescribirlo?", path), false) {
+			return fmt.Errorf("configuración sin cambios")
+		}
+	}
+
+	config := make(map[string]interface{})
+
+	// Acciones: ADRs en Nueva York y/o panel líder de BYMA
+	var watched []WatchlistEntry
+	if w.yes("¿Seguir los ADRs argentinos en Nueva York?", true) {
+		for _, entry := range catalog {
+			if entry.Market == MarketNYSE {
+				watched = append(watched, WatchlistEntry{Symbol: entry.Symbol, Market: entry.Market})
+			}
+		}
+	}
+	if w.yes("¿Seguir el panel líder de BYMA (en pesos)?", false) {
+		for _, entry := range catalog {
+			if entry.Market == MarketBYMA {
+				watched = append(watched, WatchlistEntry{Symbol: entry.Symbol, Market: entry.Market})
+			}
+		}
+	}
+	if extra := w.ask("Otros símbolos separados por coma (por ejemplo AAPL,KO.BA)", ""); extra != "" {
+		for _, symbol := range strings.Split(extra, ",") {
+			symbol = strings.ToUpper(strings.TrimSpace(symbol))
+			if symbol == "" {
+				continue
+			}
+			watched = append(watched, WatchlistEntry{Symbol: symbol, Market: marketForSymbol(symbol)})
+		}
+	}
+	if len(watched) > 0 {
+		config["stocks"] = watched
+	}
+
+	// Tipos de cambio y cripto
+	var forex []ForexSymbol
+	if w.yes("¿Seguir dólar oficial y euro?", true) {
+		forex = append(forex, ForexSymbol{Symbol: "ARS=X", Name: "Dólar Oficial"}, ForexSymbol{Symbol: "EURARS=X", Name: "Euro"})
+	}
+	if w.yes("¿Seguir criptomonedas (Bitcoin, Ethereum, USDT)?", false) {
+		forex = append(forex, initCrypto...)
+	}
+	if len(forex) > 0 {
+		config["forex"] = forex
+	}
+
+	if !w.yes("¿Seguir bonos soberanos (GD29, GD30, GD35 y sus AL)?", true) {
+		config["disableBonds"] = true
+	}
+
+	// Intervalo de actualización
+	for {
+		answer := w.ask("Intervalo de actualización", "5s")
+		interval, err := time.ParseDuration(answer)
+		if err == nil && interval >= time.Second {
+			config["interval"] = Duration(interval)
+			break
+		}
+		fmt.Println("Usá una duración como 5s, 30s o 1m (mínimo 1s).")
+	}
+
+	// Notificaciones
+	notify := make(map[string]interface{})
+	if w.yes("¿Recibir alertas por Telegram?", false) {
+		fmt.Println("Creá un bot con @BotFather y obtené el chat ID escribiéndole al bot y consultando getUpdates.")
+		if token := w.ask("Token del bot (vacío para usar TELEGRAM_BOT_TOKEN)", ""); token != "" {
+			notify["telegramToken"] = token
+		}
+		if chat := w.ask("Chat ID", ""); chat != "" {
+			notify["telegramChats"] = []string{chat}
+		}
+	}
+	if w.yes("¿Recibir alertas por email?", false) {
+		email := EmailConfig{
+			Host:     w.ask("Servidor SMTP", "smtp.gmail.com"),
+			Username: w.ask("Usuario SMTP", ""),
+			From:     w.ask("Remitente", ""),
+			To:       []string{w.ask("Destinatario", "")},
+		}
+		fmt.Println("La contraseña se lee de la variable de entorno BOLSA_SMTP_PASSWORD.")
+		notify["email"] = email
+	}
+	if len(notify) > 0 {
+		config["notify"] = notify
+	}
+
+	data, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// El archivo puede tener el token del bot: solo lo lee el usuario
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%s✓ Configuración guardada en %s%s\n", Green, path, Reset)
+	fmt.Println("Revisala con `bolsa config check` e iniciá el monitor con `bolsa`.")
+	return nil
+}
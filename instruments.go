@@ -0,0 +1,149 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Market es el mercado en el que cotiza un papel de la watchlist
+type Market string
+
+const (
+	MarketNYSE  Market = "NYSE" // ADRs y acciones en Nueva York (cotizan en dólares)
+	MarketBYMA  Market = "BYMA" // Bolsas y Mercados Argentinos (cotizan en pesos)
+	MarketOther Market = ""     // Otros mercados devueltos por la búsqueda
+)
+
+// ParseMarket interpreta el nombre de un mercado sin distinguir mayúsculas; acepta también los códigos de bolsa de Yahoo
+func ParseMarket(s string) (Market, error) {
+	switch strings.ToUpper(strings.TrimSpace(s)) {
+	case "NYSE", "NASDAQ", "NYQ", "NYS", "NMS", "NGM", "NCM", "ASE", "PCX", "BTS":
+		return MarketNYSE, nil
+	case "BYMA", "BCBA", "BUE":
+		return MarketBYMA, nil
+	default:
+		return MarketOther, fmt.Errorf("mercado desconocido %q (usar NYSE o BYMA)", s)
+	}
+}
+
+// marketForSymbol deduce el mercado de un símbolo de Yahoo por su sufijo
+func marketForSymbol(symbol string) Market {
+	if strings.HasSuffix(strings.ToUpper(symbol), ".BA") {
+		return MarketBYMA
+	}
+	return MarketNYSE
+}
+
+// Valid indica si el mercado es uno de los conocidos
+func (m Market) Valid() bool {
+	return m == MarketNYSE || m == MarketBYMA
+}
+
+// String devuelve el nombre del mercado; los otros mercados se muestran como "otros"
+func (m Market) String() string {
+	if m == MarketOther {
+		return "otros"
+	}
+	return string(m)
+}
+
+// Currency devuelve la moneda en la que cotizan los papeles del mercado
+func (m Market) Currency() string {
+	if m == MarketBYMA {
+		return "ARS"
+	}
+	return "USD"
+}
+
+// Hours devuelve el horario de rueda del mercado; los otros mercados se asimilan a BYMA
+func (m Market) Hours() MarketHours {
+	if m == MarketNYSE {
+		return nyseHours
+	}
+	return bymaHours
+}
+
+// UnmarshalText valida el mercado al leer watchlists y config.json; los desconocidos se conservan como otros mercados
+func (m *Market) UnmarshalText(text []byte) error {
+	parsed, err := ParseMarket(string(text))
+	if err != nil {
+		*m = Market(strings.TrimSpace(string(text)))
+		return nil
+	}
+	*m = parsed
+	return nil
+}
+
+// AssetClass es el tipo de instrumento, para no depender de sufijos y nombres de mercado al tratarlos
+type AssetClass int
+
+const (
+	AssetStock  AssetClass = iota // Acción local en pesos
+	AssetADR                      // ADR o acción en Nueva York
+	AssetCedear                   // CEDEAR: certificado local de una acción extranjera
+	AssetBond                     // Bono soberano o corporativo
+	AssetFund                     // Fondo común de inversión
+	AssetForex                    // Tipo de cambio
+	AssetCrypto                   // Criptomoneda
+	AssetFuture                   // Futuro (índices, commodities)
+)
+
+// Nombres de las clases de activo tal como se muestran y se escriben en la configuración
+var assetClassNames = map[AssetClass]string{
+	AssetStock:  "accion",
+	AssetADR:    "adr",
+	AssetCedear: "cedear",
+	AssetBond:   "bono",
+	AssetFund:   "fondo",
+	AssetForex:  "forex",
+	AssetCrypto: "cripto",
+	AssetFuture: "futuro",
+}
+
+// String devuelve el nombre de la clase de activo
+func (c AssetClass) String() string {
+	if name, ok := assetClassNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("AssetClass(%d)", int(c))
+}
+
+// Valid indica si la clase de activo es una de las definidas
+func (c AssetClass) Valid() bool {
+	_, ok := assetClassNames[c]
+	return ok
+}
+
+// Trades24h indica si la clase opera todo el día (tipos de cambio y cripto)
+func (c AssetClass) Trades24h() bool {
+	return c == AssetForex || c == AssetCrypto
+}
+
+// ParseAssetClass interpreta el nombre de una clase de activo, sin distinguir mayúsculas ni acentos y aceptando plurales
+func ParseAssetClass(s string) (AssetClass, error) {
+	name := strings.TrimSuffix(foldAccents(s), "s")
+	name = strings.TrimSuffix(name, "e") // "acciones" → "accion"
+	for class, n := range assetClassNames {
+		if name == n {
+			return class, nil
+		}
+	}
+	return 0, fmt.Errorf("clase de activo desconocida %q", s)
+}
+
+// classifySymbol deduce la clase de activo de un símbolo de Yahoo y el mercado en el que se sigue
+func classifySymbol(symbol string, market Market) AssetClass {
+	upper := strings.ToUpper(symbol)
+	switch {
+	case strings.HasSuffix(upper, "=X"):
+		return AssetForex
+	case strings.HasSuffix(upper, "=F"):
+		return AssetFuture
+	case strings.HasSuffix(upper, "-USD"):
+		return AssetCrypto
+	case market == MarketNYSE:
+		return AssetADR
+	default:
+		return AssetStock
+	}
+}
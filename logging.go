@@ -0,0 +1,91 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// debugLogging muestra el detalle de cada request (headers, reintentos, datos obtenidos); --debug o BOLSA_DEBUG=1
+var debugLogging = os.Getenv("BOLSA_DEBUG") != ""
+
+// debugf imprime solo en nivel debug
+func debugf(format string, args ...interface{}) {
+	if debugLogging {
+		fmt.Printf(format, args...)
+	}
+}
+
+// cycleLog agrega la actividad HTTP de un ciclo para mostrar una sola línea de resumen
+var cycleLog struct {
+	sync.Mutex
+	requests int
+	retries  int
+	errors   map[string]int // Por tipo: timeout, conexión, HTTP 502...
+}
+
+// resetCycleLog empieza a contar un ciclo nuevo
+func resetCycleLog() {
+	cycleLog.Lock()
+	defer cycleLog.Unlock()
+	cycleLog.requests, cycleLog.retries, cycleLog.errors = 0, 0, make(map[string]int)
+}
+
+// logRequest cuenta un request; attempt es 0 para el primer intento y mayor para los reintentos
+func logRequest(attempt int) {
+	cycleLog.Lock()
+	defer cycleLog.Unlock()
+	cycleLog.requests++
+	if attempt > 0 {
+		cycleLog.retries++
+	}
+}
+
+// logRequestError cuenta un intento fallido por tipo de error
+func logRequestError(kind string) {
+	cycleLog.Lock()
+	defer cycleLog.Unlock()
+	if cycleLog.errors == nil {
+		cycleLog.errors = make(map[string]int)
+	}
+	cycleLog.errors[kind]++
+}
+
+// requestErrorKind clasifica un error de red para el resumen del ciclo
+func requestErrorKind(err error) string {
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return "timeout"
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return "DNS"
+	}
+	return "conexión"
+}
+
+// cycleLogSummary arma la línea de resumen del ciclo: requests, reintentos y errores por tipo
+func cycleLogSummary() string {
+	cycleLog.Lock()
+	defer cycleLog.Unlock()
+
+	summary := fmt.Sprintf("Ciclo: %d requests, %d reintentos", cycleLog.requests, cycleLog.retries)
+	if len(cycleLog.errors) == 0 {
+		return summary + ", sin errores"
+	}
+
+	var kinds []string
+	for kind := range cycleLog.errors {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	var parts []string
+	for _, kind := range kinds {
+		parts = append(parts, fmt.Sprintf("%s ×%d", kind, cycleLog.errors[kind]))
+	}
+	return summary + ", errores: " + strings.Join(parts, ", ")
+}
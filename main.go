@@ -1,30 +1,31 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
-	"os/exec"
 	"os/signal"
-	"runtime"
-	"sort"
 	"strings"
-	"sync"
-	"syscall"
 	"time"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
 )
 
 // Colores para la consola
 const (
-	Reset  = "\033[0m"
-	Red    = "\033[31m"
-	Green  = "\033[32m"
-	Yellow = "\033[33m"
-	Blue   = "\033[34m"
-	Cyan   = "\033[36m"
-	White  = "\033[37m"
+	Reset   = "\033[0m"
+	Red     = "\033[31m"
+	Green   = "\033[32m"
+	Yellow  = "\033[33m"
+	Blue    = "\033[34m"
+	Magenta = "\033[35m"
+	Cyan    = "\033[36m"
+	White   = "\033[37m"
 )
 
 // ForexInfo representa la información de un tipo de cambio
@@ -46,80 +47,101 @@ type StockInfo struct {
 	Change        float64
 	ChangePercent float64
 	Volume        int64
-	Market        string
-}
-
-// YahooResponse representa la respuesta de la API de Yahoo Finance
-type YahooResponse struct {
-	QuoteSummary struct {
-		Result []struct {
-			Price struct {
-				RegularMarketPrice struct {
-					Raw float64 `json:"raw"`
-				} `json:"regularMarketPrice"`
-				RegularMarketPreviousClose struct {
-					Raw float64 `json:"raw"`
-				} `json:"regularMarketPreviousClose"`
-				RegularMarketVolume struct {
-					Raw int64 `json:"raw"`
-				} `json:"regularMarketVolume"`
-				ShortName string `json:"shortName"`
-				LongName  string `json:"longName"`
-			} `json:"price"`
-		} `json:"result"`
-		Error *struct {
-			Code        string `json:"code"`
-			Description string `json:"description"`
-		} `json:"error"`
-	} `json:"quoteSummary"`
+	Market        Market
+	Sources       map[string]string // Proveedor que aportó cada campo (price, volume...)
+	Deferred      bool              // Postergado por el scheduler: es la cotización de un ciclo anterior
 }
 
 // HTTPClient con reintentos y timeouts
 type HTTPClient struct {
-	client http.Client
+	client   http.Client
+	doer     Doer // Ejecuta los requests; por defecto client, reemplazable para probar sin red
+	provider string
+	config   ProviderConfig
 }
 
-// NewHTTPClient crea un nuevo cliente HTTP con configuración optimizada
+// NewHTTPClient crea un nuevo cliente HTTP con configuración optimizada para Yahoo Finance
 func NewHTTPClient() *HTTPClient {
+	return NewProviderClient("yahoo")
+}
+
+// NewProviderClient crea un cliente HTTP con los timeouts y reintentos configurados para un proveedor
+func NewProviderClient(provider string) *HTTPClient {
+	cfg := appConfig().provider(provider)
+
 	// Crear un transporte personalizado para configurar timeouts
 	transport := &http.Transport{
-		MaxIdleConns:       10,
-		IdleConnTimeout:    30 * time.Second,
-		DisableCompression: false,
+		DialContext: (&net.Dialer{
+			Timeout: time.Duration(cfg.ConnectTimeout),
+		}).DialContext,
+		TLSHandshakeTimeout: time.Duration(cfg.ConnectTimeout),
+		MaxIdleConns:        10,
+		IdleConnTimeout:     30 * time.Second,
+		DisableCompression:  false,
 		// Configurar proxy si es necesario:
 		// Proxy: http.ProxyURL(proxyURL),
 	}
 
-	return &HTTPClient{
+	c := &HTTPClient{
 		client: http.Client{
-			Timeout:   15 * time.Second,
+			Timeout:   time.Duration(cfg.Timeout),
 			Transport: transport,
 		},
+		provider: provider,
+		config:   cfg,
 	}
+	c.doer = &c.client
+	return c
+}
+
+// backoff devuelve la espera antes del reintento i (exponencial, con tope)
+func (c *HTTPClient) backoff(i int) time.Duration {
+	wait := time.Duration(c.config.Backoff) << uint(i)
+	if max := time.Duration(c.config.MaxBackoff); max > 0 && wait > max {
+		wait = max
+	}
+	return wait
 }
 
 // GetWithRetry realiza una solicitud GET con reintentos
 func (c *HTTPClient) GetWithRetry(url string, headers map[string]string) (*http.Response, error) {
-	maxRetries := 3
+	maxRetries := c.config.MaxRetries
+	if maxRetries < 1 {
+		maxRetries = 1
+	}
 	var resp *http.Response
 	var err error
 
 	for i := 0; i < maxRetries; i++ {
 		if i > 0 {
-			fmt.Printf("Reintento %d/%d para URL: %s\n", i+1, maxRetries, url)
+			debugf("Reintento %d/%d para URL: %s\n", i+1, maxRetries, url)
+		}
+
+		// Con el presupuesto diario agotado no se hacen más requests al proveedor hasta mañana
+		if budgetExhausted(c.provider) {
+			return nil, budgetError(c.provider)
+		}
+		if c.provider == "yahoo" {
+			if err := yahooPaused(); err != nil {
+				return nil, err
+			}
 		}
 
-		req, err := http.NewRequest("GET", url, nil)
+		// Se asigna al err de afuera: si se agotan los reintentos, el error que se devuelve es el del transporte
+		var req *http.Request
+		req, err = http.NewRequest("GET", url, nil)
 		if err != nil {
 			fmt.Printf("Error al crear la solicitud: %v\n", err)
 			return nil, err
 		}
 
-		// Configurar cookies para evitar detección de bot
-		req.AddCookie(&http.Cookie{
-			Name:  "B",
-			Value: "59jd1o5g2nojr&b=3&s=ls",
-		})
+		// Cookie de Yahoo para evitar la detección de bot; no se manda a otros proveedores
+		if c.provider == "yahoo" {
+			req.AddCookie(&http.Cookie{
+				Name:  "B",
+				Value: "59jd1o5g2nojr&b=3&s=ls",
+			})
+		}
 
 		// Agregar headers mejorados
 		for key, value := range headers {
@@ -127,28 +149,65 @@ func (c *HTTPClient) GetWithRetry(url string, headers map[string]string) (*http.
 		}
 
 		// Imprimir los headers para depuración
-		if i == 0 {
+		if i == 0 && debugLogging {
 			fmt.Println("Headers de la solicitud:")
 			for key, values := range req.Header {
 				fmt.Printf("  %s: %s\n", key, values)
 			}
 		}
 
-		fmt.Printf("Realizando solicitud a: %s\n", url)
-		resp, err = c.client.Do(req)
+		debugf("Realizando solicitud a: %s\n", url)
+		logRequest(i)
+		start := time.Now()
+		resp, err = c.doer.Do(req)
+		recordRequest(c.provider, time.Since(start), err)
+		if err == nil {
+			resp.Body = countingBody{ReadCloser: resp.Body, provider: c.provider}
+		}
+
+		if err == nil && recorder != nil {
+			recorder.record(c.provider, req, resp, i+1, time.Since(start))
+		}
 
 		if err != nil {
-			fmt.Printf("Error en la solicitud HTTP: %v\n", err)
+			debugf("Error en la solicitud HTTP: %v\n", err)
+			logRequestError(requestErrorKind(err))
 			// Esperar antes de reintentar
-			waitTime := time.Duration(1<<uint(i)) * time.Second
-			fmt.Printf("Esperando %v antes del siguiente reintento...\n", waitTime)
+			waitTime := c.backoff(i)
+			debugf("Esperando %v antes del siguiente reintento...\n", waitTime)
 			time.Sleep(waitTime)
 			continue
 		}
 
-		fmt.Printf("Respuesta recibida. Código de estado: %d\n", resp.StatusCode)
+		debugf("Respuesta recibida. Código de estado: %d\n", resp.StatusCode)
+
+		// Cada error de Yahoo tiene su política: pausa global, reintento corto, backoff o fallar
+		if code := yahooStatusCode(resp.StatusCode); c.provider == "yahoo" && code != "" {
+			logRequestError(fmt.Sprintf("HTTP %d", resp.StatusCode))
+			resp.Body.Close()
+			switch yahooPolicy(code) {
+			case PolicyPause:
+				err := pauseYahoo(code, resp.StatusCode)
+				markProviderError(c.provider, err)
+				return nil, err
+			case PolicyRetry:
+				time.Sleep(time.Duration(c.config.Backoff))
+				continue
+			case PolicyBackoff:
+				time.Sleep(c.backoff(i))
+				continue
+			default:
+				err := fmt.Errorf("Yahoo respondió %d", resp.StatusCode)
+				markProviderError(c.provider, err)
+				return nil, &QuoteError{Kind: kindForStatus(resp.StatusCode), Status: resp.StatusCode, Code: code, Err: err}
+			}
+		}
 
 		if resp.StatusCode < 500 && resp.StatusCode != 401 {
+			markProviderOK(c.provider)
+			if c.provider == "yahoo" {
+				yahooRecovered()
+			}
 			return resp, nil
 		}
 
@@ -156,32 +215,67 @@ func (c *HTTPClient) GetWithRetry(url string, headers map[string]string) (*http.
 		if resp.StatusCode == 401 && i < maxRetries-1 {
 			resp.Body.Close()
 
-			// Si estamos probando v10, cambiar a v8
-			if strings.Contains(url, "v10") {
+			// Si estamos probando v10 de Yahoo, cambiar a v8; en otros proveedores la URL no se toca
+			if c.provider == "yahoo" && strings.Contains(url, "/v10/") {
 				url = strings.Replace(url, "v10", "v8", 1)
-				fmt.Printf("Cambiando a endpoint v8: %s\n", url)
+				debugf("Cambiando a endpoint v8: %s\n", url)
 				continue
 			}
 		}
 
-		fmt.Printf("Error del servidor (código %d). Reintentando...\n", resp.StatusCode)
+		debugf("Error del servidor (código %d). Reintentando...\n", resp.StatusCode)
+		logRequestError(fmt.Sprintf("HTTP %d", resp.StatusCode))
 		resp.Body.Close()
 
 		// Esperar antes de reintentar (backoff exponencial)
-		waitTime := time.Duration(1<<uint(i)) * time.Second
-		fmt.Printf("Esperando %v antes del siguiente reintento...\n", waitTime)
+		waitTime := c.backoff(i)
+		debugf("Esperando %v antes del siguiente reintento...\n", waitTime)
 		time.Sleep(waitTime)
 	}
 
 	if err != nil {
-		return nil, err
+		markProviderError(c.provider, err)
+		return nil, &QuoteError{Kind: ErrNetwork, Err: err}
 	}
 
 	if resp != nil {
-		return resp, fmt.Errorf("después de %d intentos, el último código de estado fue: %d", maxRetries, resp.StatusCode)
+		err = fmt.Errorf("después de %d intentos, el último código de estado fue: %d", maxRetries, resp.StatusCode)
+		markProviderError(c.provider, err)
+		return resp, &QuoteError{Kind: kindForStatus(resp.StatusCode), Status: resp.StatusCode, Err: err}
 	}
 
-	return nil, fmt.Errorf("después de %d intentos, no se pudo obtener una respuesta", maxRetries)
+	err = fmt.Errorf("después de %d intentos, no se pudo obtener una respuesta", maxRetries)
+	markProviderError(c.provider, err)
+	return nil, &QuoteError{Kind: ErrNetwork, Err: err}
+}
+
+// PostForm envía un formulario en un único intento, con el mismo transporte, presupuesto, estadísticas y
+// grabación que GetWithRetry. No reintenta: repetir un login rechazado puede bloquear la cuenta
+func (c *HTTPClient) PostForm(rawURL string, form url.Values) (*http.Response, error) {
+	if budgetExhausted(c.provider) {
+		return nil, budgetError(c.provider)
+	}
+	req, err := http.NewRequest(http.MethodPost, rawURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	debugf("Realizando solicitud POST a: %s\n", rawURL)
+	logRequest(0)
+	start := time.Now()
+	resp, err := c.doer.Do(req)
+	recordRequest(c.provider, time.Since(start), err)
+	if err != nil {
+		logRequestError(requestErrorKind(err))
+		markProviderError(c.provider, err)
+		return nil, &QuoteError{Kind: ErrNetwork, Err: err}
+	}
+	resp.Body = countingBody{ReadCloser: resp.Body, provider: c.provider}
+	if recorder != nil {
+		recorder.record(c.provider, req, resp, 1, time.Since(start))
+	}
+	return resp, nil
 }
 
 // Lista de símbolos de divisas
@@ -194,403 +288,536 @@ var forexSymbols = []map[string]string{
 }
 
 // Lista completa de ADRs argentinos en NYSE
-var stocks = [][]string{
+var stocks = []WatchlistEntry{
 	// Bancos y Financieras
-	{"GGAL", "NYSE"}, // Grupo Financiero Galicia
-	{"BMA", "NYSE"},  // Banco Macro
-	{"BBAR", "NYSE"}, // BBVA Banco Francés
-	{"SUPV", "NYSE"}, // Grupo Supervielle
-	{"BSMX", "NYSE"}, // Banco Santander México (relacionado con Argentina)
+	{"GGAL", MarketNYSE}, // Grupo Financiero Galicia
+	{"BMA", MarketNYSE},  // Banco Macro
+	{"BBAR", MarketNYSE}, // BBVA Banco Francés
+	{"SUPV", MarketNYSE}, // Grupo Supervielle
+	{"BSMX", MarketNYSE}, // Banco Santander México (relacionado con Argentina)
 
 	// Energía y Petróleo
-	{"YPF", "NYSE"}, // YPF
-	{"PAM", "NYSE"}, // Pampa Energía
-	{"EDN", "NYSE"}, // Edenor
+	{"YPF", MarketNYSE}, // YPF
+	{"PAM", MarketNYSE}, // Pampa Energía
+	{"EDN", MarketNYSE}, // Edenor
 
 	// Tecnología y Telecomunicaciones
-	{"TEO", "NYSE"},  // Telecom Argentina
-	{"GLOB", "NYSE"}, // Globant (tecnología)
-	{"MELI", "NYSE"}, // MercadoLibre
+	{"TEO", MarketNYSE},  // Telecom Argentina
+	{"GLOB", MarketNYSE}, // Globant (tecnología)
+	{"MELI", MarketNYSE}, // MercadoLibre
 
 	// Industria y Materiales
-	{"TS", "NYSE"}, // Tenaris
-	{"TX", "NYSE"}, // Ternium
+	{"TS", MarketNYSE}, // Tenaris
+	{"TX", MarketNYSE}, // Ternium
 
 	// Real Estate y Construcción
-	{"IRS", "NYSE"},  // IRSA
-	{"IRCP", "NYSE"}, // IRSA Propiedades Comerciales
+	{"IRS", MarketNYSE},  // IRSA
+	{"IRCP", MarketNYSE}, // IRSA Propiedades Comerciales
 
 	// Agricultura y Alimentos
-	{"CRESY", "NYSE"}, // Cresud
+	{"CRESY", MarketNYSE}, // Cresud
 
 	// Infraestructura y Transporte
-	{"TGS", "NYSE"}, // Transportadora Gas del Sur
-	{"VSH", "NYSE"}, // Vishay (con operaciones significativas en Argentina)
+	{"TGS", MarketNYSE}, // Transportadora Gas del Sur
+	{"VSH", MarketNYSE}, // Vishay (con operaciones significativas en Argentina)
 }
 
-// ClearScreen limpia la pantalla de la consola
+// Secuencias ANSI: cursor al inicio, borrar la pantalla y el historial de scroll
+const ansiClearScreen = "\033[H\033[2J\033[3J"
+
+// ClearScreen limpia la pantalla de la consola con secuencias ANSI, sin lanzar procesos externos
 func clearScreen() {
-	var cmd *exec.Cmd
-	if runtime.GOOS == "windows" {
-		cmd = exec.Command("cmd", "/c", "cls")
-	} else {
-		cmd = exec.Command("clear")
+	// En modo plano la salida se acumula (logs de CI): solo se separan los ciclos
+	if plainMode {
+		fmt.Println("\n" + strings.Repeat("=", 72))
+		return
 	}
-	cmd.Stdout = os.Stdout
-	cmd.Run()
+	fmt.Print(ansiClearScreen)
 }
 
-// GetTickerData obtiene los datos de un ticker con Yahoo Finance API
-func getTickerData(symbol string, client *HTTPClient) (float64, float64, string, int64, error) {
-	// Probamos primero con la API v8 que suele ser más estable
-	url := fmt.Sprintf("https://query2.finance.yahoo.com/v8/finance/chart/%s", symbol)
-
-	headers := map[string]string{
-		"User-Agent":                "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
-		"Accept":                    "text/html,application/xhtml+xml,application/xml;q=0.9,image/webp,*/*;q=0.8",
-		"Accept-Language":           "en-US,en;q=0.5",
-		"DNT":                       "1",
-		"Connection":                "keep-alive",
-		"Sec-Fetch-Dest":            "document",
-		"Sec-Fetch-Mode":            "navigate",
-		"Sec-Fetch-Site":            "none",
-		"Cache-Control":             "no-cache",
-		"Pragma":                    "no-cache",
-		"Sec-Fetch-User":            "?1",
-		"Upgrade-Insecure-Requests": "1",
-		"Referer":                   "https://finance.yahoo.com/",
-	}
-
-	fmt.Printf("Consultando datos para %s...\n", symbol)
-	resp, err := client.GetWithRetry(url, headers)
-	if err != nil {
-		// Si falla, intentamos con la API v10
-		fmt.Printf("Intentando con API v10 para %s...\n", symbol)
-		url = fmt.Sprintf("https://query1.finance.yahoo.com/v10/finance/quoteSummary/%s?modules=price", symbol)
-		resp, err = client.GetWithRetry(url, headers)
-
-		if err != nil {
-			fmt.Printf("Error en la solicitud HTTP para %s: %v\n", symbol, err)
-			return 0, 0, "", 0, err
+// GetTickerData obtiene los datos de un ticker, consultando una sola vez por símbolo en cada ciclo
+func getTickerData(symbol string, client QuoteFetcher) (float64, float64, string, int64, error) {
+	r := tickerCache.Do(symbol, func() tickerResult {
+		if d, ok := symbolDisabled(symbol); ok {
+			return tickerResult{err: symbolError(symbol, fmt.Errorf("%s desactivado: %s", symbol, d.Reason))}
 		}
-	}
-	defer resp.Body.Close()
-
-	// Verificar el código de estado
-	if resp.StatusCode != http.StatusOK {
-		return 0, 0, "", 0, fmt.Errorf("código de estado HTTP inesperado: %d para %s", resp.StatusCode, symbol)
-	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		fmt.Printf("Error al leer el cuerpo de la respuesta para %s: %v\n", symbol, err)
-		return 0, 0, "", 0, err
-	}
-
-	// Si es API v8 (chart), parseamos diferente
-	if strings.Contains(url, "v8/finance/chart") {
-		return parseV8Response(body, symbol)
-	}
-
-	// Si es API v10 (quoteSummary), usamos el parser original
-	return parseV10Response(body, symbol)
-}
-
-// Parsea respuesta de la API v8 (chart)
-func parseV8Response(body []byte, symbol string) (float64, float64, string, int64, error) {
-	// Definir estructura para API v8
-	var chartResp struct {
-		Chart struct {
-			Result []struct {
-				Meta struct {
-					RegularMarketPrice  float64 `json:"regularMarketPrice"`
-					PreviousClose       float64 `json:"previousClose"`
-					RegularMarketVolume int64   `json:"regularMarketVolume"`
-					ExchangeName        string  `json:"exchangeName"`
-					InstrumentType      string  `json:"instrumentType"`
-					ShortName           string  `json:"shortName"`
-				} `json:"meta"`
-			} `json:"result"`
-			Error *struct {
-				Code        string `json:"code"`
-				Description string `json:"description"`
-			} `json:"error"`
-		} `json:"chart"`
-	}
-
-	err := json.Unmarshal(body, &chartResp)
-	if err != nil {
-		fmt.Printf("Error al decodificar JSON v8 para %s: %v\n", symbol, err)
-		return 0, 0, "", 0, err
-	}
-
-	// Verificar si hay error en la respuesta
-	if chartResp.Chart.Error != nil {
-		fmt.Printf("Error en la respuesta para %s: %s - %s\n",
-			symbol,
-			chartResp.Chart.Error.Code,
-			chartResp.Chart.Error.Description)
-		return 0, 0, "", 0, fmt.Errorf("%s: %s",
-			chartResp.Chart.Error.Code,
-			chartResp.Chart.Error.Description)
-	}
-
-	// Verificar que haya resultados
-	if len(chartResp.Chart.Result) == 0 {
-		fmt.Printf("No hay resultados disponibles para %s\n", symbol)
-		return 0, 0, "", 0, fmt.Errorf("no data available for %s", symbol)
-	}
-
-	meta := chartResp.Chart.Result[0].Meta
-	name := meta.ShortName
-	if name == "" {
-		name = symbol // Si no hay nombre, usamos el símbolo
-	}
-
-	fmt.Printf("Datos obtenidos para %s: precio=%f, previo=%f, nombre=%s\n",
-		symbol, meta.RegularMarketPrice, meta.PreviousClose, name)
-
-	return meta.RegularMarketPrice, meta.PreviousClose, name, meta.RegularMarketVolume, nil
+		// Otro proceso conectado al mismo Redis puede haberlo consultado hace instantes
+		if shared, ok := sharedQuote(symbol); ok {
+			return shared
+		}
+		price, previousClose, name, volume, err := fetchTickerData(symbol, client)
+		result := tickerResult{price: price, previousClose: previousClose, name: name, volume: volume, err: err}
+		if err == nil {
+			storeSharedQuote(symbol, result)
+		} else {
+			applySymbolPolicy(symbol, err)
+		}
+		return result
+	})
+	return r.price, r.previousClose, r.name, r.volume, r.err
 }
 
-// Parsea respuesta de la API v10 (quoteSummary)
-func parseV10Response(body []byte, symbol string) (float64, float64, string, int64, error) {
-	var yahooResp YahooResponse
-	err := json.Unmarshal(body, &yahooResp)
+// FetchTickerData obtiene los datos de un ticker con Yahoo Finance API
+func fetchTickerData(symbol string, client QuoteFetcher) (float64, float64, string, int64, error) {
+	debugf("Consultando datos para %s...\n", symbol)
+	quote, err := bolsa.GetQuote(client, symbol)
 	if err != nil {
-		fmt.Printf("Error al decodificar JSON para %s: %v\n", symbol, err)
-		return 0, 0, "", 0, err
-	}
-
-	if len(yahooResp.QuoteSummary.Result) == 0 {
-		fmt.Printf("No hay resultados disponibles para %s\n", symbol)
-		return 0, 0, "", 0, fmt.Errorf("no data available for %s", symbol)
+		debugf("Error al consultar %s: %v\n", symbol, err)
+		return 0, 0, "", 0, yahooError(symbol, err)
 	}
 
-	price := yahooResp.QuoteSummary.Result[0].Price
-	currentPrice := price.RegularMarketPrice.Raw
-	previousClose := price.RegularMarketPreviousClose.Raw
-	volume := price.RegularMarketVolume.Raw
-
-	name := price.ShortName
-	if name == "" {
-		name = price.LongName
+	if quote.MarketTime > 0 {
+		recordQuoteTime(symbol, time.Unix(quote.MarketTime, 0))
 	}
+	debugf("Datos obtenidos para %s: precio=%f, previo=%f, nombre=%s\n",
+		symbol, quote.Price, quote.PreviousClose, quote.Name)
 
-	fmt.Printf("Datos obtenidos para %s: precio=%f, previo=%f, nombre=%s\n",
-		symbol, currentPrice, previousClose, name)
-
-	return currentPrice, previousClose, name, volume, nil
+	return quote.Price, quote.PreviousClose, quote.Name, quote.Volume, nil
 }
 
-// GetForexData obtiene datos de tipos de cambio
-func getForexData(client *HTTPClient) ([]ForexInfo, error) {
-	var forexData []ForexInfo
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	errorCh := make(chan error, len(forexSymbols))
-
-	for _, forex := range forexSymbols {
-		wg.Add(1)
-		go func(symbol, name string) {
-			defer wg.Done()
+// GetForexData obtiene datos de tipos de cambio; los símbolos que fallan se registran en errs
+// y si no se obtuvo ninguno se devuelve el error con las causas
+func getForexData(ctx context.Context, client QuoteFetcher, errs *fetchErrors) ([]ForexInfo, error) {
+	// Cada tarea escribe solo su lugar del slice: no hace falta lock y se conserva el orden configurado
+	results := make([]*ForexInfo, len(forexSymbols))
+	group := newWorkGroup(ctx, fetchConcurrency)
+	for i, forex := range forexSymbols {
+		i, symbol, name := i, forex["symbol"], forex["name"]
+		group.Go(func(ctx context.Context) error {
 			currentPrice, previousClose, _, _, err := getTickerData(symbol, client)
 			if err != nil {
-				errorCh <- fmt.Errorf("error al obtener datos para %s: %v", symbol, err)
-				return
-			}
-
-			change := currentPrice - previousClose
-			changePercent := 0.0
-			if previousClose != 0 {
-				changePercent = (change / previousClose) * 100
+				errs.add("Forex", symbol, err)
+				return err
 			}
 
-			mu.Lock()
-			forexData = append(forexData, ForexInfo{
+			change, changePercent := priceChange(currentPrice, previousClose)
+			results[i] = &ForexInfo{
 				Symbol:        symbol,
 				Name:          name,
 				Price:         currentPrice,
 				PreviousClose: previousClose,
 				Change:        change,
 				ChangePercent: changePercent,
-			})
-			mu.Unlock()
-		}(forex["symbol"], forex["name"])
+			}
+			return nil
+		})
 	}
+	err := group.Wait()
 
-	wg.Wait()
-	close(errorCh)
-
-	// Procesar errores
-	for err := range errorCh {
-		fmt.Println(err)
+	var forexData []ForexInfo
+	for _, forex := range results {
+		if forex != nil {
+			forexData = append(forexData, *forex)
+		}
+	}
+	if len(forexData) == 0 && err != nil {
+		return nil, fmt.Errorf("no se obtuvo ningún tipo de cambio: %w", err)
 	}
-
 	return forexData, nil
 }
 
-// GetStockData obtiene datos actualizados de las acciones
-func getStockData(dolarRate float64, client *HTTPClient) ([]StockInfo, error) {
-	var stocksData []StockInfo
-	var wg sync.WaitGroup
-	var mu sync.Mutex
-	errorCh := make(chan error, len(stocks))
-
-	for _, stock := range stocks {
-		symbol := stock[0]
-		market := stock[1]
-
-		wg.Add(1)
-		go func(symbol, market string) {
-			defer wg.Done()
-			currentPrice, previousClose, name, volume, err := getTickerData(symbol, client)
-			if err != nil {
-				errorCh <- fmt.Errorf("error al obtener datos para %s: %v", symbol, err)
-				return
-			}
+// newStockInfo arma la fila de una acción a partir de su cotización combinada
+func newStockInfo(symbol string, market Market, quote MergedQuote, dolarRate float64) StockInfo {
+	currentPrice, previousClose := quote.Price, quote.PreviousClose
+
+	change, changePercent := priceChange(currentPrice, previousClose)
+
+	// Convertir a pesos si tenemos la tasa de cambio y el papel cotiza en dólares
+	currentPrice, change = toPesos(currentPrice, change, dolarRate, market)
+
+	return StockInfo{
+		Symbol:        symbol,
+		Name:          displayName(symbol, quote.Name),
+		Price:         currentPrice,
+		PreviousClose: previousClose,
+		Change:        change,
+		ChangePercent: changePercent,
+		Volume:        quote.Volume,
+		Market:        market,
+		Sources:       quote.Sources,
+	}
+}
 
-			change := currentPrice - previousClose
-			changePercent := 0.0
-			if previousClose != 0 {
-				changePercent = (change / previousClose) * 100
-			}
+// GetStockData obtiene datos actualizados de las acciones; los símbolos que fallan se registran en errs
+// y se devuelven unidos en el error, junto con los datos que sí se obtuvieron
+func getStockData(ctx context.Context, dolarRate float64, client QuoteFetcher, errs *fetchErrors) ([]StockInfo, error) {
+	watchlist := watchlistSnapshot()
+
+	// Cerca del límite diario de requests solo se consultan los símbolos prioritarios
+	var priority map[string]bool
+	if budgetTight("yahoo") {
+		priority = prioritySymbols()
+	}
 
-			// Convertir a pesos si tenemos la tasa de cambio y es del mercado NYSE
-			if dolarRate != 0 && market == "NYSE" {
-				currentPrice *= dolarRate
-				change *= dolarRate
+	// Si consultar la watchlist excede el intervalo, una parte de los no importantes se posterga
+	plan := scheduler.Plan(watchlist)
+	start := time.Now()
+
+	results := make([]*StockInfo, len(watchlist))
+	group := newWorkGroup(ctx, fetchConcurrency)
+	skipped, disabledCount, deferred := 0, 0, 0
+	for i, stock := range watchlist {
+		i, symbol, market := i, stock.Symbol, stock.Market
+		if priority != nil && !priority[symbol] {
+			skipped++
+			continue
+		}
+		if _, ok := symbolDisabled(symbol); ok {
+			disabledCount++
+			continue
+		}
+		if plan != nil && !plan[symbol] {
+			if last, ok := scheduler.Deferred(symbol); ok {
+				results[i] = &last
 			}
+			deferred++
+			continue
+		}
 
-			mu.Lock()
-			stocksData = append(stocksData, StockInfo{
-				Symbol:        symbol,
-				Name:          name,
-				Price:         currentPrice,
-				PreviousClose: previousClose,
-				Change:        change,
-				ChangePercent: changePercent,
-				Volume:        volume,
-				Market:        market,
-			})
-			mu.Unlock()
-		}(symbol, market)
+		group.Go(func(ctx context.Context) error {
+			quote, err := getMergedQuote(symbol, market, client)
+			if err != nil {
+				errs.add("Acciones", symbol, err)
+				return err
+			}
+			stock := newStockInfo(symbol, market, quote, dolarRate)
+			results[i] = &stock
+			return nil
+		})
 	}
+	err := group.Wait()
+	scheduler.Record(time.Since(start), time.Duration(appConfig().Interval), len(watchlist))
 
-	wg.Wait()
-	close(errorCh)
-
-	// Procesar errores
-	for err := range errorCh {
-		fmt.Println(err)
+	if skipped > 0 {
+		skippedErr := fmt.Errorf("%d símbolos no prioritarios omitidos: %w", skipped, budgetError("yahoo"))
+		errs.add("Acciones", "", skippedErr)
+		err = errors.Join(err, skippedErr)
+	}
+	if disabledCount > 0 {
+		errs.add("Acciones", "", symbolError("", fmt.Errorf("%d símbolos desactivados por Yahoo (bolsa disabled list)", disabledCount)))
+	}
+	if deferred > 0 {
+		errs.add("Acciones", "", symbolError("", fmt.Errorf("%d símbolos postergados al próximo ciclo por exceder el intervalo (se muestra su última cotización)", deferred)))
 	}
 
-	return stocksData, nil
+	var stocksData []StockInfo
+	for _, stock := range results {
+		if stock != nil {
+			stocksData = append(stocksData, *stock)
+		}
+	}
+	scheduler.Remember(stocksData)
+	return stocksData, err
 }
 
 // DisplayStockRow muestra una fila de datos de acción con formato
-func displayStockRow(stock StockInfo) {
-	// Color según el cambio sea positivo o negativo
-	changeColor := Red
-	if stock.Change >= 0 {
-		changeColor = Green
+func displayStockRow(stock StockInfo, layout StockLayout) {
+	if plainMode {
+		fmt.Println(plainRow(fmt.Sprintf("%-10s", stock.Symbol), fmt.Sprintf("%-30.30s", stock.Name),
+			fmt.Sprintf("%14.2f", stock.Price), fmt.Sprintf("%+10.2f", stock.Change), fmt.Sprintf("%+7.2f%%", stock.ChangePercent),
+			fmt.Sprintf("%12d", stock.Volume), fmt.Sprintf("%-6s", plainRiskLevel(stock.Symbol)), plainSessionRange(stock.Symbol)))
+		return
 	}
 
+	// Color según el signo y la magnitud del cambio
+	changeColor := variationColor(stock.ChangePercent)
+
 	marketColor := Yellow
-	if stock.Market != "NYSE" {
+	if stock.Market != MarketNYSE {
 		marketColor = White
 	}
 
 	// Mostrar símbolo y nombre de la empresa
 	fmt.Printf("%s%-10s%s", marketColor, stock.Symbol, Reset)
 
-	name := stock.Name
-	if len(name) > 30 {
-		name = name[:30]
+	if layout.NameWidth > 0 {
+		fmt.Printf("%s%-*.*s%s ", Cyan, layout.NameWidth, layout.NameWidth, stock.Name, Reset)
 	}
-	fmt.Printf("%s%-31s%s", Cyan, name, Reset)
 
 	// Mostrar precio y cambios
 	fmt.Printf("$%.2f ", stock.Price)
 	fmt.Printf("%s%+.2f (%+.2f%%)%s", changeColor, stock.Change, stock.ChangePercent, Reset)
-	fmt.Printf(" Vol: %d\n", stock.Volume)
+	if layout.Volume {
+		fmt.Printf(" Vol: %d", stock.Volume)
+	}
+	if risk, ok := getRiskProfile(stock.Symbol); ok && layout.Risk {
+		fmt.Printf(" %sRiesgo: %s%s", riskColor(risk.Level), risk.Level, Reset)
+	}
+
+	// Mínimo y máximo propios de la sesión de monitoreo
+	if r, ok := getSessionRange(stock.Symbol); ok && layout.Session {
+		fmt.Printf(" %sSesión: %.2f - %.2f%s", Blue, r.Low, r.High, Reset)
+	}
+	fmt.Println()
 }
 
 // DisplayData muestra los datos en la consola con formato
-func displayData(forexData []ForexInfo, stocksData []StockInfo) {
+func displayData(snapshot *Snapshot, view View) {
+	// No pisar la pantalla mientras hay una búsqueda en curso
+	screenMu.Lock()
+	defer screenMu.Unlock()
+	lastScreen, lastView = snapshot, view
+	setRiskProfiles(snapshot.Risk)
+
+	// La tabla de acciones usa las columnas que entran a lo ancho y las filas que sobran a lo alto
+	layout := fullStockLayout
+	if !plainMode {
+		cols, rows := terminalSize()
+		layout = stockLayoutFor(cols, rows-reservedLines(snapshot, view))
+	}
+
 	clearScreen()
-	fmt.Printf("\n%s=== TIPOS DE CAMBIO ===%s\n", Cyan, Reset)
-	fmt.Printf("Actualizado: %s\n\n", time.Now().Format("2006-01-02 15:04:05"))
+	displayStatusHeader(snapshot.Status, snapshot.Quality)
+	fmt.Printf("Actualizado: %s\n", snapshot.Time.Format("2006-01-02 15:04:05"))
+	if snapshot.Cached {
+		displayCachedBanner(snapshot)
+	}
+
+	if view.Heatmap() {
+		displayHeatmap(snapshot)
+	}
+	if view.Includes(ViewForex) {
+		displayForex(snapshot.Forex)
+		displayEquilibrium(snapshot.Equilibrium)
+		displayExportRates(snapshot.ExportRates)
+		displayCarry(snapshot.Carry)
+	}
+	if view.Includes(ViewStocks) {
+		displayMerval(snapshot.Merval)
+		displayStocks(snapshot.Stocks, layout)
+		displayOrderBooks(snapshot.OrderBooks)
+	}
+	if view.Includes(ViewBonds) {
+		displayBonds(snapshot.Bonds)
+	}
+	if view.Includes(ViewFunds) {
+		displayFunds(snapshot.Funds)
+	}
+	if view.Includes(ViewCommodities) {
+		displayCommodities(snapshot.Commodities)
+	}
+	displayFetchErrors(snapshot.Errors)
+
+	if view.Interactive() {
+		fmt.Printf("\n%sEscribí /texto y Enter para buscar y agregar un símbolo%s\n", Yellow, Reset)
+		fmt.Printf("%sSectores: :sector NOMBRE agrega todos los papeles del sector; :heatmap alterna el mapa de calor%s\n", Yellow, Reset)
+		fmt.Printf("%sAlertas: :ack REGLA, :snooze REGLA 2h, :disable REGLA, :enable REGLA, :list%s\n", Yellow, Reset)
+		fmt.Printf("%sFijar arriba: :pin SIMBOLO, :unpin SIMBOLO; ordenar: :sort change desc (symbol, change, volume, price, sector); detalle: :detail SIMBOLO%s\n", Yellow, Reset)
+		fmt.Printf("%sPáginas de acciones: :next, :prev, :page N; por empresa (ADR + local): :empresas, :expand SIMBOLO%s\n", Yellow, Reset)
+	}
+	fmt.Printf("%sPresiona Ctrl+C para detener el programa%s\n", Yellow, Reset)
+}
+
+// displayForex muestra la sección de tipos de cambio
+func displayForex(forexData []ForexInfo) {
+	fmt.Printf("\n%s=== TIPOS DE CAMBIO ===%s\n\n", Cyan, Reset)
 
 	if len(forexData) > 0 {
-		for _, forex := range forexData {
-			changeColor := Red
-			if forex.Change >= 0 {
-				changeColor = Green
+		pins, rest := splitPinnedForex(forexData)
+		if plainMode {
+			fmt.Println(plainRow(fmt.Sprintf("%-20s", "Tipo de cambio"), fmt.Sprintf("%12s", "Precio"),
+				fmt.Sprintf("%10s", "Cambio"), fmt.Sprintf("%8s", "Var"), "Sesión"))
+		}
+		for i, forex := range append(pins, rest...) {
+			if i == len(pins) && len(pins) > 0 {
+				fmt.Println()
 			}
+			if plainMode {
+				fmt.Println(plainRow(fmt.Sprintf("%-20s", forex.Name), fmt.Sprintf("%12.2f", forex.Price),
+					fmt.Sprintf("%+10.2f", forex.Change), fmt.Sprintf("%+7.2f%%", forex.ChangePercent), plainSessionRange(forex.Symbol)))
+				continue
+			}
+			changeColor := variationColor(forex.ChangePercent)
 
-			fmt.Printf("%s%-12s%s", White, forex.Name, Reset)
+			nameColor := White
+			if i < len(pins) {
+				nameColor = Magenta
+			}
+			fmt.Printf("%s%-12s%s", nameColor, forex.Name, Reset)
 			fmt.Printf("$%.2f ", forex.Price)
-			fmt.Printf("%s%+.2f (%+.2f%%)%s\n", changeColor, forex.Change, forex.ChangePercent, Reset)
+			fmt.Printf("%s%+.2f (%+.2f%%)%s", changeColor, forex.Change, forex.ChangePercent, Reset)
+			if r, ok := getSessionRange(forex.Symbol); ok {
+				fmt.Printf(" %sSesión: %.2f - %.2f%s", Blue, r.Low, r.High, Reset)
+			}
+			fmt.Println()
 		}
 	} else {
 		fmt.Printf("%sNo hay datos disponibles de tipos de cambio%s\n", Red, Reset)
 	}
+}
 
+// displayStocks muestra la sección de acciones
+func displayStocks(stocksData []StockInfo, layout StockLayout) {
 	fmt.Printf("\n%s=== MERCADO DE VALORES ARGENTINO ===%s\n", Cyan, Reset)
 
 	if len(stocksData) > 0 {
+		if plainMode {
+			fmt.Println()
+			fmt.Println(plainRow(fmt.Sprintf("%-10s", "Símbolo"), fmt.Sprintf("%-30s", "Nombre"), fmt.Sprintf("%14s", "Precio"),
+				fmt.Sprintf("%10s", "Cambio"), fmt.Sprintf("%8s", "Var"), fmt.Sprintf("%12s", "Volumen"), fmt.Sprintf("%-6s", "Riesgo"), "Sesión"))
+		}
+
+		// Los fijados van primero, en el orden en que se fijaron, y no se repiten abajo
+		pins, rest := splitPinnedStocks(stocksData)
+
+		// En la vista por empresa, el ADR y el papel local de una misma empresa van en una sola fila
+		var groups []CompanyGroup
+		if companyViewEnabled() {
+			groups, rest = groupByCompany(rest)
+		}
+
 		// Filtrar y ordenar acciones NYSE
 		var nyseStocks []StockInfo
-		for _, stock := range stocksData {
-			if stock.Market == "NYSE" {
+		var otherStocks []StockInfo
+		for _, stock := range rest {
+			if stock.Market == MarketNYSE {
 				nyseStocks = append(nyseStocks, stock)
+			} else {
+				otherStocks = append(otherStocks, stock)
 			}
 		}
 
-		// Ordenar por símbolo
-		sort.Slice(nyseStocks, func(i, j int) bool {
-			return nyseStocks[i].Symbol < nyseStocks[j].Symbol
-		})
+		// Ordenar según el criterio elegido (--sort o :sort)
+		order := currentTableSort()
+		sortStocks(nyseStocks, order)
+		sortStocks(otherStocks, order)
 
-		fmt.Printf("\n%sAcciones argentinas en NYSE (en pesos)%s\n", Yellow, Reset)
-		fmt.Printf("\n%sOrganizado por sectores:%s\n\n", White, Reset)
+		// Los fijados quedan arriba en todas las páginas; el resto se pagina según el alto de la terminal
+		if len(pins) > 0 {
+			fmt.Printf("\n%sFijados%s\n\n", Magenta, Reset)
+			for _, stock := range pins {
+				displayStockRow(stock, layout)
+			}
+		}
+		displayCompanies(groups, layout)
+
+		// Símbolos agregados desde la búsqueda que no cotizan en NYSE van en su propio grupo
+		pageRows := append(append([]StockInfo(nil), nyseStocks...), otherStocks...)
+		page, pages := 0, 1
+		if layout.MaxRows > 0 {
+			perPage := max(layout.MaxRows-len(pins)-companyLines(groups), 3)
+			page, pages = currentStockPage(len(pageRows), perPage)
+			pageRows = pageRows[page*perPage : min((page+1)*perPage, len(pageRows))]
+		}
 
-		for _, stock := range nyseStocks {
-			displayStockRow(stock)
+		// El encabezado de cada grupo se repite en cada página en la que aparece
+		group := ""
+		for _, stock := range pageRows {
+			switch {
+			case stock.Market == MarketNYSE && group != "NYSE":
+				fmt.Printf("\n%sAcciones argentinas en NYSE (en pesos)%s\n", Yellow, Reset)
+				fmt.Printf("\n%sOrdenado por %s:%s\n\n", White, order, Reset)
+			case stock.Market != MarketNYSE && group != "otros":
+				fmt.Printf("\n%sOtros mercados%s\n\n", Yellow, Reset)
+			}
+			group = "otros"
+			if stock.Market == MarketNYSE {
+				group = "NYSE"
+			}
+			displayStockRow(stock, layout)
 		}
-	} else {
-		fmt.Printf("\n%sNo hay datos disponibles del mercado de valores%s\n", Red, Reset)
-	}
 
-	fmt.Printf("\n%sPresiona Ctrl+C para detener el programa%s\n", Yellow, Reset)
-}
+		if pages > 1 {
+			fmt.Printf("\n%sPágina %d/%d de acciones (:next, :prev o :page N)%s\n", Yellow, page+1, pages, Reset)
+		}
 
-// Intenta obtener datos para un símbolo individual como prueba
-func testSymbol(symbol string, client *HTTPClient) {
-	fmt.Printf("\n==== PROBANDO CONEXIÓN CON SÍMBOLO: %s ====\n", symbol)
-	currentPrice, previousClose, name, volume, err := getTickerData(symbol, client)
-	if err != nil {
-		fmt.Printf("❌ Error al probar el símbolo %s: %v\n", symbol, err)
+		// El resumen abarca todo el panel, no solo la página visible
+		displayPanelSummary(stocksData)
 	} else {
-		fmt.Printf("✅ Éxito para el símbolo %s:\n", symbol)
-		fmt.Printf("   Nombre: %s\n", name)
-		fmt.Printf("   Precio actual: %.2f\n", currentPrice)
-		fmt.Printf("   Precio anterior: %.2f\n", previousClose)
-		fmt.Printf("   Volumen: %d\n", volume)
+		fmt.Printf("\n%sNo hay datos disponibles del mercado de valores%s\n", Red, Reset)
 	}
-	fmt.Println("============================================")
 }
 
 func main() {
+	// Las consolas de Windows sin soporte ANSI (o la salida redirigida en Windows) usan el modo plano
+	if os.Getenv("BOLSA_PLAIN") != "" || !enableANSI() {
+		enablePlainMode()
+	}
+
+	// Subcomandos (bolsa curve, ...); sin comando se inicia el monitor
+	if len(os.Args) > 1 && !strings.HasPrefix(os.Args[1], "-") {
+		exitProgram(runCommand(os.Args[1], os.Args[2:]))
+	}
+
+	// Opciones del monitor en vivo
+	gapThreshold := flag.Float64("gap", 3, "alertar en la apertura los papeles con gap mayor a este porcentaje (0 desactiva)")
+	watchlistName := flag.String("watchlist", "", "usar una watchlist guardada en lugar de la lista de ADRs por defecto")
+	recordDir := flag.String("record", "", "guardar cada respuesta HTTP cruda con sus metadatos en este directorio")
+	pprofAddr := flag.String("pprof", "", "exponer net/http/pprof en esta dirección (por ejemplo :6060)")
+	debug := flag.Bool("debug", false, "mostrar el detalle de cada request (headers, reintentos); sin él solo se muestra un resumen por ciclo")
+	plain := flag.Bool("plain", false, "salida ASCII sin colores ni símbolos, con tablas delimitadas por pipes (también BOLSA_PLAIN=1)")
+	rotate := flag.Duration("rotate", 0, "pasar sola a la siguiente página de acciones cada este intervalo (por ejemplo 10s)")
+	sortSpec := flag.String("sort", "", "ordenar las acciones por symbol, change, volume, price o sector, con :asc o :desc (se recuerda)")
+	follow := flag.Bool("follow", false, "no consultar proveedores: mostrar los snapshots que otro proceso publica en Redis")
+	flag.Parse()
+
+	if *plain {
+		enablePlainMode()
+	}
+	if *debug {
+		debugLogging = true
+	}
+
+	if *sortSpec != "" {
+		order, err := parseTableSort(*sortSpec)
+		if err == nil {
+			err = setTableSort(order)
+		}
+		if err != nil {
+			fmt.Printf("%sError: %v%s\n", Red, err, Reset)
+			exitProgram(1)
+		}
+	}
+
+	if *pprofAddr != "" {
+		if err := startPprof(*pprofAddr); err != nil {
+			fmt.Printf("%sError: %v%s\n", Red, err, Reset)
+			exitProgram(1)
+		}
+	}
+
+	if *recordDir != "" {
+		if err := enableRecording(*recordDir); err != nil {
+			fmt.Printf("%sError: %v%s\n", Red, err, Reset)
+			exitProgram(1)
+		}
+	}
+
+	applyWatchedSymbols()
+	if *watchlistName != "" {
+		if err := useWatchlist(*watchlistName); err != nil {
+			fmt.Printf("%sError: %v%s\n", Red, err, Reset)
+			exitProgram(1)
+		}
+	}
+
 	fmt.Println("Iniciando monitoreo del mercado argentino y tipos de cambio...")
 
 	// Crear cliente HTTP
 	client := NewHTTPClient()
 
-	// Canal para manejar la señal de interrupción (Ctrl+C)
+	// Recuperar los mínimos/máximos de sesión si el programa se reinició durante el día
+	if err := loadSessionRanges(); err != nil {
+		fmt.Printf("No se pudieron cargar los rangos de sesión: %v\n", err)
+	}
+
+	// Cargar el catálogo de bonos (embebido más bonds.json del usuario)
+	bonds, err := loadBonds()
+	if err != nil {
+		fmt.Printf("Error al cargar bonos personalizados: %v\n", err)
+	}
+	bondHoldings, err = loadBondHoldings()
+	if err != nil {
+		fmt.Printf("Error al cargar tenencias de bonos: %v\n", err)
+	}
+
+	// Canales de notificación (consola y Telegram si está configurado)
+	notifiers := configuredNotifiers()
+	gapWatcher := NewGapWatcher(*gapThreshold)
+
+	// Canal para manejar la interrupción (Ctrl+C, kill o el cierre de la ventana en Windows)
 	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sigChan, shutdownSignals()...)
 
 	// Canal para salir del bucle principal
 	done := make(chan bool)
@@ -598,69 +825,39 @@ func main() {
 	go func() {
 		<-sigChan
 		fmt.Println("\nMonitoreo finalizado.")
+		leaderLock.Release()
 		done <- true
 	}()
 
-	// Realizar pruebas iniciales de conectividad
-	fmt.Println("\n=== REALIZANDO PRUEBAS DE CONEXIÓN ===")
-	// Probar un símbolo de Yahoo ampliamente conocido - Apple
-	testSymbol("AAPL", client)
-	// Probar un símbolo forex
-	testSymbol("ARS=X", client)
-	// Probar un símbolo argentino
-	testSymbol("YPF", client)
-	fmt.Println("=== FIN DE PRUEBAS DE CONEXIÓN ===\n")
-
-	// Bucle principal de actualización
-	go func() {
-		for {
-			fmt.Println("\n=== INICIANDO CICLO DE ACTUALIZACIÓN ===")
-			// Obtener datos de forex primero para tener la tasa de cambio
-			fmt.Println("Obteniendo datos de FOREX...")
-			forexData, err := getForexData(client)
-			if err != nil {
-				fmt.Printf("\nError al obtener datos forex: %v\n", err)
-				fmt.Println("Reintentando en 5 segundos...")
-				time.Sleep(5 * time.Second)
-				continue
-			}
-
-			fmt.Printf("Se obtuvieron %d registros de FOREX\n", len(forexData))
-
-			// Obtener tasa de cambio del dólar si está disponible
-			var dolarRate float64
-			for _, forex := range forexData {
-				if strings.Contains(forex.Name, "Dólar Oficial") {
-					dolarRate = forex.Price
-					fmt.Printf("Tasa de cambio del dólar: %.2f\n", dolarRate)
-					break
-				}
-			}
-
-			if dolarRate == 0 {
-				fmt.Println("⚠️ No se pudo obtener la tasa del dólar oficial")
-			}
-
-			// Obtener datos de acciones
-			fmt.Println("Obteniendo datos de acciones...")
-			stocksData, err := getStockData(dolarRate, client)
-			if err != nil {
-				fmt.Printf("\nError al obtener datos de acciones: %v\n", err)
-				fmt.Println("Reintentando en 5 segundos...")
-				time.Sleep(5 * time.Second)
-				continue
-			}
+	// Mostrar enseguida la última tabla conocida mientras se completa el primer ciclo
+	if cached, err := loadLastSnapshot(); err != nil {
+		fmt.Printf("No se pudo leer el último snapshot: %v\n", err)
+	} else if cached != nil {
+		displayData(cached, currentMonitorView())
+	}
 
-			fmt.Printf("Se obtuvieron %d registros de acciones\n", len(stocksData))
+	checkForUpdate()
 
-			// Mostrar datos
-			displayData(forexData, stocksData)
+	// Leer comandos del teclado (búsqueda de símbolos con "/")
+	go handleInput(client)
 
-			// Esperar antes de la siguiente actualización
-			fmt.Println("Esperando 5 segundos para la próxima actualización...")
-			time.Sleep(5 * time.Second)
+	// Bucle principal de actualización
+	pipeline := NewPipeline(client, bonds, notifiers, gapWatcher)
+	if *follow {
+		if err := pipeline.Follow(); err != nil {
+			fmt.Printf("%sError: %v%s\n", Red, err, Reset)
+			exitProgram(1)
 		}
-	}()
+	}
+	pipeline.OnSnapshot(func(snapshot *Snapshot) {
+		displayData(snapshot, currentMonitorView())
+	})
+	startTelegramBot(pipeline, client)
+	go watchResize(redrawScreen)
+	if *rotate > 0 {
+		go rotateStockPages(*rotate)
+	}
+	go pipeline.Run()
 
 	// Esperar señal de finalización
 	<-done
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// TestMain aísla las pruebas del directorio de estado real: config.json, historiales y credenciales
+// se leen y escriben en un directorio temporal
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "bolsa-test")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CONFIG_HOME", dir)
+	os.Setenv("HOME", dir)
+	os.Setenv("APPDATA", dir)
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
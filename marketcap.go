@@ -0,0 +1,122 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// La capitalización cambia poco durante la rueda: se consulta como mucho una vez por hora
+const marketCapTTL = time.Hour
+
+// marketCapEntry es la capitalización de un papel en su moneda de cotización
+type marketCapEntry struct {
+	Value    float64
+	Currency string
+	Fetched  time.Time
+}
+
+var (
+	marketCapMu    sync.Mutex
+	marketCapCache = make(map[string]marketCapEntry)
+)
+
+// getMarketCaps devuelve la capitalización en pesos de cada símbolo, usando dolarRate para los que cotizan en dólares
+func getMarketCaps(symbols []string, dolarRate float64, client QuoteFetcher, errs *fetchErrors) map[string]float64 {
+	now := time.Now()
+
+	var stale []string
+	marketCapMu.Lock()
+	for _, symbol := range symbols {
+		if entry, ok := marketCapCache[symbol]; !ok || now.Sub(entry.Fetched) > marketCapTTL {
+			stale = append(stale, symbol)
+		}
+	}
+	marketCapMu.Unlock()
+
+	// La capitalización es accesoria: con el presupuesto en reserva se usa la última conocida
+	if len(stale) > 0 && !budgetTight("yahoo") {
+		fetched, err := fetchMarketCaps(stale, client)
+		if err != nil {
+			errs.add("Capitalización", "", err)
+		}
+		marketCapMu.Lock()
+		for symbol, entry := range fetched {
+			entry.Fetched = now
+			marketCapCache[symbol] = entry
+		}
+		marketCapMu.Unlock()
+	}
+
+	caps := make(map[string]float64, len(symbols))
+	marketCapMu.Lock()
+	defer marketCapMu.Unlock()
+	for _, symbol := range symbols {
+		entry, ok := marketCapCache[symbol]
+		if !ok || entry.Value <= 0 {
+			continue
+		}
+		switch entry.Currency {
+		case "ARS":
+			caps[symbol] = entry.Value
+		case "USD":
+			if dolarRate > 0 {
+				caps[symbol] = entry.Value * dolarRate
+			}
+		}
+	}
+	return caps
+}
+
+// fetchMarketCaps consulta en un solo request la capitalización de varios símbolos (endpoint v7 quote)
+func fetchMarketCaps(symbols []string, client QuoteFetcher) (map[string]marketCapEntry, error) {
+	quoteURL := fmt.Sprintf("https://query1.finance.yahoo.com/v7/finance/quote?symbols=%s&fields=marketCap,currency",
+		url.QueryEscape(strings.Join(symbols, ",")))
+
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Accept":     "application/json",
+		"Referer":    "https://finance.yahoo.com/",
+	}
+
+	resp, err := client.GetWithRetry(quoteURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "al consultar capitalizaciones")
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var quoteResp struct {
+		QuoteResponse struct {
+			Result []struct {
+				Symbol    string    `json:"symbol"`
+				MarketCap FlexFloat `json:"marketCap"`
+				Currency  string    `json:"currency"`
+			} `json:"result"`
+		} `json:"quoteResponse"`
+	}
+	if err := json.Unmarshal(body, &quoteResp); err != nil {
+		return nil, schemaError("v7", strings.Join(symbols, ","), body, err.Error())
+	}
+
+	caps := make(map[string]marketCapEntry)
+	for _, quote := range quoteResp.QuoteResponse.Result {
+		if quote.MarketCap.Valid {
+			caps[quote.Symbol] = marketCapEntry{Value: quote.MarketCap.Value, Currency: quote.Currency}
+		}
+	}
+	return caps, nil
+}
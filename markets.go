@@ -0,0 +1,65 @@
+package main
+
+import (
+	"time"
+)
+
+// MarketHours representa el horario de rueda de un mercado en su zona horaria
+type MarketHours struct {
+	Name     string
+	Location *time.Location
+	Open     time.Duration // Desde la medianoche local
+	Close    time.Duration
+}
+
+// loadLocation carga una zona horaria con un desfasaje fijo como respaldo si no hay tzdata
+func loadLocation(name string, fallbackOffset int) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.FixedZone(name, fallbackOffset*3600)
+	}
+	return loc
+}
+
+var (
+	argentinaLocation = loadLocation("America/Argentina/Buenos_Aires", -3)
+	newYorkLocation   = loadLocation("America/New_York", -5)
+
+	bymaHours = MarketHours{Name: "BYMA", Location: argentinaLocation, Open: 11 * time.Hour, Close: 17 * time.Hour}
+	nyseHours = MarketHours{Name: "NYSE", Location: newYorkLocation, Open: 9*time.Hour + 30*time.Minute, Close: 16 * time.Hour}
+)
+
+// sessionTimes devuelve la apertura y el cierre de la rueda del día de t
+func (m MarketHours) sessionTimes(t time.Time) (time.Time, time.Time) {
+	local := t.In(m.Location)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, m.Location)
+	return midnight.Add(m.Open), midnight.Add(m.Close)
+}
+
+// IsTradingDay indica si en la fecha de t hay rueda: excluye fines de semana y feriados del mercado
+func (m MarketHours) IsTradingDay(t time.Time) bool {
+	switch t.In(m.Location).Weekday() {
+	case time.Saturday, time.Sunday:
+		return false
+	}
+	_, holiday := holidayOn(Market(m.Name), t)
+	return !holiday
+}
+
+// IsOpen indica si el mercado está en horario de rueda en el instante t
+func (m MarketHours) IsOpen(t time.Time) bool {
+	if !m.IsTradingDay(t) {
+		return false
+	}
+	open, close := m.sessionTimes(t)
+	return !t.Before(open) && t.Before(close)
+}
+
+// IsClosedForDay indica si la rueda del día de t ya terminó
+func (m MarketHours) IsClosedForDay(t time.Time) bool {
+	if !m.IsTradingDay(t) {
+		return false
+	}
+	_, close := m.sessionTimes(t)
+	return !t.Before(close)
+}
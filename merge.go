@@ -0,0 +1,221 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// Campos de una cotización que se pueden tomar de distintos proveedores
+const (
+	FieldPrice         = "price"
+	FieldPreviousClose = "previousClose"
+	FieldVolume        = "volume"
+	FieldName          = "name"
+)
+
+var quoteFields = []string{FieldPrice, FieldPreviousClose, FieldVolume, FieldName}
+
+// Proveedores de cotizaciones que puede usar el merger
+var quoteSources = []string{"yahoo", "iol", "byma"}
+
+// Por defecto todo sale de Yahoo; IOL completa lo que falte en los papeles de BYMA si hay credenciales
+var defaultMergeRules = map[string][]string{
+	FieldPrice:         {"yahoo", "iol"},
+	FieldPreviousClose: {"yahoo", "iol"},
+	FieldVolume:        {"yahoo", "iol"},
+	FieldName:          {"yahoo", "iol"},
+}
+
+// validateMergeRules verifica que las reglas de config.json usen campos y proveedores conocidos
+func validateMergeRules(rules map[string][]string) error {
+	for field, sources := range rules {
+		known := false
+		for _, f := range quoteFields {
+			known = known || f == field
+		}
+		if !known {
+			return fmt.Errorf("campo desconocido %q (campos: %s)", field, strings.Join(quoteFields, ", "))
+		}
+		if len(sources) == 0 {
+			return fmt.Errorf("el campo %q no tiene proveedores", field)
+		}
+		for _, source := range sources {
+			known := false
+			for _, s := range quoteSources {
+				known = known || s == source
+			}
+			if !known {
+				return fmt.Errorf("proveedor desconocido %q para %q (proveedores: %s)", source, field, strings.Join(quoteSources, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// mergeRule devuelve los proveedores de un campo en orden de prioridad
+func mergeRule(field string) []string {
+	if sources, ok := appConfig().Merge[field]; ok {
+		return sources
+	}
+	return defaultMergeRules[field]
+}
+
+// yahooQuote consulta Yahoo Finance, compartiendo la consulta del ciclo con el resto de las secciones
+func yahooQuote(symbol string, client QuoteFetcher) tickerResult {
+	price, previousClose, name, volume, err := getTickerData(symbol, client)
+	if name == symbol {
+		name = "" // fetchTickerData completa el nombre con el símbolo cuando Yahoo no lo informa
+	}
+	return tickerResult{price: price, previousClose: previousClose, name: name, volume: volume, err: err}
+}
+
+// iolQuote consulta la cotización de un papel de BYMA en InvertirOnline, una vez por ciclo
+func iolQuote(symbol string, market Market) tickerResult {
+	cfg := appConfig().IOL
+	if market != MarketBYMA || cfg == nil || cfg.Username == "" {
+		return tickerResult{err: fmt.Errorf("IOL solo cotiza papeles de BYMA con credenciales configuradas")}
+	}
+	return tickerCache.Do("iol:"+symbol, func() tickerResult {
+		token, err := iol.accessToken(cfg)
+		if err != nil {
+			return tickerResult{err: err}
+		}
+		resp, err := iol.client.GetWithRetry(fmt.Sprintf("%s/api/v2/bCBA/Titulos/%s/Cotizacion", iolBaseURL, url.PathEscape(strings.TrimSuffix(symbol, ".BA"))),
+			map[string]string{"Authorization": "Bearer " + token, "Accept": "application/json"})
+		if err != nil {
+			return tickerResult{err: err}
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return tickerResult{err: statusError(resp.StatusCode, "para la cotización de %s en IOL", symbol)}
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return tickerResult{err: err}
+		}
+
+		var quote struct {
+			UltimoPrecio      FlexFloat `json:"ultimoPrecio"`
+			CierreAnterior    FlexFloat `json:"cierreAnterior"`
+			VolumenNominal    FlexFloat `json:"volumenNominal"`
+			DescripcionTitulo string    `json:"descripcionTitulo"`
+		}
+		if err := json.Unmarshal(body, &quote); err != nil {
+			return tickerResult{err: &QuoteError{Kind: ErrParse, Symbol: symbol, Err: fmt.Errorf("respuesta inválida de IOL para %s: %v", symbol, err)}}
+		}
+		return tickerResult{
+			price:         quote.UltimoPrecio.Value,
+			previousClose: quote.CierreAnterior.Value,
+			volume:        quote.VolumenNominal.Int(),
+			name:          strings.TrimSpace(quote.DescripcionTitulo),
+		}
+	})
+}
+
+// MergedQuote es una cotización armada campo por campo con el primer proveedor que lo informa
+type MergedQuote struct {
+	Price         float64
+	PreviousClose float64
+	Volume        int64
+	Name          string
+	Sources       map[string]string // Campo → proveedor que lo aportó
+}
+
+// has indica si el resultado de un proveedor informa el campo
+func (r tickerResult) has(field string) bool {
+	switch field {
+	case FieldPrice:
+		return r.price > 0
+	case FieldPreviousClose:
+		return r.previousClose > 0
+	case FieldVolume:
+		return r.volume > 0
+	case FieldName:
+		return r.name != ""
+	}
+	return false
+}
+
+// getMergedQuote arma la cotización de un símbolo según las reglas de prioridad por campo;
+// cada proveedor se consulta solo si hace falta y como mucho una vez
+func getMergedQuote(symbol string, market Market, client QuoteFetcher) (MergedQuote, error) {
+	results := make(map[string]tickerResult)
+	fetch := func(source string) tickerResult {
+		if r, ok := results[source]; ok {
+			return r
+		}
+		// Un campo en cero o vacío se toma como no informado por el proveedor
+		var r tickerResult
+		switch source {
+		case "yahoo":
+			r = yahooQuote(symbol, client)
+		case "iol":
+			r = iolQuote(symbol, market)
+		case "byma":
+			r = bymaQuote(symbol, market)
+		}
+		results[source] = r
+		return r
+	}
+
+	merged := MergedQuote{Sources: make(map[string]string)}
+	var firstErr error
+	for _, field := range quoteFields {
+		for _, source := range mergeRule(field) {
+			r := fetch(source)
+			if r.err != nil {
+				if firstErr == nil {
+					firstErr = r.err
+				}
+				continue
+			}
+			if !r.has(field) {
+				continue
+			}
+			switch field {
+			case FieldPrice:
+				merged.Price = r.price
+			case FieldPreviousClose:
+				merged.PreviousClose = r.previousClose
+			case FieldVolume:
+				merged.Volume = r.volume
+			case FieldName:
+				merged.Name = r.name
+			}
+			merged.Sources[field] = source
+			break
+		}
+	}
+
+	if _, ok := merged.Sources[FieldPrice]; !ok {
+		if firstErr == nil {
+			firstErr = symbolError(symbol, fmt.Errorf("ningún proveedor informó el precio de %s", symbol))
+		}
+		return MergedQuote{}, firstErr
+	}
+	if merged.Name == "" {
+		merged.Name = symbol
+	}
+	return merged, nil
+}
+
+// sourcesText describe qué proveedor aportó cada campo, agrupando los campos por proveedor
+func sourcesText(sources map[string]string) string {
+	byProvider := make(map[string][]string)
+	for _, field := range quoteFields {
+		if source, ok := sources[field]; ok {
+			byProvider[source] = append(byProvider[source], field)
+		}
+	}
+	var parts []string
+	for source, fields := range byProvider {
+		parts = append(parts, fmt.Sprintf("%s (%s)", source, strings.Join(fields, ", ")))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, "; ")
+}
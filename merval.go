@@ -0,0 +1,130 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+const mervalSymbol = "^MERV"
+
+// Ponderaciones aproximadas del panel líder; se pueden ajustar con mervalWeights en config.json
+var defaultMervalWeights = map[string]float64{
+	"GGAL.BA":  0.12,
+	"YPFD.BA":  0.11,
+	"PAMP.BA":  0.09,
+	"BMA.BA":   0.07,
+	"TGSU2.BA": 0.06,
+	"TXAR.BA":  0.05,
+	"CEPU.BA":  0.05,
+	"BBAR.BA":  0.04,
+	"ALUA.BA":  0.04,
+	"TECO2.BA": 0.04,
+	"SUPV.BA":  0.03,
+	"EDN.BA":   0.03,
+	"LOMA.BA":  0.03,
+	"CRES.BA":  0.03,
+	"TRAN.BA":  0.03,
+	"BYMA.BA":  0.03,
+	"VALO.BA":  0.02,
+	"COME.BA":  0.02,
+	"IRSA.BA":  0.02,
+	"MIRG.BA":  0.02,
+	"TGNO4.BA": 0.02,
+	"METR.BA":  0.01,
+	"CVH.BA":   0.01,
+}
+
+// IndexQuote es la cotización de un índice, oficial o estimada a partir de sus componentes
+type IndexQuote struct {
+	Name          string    `json:"name"`
+	Price         float64   `json:"price"`
+	PreviousClose float64   `json:"previous_close"`
+	ChangePercent float64   `json:"change_percent"`
+	AsOf          time.Time `json:"as_of"`
+	Synthetic     bool      `json:"synthetic"` // true si se calculó a partir de los componentes
+	Coverage      float64   `json:"coverage"`  // Fracción de la ponderación con precio disponible
+	Official      float64   `json:"official"`  // Último valor publicado de ^MERV
+	OfficialAsOf  time.Time `json:"official_as_of"`
+}
+
+// getMerval devuelve ^MERV y, si viene demorado durante la rueda, un MERVAL estimado por componentes
+func getMerval(now time.Time, client QuoteFetcher) (*IndexQuote, error) {
+	cfg := appConfig()
+
+	// El histórico intradiario trae el horario del último dato, que el endpoint de cotización no expone
+	points, err := getHistory(mervalSymbol, "1d", "1m", client)
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 {
+		return nil, fmt.Errorf("no hay datos de %s", mervalSymbol)
+	}
+	last := points[len(points)-1]
+
+	_, previousClose, _, _, err := getTickerData(mervalSymbol, client)
+	if err != nil {
+		return nil, err
+	}
+
+	quote := &IndexQuote{
+		Name:          "MERVAL",
+		Price:         last.Close,
+		PreviousClose: previousClose,
+		AsOf:          last.Time,
+		Coverage:      1,
+		Official:      last.Close,
+		OfficialAsOf:  last.Time,
+	}
+
+	delayed := bymaHours.IsOpen(now) && now.Sub(last.Time) > time.Duration(cfg.MervalMaxDelay)
+	if delayed && previousClose > 0 {
+		if ratio, coverage, ok := weightedRatio(cfg.MervalWeights, client); ok {
+			quote.Price = previousClose * ratio
+			quote.AsOf = now
+			quote.Synthetic = true
+			quote.Coverage = coverage
+		}
+	}
+
+	if quote.PreviousClose > 0 {
+		quote.ChangePercent = (quote.Price/quote.PreviousClose - 1) * 100
+	}
+	return quote, nil
+}
+
+// weightedRatio calcula la variación ponderada de la canasta respecto del cierre anterior
+// (1.02 = +2%), renormalizando las ponderaciones sobre los componentes con precio
+func weightedRatio(weights map[string]float64, client QuoteFetcher) (float64, float64, bool) {
+	var total, covered, sum float64
+	for symbol, weight := range weights {
+		total += weight
+		price, previousClose, _, _, err := getTickerData(symbol, client)
+		if err != nil || price <= 0 || previousClose <= 0 {
+			continue
+		}
+		covered += weight
+		sum += weight * price / previousClose
+	}
+
+	// Con menos de la mitad de la canasta el estimado no es representativo
+	if total == 0 || covered < total/2 {
+		return 0, 0, false
+	}
+	return sum / covered, covered / total, true
+}
+
+// displayMerval muestra la línea del índice, aclarando si el valor es estimado
+func displayMerval(quote *IndexQuote) {
+	if quote == nil {
+		return
+	}
+
+	changeColor := variationColor(quote.ChangePercent)
+
+	fmt.Printf("\n%s%-12s%s%.2f %s%+.2f%%%s", White, quote.Name, Reset, quote.Price, changeColor, quote.ChangePercent, Reset)
+	if quote.Synthetic {
+		fmt.Printf(" %s(estimado por componentes, %.0f%% de la canasta; ^MERV %.2f a las %s)%s",
+			Yellow, quote.Coverage*100, quote.Official, quote.OfficialAsOf.In(argentinaLocation).Format("15:04"), Reset)
+	}
+	fmt.Println()
+}
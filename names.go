@@ -0,0 +1,252 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Idiomas de los nombres de empresas: "es" usa el catálogo localizado, "en" el shortName de Yahoo
+const (
+	LanguageSpanish = "es"
+	LanguageEnglish = "en"
+)
+
+const namesFile = "names.json"
+
+// LocalizedName es el nombre y la descripción que el usuario definió para un símbolo (names.json)
+type LocalizedName struct {
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// Descripciones en español por empresa; las comparten el ADR y la acción local del catálogo
+var companyDescriptions = map[string]string{
+	"Grupo Financiero Galicia":        "Holding financiero dueño del Banco Galicia, uno de los mayores bancos privados del país.",
+	"Banco Macro":                     "Banco privado de capital nacional con fuerte presencia en el interior del país.",
+	"BBVA Argentina":                  "Filial argentina del banco español BBVA.",
+	"Grupo Supervielle":               "Grupo financiero con banca minorista, seguros y gestión de activos.",
+	"Grupo Financiero Valores":        "Grupo dueño del Banco de Valores, especializado en mercado de capitales.",
+	"Bolsas y Mercados Argentinos":    "Operador de la bolsa de Buenos Aires, la cámara compensadora y la caja de valores.",
+	"YPF":                             "Petrolera integrada controlada por el Estado nacional, principal operadora de Vaca Muerta.",
+	"Pampa Energía":                   "Empresa integrada de generación eléctrica, petróleo y gas.",
+	"Vista Energy":                    "Petrolera enfocada en la producción no convencional de Vaca Muerta.",
+	"Edenor":                          "Distribuidora de electricidad del norte y oeste del conurbano bonaerense y de CABA.",
+	"Central Puerto":                  "Generadora de energía eléctrica térmica, hidráulica y renovable.",
+	"Transportadora de Gas del Sur":   "Transportista de gas natural del sur del país y procesadora de líquidos.",
+	"Transportadora de Gas del Norte": "Transportista de gas natural del norte y centro del país.",
+	"Transener":                       "Operadora de la red de transmisión eléctrica en alta tensión.",
+	"Metrogas":                        "Distribuidora de gas natural de CABA y el sur del conurbano.",
+	"Telecom Argentina":               "Telefonía fija y móvil, internet y televisión por cable (Personal, Fibertel, Flow).",
+	"Cablevisión Holding":             "Holding controlante de Telecom Argentina.",
+	"Globant":                         "Empresa de desarrollo de software y servicios digitales de origen argentino.",
+	"MercadoLibre":                    "Plataforma de comercio electrónico y pagos (Mercado Pago) de América Latina.",
+	"Despegar":                        "Agencia de viajes online de América Latina.",
+	"Tenaris":                         "Productor de tubos de acero sin costura para la industria petrolera.",
+	"Ternium":                         "Siderúrgica del grupo Techint con plantas en México, Brasil y Argentina.",
+	"Ternium Argentina":               "Siderúrgica del grupo Techint, la mayor productora de acero plano del país.",
+	"Loma Negra":                      "Principal productora de cemento de la Argentina.",
+	"Aluar":                           "Única productora de aluminio primario del país.",
+	"IRSA":                            "Desarrolladora inmobiliaria de shoppings, oficinas y hoteles.",
+	"Cresud":                          "Empresa agropecuaria con tierras en la región y controlante de IRSA.",
+	"Bioceres Crop Solutions":         "Biotecnología agrícola: semillas, inoculantes y el trigo HB4.",
+	"Corporación América Airports":    "Operadora de aeropuertos, entre ellos Ezeiza y Aeroparque.",
+	"Sociedad Comercial del Plata":    "Holding con inversiones en energía, petróleo, ferrocarriles y construcción.",
+	"Mirgor":                          "Fabricante de electrónica y autopartes en Tierra del Fuego.",
+}
+
+// Sufijos societarios y de tipo de instrumento que Yahoo agrega al nombre
+var corporateSuffixes = []string{
+	" American Depositary Shares", " Sponsored ADR", " ADR", " ADS",
+	" S.A.B. de C.V.", " S.A.U.", " S.A.", " SA", " S.A", ", Inc.", " Inc.", " Inc", " Ltd.", " N.V.", " plc",
+}
+
+var (
+	userNamesMu sync.Mutex
+	userNames   map[string]LocalizedName
+)
+
+// loadUserNames lee names.json, donde el usuario corrige o agrega nombres y descripciones
+func loadUserNames() (map[string]LocalizedName, error) {
+	path, err := appFile(namesFile)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]LocalizedName)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return names, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &names); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return names, nil
+}
+
+// saveUserNames guarda names.json
+func saveUserNames(names map[string]LocalizedName) error {
+	path, err := appFile(namesFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(names, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// userName devuelve el nombre definido por el usuario para un símbolo; names.json se lee una sola vez
+func userName(symbol string) (LocalizedName, bool) {
+	userNamesMu.Lock()
+	defer userNamesMu.Unlock()
+	if userNames == nil {
+		names, err := loadUserNames()
+		if err != nil {
+			fmt.Printf("Error al cargar los nombres personalizados: %v\n", err)
+		}
+		if names == nil {
+			names = make(map[string]LocalizedName)
+		}
+		userNames = names
+	}
+	name, ok := userNames[strings.ToUpper(symbol)]
+	return name, ok
+}
+
+// cleanCompanyName quita del nombre de Yahoo los sufijos societarios y de ADR
+func cleanCompanyName(name string) string {
+	name = strings.TrimSpace(name)
+	for changed := true; changed; {
+		changed = false
+		for _, suffix := range corporateSuffixes {
+			if len(name) > len(suffix) && strings.HasSuffix(strings.ToLower(name), strings.ToLower(suffix)) {
+				name = strings.TrimRight(name[:len(name)-len(suffix)], " ,")
+				changed = true
+			}
+		}
+	}
+	return name
+}
+
+// displayName devuelve el nombre a mostrar: el del usuario, el del catálogo localizado o el de Yahoo sin sufijos
+func displayName(symbol, yahooName string) string {
+	if custom, ok := userName(symbol); ok && custom.Name != "" {
+		return custom.Name
+	}
+	if appConfig().Language == LanguageEnglish {
+		return yahooName
+	}
+	if entry, ok := catalogLookup(symbol); ok {
+		return entry.Name
+	}
+	if yahooName == "" {
+		return symbol
+	}
+	return cleanCompanyName(yahooName)
+}
+
+// companyDescription devuelve la descripción en español de la empresa de un símbolo, si se conoce
+func companyDescription(symbol string) string {
+	if custom, ok := userName(symbol); ok && custom.Description != "" {
+		return custom.Description
+	}
+	if entry, ok := catalogLookup(symbol); ok {
+		return companyDescriptions[entry.Name]
+	}
+	return ""
+}
+
+// runNames implementa `bolsa names`: lista y edita los nombres y descripciones localizados
+func runNames(args []string) error {
+	if len(args) == 0 {
+		args = []string{"list"}
+	}
+
+	switch args[0] {
+	case "list":
+		names, err := loadUserNames()
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%sNombres del catálogo%s (los personalizados se marcan con *)\n", Cyan, Reset)
+		seen := make(map[string]bool)
+		for _, entry := range catalog {
+			seen[entry.Symbol] = true
+			name, mark := entry.Name, " "
+			if custom, ok := names[entry.Symbol]; ok && custom.Name != "" {
+				name, mark = custom.Name, "*"
+			}
+			fmt.Printf(" %s%-10s %s\n", mark, entry.Symbol, name)
+		}
+		var extra []string
+		for symbol := range names {
+			if !seen[symbol] {
+				extra = append(extra, symbol)
+			}
+		}
+		sort.Strings(extra)
+		for _, symbol := range extra {
+			fmt.Printf(" *%-10s %s\n", symbol, names[symbol].Name)
+		}
+		return nil
+
+	case "set":
+		fs := flag.NewFlagSet("names set", flag.ExitOnError)
+		name := fs.String("name", "", "nombre a mostrar en la tabla")
+		description := fs.String("description", "", "descripción en español para el panel de detalle")
+		if len(args) < 2 {
+			return fmt.Errorf("uso: bolsa names set SIMBOLO --name \"Nombre\" [--description \"...\"]")
+		}
+		symbol := strings.ToUpper(args[1])
+		fs.Parse(args[2:])
+		if *name == "" && *description == "" {
+			return fmt.Errorf("indicar --name o --description")
+		}
+
+		names, err := loadUserNames()
+		if err != nil {
+			return err
+		}
+		entry := names[symbol]
+		if *name != "" {
+			entry.Name = *name
+		}
+		if *description != "" {
+			entry.Description = *description
+		}
+		names[symbol] = entry
+		if err := saveUserNames(names); err != nil {
+			return err
+		}
+		fmt.Printf("%s✅ Nombre de %s actualizado en %s%s\n", Green, symbol, namesFile, Reset)
+		return nil
+
+	case "unset":
+		if len(args) != 2 {
+			return fmt.Errorf("uso: bolsa names unset SIMBOLO")
+		}
+		symbol := strings.ToUpper(args[1])
+		names, err := loadUserNames()
+		if err != nil {
+			return err
+		}
+		if _, ok := names[symbol]; !ok {
+			return fmt.Errorf("%s no tiene un nombre personalizado", symbol)
+		}
+		delete(names, symbol)
+		if err := saveUserNames(names); err != nil {
+			return err
+		}
+		fmt.Printf("%s vuelve a usar el nombre del catálogo\n", symbol)
+		return nil
+	}
+	return fmt.Errorf("subcomando desconocido %q (list, set, unset)", args[0])
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+const netWorthFile = "networth.json"
+
+// MonthlyValuation es la valuación de la cartera al cierre del último día hábil de un mes
+type MonthlyValuation struct {
+	Month    string             `json:"month"` // 2026-10
+	Date     time.Time          `json:"date"`
+	ARS      float64            `json:"ars"`
+	MEP      float64            `json:"mep"` // Dólar MEP usado para la valuación en dólares
+	USD      float64            `json:"usd"`
+	CER      float64            `json:"cer"`     // CER del día, para expresar los pesos en moneda constante
+	ByClass  map[string]float64 `json:"byClass"` // Valor en pesos por clase de activo
+	Holdings int                `json:"holdings"`
+}
+
+// lastBusinessDay devuelve el último día hábil de BYMA del mes de t
+func lastBusinessDay(t time.Time) time.Time {
+	t = t.In(argentinaLocation)
+	day := time.Date(t.Year(), t.Month()+1, 0, 12, 0, 0, 0, argentinaLocation)
+	for !bymaHours.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// loadNetWorth lee las valuaciones mensuales guardadas, ordenadas por mes
+func loadNetWorth() ([]MonthlyValuation, error) {
+	path, err := appFile(netWorthFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var history []MonthlyValuation
+	if err := json.Unmarshal(data, &history); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Month < history[j].Month })
+	return history, nil
+}
+
+// saveValuation agrega la valuación del mes, reemplazando la que hubiera para ese mes
+func saveValuation(v MonthlyValuation) error {
+	history, err := loadNetWorth()
+	if err != nil {
+		return err
+	}
+	replaced := false
+	for i := range history {
+		if history[i].Month == v.Month {
+			history[i], replaced = v, true
+		}
+	}
+	if !replaced {
+		history = append(history, v)
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].Month < history[j].Month })
+
+	path, err := appFile(netWorthFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// valuePortfolio valúa la cartera completa en pesos y dólares MEP, con el CER del día
+func valuePortfolio(now time.Time, client QuoteFetcher) (MonthlyValuation, error) {
+	portfolio, err := loadPortfolio()
+	if err != nil {
+		return MonthlyValuation{}, err
+	}
+	priced, err := pricePortfolio(portfolio, client)
+	if err != nil {
+		return MonthlyValuation{}, err
+	}
+	mep, err := liveMEP(client)
+	if err != nil {
+		return MonthlyValuation{}, fmt.Errorf("no se pudo calcular el dólar MEP: %v", err)
+	}
+
+	v := MonthlyValuation{
+		Month:    now.In(argentinaLocation).Format("2006-01"),
+		Date:     now,
+		MEP:      mep,
+		ByClass:  make(map[string]float64),
+		Holdings: len(priced),
+	}
+	for _, h := range priced {
+		v.ARS += h.Value
+		v.ByClass[h.Class] += h.Value
+	}
+	if mep > 0 {
+		v.USD = v.ARS / mep
+	}
+
+	// Sin CER la valuación se guarda igual; el ajuste por inflación omite ese mes
+	cer, err := getBCRASeries(bcraVariableCER, now.AddDate(0, 0, -15), now, NewProviderClient("bcra"))
+	if err != nil {
+		fmt.Printf("%sNo se pudo obtener el CER: %v%s\n", Yellow, err, Reset)
+	} else if len(cer) > 0 {
+		v.CER = cer[len(cer)-1].Value
+	}
+	return v, nil
+}
+
+// maybeSaveMonthEnd guarda la valuación de la cartera tras el cierre del último día hábil del mes
+func maybeSaveMonthEnd(client QuoteFetcher) {
+	now := time.Now()
+	if !bymaHours.IsClosedForDay(now) || lastBusinessDay(now).Format("2006-01-02") != now.In(argentinaLocation).Format("2006-01-02") {
+		return
+	}
+	history, err := loadNetWorth()
+	if err != nil {
+		fmt.Printf("Error al leer la evolución patrimonial: %v\n", err)
+		return
+	}
+	month := now.In(argentinaLocation).Format("2006-01")
+	for _, v := range history {
+		if v.Month == month {
+			return
+		}
+	}
+	// Sin cartera definida no hay nada que valuar
+	if path, err := appFile("portfolio.json"); err != nil {
+		return
+	} else if _, err := os.Stat(path); err != nil {
+		return
+	}
+
+	v, err := valuePortfolio(now, client)
+	if err != nil {
+		fmt.Printf("%sNo se pudo valuar la cartera de fin de mes: %v%s\n", Yellow, err, Reset)
+		return
+	}
+	if err := saveValuation(v); err != nil {
+		fmt.Printf("Error al guardar la valuación de fin de mes: %v\n", err)
+		return
+	}
+	fmt.Printf("%sValuación de fin de mes guardada: $%.2f (US$ %.2f MEP)%s\n", Green, v.ARS, v.USD, Reset)
+}
+
+// changeText formatea la variación entre dos valores, o un guion si no hay base
+func changeText(current, previous float64) string {
+	if previous == 0 || current == 0 {
+		return fmt.Sprintf("%9s", "-")
+	}
+	change := (current/previous - 1) * 100
+	color := Green
+	if change < 0 {
+		color = Red
+	}
+	return fmt.Sprintf("%s%+8.2f%%%s", color, change, Reset)
+}
+
+// runNetWorth implementa `bolsa networth`: evolución patrimonial mensual en pesos, dólares MEP y pesos constantes
+func runNetWorth(args []string) error {
+	fs := flag.NewFlagSet("networth", flag.ExitOnError)
+	save := fs.Bool("save", false, "valuar la cartera ahora y guardarla como la del mes en curso")
+	fs.Parse(args)
+
+	if *save {
+		v, err := valuePortfolio(time.Now(), NewHTTPClient())
+		if err != nil {
+			return err
+		}
+		if err := saveValuation(v); err != nil {
+			return err
+		}
+		fmt.Printf("Valuación de %s guardada.\n", v.Month)
+	}
+
+	history, err := loadNetWorth()
+	if err != nil {
+		return err
+	}
+	if len(history) == 0 {
+		return fmt.Errorf("todavía no hay valuaciones: se guardan solas el último día hábil de cada mes con el monitor abierto, o con bolsa networth --save")
+	}
+
+	// Los pesos constantes se expresan en moneda del último mes con CER
+	var lastCER float64
+	for _, v := range history {
+		if v.CER > 0 {
+			lastCER = v.CER
+		}
+	}
+
+	fmt.Printf("\n%s=== EVOLUCIÓN PATRIMONIAL ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-8s %16s %9s %14s %9s %16s %9s\n", "Mes", "Pesos", "Var", "US$ MEP", "Var", "Pesos constantes", "Var real")
+	var prev MonthlyValuation
+	var prevReal float64
+	for _, v := range history {
+		real := 0.0
+		if v.CER > 0 && lastCER > 0 {
+			real = v.ARS * lastCER / v.CER
+		}
+		realText := fmt.Sprintf("%16s", "-")
+		if real > 0 {
+			realText = fmt.Sprintf("%16.2f", real)
+		}
+		fmt.Printf("%-8s %16.2f %s %14.2f %s %s %s\n",
+			v.Month, v.ARS, changeText(v.ARS, prev.ARS), v.USD, changeText(v.USD, prev.USD), realText, changeText(real, prevReal))
+		prev, prevReal = v, real
+	}
+
+	first, last := history[0], history[len(history)-1]
+	if len(history) > 1 && first.USD > 0 {
+		fmt.Printf("\nDesde %s: %+.2f%% en pesos, %+.2f%% en dólares MEP\n",
+			first.Month, (last.ARS/first.ARS-1)*100, (last.USD/first.USD-1)*100)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Regla con la que se registran y rutean las alertas de noticias
+const newsRule = "noticia"
+
+const newsSeenFile = "news_seen.json"
+
+// Términos que asocian un titular a la macro argentina cuando no menciona papeles de la watchlist
+var defaultNewsMacroTerms = []string{"argentina", "bcra", "banco central", "tesoro", "gobierno", "deuda", "ministerio de economia", "caputo", "fmi", "riesgo pais", "reservas", "dolar", "inflacion", "indec"}
+
+// NewsMatch es un titular que contiene palabras clave configuradas
+type NewsMatch struct {
+	Keywords []string
+	Symbols  []string // Papeles de la watchlist que menciona; vacío si es macro
+	Macro    bool
+}
+
+// matchNews busca las palabras clave en el titular y lo asocia a papeles de la watchlist o a la macro
+func matchNews(a Announcement, keywords, macroTerms []string, terms map[string][]string) (NewsMatch, bool) {
+	title := foldAccents(a.Title)
+	var m NewsMatch
+	for _, keyword := range keywords {
+		if k := foldAccents(keyword); k != "" && strings.Contains(title, k) {
+			m.Keywords = append(m.Keywords, keyword)
+		}
+	}
+	if len(m.Keywords) == 0 {
+		return m, false
+	}
+
+	m.Symbols = matchAnnouncement(a, terms)
+	if len(m.Symbols) > 0 {
+		return m, true
+	}
+	text := foldAccents(a.Title + " " + a.Summary)
+	for _, term := range macroTerms {
+		if strings.Contains(text, foldAccents(term)) {
+			m.Macro = true
+			return m, true
+		}
+	}
+	return m, false
+}
+
+// newsMacroTerms devuelve los términos macro de config.json o los por defecto
+func newsMacroTerms() []string {
+	if terms := appConfig().NewsMacroTerms; len(terms) > 0 {
+		return terms
+	}
+	return defaultNewsMacroTerms
+}
+
+// NewsWatcher consulta los feeds de noticias y alerta los titulares con palabras clave sobre la watchlist o la macro
+type NewsWatcher struct {
+	Feeds    []string
+	Keywords []string
+
+	mu      sync.Mutex
+	last    time.Time
+	history feedHistory
+}
+
+// NewNewsWatcher crea el vigilante de noticias
+func NewNewsWatcher(feeds, keywords []string) *NewsWatcher {
+	return &NewsWatcher{Feeds: feeds, Keywords: keywords, history: feedHistory{file: newsSeenFile}}
+}
+
+// Check consulta los feeds si pasó el intervalo y alerta los titulares nuevos con palabras clave
+func (w *NewsWatcher) Check(snapshot *Snapshot, notifiers []Notifier, client QuoteFetcher) {
+	if w == nil || len(w.Feeds) == 0 || len(w.Keywords) == 0 {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(w.last) < announcementInterval {
+		return
+	}
+	w.last = now
+
+	terms := announcementTerms(watchlistSnapshot())
+	macroTerms := newsMacroTerms()
+	var alerts []Alert
+	for _, feed := range w.Feeds {
+		headlines, err := fetchAnnouncements(feed, client)
+		if err != nil {
+			fmt.Printf("%sNo se pudieron consultar las noticias: %v%s\n", Yellow, err, Reset)
+			continue
+		}
+		for _, a := range headlines {
+			if !w.history.isNew(a.ID, now) {
+				continue
+			}
+			m, ok := matchNews(a, w.Keywords, macroTerms, terms)
+			if !ok {
+				continue
+			}
+			keywords := strings.Join(m.Keywords, ", ")
+			if m.Macro {
+				alerts = append(alerts, Alert{
+					Rule:     newsRule,
+					Severity: SeverityWarning,
+					Message:  fmt.Sprintf("Macro (%s): %s\n    %s", keywords, a.Title, a.Link),
+					Time:     now,
+				})
+				continue
+			}
+			for _, symbol := range m.Symbols {
+				alerts = append(alerts, Alert{
+					Rule:     newsRule,
+					Severity: SeverityWarning,
+					Symbol:   symbol,
+					Message:  fmt.Sprintf("%s en las noticias (%s): %s\n    %s", symbol, keywords, a.Title, a.Link),
+					Time:     now,
+				})
+			}
+		}
+	}
+	w.history.save(now)
+
+	if len(alerts) > 0 && alertAllowed(newsRule, now) {
+		dispatchAlerts(notifiers, "Noticias", alerts, snapshot)
+	}
+}
+
+// runNews implementa `bolsa news`: últimos titulares con las palabras clave configuradas
+func runNews(args []string) error {
+	fs := flag.NewFlagSet("news", flag.ExitOnError)
+	feed := fs.String("feed", "", "consultar este feed RSS/Atom en lugar de los de config.json")
+	keywords := fs.String("keywords", "", "palabras clave separadas por comas en lugar de las de config.json")
+	limit := fs.Int("n", 20, "cantidad máxima de titulares a mostrar")
+	fs.Parse(args)
+
+	cfg := appConfig()
+	feeds := cfg.NewsFeeds
+	if *feed != "" {
+		feeds = []string{*feed}
+	}
+	if len(feeds) == 0 {
+		return fmt.Errorf("no hay feeds de noticias: agregar \"newsFeeds\" en config.json o usar --feed URL")
+	}
+	words := cfg.NewsKeywords
+	if *keywords != "" {
+		words = strings.Split(*keywords, ",")
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("no hay palabras clave: agregar \"newsKeywords\" en config.json o usar --keywords cepo,canje")
+	}
+
+	client := NewHTTPClient()
+	var headlines []Announcement
+	for _, f := range feeds {
+		list, err := fetchAnnouncements(f, client)
+		if err != nil {
+			fmt.Printf("%s%v%s\n", Red, err, Reset)
+			continue
+		}
+		headlines = append(headlines, list...)
+	}
+	sort.SliceStable(headlines, func(i, j int) bool { return headlines[i].Published.After(headlines[j].Published) })
+
+	terms := announcementTerms(watchlistSnapshot())
+	macroTerms := newsMacroTerms()
+	shown := 0
+	for _, a := range headlines {
+		if shown >= *limit {
+			break
+		}
+		m, ok := matchNews(a, words, macroTerms, terms)
+		if !ok {
+			continue
+		}
+		shown++
+
+		date := "s/f"
+		if !a.Published.IsZero() {
+			date = a.Published.In(argentinaLocation).Format("2006-01-02 15:04")
+		}
+		tag := "macro"
+		if !m.Macro {
+			tag = strings.Join(m.Symbols, ", ")
+		}
+		fmt.Printf("%s%s%s %s[%s · %s]%s %s\n    %s\n", Cyan, date, Reset, Yellow, tag, strings.Join(m.Keywords, ", "), Reset, a.Title, a.Link)
+	}
+	if shown == 0 {
+		fmt.Println("No hay titulares recientes con las palabras clave sobre la watchlist o la macro.")
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Attachment representa un archivo adjunto a una notificación (por ejemplo un gráfico PNG)
+type Attachment struct {
+	Name string
+	Data []byte
+}
+
+// Notification representa un mensaje a enviar por los canales configurados
+type Notification struct {
+	Title       string
+	Text        string
+	Attachments []Attachment
+}
+
+// Notifier es un canal de envío de notificaciones
+type Notifier interface {
+	Name() string
+	Send(n Notification) error
+}
+
+// ConsoleNotifier imprime las notificaciones en la consola
+type ConsoleNotifier struct{}
+
+// Name devuelve el nombre del canal
+func (ConsoleNotifier) Name() string { return "console" }
+
+// Send imprime la notificación
+func (ConsoleNotifier) Send(n Notification) error {
+	fmt.Printf("\n%s🔔 %s%s\n%s\n", Yellow, n.Title, Reset, n.Text)
+	for _, a := range n.Attachments {
+		fmt.Printf("   (adjunto: %s, %d bytes)\n", a.Name, len(a.Data))
+	}
+	return nil
+}
+
+// LogNotifier agrega las notificaciones a notifications.log, para las alertas informativas
+type LogNotifier struct{}
+
+const notificationLogFile = "notifications.log"
+
+var notificationLogMu sync.Mutex
+
+// Name devuelve el nombre del canal
+func (LogNotifier) Name() string { return "log" }
+
+// Send agrega la notificación al archivo de log
+func (LogNotifier) Send(n Notification) error {
+	path, err := appFile(notificationLogFile)
+	if err != nil {
+		return err
+	}
+
+	notificationLogMu.Lock()
+	defer notificationLogMu.Unlock()
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "%s %s\n%s\n", time.Now().Format("2006-01-02 15:04:05"), n.Title, strings.TrimRight(n.Text, "\n"))
+	return err
+}
+
+// configuredNotifiers arma la lista de canales disponibles a partir del entorno y de config.json
+func configuredNotifiers() []Notifier {
+	notifiers := []Notifier{ConsoleNotifier{}, LogNotifier{}}
+
+	token, chatIDs := telegramCredentials()
+	if token != "" {
+		for _, chatID := range chatIDs {
+			notifiers = append(notifiers, NewTelegramNotifier(token, chatID))
+		}
+	}
+
+	if email := appConfig().Notify.Email; email != nil && email.Host != "" && len(email.To) > 0 {
+		notifiers = append(notifiers, NewEmailNotifier(*email))
+	}
+
+	return notifiers
+}
+
+// telegramCredentials devuelve el token del bot y los chats configurados, sin repetidos.
+// TELEGRAM_CHAT_ID acepta varios chats separados por coma; config.json puede sumar más
+func telegramCredentials() (string, []string) {
+	token := strings.TrimSpace(os.Getenv("TELEGRAM_BOT_TOKEN"))
+	if token == "" {
+		token = appConfig().Notify.TelegramToken
+	}
+
+	var chats []string
+	seen := make(map[string]bool)
+	for _, chatID := range append(strings.Split(os.Getenv("TELEGRAM_CHAT_ID"), ","), appConfig().Notify.TelegramChats...) {
+		chatID = strings.TrimSpace(chatID)
+		if chatID == "" || seen[chatID] {
+			continue
+		}
+		seen[chatID] = true
+		chats = append(chats, chatID)
+	}
+	return token, chats
+}
+
+// Delivery representa el resultado del envío de una notificación por un canal
+type Delivery struct {
+	Channel string
+	Err     error
+}
+
+// anyDelivered indica si al menos un canal recibió la notificación
+func anyDelivered(deliveries []Delivery) bool {
+	for _, delivery := range deliveries {
+		if delivery.Err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyAll envía una notificación por todos los canales y reporta los que fallen
+func notifyAll(notifiers []Notifier, n Notification) []Delivery {
+	var deliveries []Delivery
+	for _, notifier := range notifiers {
+		err := notifier.Send(n)
+		if err != nil {
+			fmt.Printf("%sError al enviar notificación por %s: %v%s\n", Red, notifier.Name(), err, Reset)
+		}
+		deliveries = append(deliveries, Delivery{Channel: notifier.Name(), Err: err})
+	}
+	return deliveries
+}
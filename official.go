@@ -0,0 +1,304 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const officialDir = "official"
+
+// Origen de la apertura y el cierre registrados
+const (
+	OfficialSourceExchange = "oficial" // Vela diaria del mercado (Yahoo)
+	OfficialSourceTicks    = "ticks"   // Primer y último precio visto por el monitor, si no hubo vela diaria
+)
+
+// OfficialPrice es la apertura y el cierre de un símbolo en una rueda
+type OfficialPrice struct {
+	Open     float64   `json:"open"`
+	Close    float64   `json:"close"`
+	Volume   int64     `json:"volume"`
+	Source   string    `json:"source"`
+	Recorded time.Time `json:"recorded"`
+}
+
+// Los archivos de precios oficiales se escriben desde el pipeline y desde `bolsa official --backfill`
+var officialMu sync.Mutex
+
+// officialPath devuelve el archivo de un mes: día → símbolo → precios
+func officialPath(month string) (string, error) {
+	dir, err := appFile(officialDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, month+".json"), nil
+}
+
+// loadOfficialMonth lee los precios oficiales de un mes (AAAA-MM)
+func loadOfficialMonth(month string) (map[string]map[string]OfficialPrice, error) {
+	prices := make(map[string]map[string]OfficialPrice)
+	path, err := officialPath(month)
+	if err != nil {
+		return prices, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return prices, nil
+	}
+	if err != nil {
+		return prices, err
+	}
+	if err := json.Unmarshal(data, &prices); err != nil {
+		return prices, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return prices, nil
+}
+
+// saveOfficialPrices agrega los precios de un día, pisando los de los mismos símbolos
+func saveOfficialPrices(day string, prices map[string]OfficialPrice) error {
+	officialMu.Lock()
+	defer officialMu.Unlock()
+
+	month := day[:7]
+	stored, err := loadOfficialMonth(month)
+	if err != nil {
+		return err
+	}
+	if stored[day] == nil {
+		stored[day] = make(map[string]OfficialPrice)
+	}
+	for symbol, price := range prices {
+		stored[day][symbol] = price
+	}
+
+	path, err := officialPath(month)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(stored, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// officialPrice devuelve la apertura y el cierre registrados de un símbolo en un día
+func officialPrice(symbol, day string) (OfficialPrice, bool) {
+	officialMu.Lock()
+	defer officialMu.Unlock()
+	stored, err := loadOfficialMonth(day[:7])
+	if err != nil {
+		return OfficialPrice{}, false
+	}
+	p, ok := stored[day][symbol]
+	return p, ok
+}
+
+// officialCloses devuelve los cierres oficiales registrados de un símbolo en los días indicados, en el mismo orden
+func officialCloses(symbol string, days []string) []float64 {
+	officialMu.Lock()
+	defer officialMu.Unlock()
+	months := make(map[string]map[string]map[string]OfficialPrice)
+	var closes []float64
+	for _, day := range days {
+		stored, ok := months[day[:7]]
+		if !ok {
+			stored, _ = loadOfficialMonth(day[:7])
+			months[day[:7]] = stored
+		}
+		if p, ok := stored[day][symbol]; ok && p.Close > 0 {
+			closes = append(closes, p.Close)
+		}
+	}
+	return closes
+}
+
+// fetchOfficialPrice obtiene la vela diaria de un símbolo para el día indicado
+func fetchOfficialPrice(symbol, day string, client QuoteFetcher) (OfficialPrice, error) {
+	points, err := getHistory(symbol, "5d", "1d", client)
+	if err != nil {
+		return OfficialPrice{}, err
+	}
+	for _, p := range points {
+		if p.Time.In(argentinaLocation).Format("2006-01-02") == day && p.Close > 0 {
+			return OfficialPrice{Open: p.Open, Close: p.Close, Volume: p.Volume, Source: OfficialSourceExchange, Recorded: time.Now()}, nil
+		}
+	}
+	return OfficialPrice{}, fmt.Errorf("Yahoo no publica todavía la vela del %s de %s", day, symbol)
+}
+
+// ticksPrice arma apertura y cierre con el primer y el último precio guardado en el historial intradiario
+func ticksPrice(symbol string, snapshots []Snapshot) (OfficialPrice, bool) {
+	var p OfficialPrice
+	for _, snapshot := range snapshots {
+		for _, stock := range snapshot.Stocks {
+			if stock.Symbol != symbol || stock.PreviousClose <= 0 {
+				continue
+			}
+			// En la moneda de origen, como la vela diaria: los ADRs se guardan convertidos a pesos
+			price := stock.PreviousClose * (1 + stock.ChangePercent/100)
+			if p.Open == 0 {
+				p.Open = price
+			}
+			p.Close, p.Volume = price, stock.Volume
+		}
+	}
+	p.Source, p.Recorded = OfficialSourceTicks, time.Now()
+	return p, p.Close > 0
+}
+
+// OfficialRecorder registra la apertura y el cierre oficiales de cada mercado una vez terminada su rueda
+type OfficialRecorder struct {
+	mu       sync.Mutex
+	recorded map[string]string // Mercado → último día registrado
+}
+
+var officials = &OfficialRecorder{recorded: make(map[string]string)}
+
+// Check registra, una vez por día y mercado, los precios oficiales de los papeles del snapshot
+func (r *OfficialRecorder) Check(snapshot *Snapshot, client QuoteFetcher) {
+	now := time.Now()
+	for _, hours := range []MarketHours{bymaHours, nyseHours} {
+		if !hours.IsTradingDay(now) || !hours.IsClosedForDay(now) {
+			continue
+		}
+		day := now.In(hours.Location).Format("2006-01-02")
+		r.mu.Lock()
+		done := r.recorded[hours.Name] == day
+		r.recorded[hours.Name] = day
+		r.mu.Unlock()
+		if done {
+			continue
+		}
+
+		var symbols []string
+		for _, stock := range snapshot.Stocks {
+			if stock.Market.Hours().Name == hours.Name {
+				symbols = append(symbols, stock.Symbol)
+			}
+		}
+		// Los tipos de cambio toman como cierre del día el de la rueda local
+		if hours.Name == bymaHours.Name {
+			for _, forex := range snapshot.Forex {
+				symbols = append(symbols, forex.Symbol)
+			}
+		}
+		if len(symbols) == 0 {
+			continue
+		}
+
+		prices, fallbacks := recordOfficialDay(day, symbols, client)
+		if err := saveOfficialPrices(day, prices); err != nil {
+			fmt.Printf("Error al guardar los cierres oficiales de %s: %v\n", hours.Name, err)
+			continue
+		}
+		fmt.Printf("Cierres oficiales de %s registrados: %d símbolos (%d con el último precio visto)\n", hours.Name, len(prices), fallbacks)
+	}
+}
+
+// recordOfficialDay obtiene los precios oficiales de un día; si no hay vela diaria usa el historial intradiario
+func recordOfficialDay(day string, symbols []string, client QuoteFetcher) (map[string]OfficialPrice, int) {
+	snapshots, _ := loadHistory(day)
+	prices := make(map[string]OfficialPrice)
+	fallbacks := 0
+	for _, symbol := range symbols {
+		p, err := fetchOfficialPrice(symbol, day, client)
+		if err != nil {
+			var ok bool
+			if p, ok = ticksPrice(symbol, snapshots); !ok {
+				continue
+			}
+			fallbacks++
+		}
+		prices[symbol] = p
+	}
+	return prices, fallbacks
+}
+
+// runOfficial implementa `bolsa official SIMBOLO`: aperturas y cierres oficiales registrados por día
+func runOfficial(args []string) error {
+	fs := flag.NewFlagSet("official", flag.ExitOnError)
+	days := fs.Int("days", 20, "cantidad de ruedas a mostrar")
+	backfill := fs.Bool("backfill", false, "completar los días faltantes con las velas diarias de Yahoo")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return fmt.Errorf("uso: bolsa official [--days N] [--backfill] SIMBOLO")
+	}
+	symbol := strings.ToUpper(fs.Arg(0))
+
+	if *backfill {
+		points, err := getHistory(symbol, "3mo", "1d", NewHTTPClient())
+		if err != nil {
+			return err
+		}
+		added := 0
+		today := time.Now().In(argentinaLocation).Format("2006-01-02")
+		for _, p := range points {
+			day := p.Time.In(argentinaLocation).Format("2006-01-02")
+			if day >= today {
+				continue // La vela de hoy no es definitiva hasta el cierre
+			}
+			if existing, ok := officialPrice(symbol, day); ok && existing.Source == OfficialSourceExchange {
+				continue
+			}
+			price := OfficialPrice{Open: p.Open, Close: p.Close, Volume: p.Volume, Source: OfficialSourceExchange, Recorded: time.Now()}
+			if err := saveOfficialPrices(day, map[string]OfficialPrice{symbol: price}); err != nil {
+				return err
+			}
+			added++
+		}
+		fmt.Printf("%d ruedas de %s completadas\n", added, symbol)
+	}
+
+	// Se recorre hacia atrás mes a mes hasta juntar las ruedas pedidas o agotar un año
+	type row struct {
+		day string
+		OfficialPrice
+	}
+	var rows []row
+	month := time.Now().In(argentinaLocation)
+	for i := 0; i < 12 && len(rows) < *days; i++ {
+		officialMu.Lock()
+		stored, err := loadOfficialMonth(month.Format("2006-01"))
+		officialMu.Unlock()
+		if err != nil {
+			return err
+		}
+		var monthRows []row
+		for day, prices := range stored {
+			if p, ok := prices[symbol]; ok {
+				monthRows = append(monthRows, row{day, p})
+			}
+		}
+		sort.Slice(monthRows, func(i, j int) bool { return monthRows[i].day > monthRows[j].day })
+		rows = append(rows, monthRows...)
+		month = time.Date(month.Year(), month.Month()-1, 1, 0, 0, 0, 0, argentinaLocation)
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("no hay cierres oficiales de %s: se registran al terminar cada rueda con el monitor abierto, o con --backfill", symbol)
+	}
+	if len(rows) > *days {
+		rows = rows[:*days]
+	}
+
+	fmt.Printf("\n%s=== APERTURAS Y CIERRES OFICIALES DE %s ===%s\n\n", Cyan, symbol, Reset)
+	fmt.Printf("%-10s %12s %12s %9s %14s  %s\n", "Día", "Apertura", "Cierre", "Var", "Volumen", "Origen")
+	for i, r := range rows {
+		change := fmt.Sprintf("%9s", "-")
+		if i+1 < len(rows) && rows[i+1].Close > 0 {
+			change = changeText(r.Close, rows[i+1].Close)
+		}
+		fmt.Printf("%-10s %12.2f %12.2f %s %14d  %s\n", r.day, r.Open, r.Close, change, r.Volume, r.Source)
+	}
+	return nil
+}
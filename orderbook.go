@@ -0,0 +1,329 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Regla con la que se registran y rutean las alertas de spread
+const spreadRule = "spread"
+
+// Muestras mínimas del spread de un papel antes de considerarlo anormal
+const spreadMinSamples = 10
+
+const iolBaseURL = "https://api.invertironline.com"
+
+// IOLConfig son las credenciales de la API de InvertirOnline, que publica las puntas de BYMA
+type IOLConfig struct {
+	Username    string  `json:"username"`
+	Password    string  `json:"password"`    // BOLSA_IOL_PASSWORD tiene prioridad
+	SpreadAlert float64 `json:"spreadAlert"` // Alertar cuando el spread supera N veces su promedio; 0 desactiva
+}
+
+// OrderBook son las mejores puntas de un papel local
+type OrderBook struct {
+	Symbol   string    `json:"symbol"`
+	BidPrice float64   `json:"bid"`
+	BidSize  float64   `json:"bid_size"`
+	AskPrice float64   `json:"ask"`
+	AskSize  float64   `json:"ask_size"`
+	Time     time.Time `json:"time"`
+}
+
+// Spread devuelve la diferencia entre la punta vendedora y la compradora
+func (b OrderBook) Spread() float64 {
+	return b.AskPrice - b.BidPrice
+}
+
+// SpreadPercent devuelve el spread como porcentaje del precio medio; 0 si falta alguna punta
+func (b OrderBook) SpreadPercent() float64 {
+	if b.BidPrice <= 0 || b.AskPrice <= 0 {
+		return 0
+	}
+	return b.Spread() / ((b.AskPrice + b.BidPrice) / 2) * 100
+}
+
+// Espera tras un login fallido por red o error del servidor; se duplica en cada fallo seguido hasta el tope
+const (
+	iolLoginBackoff    = time.Minute
+	iolLoginMaxBackoff = 30 * time.Minute
+)
+
+// iolSession mantiene el token de acceso de IOL, que vence a los 15 minutos. Tras un login fallido no se vuelve
+// a intentar en cada ciclo: si IOL rechazó las credenciales se espera a que cambien, y si falló la conexión se
+// espera con backoff, para no bloquear la cuenta del broker
+type iolSession struct {
+	mu      sync.Mutex
+	client  *HTTPClient
+	token   string
+	expires time.Time
+
+	rejected   string    // Credenciales (usuario y contraseña) que IOL rechazó
+	loginErr   error     // Último error de login, devuelto mientras no se reintenta
+	retryAt    time.Time // Próximo intento tras un fallo que no es de credenciales
+	retryAfter time.Duration
+}
+
+var iol = &iolSession{}
+
+// iolPassword devuelve la contraseña de IOL, priorizando la variable de entorno
+func iolPassword(cfg *IOLConfig) string {
+	if password := os.Getenv("BOLSA_IOL_PASSWORD"); password != "" {
+		return password
+	}
+	return cfg.Password
+}
+
+// accessToken devuelve un token vigente, pidiendo uno nuevo con usuario y contraseña si hace falta
+func (s *iolSession) accessToken(cfg *IOLConfig) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+	password := iolPassword(cfg)
+	credentials := cfg.Username + "\x00" + password
+	if s.rejected == credentials {
+		return "", s.loginErr
+	}
+	if time.Now().Before(s.retryAt) {
+		return "", s.loginErr
+	}
+	if s.client == nil {
+		s.client = NewProviderClient("iol")
+	}
+
+	token, err := s.login(cfg.Username, password)
+	if err != nil {
+		s.loginErr = err
+		var quoteErr *QuoteError
+		if errors.As(err, &quoteErr) && (quoteErr.Status == http.StatusBadRequest || quoteErr.Status == http.StatusUnauthorized || quoteErr.Status == http.StatusForbidden) {
+			s.rejected = credentials
+			fmt.Printf("%sIOL rechazó las credenciales: no se reintenta hasta que cambien en config.json o BOLSA_IOL_PASSWORD%s\n", Red, Reset)
+			return "", err
+		}
+		s.retryAfter = min(max(2*s.retryAfter, iolLoginBackoff), iolLoginMaxBackoff)
+		s.retryAt = time.Now().Add(s.retryAfter)
+		return "", err
+	}
+
+	s.rejected, s.loginErr, s.retryAt, s.retryAfter = "", nil, time.Time{}, 0
+	// Se renueva un minuto antes del vencimiento para no usar un token vencido en medio del ciclo
+	s.token = token.AccessToken
+	s.expires = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return s.token, nil
+}
+
+// iolToken es la respuesta del endpoint /token de IOL
+type iolToken struct {
+	AccessToken string `json:"access_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// login pide un token con usuario y contraseña, por el cliente del proveedor
+func (s *iolSession) login(username, password string) (iolToken, error) {
+	var token iolToken
+	resp, err := s.client.PostForm(iolBaseURL+"/token", url.Values{
+		"username":   {username},
+		"password":   {password},
+		"grant_type": {"password"},
+	})
+	if err != nil {
+		return token, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return token, statusError(resp.StatusCode, "al autenticarse en IOL (revisar usuario y contraseña)")
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil || token.AccessToken == "" {
+		return token, &QuoteError{Kind: ErrParse, Err: fmt.Errorf("respuesta inválida del token de IOL: %v", err)}
+	}
+	return token, nil
+}
+
+// fetchOrderBook consulta las puntas de un papel de BYMA en IOL
+func fetchOrderBook(symbol, token string, client QuoteFetcher) (OrderBook, error) {
+	ticker := strings.TrimSuffix(symbol, ".BA")
+	resp, err := client.GetWithRetry(fmt.Sprintf("%s/api/v2/bCBA/Titulos/%s/Cotizacion", iolBaseURL, url.PathEscape(ticker)),
+		map[string]string{"Authorization": "Bearer " + token, "Accept": "application/json"})
+	if err != nil {
+		return OrderBook{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return OrderBook{}, statusError(resp.StatusCode, "para las puntas de %s", symbol)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return OrderBook{}, err
+	}
+
+	var quote struct {
+		Puntas []struct {
+			CantidadCompra FlexFloat `json:"cantidadCompra"`
+			PrecioCompra   FlexFloat `json:"precioCompra"`
+			PrecioVenta    FlexFloat `json:"precioVenta"`
+			CantidadVenta  FlexFloat `json:"cantidadVenta"`
+		} `json:"puntas"`
+	}
+	if err := json.Unmarshal(body, &quote); err != nil {
+		return OrderBook{}, &QuoteError{Kind: ErrParse, Symbol: symbol, Err: fmt.Errorf("respuesta inválida de IOL para %s: %v", symbol, err)}
+	}
+	if len(quote.Puntas) == 0 {
+		return OrderBook{}, symbolError(symbol, fmt.Errorf("IOL no informa puntas para %s", symbol))
+	}
+
+	best := quote.Puntas[0]
+	return OrderBook{
+		Symbol:   symbol,
+		BidPrice: best.PrecioCompra.Value,
+		BidSize:  best.CantidadCompra.Value,
+		AskPrice: best.PrecioVenta.Value,
+		AskSize:  best.CantidadVenta.Value,
+		Time:     time.Now(),
+	}, nil
+}
+
+// getOrderBooks consulta las puntas de los papeles de BYMA del ciclo; sin credenciales de IOL no hace nada
+func getOrderBooks(stocks []StockInfo, errs *fetchErrors) map[string]OrderBook {
+	cfg := appConfig().IOL
+	if cfg == nil || cfg.Username == "" || !bymaHours.IsOpen(time.Now()) {
+		return nil
+	}
+
+	var symbols []string
+	for _, stock := range stocks {
+		if stock.Market == MarketBYMA {
+			symbols = append(symbols, stock.Symbol)
+		}
+	}
+	if len(symbols) == 0 {
+		return nil
+	}
+
+	token, err := iol.accessToken(cfg)
+	if err != nil {
+		errs.add("Puntas", "", err)
+		return nil
+	}
+
+	books := make(map[string]OrderBook)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, symbol := range symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			book, err := fetchOrderBook(symbol, token, iol.client)
+			if err != nil {
+				errs.add("Puntas", symbol, err)
+				return
+			}
+			mu.Lock()
+			books[symbol] = book
+			mu.Unlock()
+		}(symbol)
+	}
+	wg.Wait()
+	return books
+}
+
+// displayOrderBooks muestra las mejores puntas y el spread de los papeles locales
+func displayOrderBooks(books map[string]OrderBook) {
+	if len(books) == 0 {
+		return
+	}
+
+	symbols := make([]string, 0, len(books))
+	for symbol := range books {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	fmt.Printf("\n%s=== PUNTAS BYMA (IOL) ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-10s %12s %12s %12s %12s %8s\n", "Símbolo", "Cant. compra", "Compra", "Venta", "Cant. venta", "Spread")
+	for _, symbol := range symbols {
+		book := books[symbol]
+		color := White
+		if spreads.abnormal(book) {
+			color = Red
+		}
+		fmt.Printf("%-10s %12.0f %12.2f %12.2f %12.0f %s%7.2f%%%s\n",
+			symbol, book.BidSize, book.BidPrice, book.AskPrice, book.AskSize, color, book.SpreadPercent(), Reset)
+	}
+}
+
+// SpreadWatcher lleva el spread promedio de cada papel y alerta cuando se amplía anormalmente
+type SpreadWatcher struct {
+	mu      sync.Mutex
+	average map[string]float64 // Promedio móvil exponencial del spread en %
+	samples map[string]int
+}
+
+var spreads = &SpreadWatcher{average: make(map[string]float64), samples: make(map[string]int)}
+
+// abnormal indica si el spread actual supera el múltiplo configurado de su promedio
+func (w *SpreadWatcher) abnormal(book OrderBook) bool {
+	cfg := appConfig().IOL
+	if cfg == nil || cfg.SpreadAlert <= 0 {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	avg := w.average[book.Symbol]
+	return w.samples[book.Symbol] >= spreadMinSamples && avg > 0 && book.SpreadPercent() >= avg*cfg.SpreadAlert
+}
+
+// Check compara el spread de cada papel con su promedio, alerta los anormales y actualiza el promedio
+func (w *SpreadWatcher) Check(snapshot *Snapshot, notifiers []Notifier) {
+	if len(snapshot.OrderBooks) == 0 {
+		return
+	}
+
+	var alerts []Alert
+	for symbol, book := range snapshot.OrderBooks {
+		spread := book.SpreadPercent()
+		if spread <= 0 {
+			continue
+		}
+		if w.abnormal(book) {
+			w.mu.Lock()
+			avg := w.average[symbol]
+			w.mu.Unlock()
+			alerts = append(alerts, Alert{
+				Rule:     spreadRule,
+				Severity: SeverityWarning,
+				Symbol:   symbol,
+				Value:    spread,
+				Message: fmt.Sprintf("%s: el spread se amplió a %.2f%% (promedio %.2f%%): compra %.2f x %.0f, venta %.2f x %.0f",
+					symbol, spread, avg, book.BidPrice, book.BidSize, book.AskPrice, book.AskSize),
+				Time: snapshot.Time,
+			})
+		}
+
+		w.mu.Lock()
+		if w.samples[symbol] == 0 {
+			w.average[symbol] = spread
+		} else {
+			w.average[symbol] = 0.9*w.average[symbol] + 0.1*spread
+		}
+		w.samples[symbol]++
+		w.mu.Unlock()
+	}
+
+	if len(alerts) > 0 && alertAllowed(spreadRule, snapshot.Time) {
+		sort.Slice(alerts, func(i, j int) bool { return alerts[i].Symbol < alerts[j].Symbol })
+		dispatchAlerts(notifiers, "Spread anormal", alerts, snapshot)
+	}
+}
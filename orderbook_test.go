@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// fakeIOLLogin responde el login de IOL: acepta solo la contraseña "correcta" y cuenta los intentos
+func fakeIOLLogin(t *testing.T, attempts *int, down *bool) *iolSession {
+	t.Helper()
+	t.Setenv("BOLSA_IOL_PASSWORD", "")
+	client := NewHTTPClientWithDoer("iol", doerFunc(func(req *http.Request) (*http.Response, error) {
+		*attempts++
+		if *down {
+			return nil, errors.New("connection refused")
+		}
+		if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/token") {
+			t.Errorf("login por %s %s", req.Method, req.URL)
+		}
+		req.ParseForm()
+		status, body := http.StatusUnauthorized, `{"error": "invalid_grant"}`
+		if req.PostForm.Get("password") == "correcta" {
+			status, body = http.StatusOK, `{"access_token": "tok", "expires_in": 900}`
+		}
+		return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}}, nil
+	}))
+	return &iolSession{client: client}
+}
+
+func TestIOLLoginRejectedWaitsForNewCredentials(t *testing.T) {
+	attempts, down := 0, false
+	session := fakeIOLLogin(t, &attempts, &down)
+	cfg := &IOLConfig{Username: "usuario", Password: "vieja"}
+
+	for i := 0; i < 3; i++ {
+		if _, err := session.accessToken(cfg); err == nil {
+			t.Fatal("se esperaba el rechazo de las credenciales")
+		}
+	}
+	if attempts != 1 {
+		t.Errorf("%d logins con las mismas credenciales rechazadas, se esperaba 1", attempts)
+	}
+
+	cfg.Password = "correcta"
+	token, err := session.accessToken(cfg)
+	if err != nil || token != "tok" {
+		t.Fatalf("con credenciales nuevas: token %q, error %v", token, err)
+	}
+	if attempts != 2 {
+		t.Errorf("%d logins, se esperaban 2", attempts)
+	}
+	// Con el token vigente no se vuelve a pedir
+	session.accessToken(cfg)
+	if attempts != 2 {
+		t.Errorf("se pidió otro token con uno vigente (%d logins)", attempts)
+	}
+}
+
+func TestIOLLoginNetworkErrorBacksOff(t *testing.T) {
+	attempts, down := 0, true
+	session := fakeIOLLogin(t, &attempts, &down)
+	cfg := &IOLConfig{Username: "usuario", Password: "correcta"}
+
+	_, err := session.accessToken(cfg)
+	if !errors.Is(err, ErrNetwork) {
+		t.Errorf("error = %v, se esperaba ErrNetwork", err)
+	}
+	down = false
+	if _, err := session.accessToken(cfg); err == nil || attempts != 1 {
+		t.Errorf("durante el backoff no se reintenta: %d logins, error %v", attempts, err)
+	}
+	if session.retryAfter != iolLoginBackoff {
+		t.Errorf("backoff = %v, se esperaba %v", session.retryAfter, iolLoginBackoff)
+	}
+
+	// Vencido el backoff, el login vuelve a intentarse y limpia el estado de error
+	session.retryAt = session.retryAt.Add(-2 * iolLoginBackoff)
+	if token, err := session.accessToken(cfg); err != nil || token != "tok" {
+		t.Errorf("después del backoff: token %q, error %v", token, err)
+	}
+	if session.retryAfter != 0 || session.loginErr != nil {
+		t.Errorf("el login exitoso no limpió el backoff: %+v", session)
+	}
+}
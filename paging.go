@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Página de la tabla de acciones que se muestra cuando no entran todas en la terminal
+// (protegida por screenMu, igual que el resto del estado de la pantalla)
+var (
+	stockPage  int
+	stockPages = 1
+)
+
+// currentStockPage devuelve la página a mostrar y la cantidad de páginas para rows filas de a perPage
+func currentStockPage(rows, perPage int) (int, int) {
+	stockPages = max((rows+perPage-1)/perPage, 1)
+	if stockPage >= stockPages {
+		stockPage = stockPages - 1
+	}
+	return stockPage, stockPages
+}
+
+// moveStockPage avanza (o retrocede, con delta negativo) la página dando la vuelta al final
+func moveStockPage(delta int) {
+	screenMu.Lock()
+	stockPage = ((stockPage+delta)%stockPages + stockPages) % stockPages
+	screenMu.Unlock()
+}
+
+// setStockPage salta a una página (1 es la primera)
+func setStockPage(arg string) error {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n < 1 {
+		return fmt.Errorf("página inválida %q", arg)
+	}
+
+	screenMu.Lock()
+	defer screenMu.Unlock()
+	if n > stockPages {
+		return fmt.Errorf("la tabla tiene %d página(s)", stockPages)
+	}
+	stockPage = n - 1
+	return nil
+}
+
+// rotateStockPages avanza de página cada interval y redibuja (paginación automática, --rotate)
+func rotateStockPages(interval time.Duration) {
+	for range time.Tick(interval) {
+		screenMu.Lock()
+		pages := stockPages
+		screenMu.Unlock()
+
+		if pages > 1 {
+			moveStockPage(1)
+			redrawScreen()
+		}
+	}
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// configBaseDir devuelve el directorio base de configuración del usuario: %APPDATA% en Windows
+// (C:\Users\<usuario>\AppData\Roaming), $XDG_CONFIG_HOME o ~/.config en Linux
+func configBaseDir() (string, error) {
+	if runtime.GOOS == "windows" {
+		if base := os.Getenv("APPDATA"); base != "" {
+			return base, nil
+		}
+		return "", fmt.Errorf("la variable %%APPDATA%% no está definida")
+	}
+	return os.UserConfigDir()
+}
+
+// appDir devuelve el directorio donde el programa guarda su estado, creándolo si no existe
+func appDir() (string, error) {
+	base, err := configBaseDir()
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(base, "bolsa")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
+
+// appFile devuelve la ruta de un archivo dentro del directorio de estado del programa
+func appFile(name string) (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const pinsFile = "pins.json"
+
+var (
+	pinsMu     sync.Mutex
+	pinsLoaded bool
+	pinned     []string // Símbolos fijados en orden de fijación, en mayúsculas
+)
+
+// loadPins combina los símbolos fijados en config.json con los fijados desde el monitor
+func loadPins() {
+	if pinsLoaded {
+		return
+	}
+	pinsLoaded = true
+
+	for _, symbol := range appConfig().Pinned {
+		pinned = appendUnique(pinned, strings.ToUpper(symbol))
+	}
+
+	path, err := appFile(pinsFile)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	var saved []string
+	if err := json.Unmarshal(data, &saved); err != nil {
+		fmt.Printf("Error al leer %s: %v\n", path, err)
+		return
+	}
+	for _, symbol := range saved {
+		pinned = appendUnique(pinned, symbol)
+	}
+}
+
+// appendUnique agrega el símbolo si no estaba en la lista
+func appendUnique(list []string, symbol string) []string {
+	for _, s := range list {
+		if s == symbol {
+			return list
+		}
+	}
+	return append(list, symbol)
+}
+
+// setPinned fija o libera un símbolo y guarda la lista
+func setPinned(symbol string, pin bool) error {
+	symbol = strings.ToUpper(symbol)
+
+	pinsMu.Lock()
+	defer pinsMu.Unlock()
+	loadPins()
+
+	var updated []string
+	for _, s := range pinned {
+		if s != symbol {
+			updated = append(updated, s)
+		}
+	}
+	if pin {
+		updated = append(updated, symbol)
+	}
+	pinned = updated
+
+	path, err := appFile(pinsFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(pinned, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// pinRank devuelve la posición de un símbolo (o nombre de tipo de cambio) entre los fijados
+func pinRank(keys ...string) (int, bool) {
+	pinsMu.Lock()
+	defer pinsMu.Unlock()
+	loadPins()
+
+	for i, s := range pinned {
+		for _, key := range keys {
+			if strings.EqualFold(s, key) {
+				return i, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// splitPinnedStocks separa las acciones fijadas, en el orden en que se fijaron, del resto
+func splitPinnedStocks(stocksData []StockInfo) (pins, rest []StockInfo) {
+	ranks := make(map[string]int)
+	for _, stock := range stocksData {
+		if rank, ok := pinRank(stock.Symbol); ok {
+			ranks[stock.Symbol] = rank
+			pins = append(pins, stock)
+		} else {
+			rest = append(rest, stock)
+		}
+	}
+	sort.Slice(pins, func(i, j int) bool { return ranks[pins[i].Symbol] < ranks[pins[j].Symbol] })
+	return pins, rest
+}
+
+// splitPinnedForex separa los tipos de cambio fijados (por símbolo o por nombre) del resto
+func splitPinnedForex(forexData []ForexInfo) (pins, rest []ForexInfo) {
+	ranks := make(map[string]int)
+	for _, forex := range forexData {
+		if rank, ok := pinRank(forex.Symbol, forex.Name); ok {
+			ranks[forex.Symbol] = rank
+			pins = append(pins, forex)
+		} else {
+			rest = append(rest, forex)
+		}
+	}
+	sort.Slice(pins, func(i, j int) bool { return ranks[pins[i].Symbol] < ranks[pins[j].Symbol] })
+	return pins, rest
+}
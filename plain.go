@@ -0,0 +1,143 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// plainMode indica que la salida es ASCII puro, sin colores ni secuencias de control (--plain o BOLSA_PLAIN=1)
+var plainMode bool
+
+// Reemplazos ASCII de los símbolos que usa la salida; el resto de los caracteres no ASCII se cambia por "?"
+var asciiReplacements = map[rune]string{
+	'á': "a", 'é': "e", 'í': "i", 'ó': "o", 'ú': "u", 'ü': "u", 'ñ': "n",
+	'Á': "A", 'É': "E", 'Í': "I", 'Ó': "O", 'Ú': "U", 'Ü': "U", 'Ñ': "N",
+	'¿': "", '¡': "", '°': "o", 'º': "o", 'ª': "a", '·': ".", '×': "x",
+	'✅': "[OK]", '✓': "OK", '✔': "OK", '✗': "X", '❌': "[X]", '⚠': "[!]", '📌': "*",
+	'🟢': "(o)", '🟡': "(~)", '🔴': "(x)", '⚪': "( )", '•': "*", '…': "...",
+	'→': "->", '←': "<-", '↑': "^", '↓': "v", '▲': "^", '▼': "v", '–': "-", '—': "-",
+	'“': "\"", '”': "\"", '‘': "'", '’': "'", '€': "EUR",
+	'─': "-", '━': "-", '│': "|", '┃': "|", '┌': "+", '┐': "+", '└': "+", '┘': "+",
+	'├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+	'█': "#", '▓': "#", '▒': "+", '░': ".", '▁': "_", '▂': "_", '▃': "-", '▄': "-",
+	'▅': "=", '▆': "=", '▇': "#",
+	'️': "", // Selector de variación de los emojis
+}
+
+// asciiWriter pasa la salida a ASCII: descarta las secuencias ANSI y translitera el resto.
+// Guarda entre escrituras las secuencias o runas UTF-8 que llegan partidas
+type asciiWriter struct {
+	out     io.Writer
+	pending []byte
+	escape  bool // Dentro de una secuencia ANSI
+}
+
+func (w *asciiWriter) Write(p []byte) (int, error) {
+	data := append(w.pending, p...)
+	w.pending = nil
+
+	var b strings.Builder
+	for i := 0; i < len(data); {
+		c := data[i]
+		if w.escape {
+			// Las secuencias CSI terminan en un byte entre '@' y '~'; se omite el '[' inicial
+			if c >= '@' && c <= '~' && c != '[' {
+				w.escape = false
+			}
+			i++
+			continue
+		}
+		if c == 0x1b {
+			w.escape = true
+			i++
+			continue
+		}
+		if c < utf8.RuneSelf {
+			b.WriteByte(c)
+			i++
+			continue
+		}
+
+		if !utf8.FullRune(data[i:]) {
+			w.pending = append([]byte(nil), data[i:]...)
+			break
+		}
+		r, size := utf8.DecodeRune(data[i:])
+		if replacement, ok := asciiReplacements[r]; ok {
+			b.WriteString(replacement)
+		} else {
+			b.WriteByte('?')
+		}
+		i += size
+	}
+
+	if _, err := io.WriteString(w.out, b.String()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Pipes de la salida en modo plano; cada canal se cierra cuando terminó de copiarse lo pendiente
+var (
+	plainPipes []*os.File
+	plainDone  []chan struct{}
+)
+
+// enablePlainMode redirige stdout y stderr a través de un asciiWriter
+func enablePlainMode() {
+	if plainMode {
+		return
+	}
+	plainMode = true
+
+	for _, target := range []**os.File{&os.Stdout, &os.Stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "No se pudo activar el modo plano: %v\n", err)
+			return
+		}
+		done := make(chan struct{})
+		go func(out *os.File) {
+			io.Copy(&asciiWriter{out: out}, r)
+			close(done)
+		}(*target)
+		*target = w
+		plainPipes = append(plainPipes, w)
+		plainDone = append(plainDone, done)
+	}
+}
+
+// exitProgram termina el proceso después de vaciar la salida del modo plano
+func exitProgram(code int) {
+	for _, w := range plainPipes {
+		w.Close()
+	}
+	for _, done := range plainDone {
+		<-done
+	}
+	os.Exit(code)
+}
+
+// plainRow arma una fila de tabla delimitada por pipes para el modo plano
+func plainRow(cells ...string) string {
+	return "| " + strings.Join(cells, " | ") + " |"
+}
+
+// plainSessionRange devuelve el mínimo y máximo de la sesión como celda de tabla
+func plainSessionRange(symbol string) string {
+	if r, ok := getSessionRange(symbol); ok {
+		return fmt.Sprintf("%.2f - %.2f", r.Low, r.High)
+	}
+	return "-"
+}
+
+// plainRiskLevel devuelve la etiqueta de riesgo de un símbolo para la tabla plana, o "-" si no tiene score
+func plainRiskLevel(symbol string) string {
+	if risk, ok := getRiskProfile(symbol); ok {
+		return risk.Level
+	}
+	return "-"
+}
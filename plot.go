@@ -0,0 +1,442 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Series representa una serie de datos a graficar
+type Series struct {
+	Name    string
+	X       []float64
+	Y       []float64
+	Labels  []string // Etiqueta opcional por punto (especie, fecha...)
+	Scatter bool     // true para puntos sueltos, false para línea
+}
+
+// Chart representa un gráfico de una o más series sobre ejes comunes
+type Chart struct {
+	Title  string
+	XLabel string
+	YLabel string
+	Series []Series
+	XTime  bool // El eje X son fechas en segundos Unix
+
+	TimeLayout string // Formato de las fechas del eje X (por defecto DD/MM/AA)
+}
+
+// formatX rotula un valor del eje X, como fecha si el gráfico es temporal
+func (c *Chart) formatX(x float64) string {
+	if c.XTime {
+		layout := c.TimeLayout
+		if layout == "" {
+			layout = "02/01/06"
+		}
+		return time.Unix(int64(x), 0).Format(layout)
+	}
+	return fmt.Sprintf("%.2f", x)
+}
+
+// Colores de las series en los gráficos exportados
+var seriesColors = []color.RGBA{
+	{R: 31, G: 119, B: 180, A: 255},
+	{R: 214, G: 39, B: 40, A: 255},
+	{R: 44, G: 160, B: 44, A: 255},
+	{R: 255, G: 127, B: 14, A: 255},
+	{R: 148, G: 103, B: 189, A: 255},
+}
+
+// Marcas de las series en los gráficos ASCII
+var seriesMarks = []rune{'*', 'o', '+', 'x', '#'}
+
+// bounds devuelve el rango de los ejes con un pequeño margen
+func (c *Chart) bounds() (minX, maxX, minY, maxY float64, ok bool) {
+	minX, minY = math.Inf(1), math.Inf(1)
+	maxX, maxY = math.Inf(-1), math.Inf(-1)
+
+	for _, s := range c.Series {
+		for i := range s.X {
+			if i >= len(s.Y) || math.IsNaN(s.Y[i]) {
+				continue
+			}
+			minX, maxX = math.Min(minX, s.X[i]), math.Max(maxX, s.X[i])
+			minY, maxY = math.Min(minY, s.Y[i]), math.Max(maxY, s.Y[i])
+			ok = true
+		}
+	}
+	if !ok {
+		return 0, 0, 0, 0, false
+	}
+
+	if maxX == minX {
+		minX, maxX = minX-1, maxX+1
+	}
+	if maxY == minY {
+		minY, maxY = minY-1, maxY+1
+	}
+	padX, padY := (maxX-minX)*0.05, (maxY-minY)*0.05
+	return minX - padX, maxX + padX, minY - padY, maxY + padY, true
+}
+
+// RenderASCII dibuja el gráfico en la terminal con caracteres
+func (c *Chart) RenderASCII(w io.Writer, width, height int) {
+	minX, maxX, minY, maxY, ok := c.bounds()
+	if !ok {
+		fmt.Fprintf(w, "%s: sin datos para graficar\n", c.Title)
+		return
+	}
+
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(" ", width))
+	}
+
+	toCol := func(x float64) int {
+		return int(math.Round((x - minX) / (maxX - minX) * float64(width-1)))
+	}
+	toRow := func(y float64) int {
+		return height - 1 - int(math.Round((y-minY)/(maxY-minY)*float64(height-1)))
+	}
+
+	for n, s := range c.Series {
+		mark := seriesMarks[n%len(seriesMarks)]
+		prevCol, prevRow := -1, -1
+		for i := range s.X {
+			if i >= len(s.Y) || math.IsNaN(s.Y[i]) {
+				continue
+			}
+			col, row := toCol(s.X[i]), toRow(s.Y[i])
+
+			// Para las líneas rellenamos las columnas intermedias
+			if !s.Scatter && prevCol >= 0 && col > prevCol+1 {
+				for x := prevCol + 1; x < col; x++ {
+					r := prevRow + (row-prevRow)*(x-prevCol)/(col-prevCol)
+					grid[r][x] = '.'
+				}
+			}
+			grid[row][col] = mark
+			prevCol, prevRow = col, row
+
+			// Etiquetas de los puntos a la derecha de la marca, si hay lugar
+			if s.Scatter && i < len(s.Labels) {
+				for j, ch := range s.Labels[i] {
+					if col+1+j < width && grid[row][col+1+j] == ' ' {
+						grid[row][col+1+j] = ch
+					}
+				}
+			}
+		}
+	}
+
+	fmt.Fprintf(w, "%s\n", c.Title)
+	for i, line := range grid {
+		label := ""
+		if i == 0 || i == height-1 || i == height/2 {
+			label = fmt.Sprintf("%.2f", maxY-(maxY-minY)*float64(i)/float64(height-1))
+		}
+		fmt.Fprintf(w, "%10s |%s\n", label, string(line))
+	}
+	fmt.Fprintf(w, "%10s +%s\n", "", strings.Repeat("-", width))
+	fmt.Fprintf(w, "%10s  %-*s%*s\n", "", width/2, c.formatX(minX), width-width/2, c.formatX(maxX))
+	fmt.Fprintf(w, "%10s  %s / %s\n", "", c.XLabel, c.YLabel)
+
+	for n, s := range c.Series {
+		fmt.Fprintf(w, "%10s  %c %s\n", "", seriesMarks[n%len(seriesMarks)], s.Name)
+	}
+}
+
+// canvas es una imagen RGBA con primitivas mínimas de dibujo
+type canvas struct {
+	img *image.RGBA
+}
+
+func newCanvas(width, height int) *canvas {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	return &canvas{img: img}
+}
+
+// line dibuja una recta con el algoritmo de Bresenham
+func (cv *canvas) line(x0, y0, x1, y1 int, c color.RGBA) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+	err := dx + dy
+	for {
+		cv.img.SetRGBA(x0, y0, c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// dot dibuja un punto cuadrado centrado en (x, y)
+func (cv *canvas) dot(x, y, size int, c color.RGBA) {
+	for i := -size; i <= size; i++ {
+		for j := -size; j <= size; j++ {
+			cv.img.SetRGBA(x+i, y+j, c)
+		}
+	}
+}
+
+// text escribe texto con la fuente de mapa de bits embebida, escalada por scale
+func (cv *canvas) text(x, y int, s string, scale int, c color.RGBA) {
+	for _, ch := range strings.ToUpper(s) {
+		glyph, ok := font3x5[ch]
+		if !ok {
+			glyph = font3x5['?']
+		}
+		for row, bits := range glyph {
+			for col, bit := range bits {
+				if bit == '#' {
+					for i := 0; i < scale; i++ {
+						for j := 0; j < scale; j++ {
+							cv.img.SetRGBA(x+col*scale+i, y+row*scale+j, c)
+						}
+					}
+				}
+			}
+		}
+		x += 4 * scale
+	}
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// RenderPNG dibuja el gráfico como imagen PNG
+func (c *Chart) RenderPNG(w io.Writer, width, height int) error {
+	minX, maxX, minY, maxY, ok := c.bounds()
+	if !ok {
+		return fmt.Errorf("%s: sin datos para graficar", c.Title)
+	}
+
+	cv := newCanvas(width, height)
+	black := color.RGBA{A: 255}
+	grid := color.RGBA{R: 225, G: 225, B: 225, A: 255}
+
+	left, right, top, bottom := 70, width-20, 40, height-50
+	toX := func(x float64) int {
+		return left + int((x-minX)/(maxX-minX)*float64(right-left))
+	}
+	toY := func(y float64) int {
+		return bottom - int((y-minY)/(maxY-minY)*float64(bottom-top))
+	}
+
+	// Grilla y marcas de los ejes
+	for i := 0; i <= 5; i++ {
+		gy := bottom - (bottom-top)*i/5
+		cv.line(left, gy, right, gy, grid)
+		cv.text(5, gy-5, fmt.Sprintf("%.2f", minY+(maxY-minY)*float64(i)/5), 2, black)
+
+		gx := left + (right-left)*i/5
+		cv.line(gx, top, gx, bottom, grid)
+		cv.text(gx-15, bottom+10, c.formatX(minX+(maxX-minX)*float64(i)/5), 2, black)
+	}
+	cv.line(left, top, left, bottom, black)
+	cv.line(left, bottom, right, bottom, black)
+
+	cv.text(left, 10, c.Title, 2, black)
+	cv.text(right-4*2*utf8.RuneCountInString(c.XLabel), bottom+30, c.XLabel, 2, black)
+
+	for n, s := range c.Series {
+		col := seriesColors[n%len(seriesColors)]
+		prevX, prevY := -1, -1
+		for i := range s.X {
+			if i >= len(s.Y) || math.IsNaN(s.Y[i]) {
+				continue
+			}
+			px, py := toX(s.X[i]), toY(s.Y[i])
+			if s.Scatter {
+				cv.dot(px, py, 3, col)
+				if i < len(s.Labels) {
+					cv.text(px+6, py-12, s.Labels[i], 2, col)
+				}
+			} else if prevX >= 0 {
+				cv.line(prevX, prevY, px, py, col)
+			}
+			prevX, prevY = px, py
+		}
+
+		// Leyenda
+		cv.dot(right-150, top+10+n*16, 3, col)
+		cv.text(right-140, top+5+n*16, s.Name, 2, col)
+	}
+
+	return png.Encode(w, cv.img)
+}
+
+// svgColor convierte un color a notación hexadecimal para SVG
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// RenderSVG dibuja el gráfico como imagen vectorial SVG
+func (c *Chart) RenderSVG(w io.Writer, width, height int) error {
+	minX, maxX, minY, maxY, ok := c.bounds()
+	if !ok {
+		return fmt.Errorf("%s: sin datos para graficar", c.Title)
+	}
+
+	left, right, top, bottom := 70.0, float64(width-20), 40.0, float64(height-50)
+	toX := func(x float64) float64 {
+		return left + (x-minX)/(maxX-minX)*(right-left)
+	}
+	toY := func(y float64) float64 {
+		return bottom - (y-minY)/(maxY-minY)*(bottom-top)
+	}
+
+	fmt.Fprintf(w, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="sans-serif" font-size="12">`+"\n", width, height)
+	fmt.Fprintf(w, `<rect width="100%%" height="100%%" fill="white"/>`+"\n")
+	fmt.Fprintf(w, `<text x="%.0f" y="22" font-size="16">%s</text>`+"\n", left, html.EscapeString(c.Title))
+
+	// Grilla y marcas de los ejes
+	for i := 0; i <= 5; i++ {
+		gy := bottom - (bottom-top)*float64(i)/5
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e1e1e1"/>`+"\n", left, gy, right, gy)
+		fmt.Fprintf(w, `<text x="5" y="%.1f">%.2f</text>`+"\n", gy+4, minY+(maxY-minY)*float64(i)/5)
+
+		gx := left + (right-left)*float64(i)/5
+		fmt.Fprintf(w, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e1e1e1"/>`+"\n", gx, top, gx, bottom)
+		fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", gx, bottom+18, c.formatX(minX+(maxX-minX)*float64(i)/5))
+	}
+	fmt.Fprintf(w, `<polyline points="%.1f,%.1f %.1f,%.1f %.1f,%.1f" fill="none" stroke="black"/>`+"\n", left, top, left, bottom, right, bottom)
+	fmt.Fprintf(w, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`+"\n", right, bottom+38, html.EscapeString(c.XLabel))
+	fmt.Fprintf(w, `<text x="5" y="%.1f">%s</text>`+"\n", top-8, html.EscapeString(c.YLabel))
+
+	for n, s := range c.Series {
+		col := svgColor(seriesColors[n%len(seriesColors)])
+		var points []string
+		for i := range s.X {
+			if i >= len(s.Y) || math.IsNaN(s.Y[i]) {
+				continue
+			}
+			px, py := toX(s.X[i]), toY(s.Y[i])
+			if s.Scatter {
+				fmt.Fprintf(w, `<circle cx="%.1f" cy="%.1f" r="4" fill="%s"/>`+"\n", px, py, col)
+				if i < len(s.Labels) {
+					fmt.Fprintf(w, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`+"\n", px+6, py-6, col, html.EscapeString(s.Labels[i]))
+				}
+			} else {
+				points = append(points, fmt.Sprintf("%.1f,%.1f", px, py))
+			}
+		}
+		if len(points) > 0 {
+			fmt.Fprintf(w, `<polyline points="%s" fill="none" stroke="%s" stroke-width="1.5"/>`+"\n", strings.Join(points, " "), col)
+		}
+
+		// Leyenda
+		fmt.Fprintf(w, `<rect x="%.1f" y="%d" width="10" height="10" fill="%s"/>`+"\n", right-160, int(top)+n*18, col)
+		fmt.Fprintf(w, `<text x="%.1f" y="%d">%s</text>`+"\n", right-145, int(top)+n*18+10, html.EscapeString(s.Name))
+	}
+
+	_, err := fmt.Fprintln(w, "</svg>")
+	return err
+}
+
+// saveChart guarda el gráfico en PNG o SVG según la extensión del archivo
+func saveChart(chart *Chart, path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".svg":
+		return chart.RenderSVG(file, 900, 500)
+	case ".png":
+		return chart.RenderPNG(file, 900, 500)
+	default:
+		return fmt.Errorf("formato de gráfico no soportado: %s (usar .png o .svg)", path)
+	}
+}
+
+// Fuente de mapa de bits de 3x5 para rotular los gráficos exportados
+var font3x5 = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", ".#.", ".#."},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	'A': {".#.", "#.#", "###", "#.#", "#.#"},
+	'B': {"##.", "#.#", "##.", "#.#", "##."},
+	'C': {"###", "#..", "#..", "#..", "###"},
+	'D': {"##.", "#.#", "#.#", "#.#", "##."},
+	'E': {"###", "#..", "##.", "#..", "###"},
+	'F': {"###", "#..", "##.", "#..", "#.."},
+	'G': {"###", "#..", "#.#", "#.#", "###"},
+	'H': {"#.#", "#.#", "###", "#.#", "#.#"},
+	'I': {"###", ".#.", ".#.", ".#.", "###"},
+	'J': {"..#", "..#", "..#", "#.#", "###"},
+	'K': {"#.#", "#.#", "##.", "#.#", "#.#"},
+	'L': {"#..", "#..", "#..", "#..", "###"},
+	'M': {"#.#", "###", "###", "#.#", "#.#"},
+	'N': {"##.", "#.#", "#.#", "#.#", "#.#"},
+	'O': {"###", "#.#", "#.#", "#.#", "###"},
+	'P': {"###", "#.#", "###", "#..", "#.."},
+	'Q': {"###", "#.#", "#.#", "###", "..#"},
+	'R': {"##.", "#.#", "##.", "#.#", "#.#"},
+	'S': {"###", "#..", "###", "..#", "###"},
+	'T': {"###", ".#.", ".#.", ".#.", ".#."},
+	'U': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'V': {"#.#", "#.#", "#.#", "#.#", ".#."},
+	'W': {"#.#", "#.#", "###", "###", "#.#"},
+	'X': {"#.#", "#.#", ".#.", "#.#", "#.#"},
+	'Y': {"#.#", "#.#", ".#.", ".#.", ".#."},
+	'Z': {"###", "..#", ".#.", "#..", "###"},
+	'Á': {".#.", "#.#", "###", "#.#", "#.#"},
+	'É': {"###", "#..", "##.", "#..", "###"},
+	'Í': {"###", ".#.", ".#.", ".#.", "###"},
+	'Ó': {"###", "#.#", "#.#", "#.#", "###"},
+	'Ú': {"#.#", "#.#", "#.#", "#.#", "###"},
+	'Ñ': {"###", "...", "##.", "#.#", "#.#"},
+	' ': {"...", "...", "...", "...", "..."},
+	'.': {"...", "...", "...", "...", ".#."},
+	',': {"...", "...", "...", ".#.", "#.."},
+	':': {"...", ".#.", "...", ".#.", "..."},
+	'-': {"...", "...", "###", "...", "..."},
+	'+': {"...", ".#.", "###", ".#.", "..."},
+	'%': {"#.#", "..#", ".#.", "#..", "#.#"},
+	'/': {"..#", "..#", ".#.", "#..", "#.."},
+	'(': {".#.", "#..", "#..", "#..", ".#."},
+	')': {".#.", "..#", "..#", "..#", ".#."},
+	'=': {"...", "###", "...", "###", "..."},
+	'$': {".##", "##.", ".#.", ".##", "##."},
+	'?': {"###", "..#", ".#.", "...", ".#."},
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testChart es un gráfico con una línea de (0,10) a (10,20), un NaN que se saltea y dos puntos sueltos
+func testChart() *Chart {
+	return &Chart{
+		Title:  "Precio <GGAL> & cía",
+		XLabel: "días",
+		YLabel: "$",
+		Series: []Series{
+			{Name: "GGAL", X: []float64{0, 5, 10}, Y: []float64{10, math.NaN(), 20}},
+			{Name: "AL30", X: []float64{2, 8}, Y: []float64{12, 18}, Labels: []string{"a", "b"}, Scatter: true},
+		},
+	}
+}
+
+func TestChartBounds(t *testing.T) {
+	minX, maxX, minY, maxY, ok := testChart().bounds()
+	if !ok {
+		t.Fatal("el gráfico tiene datos")
+	}
+	// Margen del 5% sobre 0..10 y 10..20
+	want := []float64{-0.5, 10.5, 9.5, 20.5}
+	for i, got := range []float64{minX, maxX, minY, maxY} {
+		if math.Abs(got-want[i]) > 1e-9 {
+			t.Errorf("bounds = %v %v %v %v, se esperaba %v", minX, maxX, minY, maxY, want)
+			break
+		}
+	}
+
+	// Un único punto se abre a ±1 para no dividir por cero
+	single := &Chart{Series: []Series{{X: []float64{3}, Y: []float64{7}}}}
+	if minX, maxX, minY, maxY, ok := single.bounds(); !ok || minX >= 3 || maxX <= 3 || minY >= 7 || maxY <= 7 {
+		t.Errorf("bounds de un punto = %v %v %v %v %v", minX, maxX, minY, maxY, ok)
+	}
+
+	empty := &Chart{Series: []Series{{X: []float64{1}, Y: []float64{math.NaN()}}, {X: []float64{1, 2}}}}
+	if _, _, _, _, ok := empty.bounds(); ok {
+		t.Error("un gráfico sin valores no debería tener rango")
+	}
+}
+
+func TestCanvasLine(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+	tests := [][4]int{{2, 3, 17, 9}, {17, 9, 2, 3}, {5, 1, 5, 18}, {1, 18, 18, 1}, {4, 4, 4, 4}}
+	for _, tt := range tests {
+		cv := newCanvas(20, 20)
+		cv.line(tt[0], tt[1], tt[2], tt[3], red)
+
+		// Los extremos quedan pintados y cada punto pintado toca a otro: la recta no tiene huecos
+		if cv.img.RGBAAt(tt[0], tt[1]) != red || cv.img.RGBAAt(tt[2], tt[3]) != red {
+			t.Errorf("recta %v: faltan los extremos", tt)
+		}
+		var painted int
+		for y := 0; y < 20; y++ {
+			for x := 0; x < 20; x++ {
+				if cv.img.RGBAAt(x, y) != red {
+					continue
+				}
+				painted++
+				neighbors := 0
+				for dy := -1; dy <= 1; dy++ {
+					for dx := -1; dx <= 1; dx++ {
+						if (dx != 0 || dy != 0) && cv.img.RGBAAt(x+dx, y+dy) == red {
+							neighbors++
+						}
+					}
+				}
+				if neighbors == 0 && (tt[0] != tt[2] || tt[1] != tt[3]) {
+					t.Errorf("recta %v: el punto (%d,%d) quedó aislado", tt, x, y)
+				}
+			}
+		}
+		if want := max(abs(tt[2]-tt[0]), abs(tt[3]-tt[1])) + 1; painted != want {
+			t.Errorf("recta %v: %d puntos pintados, se esperaban %d", tt, painted, want)
+		}
+	}
+}
+
+func TestFont3x5Glyphs(t *testing.T) {
+	for ch, glyph := range font3x5 {
+		for _, row := range glyph {
+			if len(row) != 3 || strings.Trim(row, "#.") != "" {
+				t.Errorf("glifo %q mal formado: %q", ch, glyph)
+				break
+			}
+		}
+	}
+	// Todo lo que rotulan los ejes tiene glifo propio, sin caer en '?'
+	for _, ch := range "0123456789.-/:%" {
+		if _, ok := font3x5[ch]; !ok {
+			t.Errorf("falta el glifo de %q", ch)
+		}
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testChart().RenderPNG(&buf, 400, 300); err != nil {
+		t.Fatal(err)
+	}
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("PNG inválido: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 400 || size.Y != 300 {
+		t.Fatalf("imagen de %v, se esperaba 400x300", size)
+	}
+
+	count := func(c color.RGBA) int {
+		n := 0
+		for y := 0; y < 300; y++ {
+			for x := 0; x < 400; x++ {
+				if color.RGBAModel.Convert(img.At(x, y)) == c {
+					n++
+				}
+			}
+		}
+		return n
+	}
+	// Ejes en negro y cada serie con su color; los puntos sueltos son cuadrados de 7x7
+	if img.At(70, 250) != (color.RGBA{A: 255}) {
+		t.Error("falta el origen de los ejes en (70, 250)")
+	}
+	if n := count(seriesColors[0]); n < 100 {
+		t.Errorf("la línea de GGAL tiene solo %d píxeles", n)
+	}
+	if n := count(seriesColors[1]); n < 2*49 {
+		t.Errorf("los puntos de AL30 tienen solo %d píxeles", n)
+	}
+	if n := count(seriesColors[2]); n != 0 {
+		t.Errorf("hay %d píxeles del color de una tercera serie inexistente", n)
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testChart().RenderSVG(&buf, 400, 300); err != nil {
+		t.Fatal(err)
+	}
+
+	// El SVG es XML válido y los textos quedan escapados
+	counts := make(map[string]int)
+	var texts []string
+	decoder := xml.NewDecoder(bytes.NewReader(buf.Bytes()))
+	inText := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("SVG inválido: %v\n%s", err, buf.String())
+		}
+		switch el := token.(type) {
+		case xml.StartElement:
+			counts[el.Name.Local]++
+			inText = el.Name.Local == "text"
+		case xml.CharData:
+			if inText {
+				texts = append(texts, string(el))
+			}
+		case xml.EndElement:
+			inText = false
+		}
+	}
+	if counts["svg"] != 1 {
+		t.Errorf("se esperaba un elemento svg, hay %d", counts["svg"])
+	}
+	// Ejes y línea de GGAL (el NaN no la corta en dos); dos círculos de AL30
+	if counts["polyline"] != 2 || counts["circle"] != 2 {
+		t.Errorf("polylines %d y círculos %d, se esperaban 2 y 2", counts["polyline"], counts["circle"])
+	}
+	for _, want := range []string{"Precio <GGAL> & cía", "días", "GGAL", "AL30", "a", "b"} {
+		found := false
+		for _, text := range texts {
+			found = found || text == want
+		}
+		if !found {
+			t.Errorf("falta el texto %q en el SVG", want)
+		}
+	}
+	if !strings.Contains(buf.String(), svgColor(seriesColors[0])) || !strings.Contains(buf.String(), svgColor(seriesColors[1])) {
+		t.Error("las series no usan sus colores")
+	}
+}
+
+func TestRenderWithoutData(t *testing.T) {
+	chart := &Chart{Title: "Vacío"}
+	if err := chart.RenderPNG(io.Discard, 100, 100); err == nil {
+		t.Error("RenderPNG sin datos debería fallar")
+	}
+	if err := chart.RenderSVG(io.Discard, 100, 100); err == nil {
+		t.Error("RenderSVG sin datos debería fallar")
+	}
+	var out strings.Builder
+	chart.RenderASCII(&out, 40, 10)
+	if !strings.Contains(out.String(), "sin datos") {
+		t.Errorf("RenderASCII sin datos = %q", out.String())
+	}
+}
+
+func TestRenderASCII(t *testing.T) {
+	var out strings.Builder
+	testChart().RenderASCII(&out, 40, 10)
+	lines := strings.Split(out.String(), "\n")
+	if lines[0] != testChart().Title {
+		t.Errorf("título = %q", lines[0])
+	}
+	grid := strings.Join(lines[1:11], "\n")
+	if strings.Count(grid, "*") != 2 || strings.Count(grid, "o") != 2 {
+		t.Errorf("se esperaban dos marcas de cada serie:\n%s", grid)
+	}
+	if !strings.Contains(out.String(), "* GGAL") || !strings.Contains(out.String(), "o AL30") {
+		t.Errorf("falta la leyenda:\n%s", out.String())
+	}
+}
+
+func TestSaveChart(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"grafico.png", "grafico.SVG"} {
+		path := filepath.Join(dir, name)
+		if err := saveChart(testChart(), path); err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+			t.Errorf("%s quedó vacío", name)
+		}
+	}
+	if err := saveChart(testChart(), filepath.Join(dir, "grafico.jpg")); err == nil {
+		t.Error("se esperaba un error con .jpg")
+	}
+}
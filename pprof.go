@@ -0,0 +1,25 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // Registra /debug/pprof/ en http.DefaultServeMux
+)
+
+// startPprof expone net/http/pprof en addr (por ejemplo ":6060") para investigar memoria y goroutines
+func startPprof(addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return fmt.Errorf("no se pudo iniciar pprof en %s: %v", addr, err)
+	}
+
+	fmt.Printf("pprof disponible en http://%s/debug/pprof/ (heap: go tool pprof http://%s/debug/pprof/heap)\n",
+		listener.Addr(), listener.Addr())
+	go func() {
+		if err := http.Serve(listener, nil); err != nil {
+			fmt.Printf("pprof detenido: %v\n", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prediction es una expectativa personal sobre el cierre de un símbolo en una rueda
+type Prediction struct {
+	ID      int       `json:"id"`
+	Symbol  string    `json:"symbol"`
+	Above   bool      `json:"above"` // true: cierra arriba del objetivo; false: abajo
+	Target  float64   `json:"target"`
+	Date    string    `json:"date"` // Rueda (AAAA-MM-DD, Buenos Aires)
+	Created time.Time `json:"created"`
+
+	Resolved bool    `json:"resolved"`
+	Close    float64 `json:"close,omitempty"`
+	Hit      bool    `json:"hit,omitempty"`
+}
+
+const predictionsFile = "predictions.json"
+
+var predictionsMu sync.Mutex
+
+// String describe la predicción como "GGAL > 48000,00"
+func (p Prediction) String() string {
+	op := "<"
+	if p.Above {
+		op = ">"
+	}
+	return fmt.Sprintf("%s %s %.2f", p.Symbol, op, p.Target)
+}
+
+// loadPredictions lee las predicciones guardadas
+func loadPredictions() ([]Prediction, error) {
+	path, err := appFile(predictionsFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var predictions []Prediction
+	if err := json.Unmarshal(data, &predictions); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return predictions, nil
+}
+
+// savePredictions escribe las predicciones
+func savePredictions(predictions []Prediction) error {
+	path, err := appFile(predictionsFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(predictions, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// resolve marca la predicción como resuelta con el precio de cierre
+func (p *Prediction) resolve(close float64) {
+	p.Resolved, p.Close = true, close
+	p.Hit = (p.Above && close > p.Target) || (!p.Above && close < p.Target)
+}
+
+// hitRate devuelve aciertos y predicciones resueltas
+func hitRate(predictions []Prediction) (int, int) {
+	hits, resolved := 0, 0
+	for _, p := range predictions {
+		if p.Resolved {
+			resolved++
+			if p.Hit {
+				hits++
+			}
+		}
+	}
+	return hits, resolved
+}
+
+// resolveTodayPredictions resuelve al cierre las predicciones de hoy con los precios del snapshot (en pesos,
+// como se muestran en el monitor) y devuelve el texto para el resumen de cierre
+func resolveTodayPredictions(snapshot *Snapshot, now time.Time) string {
+	predictionsMu.Lock()
+	defer predictionsMu.Unlock()
+
+	predictions, err := loadPredictions()
+	if err != nil {
+		fmt.Printf("Error al leer las predicciones: %v\n", err)
+		return ""
+	}
+
+	prices := make(map[string]float64)
+	for _, forex := range snapshot.Forex {
+		prices[forex.Symbol] = forex.Price
+	}
+	for _, stock := range snapshot.Stocks {
+		prices[stock.Symbol] = stock.Price
+	}
+
+	today := now.In(argentinaLocation).Format("2006-01-02")
+	var lines []string
+	for i := range predictions {
+		p := &predictions[i]
+		if p.Resolved || p.Date != today {
+			continue
+		}
+		price, ok := prices[p.Symbol]
+		if !ok {
+			continue // Queda pendiente para bolsa predict resolve
+		}
+		p.resolve(price)
+		mark := "✗"
+		if p.Hit {
+			mark = "✓"
+		}
+		lines = append(lines, fmt.Sprintf("  %s %s (cerró %.2f)", mark, p, price))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+
+	if err := savePredictions(predictions); err != nil {
+		fmt.Printf("Error al guardar las predicciones: %v\n", err)
+	}
+
+	hits, resolved := hitRate(predictions)
+	return fmt.Sprintf("\nTus predicciones de hoy:\n%s\nHit rate histórico: %d/%d (%.0f%%)\n",
+		strings.Join(lines, "\n"), hits, resolved, float64(hits)/float64(resolved)*100)
+}
+
+// closeOn devuelve el cierre en pesos de un símbolo en una rueda pasada (los papeles de Nueva York al oficial)
+func closeOn(symbol, day string, client QuoteFetcher) (float64, error) {
+	from, err := time.ParseInLocation("2006-01-02", day, argentinaLocation)
+	if err != nil {
+		return 0, err
+	}
+	closeOf := func(sym string) (float64, error) {
+		points, err := getHistoryBetween(sym, from, from.AddDate(0, 0, 1), "1d", client)
+		if err != nil {
+			return 0, err
+		}
+		if len(points) == 0 {
+			return 0, fmt.Errorf("sin cotización de %s el %s", sym, day)
+		}
+		return points[len(points)-1].Close, nil
+	}
+
+	price, err := closeOf(symbol)
+	if err != nil {
+		return 0, err
+	}
+	for _, stock := range watchlistSnapshot() {
+		if stock.Symbol == symbol && stock.Market == MarketNYSE {
+			rate, err := closeOf("ARS=X")
+			if err != nil {
+				return 0, err
+			}
+			return price * rate, nil
+		}
+	}
+	return price, nil
+}
+
+// runPredict implementa `bolsa predict add|list|resolve|stats`
+func runPredict(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("uso: bolsa predict add SIMBOLO >|< PRECIO [--date AAAA-MM-DD] | list | resolve | stats")
+	}
+
+	predictionsMu.Lock()
+	defer predictionsMu.Unlock()
+
+	predictions, err := loadPredictions()
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "add":
+		fs := flag.NewFlagSet("predict add", flag.ExitOnError)
+		date := fs.String("date", time.Now().In(argentinaLocation).Format("2006-01-02"), "rueda de la predicción")
+		fs.Parse(args[1:])
+
+		// Acepta "GGAL > 48000", "GGAL >48000" y "GGAL arriba 48000"
+		rest := strings.Fields(strings.NewReplacer(">", " > ", "<", " < ").Replace(strings.Join(fs.Args(), " ")))
+		if len(rest) != 3 {
+			return fmt.Errorf("uso: bolsa predict add GGAL '>' 48000 (o arriba/abajo)")
+		}
+		var above bool
+		switch strings.ToLower(rest[1]) {
+		case ">", "arriba", "above":
+			above = true
+		case "<", "abajo", "below":
+		default:
+			return fmt.Errorf("operador inválido %q (usar >, <, arriba o abajo)", rest[1])
+		}
+		target, err := strconv.ParseFloat(rest[2], 64)
+		if err != nil || target <= 0 {
+			return fmt.Errorf("precio inválido %q", rest[2])
+		}
+		if _, err := time.Parse("2006-01-02", *date); err != nil {
+			return fmt.Errorf("fecha inválida %q", *date)
+		}
+
+		id := 1
+		for _, p := range predictions {
+			id = max(id, p.ID+1)
+		}
+		p := Prediction{ID: id, Symbol: strings.ToUpper(rest[0]), Above: above, Target: target, Date: *date, Created: time.Now()}
+		predictions = append(predictions, p)
+		if err := savePredictions(predictions); err != nil {
+			return err
+		}
+		fmt.Printf("Predicción #%d registrada: %s al cierre del %s\n", p.ID, p, p.Date)
+		return nil
+
+	case "list":
+		if len(predictions) == 0 {
+			fmt.Println("No hay predicciones registradas.")
+			return nil
+		}
+		fmt.Printf("%-4s %-10s %-28s %s\n", "#", "Rueda", "Predicción", "Resultado")
+		for _, p := range predictions {
+			result := Yellow + "pendiente" + Reset
+			if p.Resolved && p.Hit {
+				result = fmt.Sprintf("%sacertada%s (cerró %.2f)", Green, Reset, p.Close)
+			} else if p.Resolved {
+				result = fmt.Sprintf("%sfallada%s (cerró %.2f)", Red, Reset, p.Close)
+			}
+			fmt.Printf("%-4d %-10s %-28s %s\n", p.ID, p.Date, p.String(), result)
+		}
+		return nil
+
+	case "resolve":
+		// Predicciones de ruedas pasadas que no se resolvieron porque el monitor no estaba corriendo al cierre
+		client := NewHTTPClient()
+		today := time.Now().In(argentinaLocation).Format("2006-01-02")
+		count := 0
+		for i := range predictions {
+			p := &predictions[i]
+			if p.Resolved || p.Date >= today {
+				continue
+			}
+			price, err := closeOn(p.Symbol, p.Date, client)
+			if err != nil {
+				fmt.Printf("%s#%d %s: %v%s\n", Red, p.ID, p, err, Reset)
+				continue
+			}
+			p.resolve(price)
+			count++
+		}
+		if err := savePredictions(predictions); err != nil {
+			return err
+		}
+		fmt.Printf("%d predicción(es) resueltas\n", count)
+		return nil
+
+	case "stats":
+		hits, resolved := hitRate(predictions)
+		if resolved == 0 {
+			fmt.Println("Todavía no hay predicciones resueltas.")
+			return nil
+		}
+		fmt.Printf("Hit rate: %d/%d (%.1f%%)\n\n", hits, resolved, float64(hits)/float64(resolved)*100)
+
+		bySymbol := make(map[string][]Prediction)
+		for _, p := range predictions {
+			bySymbol[p.Symbol] = append(bySymbol[p.Symbol], p)
+		}
+		var symbols []string
+		for symbol := range bySymbol {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		for _, symbol := range symbols {
+			h, r := hitRate(bySymbol[symbol])
+			if r > 0 {
+				fmt.Printf("  %-10s %d/%d (%.0f%%)\n", symbol, h, r, float64(h)/float64(r)*100)
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("subcomando desconocido de predict: %s", args[0])
+}
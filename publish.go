@@ -0,0 +1,392 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const publishMarkerFile = "last_publish"
+
+// PublishConfig define dónde se sube la página estática con la tabla del día en cada cierre
+type PublishConfig struct {
+	Dir    string        `json:"dir"` // Directorio local (por ejemplo un checkout servido por otro medio)
+	S3     *S3Target     `json:"s3"`
+	GitHub *GitHubTarget `json:"github"`
+}
+
+// S3Target es un bucket de S3 (o compatible) configurado como sitio estático
+type S3Target struct {
+	Bucket          string `json:"bucket"`
+	Region          string `json:"region"`
+	Prefix          string `json:"prefix"`          // Carpeta dentro del bucket
+	Endpoint        string `json:"endpoint"`        // Para servicios compatibles (MinIO, R2); vacío = AWS
+	AccessKeyID     string `json:"accessKeyId"`     // Si está vacío se usa AWS_ACCESS_KEY_ID
+	SecretAccessKey string `json:"secretAccessKey"` // Si está vacío se usa AWS_SECRET_ACCESS_KEY
+}
+
+// GitHubTarget es un repositorio publicado con GitHub Pages
+type GitHubTarget struct {
+	Repo   string `json:"repo"`   // usuario/repositorio
+	Branch string `json:"branch"` // Por defecto gh-pages
+	Dir    string `json:"dir"`    // Carpeta dentro del repositorio
+	Token  string `json:"token"`  // Si está vacío se usa GITHUB_TOKEN
+}
+
+// validate completa los valores por defecto y verifica los destinos
+func (c *PublishConfig) validate() error {
+	if c.S3 != nil {
+		if c.S3.Bucket == "" {
+			return fmt.Errorf("s3: falta \"bucket\"")
+		}
+		if c.S3.Region == "" {
+			c.S3.Region = "us-east-1"
+		}
+	}
+	if c.GitHub != nil {
+		if strings.Count(c.GitHub.Repo, "/") != 1 {
+			return fmt.Errorf("github: \"repo\" debe ser usuario/repositorio, no %q", c.GitHub.Repo)
+		}
+		if c.GitHub.Branch == "" {
+			c.GitHub.Branch = "gh-pages"
+		}
+	}
+	if c.Dir == "" && c.S3 == nil && c.GitHub == nil {
+		return fmt.Errorf("no hay destinos: definir dir, s3 o github")
+	}
+	return nil
+}
+
+var snapshotPageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"signed": func(v float64) string { return fmt.Sprintf("%+.2f", v) },
+	"price":  func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"class": func(v float64) string {
+		switch {
+		case v > 0:
+			return "up"
+		case v < 0:
+			return "down"
+		}
+		return ""
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+{{if .Refresh}}<meta http-equiv="refresh" content="{{.Refresh}}">{{end}}
+<title>{{.Title}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 60em; padding: 0 1em; color: #222; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 2em; }
+th, td { padding: .35em .6em; border-bottom: 1px solid #ddd; }
+th { text-align: left; background: #f4f4f4; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+.up { color: #0a7d28; } .down { color: #c0152f; }
+footer { color: #777; font-size: .85em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Datos al {{.Updated}}</p>
+{{if .Forex}}<h2>Tipos de cambio</h2>
+<table>
+<tr><th>Moneda</th><th>Precio</th><th>Variación</th><th>%</th></tr>
+{{range .Forex}}<tr><td>{{.Name}}</td><td class="num">{{price .Price}}</td><td class="num {{class .Change}}">{{signed .Change}}</td><td class="num {{class .ChangePercent}}">{{signed .ChangePercent}}%</td></tr>
+{{end}}</table>{{end}}
+{{if .Stocks}}<h2>Acciones</h2>
+<table>
+<tr><th>Símbolo</th><th>Empresa</th><th>Mercado</th><th>Precio ($)</th><th>Variación</th><th>%</th><th>Volumen</th></tr>
+{{range .Stocks}}<tr><td>{{.Symbol}}</td><td>{{.Name}}</td><td>{{.Market}}</td><td class="num">{{price .Price}}</td><td class="num {{class .Change}}">{{signed .Change}}</td><td class="num {{class .ChangePercent}}">{{signed .ChangePercent}}%</td><td class="num">{{.Volume}}</td></tr>
+{{end}}</table>{{end}}
+<footer>Generado por bolsa. Precios de NYSE convertidos a pesos al dólar oficial. No es recomendación de inversión.</footer>
+</body>
+</html>
+`))
+
+// renderSnapshotPage genera la página HTML con la tabla del snapshot; con refresh > 0 se recarga sola cada
+// esos segundos (las páginas publicadas al cierre no se recargan)
+func renderSnapshotPage(snapshot *Snapshot, refresh int) ([]byte, error) {
+	stocks := make([]StockInfo, len(snapshot.Stocks))
+	copy(stocks, snapshot.Stocks)
+	sort.Slice(stocks, func(i, j int) bool { return stocks[i].Symbol < stocks[j].Symbol })
+
+	local := snapshot.Time.In(argentinaLocation)
+	var page bytes.Buffer
+	err := snapshotPageTemplate.Execute(&page, struct {
+		Title   string
+		Updated string
+		Refresh int
+		Forex   []ForexInfo
+		Stocks  []StockInfo
+	}{"Bolsa: rueda del " + local.Format("02/01/2006"), local.Format("02/01/2006 15:04"), refresh, snapshot.Forex, stocks})
+	return page.Bytes(), err
+}
+
+// publishFiles sube los archivos a todos los destinos configurados; sigue con los demás si uno falla
+func publishFiles(cfg *PublishConfig, files map[string][]byte) error {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var failures []string
+	for _, name := range names {
+		if cfg.Dir != "" {
+			if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(cfg.Dir, name), files[name], 0o644); err != nil {
+				failures = append(failures, fmt.Sprintf("%s en %s: %v", name, cfg.Dir, err))
+			}
+		}
+		if cfg.S3 != nil {
+			if err := s3Put(cfg.S3, name, files[name], "text/html; charset=utf-8"); err != nil {
+				failures = append(failures, fmt.Sprintf("%s en S3: %v", name, err))
+			}
+		}
+		if cfg.GitHub != nil {
+			if err := githubPut(cfg.GitHub, name, files[name]); err != nil {
+				failures = append(failures, fmt.Sprintf("%s en GitHub: %v", name, err))
+			}
+		}
+	}
+	if len(failures) > 0 {
+		return fmt.Errorf("%s", strings.Join(failures, "; "))
+	}
+	return nil
+}
+
+// publishSnapshot publica la página del snapshot como index.html y como archivo del día
+func publishSnapshot(cfg *PublishConfig, snapshot *Snapshot) error {
+	page, err := renderSnapshotPage(snapshot, 0)
+	if err != nil {
+		return err
+	}
+	day := snapshot.Time.In(argentinaLocation).Format("2006-01-02")
+	return publishFiles(cfg, map[string][]byte{"index.html": page, day + ".html": page})
+}
+
+// maybePublishSnapshot publica la página una vez por día, tras el cierre de BYMA
+func maybePublishSnapshot(snapshot *Snapshot) {
+	cfg := appConfig().Publish
+	now := time.Now()
+	if cfg == nil || !bymaHours.IsClosedForDay(now) {
+		return
+	}
+	today := now.In(argentinaLocation).Format("2006-01-02")
+	path, err := appFile(publishMarkerFile)
+	if err != nil {
+		return
+	}
+	if last, _ := os.ReadFile(path); strings.TrimSpace(string(last)) == today {
+		return
+	}
+
+	if err := publishSnapshot(cfg, snapshot); err != nil {
+		fmt.Printf("%sNo se pudo publicar la página del día: %v%s\n", Yellow, err, Reset)
+		return
+	}
+	if err := os.WriteFile(path, []byte(today), 0o644); err != nil {
+		fmt.Printf("Error al registrar la publicación: %v\n", err)
+	}
+	fmt.Printf("%sPágina del día publicada.%s\n", Green, Reset)
+}
+
+// awsEscape codifica un segmento de la ruta como lo exige la firma de AWS (RFC 3986)
+func awsEscape(s string) string {
+	var b strings.Builder
+	for _, c := range []byte(s) {
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-_.~", c) >= 0 {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Put sube un objeto a S3 con una firma AWS Signature Version 4
+func s3Put(target *S3Target, name string, body []byte, contentType string) error {
+	accessKey, secretKey := target.AccessKeyID, target.SecretAccessKey
+	if accessKey == "" {
+		accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+	}
+	if secretKey == "" {
+		secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+	}
+	if accessKey == "" || secretKey == "" {
+		return fmt.Errorf("faltan las credenciales (accessKeyId/secretAccessKey o AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY)")
+	}
+
+	var segments []string
+	for _, part := range strings.Split(strings.Trim(target.Prefix, "/")+"/"+name, "/") {
+		if part != "" {
+			segments = append(segments, awsEscape(part))
+		}
+	}
+	// Con endpoint propio se usa el estilo de ruta (bucket en el path); en AWS, el host virtual del bucket
+	host := target.Bucket + ".s3." + target.Region + ".amazonaws.com"
+	scheme := "https"
+	path := "/" + strings.Join(segments, "/")
+	if target.Endpoint != "" {
+		endpoint, err := url.Parse(target.Endpoint)
+		if err != nil || endpoint.Host == "" {
+			return fmt.Errorf("endpoint inválido %q", target.Endpoint)
+		}
+		host, scheme = endpoint.Host, endpoint.Scheme
+		path = "/" + awsEscape(target.Bucket) + path
+	}
+
+	now := time.Now().UTC()
+	amzDate, date := now.Format("20060102T150405Z"), now.Format("20060102")
+	payloadHash := fmt.Sprintf("%x", sha256.Sum256(body))
+
+	req, err := http.NewRequest(http.MethodPut, scheme+"://"+host+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		http.MethodPut, path, "",
+		"content-type:" + contentType, "host:" + host, "x-amz-content-sha256:" + payloadHash, "x-amz-date:" + amzDate, "",
+		signedHeaders, payloadHash,
+	}, "\n")
+	scope := date + "/" + target.Region + "/s3/aws4_request"
+	stringToSign := fmt.Sprintf("AWS4-HMAC-SHA256\n%s\n%s\n%x", amzDate, scope, sha256.Sum256([]byte(canonicalRequest)))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, target.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+
+	resp, err := NewProviderClient("s3").client.Do(req)
+	if err != nil {
+		return &QuoteError{Kind: ErrNetwork, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// githubPut crea o reemplaza un archivo del repositorio con la API de contenidos de GitHub
+func githubPut(target *GitHubTarget, name string, body []byte) error {
+	token := target.Token
+	if token == "" {
+		token = os.Getenv("GITHUB_TOKEN")
+	}
+	if token == "" {
+		return fmt.Errorf("falta el token (github.token o GITHUB_TOKEN)")
+	}
+
+	path := strings.Trim(strings.Trim(target.Dir, "/")+"/"+name, "/")
+	contentsURL := fmt.Sprintf("https://api.github.com/repos/%s/contents/%s", target.Repo, path)
+	headers := map[string]string{"Authorization": "Bearer " + token, "Accept": "application/vnd.github+json"}
+	client := NewProviderClient("github")
+
+	// Para reemplazar un archivo existente hay que informar su sha actual
+	var existing struct {
+		SHA string `json:"sha"`
+	}
+	resp, err := client.GetWithRetry(contentsURL+"?ref="+url.QueryEscape(target.Branch), headers)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusOK {
+		json.NewDecoder(resp.Body).Decode(&existing)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return statusError(resp.StatusCode, "al consultar %s en %s", path, target.Repo)
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"message": "Publicar " + name,
+		"content": base64.StdEncoding.EncodeToString(body),
+		"branch":  target.Branch,
+		"sha":     existing.SHA,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, contentsURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err = client.client.Do(req)
+	if err != nil {
+		return &QuoteError{Kind: ErrNetwork, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return statusError(resp.StatusCode, "al subir %s a %s", path, target.Repo)
+	}
+	return nil
+}
+
+// runPublish implementa `bolsa publish`: genera la página del último snapshot y la sube, o la escribe en un archivo
+func runPublish(args []string) error {
+	fs := flag.NewFlagSet("publish", flag.ExitOnError)
+	output := fs.String("o", "", "escribir el HTML en este archivo en lugar de subirlo a los destinos de config.json")
+	fs.Parse(args)
+
+	snapshot, err := loadLastSnapshot()
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("todavía no hay un snapshot guardado: correr el monitor al menos un ciclo")
+	}
+
+	if *output != "" {
+		page, err := renderSnapshotPage(snapshot, 0)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(*output, page, 0o644)
+	}
+
+	cfg := appConfig().Publish
+	if cfg == nil {
+		return fmt.Errorf("no hay destinos de publicación: definir \"publish\" en config.json (dir, s3 o github)")
+	}
+	if err := publishSnapshot(cfg, snapshot); err != nil {
+		return err
+	}
+	fmt.Println("Página publicada.")
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CycleQuality resume cuán confiable es un snapshot: cuántos símbolos se actualizaron, qué tan viejos son sus datos
+// y qué proveedor aportó cada precio
+type CycleQuality struct {
+	Expected   int            `json:"expected"`
+	Updated    int            `json:"updated"`
+	Success    float64        `json:"success"`     // Porcentaje de símbolos actualizados
+	AverageAge float64        `json:"average_age"` // Segundos desde la última operación informada, en promedio
+	Providers  map[string]int `json:"providers"`   // Proveedor → símbolos cuyo precio aportó
+}
+
+// Hora de la última operación que informó el proveedor para cada símbolo
+var (
+	quoteTimesMu sync.Mutex
+	quoteTimes   = make(map[string]time.Time)
+)
+
+// recordQuoteTime registra la hora de mercado de la cotización de un símbolo
+func recordQuoteTime(symbol string, t time.Time) {
+	quoteTimesMu.Lock()
+	quoteTimes[symbol] = t
+	quoteTimesMu.Unlock()
+}
+
+// Calidad del último ciclo, para /metrics
+var (
+	lastQualityMu sync.Mutex
+	lastQuality   *CycleQuality
+)
+
+// computeQuality calcula la calidad del snapshot contra los símbolos que se intentaron actualizar
+func computeQuality(snapshot *Snapshot, expected int, now time.Time) *CycleQuality {
+	q := &CycleQuality{Expected: expected, Providers: make(map[string]int)}
+
+	var symbols []string
+	for _, forex := range snapshot.Forex {
+		symbols = append(symbols, forex.Symbol)
+		q.Providers["yahoo"]++
+	}
+	for _, stock := range snapshot.Stocks {
+		// Los postergados por el scheduler conservan la cotización de un ciclo anterior
+		if stock.Deferred {
+			continue
+		}
+		symbols = append(symbols, stock.Symbol)
+		source := stock.Sources[FieldPrice]
+		if source == "" {
+			source = "yahoo"
+		}
+		q.Providers[source]++
+	}
+	q.Updated = len(symbols) + len(snapshot.Bonds)
+	q.Providers["yahoo"] += len(snapshot.Bonds)
+	if q.Expected > 0 {
+		q.Success = min(float64(q.Updated)/float64(q.Expected)*100, 100)
+	}
+
+	quoteTimesMu.Lock()
+	var total time.Duration
+	dated := 0
+	for _, symbol := range symbols {
+		if t, ok := quoteTimes[symbol]; ok && !t.IsZero() {
+			total += max(now.Sub(t), 0)
+			dated++
+		}
+	}
+	quoteTimesMu.Unlock()
+	if dated > 0 {
+		q.AverageAge = (total / time.Duration(dated)).Seconds()
+	}
+
+	lastQualityMu.Lock()
+	lastQuality = q
+	lastQualityMu.Unlock()
+	return q
+}
+
+// formatAge muestra una antigüedad en la unidad más grande que corresponda (45s, 12m, 3h, 2d)
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd", int(d.Hours()/24))
+}
+
+// qualityHeader es la parte de la cabecera con la calidad del ciclo
+func qualityHeader(q *CycleQuality) string {
+	color := Green
+	if q.Success < 80 {
+		color = Red
+	} else if q.Success < 95 {
+		color = Yellow
+	}
+
+	var names []string
+	for name := range q.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var providers []string
+	for _, name := range names {
+		providers = append(providers, fmt.Sprintf("%s %d", name, q.Providers[name]))
+	}
+
+	text := fmt.Sprintf("Calidad: %s%.0f%%%s (%d/%d)", color, q.Success, Reset, q.Updated, q.Expected)
+	if q.AverageAge > 0 {
+		text += " edad " + formatAge(time.Duration(q.AverageAge*float64(time.Second)))
+	}
+	if len(providers) > 0 {
+		text += " · " + strings.Join(providers, ", ")
+	}
+	return text
+}
+
+// handleMetrics expone la calidad del último ciclo y los contadores del daemon en formato Prometheus
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	var b strings.Builder
+	gauge := func(name, help string, value float64, labels string) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s gauge\n%s%s %g\n", name, help, name, name, labels, value)
+	}
+
+	lastQualityMu.Lock()
+	q := lastQuality
+	lastQualityMu.Unlock()
+	if q != nil {
+		gauge("bolsa_cycle_success_percent", "Porcentaje de símbolos actualizados en el último ciclo.", q.Success, "")
+		gauge("bolsa_cycle_symbols_expected", "Símbolos que se intentaron actualizar en el último ciclo.", float64(q.Expected), "")
+		gauge("bolsa_cycle_symbols_updated", "Símbolos actualizados en el último ciclo.", float64(q.Updated), "")
+		gauge("bolsa_cycle_data_age_seconds", "Antigüedad promedio de los datos del último ciclo.", q.AverageAge, "")
+		var names []string
+		for name := range q.Providers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		b.WriteString("# HELP bolsa_cycle_provider_symbols Símbolos cuyo precio aportó cada proveedor en el último ciclo.\n# TYPE bolsa_cycle_provider_symbols gauge\n")
+		for _, name := range names {
+			fmt.Fprintf(&b, "bolsa_cycle_provider_symbols{provider=%q} %d\n", name, q.Providers[name])
+		}
+	}
+
+	statsMu.Lock()
+	fmt.Fprintf(&b, "# HELP bolsa_cycles_total Ciclos de actualización desde el primer inicio.\n# TYPE bolsa_cycles_total counter\n")
+	fmt.Fprintf(&b, "bolsa_cycles_total{result=\"ok\"} %d\nbolsa_cycles_total{result=\"failed\"} %d\n", stats.CyclesOK, stats.CyclesFailed)
+	var providers []string
+	for name := range stats.Providers {
+		providers = append(providers, name)
+	}
+	sort.Strings(providers)
+	fmt.Fprintf(&b, "# HELP bolsa_provider_requests_total Solicitudes a cada proveedor.\n# TYPE bolsa_provider_requests_total counter\n")
+	for _, name := range providers {
+		fmt.Fprintf(&b, "bolsa_provider_requests_total{provider=%q} %d\n", name, stats.Providers[name].Requests)
+	}
+	fmt.Fprintf(&b, "# HELP bolsa_provider_errors_total Solicitudes fallidas a cada proveedor.\n# TYPE bolsa_provider_errors_total counter\n")
+	for _, name := range providers {
+		fmt.Fprintf(&b, "bolsa_provider_errors_total{provider=%q} %d\n", name, stats.Providers[name].Errors)
+	}
+	statsMu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write([]byte(b.String()))
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+)
+
+// Quote es la cotización de un símbolo tal como la devuelve `bolsa quote`
+type Quote = bolsa.Quote
+
+// readSymbols lee símbolos separados por espacios, comas o líneas; ignora líneas vacías y comentarios (#)
+func readSymbols(r io.Reader) ([]string, error) {
+	var symbols []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = line[:i]
+		}
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' || r == ' ' || r == '\t' }) {
+			symbols = append(symbols, strings.ToUpper(field))
+		}
+	}
+	return symbols, scanner.Err()
+}
+
+// fetchQuotes cotiza los símbolos en paralelo y devuelve los resultados en el orden pedido; cada símbolo pasa
+// por la caché del ciclo y las políticas de getTickerData
+func fetchQuotes(symbols []string, client QuoteFetcher) []Quote {
+	return bolsa.FetchQuotesWith(symbols, func(symbol string) (Quote, error) {
+		price, previousClose, name, volume, err := getTickerData(symbol, client)
+		if err != nil {
+			return Quote{}, err
+		}
+		return bolsa.NewQuote(symbol, name, price, previousClose, volume), nil
+	})
+}
+
+// runQuote implementa `bolsa quote GGAL YPF` o `cat simbolos.txt | bolsa quote --stdin --format json`
+func runQuote(args []string) error {
+	fs := flag.NewFlagSet("quote", flag.ExitOnError)
+	fromStdin := fs.Bool("stdin", false, "leer los símbolos de la entrada estándar (uno o más por línea, # para comentarios)")
+	format := fs.String("format", "table", "formato de salida: table, json o csv")
+	fs.Parse(args)
+
+	if *format != "table" && *format != "json" && *format != "csv" {
+		return fmt.Errorf("formato desconocido %q (usar table, json o csv)", *format)
+	}
+
+	symbols, err := readSymbols(strings.NewReader(strings.Join(fs.Args(), "\n")))
+	if err != nil {
+		return err
+	}
+	if *fromStdin {
+		fromInput, err := readSymbols(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("error al leer la entrada estándar: %v", err)
+		}
+		symbols = append(symbols, fromInput...)
+	}
+	if len(symbols) == 0 {
+		return fmt.Errorf("uso: bolsa quote SIMBOLO... | bolsa quote --stdin [--format table|json|csv]")
+	}
+
+	// Los mensajes de progreso van a stderr para no mezclarse con la salida del pipeline
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	quotes := fetchQuotes(symbols, NewHTTPClient())
+	os.Stdout = stdout
+
+	failed := 0
+	for _, q := range quotes {
+		if q.Error != "" {
+			failed++
+			fmt.Fprintf(os.Stderr, "%s: %s\n", q.Symbol, q.Error)
+		}
+	}
+
+	switch *format {
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(quotes); err != nil {
+			return err
+		}
+	case "csv":
+		out := csv.NewWriter(os.Stdout)
+		out.Write([]string{"symbol", "name", "price", "previous_close", "change", "change_percent", "volume", "error"})
+		format := func(v float64) string { return strconv.FormatFloat(v, 'f', 4, 64) }
+		for _, q := range quotes {
+			out.Write([]string{q.Symbol, q.Name, format(q.Price), format(q.PreviousClose), format(q.Change),
+				format(q.ChangePercent), strconv.FormatInt(q.Volume, 10), q.Error})
+		}
+		out.Flush()
+		if err := out.Error(); err != nil {
+			return err
+		}
+	default:
+		for _, q := range quotes {
+			if q.Error != "" {
+				fmt.Printf("%-10s %s%s%s\n", q.Symbol, Red, "sin datos", Reset)
+				continue
+			}
+			fmt.Printf("%-10s %-30.30s %12.2f %s%+8.2f%%%s %12d\n", q.Symbol, q.Name, q.Price,
+				variationColor(q.ChangePercent), q.ChangePercent, Reset, q.Volume)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d de %d símbolos sin cotización", failed, len(quotes))
+	}
+	return nil
+}
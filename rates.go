@@ -0,0 +1,134 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"time"
+)
+
+// Plazo mínimo de un plazo fijo UVA en días
+const plazoFijoUVAMinDays = 90
+
+// seriesChange devuelve la variación en % de una serie del BCRA entre el último valor y el de days días antes
+func seriesChange(points []RatePoint, days int) (float64, bool) {
+	last := points[len(points)-1]
+	base := rateAt(points, last.Date.AddDate(0, 0, -days))
+	if base == 0 || points[0].Date.After(last.Date.AddDate(0, 0, -days)) {
+		return 0, false
+	}
+	return (last.Value/base - 1) * 100, true
+}
+
+// runRates implementa `bolsa rates`: tasa de plazo fijo, CER y UVA, y la comparación de plazo fijo
+// tradicional, UVA y dólar MEP para un monto y plazo dados
+func runRates(args []string) error {
+	fs := flag.NewFlagSet("rates", flag.ExitOnError)
+	amount := fs.Float64("amount", 1000000, "monto a invertir en pesos")
+	days := fs.Int("days", plazoFijoUVAMinDays, "plazo en días")
+	inflation := fs.Float64("inflation", 0, "inflación mensual esperada en % (por defecto la del último mes según UVA)")
+	uvaRate := fs.Float64("uva-rate", 1, "TNA adicional del plazo fijo UVA en %")
+	devaluation := fs.Float64("devaluation", 0, "devaluación mensual esperada del MEP en %")
+	fs.Parse(args)
+
+	if *days <= 0 {
+		return fmt.Errorf("el plazo debe ser positivo")
+	}
+
+	bcra := NewProviderClient("bcra")
+	to := time.Now()
+	from := to.AddDate(-1, 0, -10)
+
+	pf, err := getBCRASeries(bcraVariablePlazoFijo, to.AddDate(0, 0, -15), to, bcra)
+	if err != nil {
+		return fmt.Errorf("no se pudo obtener la tasa de plazo fijo del BCRA: %v", err)
+	}
+	cer, err := getBCRASeries(bcraVariableCER, from, to, bcra)
+	if err != nil {
+		return fmt.Errorf("no se pudo obtener el CER del BCRA: %v", err)
+	}
+	uva, err := getBCRASeries(bcraVariableUVA, from, to, bcra)
+	if err != nil {
+		return fmt.Errorf("no se pudo obtener la UVA del BCRA: %v", err)
+	}
+
+	tna := pf[len(pf)-1].Value
+	fmt.Printf("\n%s=== TASAS E INDEXACIÓN (BCRA) ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-22s %14s %10s %10s %10s\n", "Serie", "Valor", "30 días", "90 días", "1 año")
+	fmt.Printf("%-22s %13.2f%% %10s %10s %10s   (%s)\n", "Plazo fijo 30d (TNA)", tna, "", "", "", pf[len(pf)-1].Date.Format("02/01"))
+	for _, s := range []struct {
+		name   string
+		points []RatePoint
+	}{{"CER", cer}, {"UVA", uva}} {
+		last := s.points[len(s.points)-1]
+		fmt.Printf("%-22s %14.4f", s.name, last.Value)
+		for _, d := range []int{30, 90, 365} {
+			if change, ok := seriesChange(s.points, d); ok {
+				fmt.Printf(" %9.2f%%", change)
+			} else {
+				fmt.Printf(" %10s", "-")
+			}
+		}
+		fmt.Printf("   (%s)\n", last.Date.Format("02/01"))
+	}
+
+	// Sin inflación esperada se extrapola la variación de la UVA del último mes
+	monthly := *inflation
+	inflationNote := "esperada"
+	if monthly == 0 {
+		if change, ok := seriesChange(uva, 30); ok {
+			monthly = change
+			inflationNote = "último mes de la UVA"
+		}
+	}
+
+	mep, err := liveMEP(NewHTTPClient())
+	if err != nil {
+		fmt.Printf("%sNo se pudo obtener el dólar MEP: %v%s\n", Yellow, err, Reset)
+	}
+
+	months := float64(*days) / 30
+	traditional := *amount * (1 + tna/100*float64(*days)/365)
+	uvaFactor := math.Pow(1+monthly/100, months)
+	uvaFinal := *amount * uvaFactor * (1 + *uvaRate/100*float64(*days)/365)
+	dollarFinal := *amount * math.Pow(1+*devaluation/100, months)
+
+	fmt.Printf("\n%s=== $%.2f A %d DÍAS ===%s\n", Cyan, *amount, *days, Reset)
+	fmt.Printf("Inflación mensual: %.2f%% (%s); devaluación mensual del MEP: %.2f%%\n\n", monthly, inflationNote, *devaluation)
+
+	rows := []struct {
+		name  string
+		final float64
+		note  string
+	}{
+		{"Plazo fijo tradicional", traditional, fmt.Sprintf("TNA %.2f%%", tna)},
+		{"Plazo fijo UVA", uvaFinal, fmt.Sprintf("UVA + %.2f%% TNA", *uvaRate)},
+		{"Dólar MEP", dollarFinal, fmt.Sprintf("MEP %.2f", mep)},
+	}
+	best := rows[0]
+	fmt.Printf("%-24s %16s %10s %10s  %s\n", "Alternativa", "Final", "Retorno", "Real", "Detalle")
+	for _, row := range rows {
+		nominal := (row.final / *amount - 1) * 100
+		real := (row.final / *amount / uvaFactor - 1) * 100
+		color := Green
+		if real < 0 {
+			color = Red
+		}
+		fmt.Printf("%-24s %16.2f %9.2f%% %s%9.2f%%%s  %s\n", row.name, row.final, nominal, color, real, Reset, row.note)
+		if row.final > best.final {
+			best = row
+		}
+	}
+	if *days < plazoFijoUVAMinDays {
+		fmt.Printf("%s⚠️ El plazo fijo UVA exige un mínimo de %d días%s\n", Yellow, plazoFijoUVAMinDays, Reset)
+	}
+
+	// Inflación que iguala al plazo fijo tradicional con el UVA
+	breakeven := (math.Pow(traditional / *amount / (1+*uvaRate/100*float64(*days)/365), 1/months) - 1) * 100
+	fmt.Printf("\nInflación mensual de equilibrio tradicional vs. UVA: %.2f%%\n", breakeven)
+	if mep > 0 {
+		fmt.Printf("MEP de equilibrio al vencimiento del plazo fijo tradicional: %.2f\n", mep*traditional / *amount)
+	}
+	fmt.Printf("Mejor alternativa con estos supuestos: %s%s%s\n", Green, best.name, Reset)
+	return nil
+}
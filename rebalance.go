@@ -0,0 +1,310 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Clase de activo usada por las tenencias de bonos de bond_holdings.json
+const bondAssetClass = "bonos"
+
+// PortfolioHolding es una tenencia de acciones, CEDEARs u otros activos cotizados
+type PortfolioHolding struct {
+	Symbol   string  `json:"symbol"`
+	Quantity float64 `json:"quantity"`
+	Class    string  `json:"class"` // acciones, cedears...
+}
+
+// Portfolio es la cartera del usuario con sus pesos objetivo por clase (portfolio.json);
+// los bonos se toman de bond_holdings.json
+type Portfolio struct {
+	Targets    map[string]float64 `json:"targets"`    // Porcentaje objetivo por clase
+	Holdings   []PortfolioHolding `json:"holdings"`   // Tenencias que no son bonos
+	Commission float64            `json:"commission"` // Comisión por operación en %
+}
+
+// RebalanceOrder es una compra (Quantity > 0) o venta (Quantity < 0) sugerida
+type RebalanceOrder struct {
+	Symbol     string
+	Class      string
+	Price      float64 // En pesos, por unidad (por 100 VN en bonos)
+	Quantity   float64
+	Amount     float64 // Monto en pesos, sin comisión
+	Commission float64
+}
+
+// pricedHolding es una tenencia valuada en pesos
+type pricedHolding struct {
+	Symbol   string
+	Class    string
+	Quantity float64
+	Price    float64 // En pesos por unidad de cotización
+	Per      float64 // Unidades por cotización: 100 para bonos (precio cada 100 VN), 1 para el resto
+	Value    float64
+	Currency string // Moneda en que cotiza: ARS o USD
+}
+
+// loadPortfolio lee portfolio.json
+func loadPortfolio() (*Portfolio, error) {
+	path, err := appFile("portfolio.json")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no hay pesos objetivo definidos: crear %s con targets y holdings", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var portfolio Portfolio
+	if err := json.Unmarshal(data, &portfolio); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+
+	var total float64
+	for _, weight := range portfolio.Targets {
+		total += weight
+	}
+	if math.Abs(total-100) > 0.01 {
+		return nil, fmt.Errorf("los pesos objetivo de %s suman %.2f%%, deben sumar 100%%", path, total)
+	}
+	return &portfolio, nil
+}
+
+// liveMEP calcula el dólar MEP en vivo a partir de AL30 y AL30D
+func liveMEP(client QuoteFetcher) (float64, error) {
+	pesos, _, _, _, err := getTickerData(mepPesosSymbol, client)
+	if err != nil {
+		return 0, err
+	}
+	dollars, _, _, _, err := getTickerData(mepDollarSymbol, client)
+	if err != nil {
+		return 0, err
+	}
+	if dollars == 0 {
+		return 0, fmt.Errorf("cotización inválida de %s", mepDollarSymbol)
+	}
+	return pesos / dollars, nil
+}
+
+// pricePortfolio valúa en pesos las tenencias de la cartera y los bonos con precios en vivo
+func pricePortfolio(portfolio *Portfolio, client QuoteFetcher) ([]pricedHolding, error) {
+	mep, err := liveMEP(client)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo calcular el dólar MEP: %v", err)
+	}
+
+	var priced []pricedHolding
+	for _, holding := range portfolio.Holdings {
+		price, _, _, _, err := getTickerData(holding.Symbol, client)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo cotizar %s: %v", holding.Symbol, err)
+		}
+		// Los símbolos de BYMA cotizan en pesos; el resto en dólares
+		currency := "ARS"
+		if !strings.HasSuffix(holding.Symbol, ".BA") {
+			price *= mep
+			currency = "USD"
+		}
+		priced = append(priced, pricedHolding{
+			Symbol: holding.Symbol, Class: holding.Class, Quantity: holding.Quantity,
+			Price: price, Per: 1, Value: holding.Quantity * price, Currency: currency,
+		})
+	}
+
+	bonds, err := loadBonds()
+	if err != nil {
+		fmt.Printf("Error al cargar bonos personalizados: %v\n", err)
+	}
+	holdings, err := loadBondHoldings()
+	if err != nil {
+		return nil, err
+	}
+	for _, holding := range holdings {
+		bond := findBond(bonds, holding.Symbol)
+		if bond == nil {
+			return nil, fmt.Errorf("bono desconocido en las tenencias: %s", holding.Symbol)
+		}
+		price, _, _, _, err := getTickerData(bond.QuoteSymbol, client)
+		if err != nil {
+			return nil, fmt.Errorf("no se pudo cotizar %s: %v", bond.Symbol, err)
+		}
+		currency := "ARS"
+		if bond.Currency == "USD" {
+			price *= mep
+			currency = "USD"
+		}
+		priced = append(priced, pricedHolding{
+			Symbol: bond.Symbol, Class: bondAssetClass, Quantity: holding.Nominal,
+			Price: price, Per: 100, Value: holding.Nominal * price / 100, Currency: currency,
+		})
+	}
+	return priced, nil
+}
+
+// rebalanceOrders calcula las órdenes que llevan cada clase a su peso objetivo; dentro de una clase
+// la diferencia se reparte en proporción al valor de cada tenencia y las cantidades se redondean hacia cero
+func rebalanceOrders(holdings []pricedHolding, targets map[string]float64, commission float64) ([]RebalanceOrder, []string) {
+	var total float64
+	byClass := make(map[string][]pricedHolding)
+	classValue := make(map[string]float64)
+	for _, h := range holdings {
+		total += h.Value
+		byClass[h.Class] = append(byClass[h.Class], h)
+		classValue[h.Class] += h.Value
+	}
+
+	var orders []RebalanceOrder
+	var warnings []string
+	for class := range classValue {
+		if _, ok := targets[class]; !ok {
+			warnings = append(warnings, fmt.Sprintf("la clase %q no tiene peso objetivo: se vende por completo", class))
+		}
+	}
+
+	for class, weight := range targets {
+		diff := total*weight/100 - classValue[class]
+		members := byClass[class]
+		if len(members) == 0 {
+			if diff > 0 {
+				warnings = append(warnings, fmt.Sprintf("no hay activos de la clase %q para invertir $%.2f", class, diff))
+			}
+			continue
+		}
+
+		for _, h := range members {
+			share := 1 / float64(len(members))
+			if classValue[class] > 0 {
+				share = h.Value / classValue[class]
+			}
+			amount := diff * share
+
+			// Las compras descuentan la comisión del monto disponible
+			unitPrice := h.Price / h.Per
+			if amount > 0 {
+				unitPrice *= 1 + commission/100
+			}
+			quantity := math.Trunc(amount / unitPrice)
+			if quantity == 0 {
+				continue
+			}
+			gross := quantity * h.Price / h.Per
+			orders = append(orders, RebalanceOrder{
+				Symbol: h.Symbol, Class: class, Price: h.Price, Quantity: quantity,
+				Amount: gross, Commission: math.Abs(gross) * commission / 100,
+			})
+		}
+	}
+
+	for class := range classValue {
+		if _, ok := targets[class]; ok {
+			continue
+		}
+		for _, h := range byClass[class] {
+			gross := -h.Value
+			orders = append(orders, RebalanceOrder{
+				Symbol: h.Symbol, Class: class, Price: h.Price, Quantity: -h.Quantity,
+				Amount: gross, Commission: h.Value * commission / 100,
+			})
+		}
+	}
+
+	// Primero las ventas, que generan los pesos para las compras
+	sort.Slice(orders, func(i, j int) bool {
+		if (orders[i].Quantity < 0) != (orders[j].Quantity < 0) {
+			return orders[i].Quantity < 0
+		}
+		return orders[i].Symbol < orders[j].Symbol
+	})
+	sort.Strings(warnings)
+	return orders, warnings
+}
+
+// runRebalance implementa `bolsa rebalance`: órdenes sugeridas para volver a los pesos objetivo
+func runRebalance(args []string) error {
+	fs := flag.NewFlagSet("rebalance", flag.ExitOnError)
+	commission := fs.Float64("commission", -1, "comisión por operación en % (por defecto la de portfolio.json)")
+	minAmount := fs.Float64("min", 0, "omitir órdenes menores a este monto en pesos")
+	fs.Parse(args)
+
+	portfolio, err := loadPortfolio()
+	if err != nil {
+		return err
+	}
+	if *commission < 0 {
+		*commission = portfolio.Commission
+	}
+
+	client := NewHTTPClient()
+	holdings, err := pricePortfolio(portfolio, client)
+	if err != nil {
+		return err
+	}
+
+	var total float64
+	classValue := make(map[string]float64)
+	for _, h := range holdings {
+		total += h.Value
+		classValue[h.Class] += h.Value
+	}
+	if total == 0 {
+		return fmt.Errorf("la cartera no tiene valor para rebalancear")
+	}
+
+	var classes []string
+	for class := range portfolio.Targets {
+		classes = append(classes, class)
+	}
+	for class := range classValue {
+		if _, ok := portfolio.Targets[class]; !ok {
+			classes = append(classes, class)
+		}
+	}
+	sort.Strings(classes)
+
+	fmt.Printf("\n%s=== COMPOSICIÓN ACTUAL (total $%.2f) ===%s\n\n", Cyan, total, Reset)
+	fmt.Printf("%-12s %16s %9s %9s\n", "Clase", "Valor", "Actual", "Objetivo")
+	for _, class := range classes {
+		fmt.Printf("%-12s %16.2f %8.1f%% %8.1f%%\n", class, classValue[class], classValue[class]/total*100, portfolio.Targets[class])
+	}
+
+	orders, warnings := rebalanceOrders(holdings, portfolio.Targets, *commission)
+	for _, warning := range warnings {
+		fmt.Printf("%s⚠️ %s%s\n", Yellow, warning, Reset)
+	}
+
+	fmt.Printf("\n%s=== ÓRDENES SUGERIDAS (comisión %.2f%%) ===%s\n\n", Cyan, *commission, Reset)
+	var bought, sold, fees float64
+	count := 0
+	for _, o := range orders {
+		if math.Abs(o.Amount) < *minAmount {
+			continue
+		}
+		action, color := "COMPRAR", Green
+		if o.Quantity < 0 {
+			action, color = "VENDER", Red
+			sold += -o.Amount
+		} else {
+			bought += o.Amount
+		}
+		fees += o.Commission
+		count++
+		fmt.Printf("%s%-8s%s %-10s %-10s %12.0f a $%-12.2f $%14.2f (comisión $%.2f)\n",
+			color, action, Reset, o.Symbol, o.Class, math.Abs(o.Quantity), o.Price, math.Abs(o.Amount), o.Commission)
+	}
+	if count == 0 {
+		fmt.Println("La cartera ya está en sus pesos objetivo.")
+		return nil
+	}
+
+	fmt.Printf("\nVentas: $%.2f  Compras: $%.2f  Comisiones: $%.2f  Saldo: $%.2f\n", sold, bought, fees, sold-bought-fees)
+	return nil
+}
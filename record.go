@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// RecordedResponse son los metadatos de una respuesta HTTP guardada con --record
+type RecordedResponse struct {
+	Time       time.Time           `json:"time"`
+	Provider   string              `json:"provider"`
+	Method     string              `json:"method"`
+	URL        string              `json:"url"`
+	Attempt    int                 `json:"attempt"`
+	StatusCode int                 `json:"status_code"`
+	Header     map[string][]string `json:"header"`
+	Elapsed    string              `json:"elapsed"`
+	BodyFile   string              `json:"body_file"`
+	BodySize   int                 `json:"body_size"`
+}
+
+// responseRecorder guarda cada respuesta cruda en un directorio, numeradas en orden de llegada
+type responseRecorder struct {
+	mu  sync.Mutex
+	dir string
+	seq int
+}
+
+// Grabador activo; nil si no se pasó --record
+var recorder *responseRecorder
+
+// enableRecording activa el guardado de respuestas crudas en dir
+func enableRecording(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("no se pudo crear el directorio de grabación %s: %v", dir, err)
+	}
+	recorder = &responseRecorder{dir: dir}
+	fmt.Printf("Grabando respuestas HTTP crudas en %s\n", dir)
+	return nil
+}
+
+// record guarda el body y los metadatos de la respuesta, y repone el body para que el llamador lo lea
+func (r *responseRecorder) record(provider string, req *http.Request, resp *http.Response, attempt int, elapsed time.Duration) {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		fmt.Printf("No se pudo leer la respuesta para grabarla: %v\n", err)
+		return
+	}
+
+	r.mu.Lock()
+	r.seq++
+	base := fmt.Sprintf("%s-%05d-%s", time.Now().Format("20060102-150405"), r.seq, provider)
+	r.mu.Unlock()
+
+	meta := RecordedResponse{
+		Time:       time.Now(),
+		Provider:   provider,
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Attempt:    attempt,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Elapsed:    elapsed.String(),
+		BodyFile:   base + ".body",
+		BodySize:   len(body),
+	}
+
+	if err := os.WriteFile(filepath.Join(r.dir, meta.BodyFile), body, 0o644); err != nil {
+		fmt.Printf("No se pudo grabar la respuesta de %s: %v\n", meta.URL, err)
+		return
+	}
+	data, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(r.dir, base+".json"), data, 0o644); err != nil {
+		fmt.Printf("No se pudieron grabar los metadatos de %s: %v\n", meta.URL, err)
+	}
+}
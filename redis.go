@@ -0,0 +1,353 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tiempo máximo de conexión y de cada comando a Redis; la suscripción espera mensajes sin límite
+const redisTimeout = 5 * time.Second
+
+// Tamaño máximo de un bulk string, el mismo límite que impone Redis (proto-max-bulk-len): un largo absurdo
+// en la respuesta no debe reservar memoria antes de leer los datos
+const redisMaxBulk = 512 << 20
+
+// RedisConfig define un Redis compartido por varios procesos de bolsa (monitor, servidor, API): caché de
+// cotizaciones entre procesos y canal pub/sub con cada snapshot nuevo
+type RedisConfig struct {
+	Addr     string   `json:"addr"`     // host:puerto
+	Password string   `json:"password"` // Vacío si Redis no pide AUTH
+	DB       int      `json:"db"`
+	Prefix   string   `json:"prefix"`   // Prefijo de las claves y del canal; por defecto "bolsa"
+	QuoteTTL Duration `json:"quoteTTL"` // Vigencia de una cotización en la caché; por defecto el intervalo entre ciclos
+}
+
+// validate completa los valores por defecto
+func (c *RedisConfig) validate() error {
+	if c.Addr == "" {
+		return fmt.Errorf("falta \"addr\"")
+	}
+	if c.DB < 0 {
+		return fmt.Errorf("db debe ser positivo")
+	}
+	if c.QuoteTTL < 0 {
+		return fmt.Errorf("quoteTTL debe ser positivo")
+	}
+	if c.Prefix == "" {
+		c.Prefix = "bolsa"
+	}
+	return nil
+}
+
+// redisError es un error devuelto por Redis (respuesta "-ERR ..."); la conexión sigue siendo válida
+type redisError string
+
+func (e redisError) Error() string { return "redis: " + string(e) }
+
+// redisConn es una conexión con el protocolo RESP de Redis
+type redisConn struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// dialRedis abre una conexión autenticada y con la base seleccionada
+func dialRedis(cfg *RedisConfig) (*redisConn, error) {
+	conn, err := net.DialTimeout("tcp", cfg.Addr, redisTimeout)
+	if err != nil {
+		return nil, err
+	}
+	c := &redisConn{conn: conn, r: bufio.NewReader(conn)}
+	if cfg.Password != "" {
+		if _, err := c.do("AUTH", cfg.Password); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if cfg.DB > 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(cfg.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// send escribe un comando como array de bulk strings
+func (c *redisConn) send(args ...string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := io.WriteString(c.conn, b.String())
+	return err
+}
+
+// do envía un comando y lee su respuesta, con el timeout de Redis
+func (c *redisConn) do(args ...string) (any, error) {
+	c.conn.SetDeadline(time.Now().Add(redisTimeout))
+	defer c.conn.SetDeadline(time.Time{})
+	if err := c.send(args...); err != nil {
+		return nil, err
+	}
+	return c.read()
+}
+
+// read lee una respuesta: string, int64, []byte (nil si no existe la clave), []any o redisError
+func (c *redisConn) read() (any, error) {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: respuesta vacía")
+	}
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return []byte(nil), err
+		}
+		if n > redisMaxBulk {
+			return nil, fmt.Errorf("redis: bulk string de %d bytes excede el máximo", n)
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil || n < 0 {
+			return []any(nil), err
+		}
+		// Los elementos se agregan a medida que llegan: el largo anunciado no reserva memoria por adelantado
+		items := make([]any, 0, min(n, 1024))
+		for i := 0; i < n; i++ {
+			item, err := c.read()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+		}
+		return items, nil
+	}
+	return nil, fmt.Errorf("redis: respuesta desconocida %q", line)
+}
+
+// RedisClient comparte una conexión entre goroutines y la reabre si se corta
+type RedisClient struct {
+	cfg  *RedisConfig
+	mu   sync.Mutex
+	conn *redisConn
+}
+
+// Do ejecuta un comando; ante un error de red descarta la conexión para reabrirla en el próximo
+func (r *RedisClient) Do(args ...string) (any, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.conn == nil {
+		conn, err := dialRedis(r.cfg)
+		if err != nil {
+			return nil, err
+		}
+		r.conn = conn
+	}
+	reply, err := r.conn.do(args...)
+	if _, ok := err.(redisError); err != nil && !ok {
+		r.conn.conn.Close()
+		r.conn = nil
+	}
+	return reply, err
+}
+
+// key arma el nombre de una clave con el prefijo configurado
+func (r *RedisClient) key(parts ...string) string {
+	return r.cfg.Prefix + ":" + strings.Join(parts, ":")
+}
+
+var (
+	sharedRedisOnce sync.Once
+	sharedRedisConn *RedisClient
+)
+
+// sharedRedis devuelve el cliente de Redis de config.json, o nil si no está configurado
+func sharedRedis() *RedisClient {
+	sharedRedisOnce.Do(func() {
+		if cfg := appConfig().Redis; cfg != nil {
+			sharedRedisConn = &RedisClient{cfg: cfg}
+		}
+	})
+	return sharedRedisConn
+}
+
+// redisQuote es una cotización guardada en la caché compartida
+type redisQuote struct {
+	Price         float64   `json:"price"`
+	PreviousClose float64   `json:"previous_close"`
+	Name          string    `json:"name"`
+	Volume        int64     `json:"volume"`
+	Time          time.Time `json:"time"` // Hora de mercado de la cotización, si el proveedor la informó
+}
+
+// sharedQuote busca la cotización de un símbolo que otro proceso ya consultó
+func sharedQuote(symbol string) (tickerResult, bool) {
+	r := sharedRedis()
+	if r == nil {
+		return tickerResult{}, false
+	}
+	reply, err := r.Do("GET", r.key("quote", symbol))
+	data, _ := reply.([]byte)
+	if err != nil || data == nil {
+		if err != nil {
+			debugf("Redis: no se pudo leer %s: %v\n", symbol, err)
+		}
+		return tickerResult{}, false
+	}
+	var q redisQuote
+	if json.Unmarshal(data, &q) != nil {
+		return tickerResult{}, false
+	}
+	if !q.Time.IsZero() {
+		recordQuoteTime(symbol, q.Time)
+	}
+	return tickerResult{price: q.Price, previousClose: q.PreviousClose, name: q.Name, volume: q.Volume}, true
+}
+
+// storeSharedQuote deja la cotización en la caché para los demás procesos
+func storeSharedQuote(symbol string, result tickerResult) {
+	r := sharedRedis()
+	if r == nil {
+		return
+	}
+	ttl := time.Duration(r.cfg.QuoteTTL)
+	if ttl <= 0 {
+		ttl = time.Duration(appConfig().Interval)
+	}
+	q := redisQuote{Price: result.price, PreviousClose: result.previousClose, Name: result.name, Volume: result.volume}
+	quoteTimesMu.Lock()
+	q.Time = quoteTimes[symbol]
+	quoteTimesMu.Unlock()
+	data, _ := json.Marshal(q)
+	if _, err := r.Do("SET", r.key("quote", symbol), string(data), "PX", strconv.FormatInt(max(ttl.Milliseconds(), 1), 10)); err != nil {
+		debugf("Redis: no se pudo guardar %s: %v\n", symbol, err)
+	}
+}
+
+// broadcastRedis guarda el snapshot como el último y lo publica a los procesos suscriptos
+func broadcastRedis(snapshot *Snapshot) {
+	r := sharedRedis()
+	if r == nil {
+		return
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return
+	}
+	if _, err := r.Do("SET", r.key("snapshot", "last"), string(data)); err != nil {
+		fmt.Printf("No se pudo guardar el snapshot en Redis: %v\n", err)
+		return
+	}
+	if _, err := r.Do("PUBLISH", r.key("snapshots"), string(data)); err != nil {
+		fmt.Printf("No se pudo publicar el snapshot en Redis: %v\n", err)
+	}
+}
+
+// lastSharedSnapshot devuelve el último snapshot publicado en Redis, o nil si no hay
+func lastSharedSnapshot(r *RedisClient) (*Snapshot, error) {
+	reply, err := r.Do("GET", r.key("snapshot", "last"))
+	if err != nil {
+		return nil, err
+	}
+	data, _ := reply.([]byte)
+	if data == nil {
+		return nil, nil
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// subscribeSnapshots recibe los snapshots publicados hasta que la conexión se corta
+func subscribeSnapshots(r *RedisClient, handle func(*Snapshot)) error {
+	conn, err := dialRedis(r.cfg)
+	if err != nil {
+		return err
+	}
+	defer conn.conn.Close()
+	if _, err := conn.do("SUBSCRIBE", r.key("snapshots")); err != nil {
+		return err
+	}
+
+	for {
+		reply, err := conn.read()
+		if err != nil {
+			return err
+		}
+		// Cada mensaje llega como ["message", canal, contenido]
+		msg, _ := reply.([]any)
+		if len(msg) != 3 {
+			continue
+		}
+		kind, _ := msg[0].([]byte)
+		data, _ := msg[2].([]byte)
+		if string(kind) != "message" {
+			continue
+		}
+		var snapshot Snapshot
+		if err := json.Unmarshal(data, &snapshot); err != nil {
+			fmt.Printf("Snapshot inválido recibido de Redis: %v\n", err)
+			continue
+		}
+		handle(&snapshot)
+	}
+}
+
+// Follow hace que el ciclo no consulte proveedores: muestra los snapshots que otro proceso publica en Redis
+func (p *Pipeline) Follow() error {
+	if sharedRedis() == nil {
+		return fmt.Errorf("para seguir a otro proceso hay que configurar \"redis\" en config.json")
+	}
+	p.follow = true
+	return nil
+}
+
+// followRedis entrega a los handlers el último snapshot publicado y después cada uno nuevo, reconectando si se corta
+func (p *Pipeline) followRedis() {
+	r := sharedRedis()
+	deliver := func(snapshot *Snapshot) {
+		p.mu.Lock()
+		p.last = snapshot
+		p.mu.Unlock()
+		for _, handler := range p.handlers {
+			handler(snapshot)
+		}
+	}
+
+	if last, err := lastSharedSnapshot(r); err != nil {
+		fmt.Printf("No se pudo leer el último snapshot de Redis: %v\n", err)
+	} else if last != nil {
+		deliver(last)
+	}
+	for {
+		err := subscribeSnapshots(r, deliver)
+		fmt.Printf("%s⚠️ Conexión con Redis cortada: %v; reintentando en 5 segundos%s\n", Yellow, err, Reset)
+		time.Sleep(5 * time.Second)
+	}
+}
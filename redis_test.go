@@ -0,0 +1,99 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// respReader arma una conexión que solo lee la respuesta dada
+func respReader(response string) *redisConn {
+	return &redisConn{r: bufio.NewReader(strings.NewReader(response))}
+}
+
+func TestRedisRead(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     any
+	}{
+		{"simple string", "+OK\r\n", "OK"},
+		{"entero", ":1000\r\n", int64(1000)},
+		{"entero negativo", ":-3\r\n", int64(-3)},
+		{"bulk string", "$5\r\nhola!\r\n", []byte("hola!")},
+		{"bulk string con CRLF adentro", "$4\r\na\r\nb\r\n", []byte("a\r\nb")},
+		{"bulk string vacío", "$0\r\n\r\n", []byte{}},
+		{"clave inexistente", "$-1\r\n", []byte(nil)},
+		{"array", "*3\r\n$7\r\nmessage\r\n$5\r\nbolsa\r\n:7\r\n", []any{[]byte("message"), []byte("bolsa"), int64(7)}},
+		{"array anidado", "*2\r\n*1\r\n+a\r\n*0\r\n", []any{[]any{"a"}, []any{}}},
+		{"array nulo", "*-1\r\n", []any(nil)},
+	}
+	for _, tt := range tests {
+		got, err := respReader(tt.response).read()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: read = %#v, se esperaba %#v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestRedisReadErrors(t *testing.T) {
+	var redisErr redisError
+	if _, err := respReader("-WRONGPASS invalid password\r\n").read(); !errors.As(err, &redisErr) || string(redisErr) != "WRONGPASS invalid password" {
+		t.Errorf("error de Redis = %v, se esperaba redisError", err)
+	}
+
+	tests := []struct {
+		name     string
+		response string
+	}{
+		{"respuesta vacía", "\r\n"},
+		{"tipo desconocido", "?raro\r\n"},
+		{"entero inválido", ":abc\r\n"},
+		{"bulk cortado", "$10\r\ncorto\r\n"},
+		{"array cortado", "*2\r\n+uno\r\n"},
+		{"bulk gigante", "$99999999999\r\n"},
+		{"array gigante", "*99999999999\r\n"},
+		{"sin fin de línea", "+OK"},
+	}
+	for _, tt := range tests {
+		if got, err := respReader(tt.response).read(); err == nil {
+			t.Errorf("%s: read = %#v, se esperaba un error", tt.name, got)
+		}
+	}
+}
+
+func TestRedisSend(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	c := &redisConn{conn: client}
+
+	go func() {
+		c.send("SET", "bolsa:quote:GGAL.BA", "con espacios y \r\n")
+		client.Close()
+	}()
+	got, err := io.ReadAll(server)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "*3\r\n$3\r\nSET\r\n$19\r\nbolsa:quote:GGAL.BA\r\n$17\r\ncon espacios y \r\n\r\n"
+	if string(got) != want {
+		t.Errorf("send escribió %q, se esperaba %q", got, want)
+	}
+
+	// Lo que escribe send se lee igual con read
+	items, err := respReader(want).read()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(items, []any{[]byte("SET"), []byte("bolsa:quote:GGAL.BA"), []byte("con espacios y \r\n")}) {
+		t.Errorf("ida y vuelta = %#v", items)
+	}
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Directorio con un archivo JSON Lines de snapshots por rueda (AAAA-MM-DD.jsonl, fecha de Buenos Aires)
+const historyDir = "history"
+
+var (
+	historyMu   sync.Mutex
+	historyLast time.Time
+)
+
+// historyPath devuelve el archivo de snapshots de un día
+func historyPath(day string) (string, error) {
+	dir, err := appFile(historyDir)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, day+".jsonl"), nil
+}
+
+// recordHistory guarda el snapshot en el historial intradiario, como mucho uno cada HistoryInterval
+func recordHistory(snapshot *Snapshot) {
+	interval := time.Duration(appConfig().HistoryInterval)
+	if interval <= 0 {
+		return
+	}
+
+	historyMu.Lock()
+	defer historyMu.Unlock()
+	if snapshot.Time.Sub(historyLast) < interval {
+		return
+	}
+
+	path, err := historyPath(snapshot.Time.In(argentinaLocation).Format("2006-01-02"))
+	if err != nil {
+		fmt.Printf("No se pudo guardar el historial intradiario: %v\n", err)
+		return
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		fmt.Printf("No se pudo guardar el historial intradiario: %v\n", err)
+		return
+	}
+	defer file.Close()
+
+	// La capitalización solo se usa en el mapa de calor en vivo y ocupa mucho por snapshot
+	stored := *snapshot
+	stored.MarketCaps = nil
+	if err := json.NewEncoder(file).Encode(stored); err != nil {
+		fmt.Printf("No se pudo guardar el historial intradiario: %v\n", err)
+		return
+	}
+	historyLast = snapshot.Time
+}
+
+// loadHistory lee los snapshots guardados de un día en orden cronológico
+func loadHistory(day string) ([]Snapshot, error) {
+	path, err := historyPath(day)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("no hay datos intradiarios guardados del %s (bolsa replay sin fecha lista los días disponibles)", day)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snapshots []Snapshot
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		var snapshot Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			fmt.Printf("Línea %d de %s inválida: %v\n", line, path, err)
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool { return snapshots[i].Time.Before(snapshots[j].Time) })
+	return snapshots, nil
+}
+
+// historyDays devuelve los días con datos intradiarios guardados
+func historyDays() ([]string, error) {
+	dir, err := appFile(historyDir)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+
+	var days []string
+	for _, match := range matches {
+		days = append(days, strings.TrimSuffix(filepath.Base(match), ".jsonl"))
+	}
+	sort.Strings(days)
+	return days, nil
+}
+
+// parseSpeed interpreta la velocidad de reproducción ("10x" o "10")
+func parseSpeed(s string) (float64, error) {
+	speed, err := strconv.ParseFloat(strings.TrimSuffix(strings.ToLower(s), "x"), 64)
+	if err != nil || speed <= 0 {
+		return 0, fmt.Errorf("velocidad inválida %q (usar por ejemplo 10x)", s)
+	}
+	return speed, nil
+}
+
+// runReplay implementa `bolsa replay 2024-11-15 --speed 10x`: reproduce una rueda guardada en la misma pantalla del monitor
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	speedStr := fs.String("speed", "10x", "velocidad de reproducción respecto del tiempo real")
+	from := fs.String("from", "", "empezar desde esta hora (HH:MM, hora de Buenos Aires)")
+	viewName := fs.String("view", "all", "vista a mostrar: all, forex, stocks, bonds, funds o heatmap")
+
+	// La fecha va antes de las opciones: bolsa replay 2024-11-15 --speed 10x
+	day := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		day, args = args[0], args[1:]
+	}
+	fs.Parse(args)
+	if day == "" && fs.NArg() > 0 {
+		day = fs.Arg(0)
+	}
+
+	if day == "" {
+		days, err := historyDays()
+		if err != nil {
+			return err
+		}
+		if len(days) == 0 {
+			fmt.Println("Todavía no hay ruedas guardadas: el monitor y `bolsa serve` guardan un snapshot por minuto (historyInterval).")
+			return nil
+		}
+		fmt.Println("Ruedas disponibles para bolsa replay:")
+		for _, d := range days {
+			fmt.Printf("  %s\n", d)
+		}
+		return nil
+	}
+
+	if _, err := time.Parse("2006-01-02", day); err != nil {
+		return fmt.Errorf("fecha inválida %q (usar AAAA-MM-DD)", day)
+	}
+	speed, err := parseSpeed(*speedStr)
+	if err != nil {
+		return err
+	}
+	view, err := parseView(*viewName)
+	if err != nil {
+		return err
+	}
+
+	snapshots, err := loadHistory(day)
+	if err != nil {
+		return err
+	}
+	if *from != "" {
+		start, err := time.ParseInLocation("2006-01-02 15:04", day+" "+*from, argentinaLocation)
+		if err != nil {
+			return fmt.Errorf("hora inválida %q (usar HH:MM)", *from)
+		}
+		for len(snapshots) > 0 && snapshots[0].Time.Before(start) {
+			snapshots = snapshots[1:]
+		}
+	}
+	if len(snapshots) == 0 {
+		return fmt.Errorf("no hay snapshots guardados del %s en ese horario", day)
+	}
+
+	go watchResize(redrawScreen)
+	for i := range snapshots {
+		snapshot := &snapshots[i]
+		if i > 0 {
+			time.Sleep(time.Duration(float64(snapshot.Time.Sub(snapshots[i-1].Time)) / speed))
+		}
+		setSessionRanges(snapshot.Ranges)
+		displayData(snapshot, view)
+		fmt.Printf("%sREPLAY %s a %gx — %s (%d/%d)%s\n", Magenta, day, speed,
+			snapshot.Time.In(argentinaLocation).Format("15:04:05"), i+1, len(snapshots), Reset)
+	}
+
+	fmt.Printf("%sFin de la rueda del %s%s\n", Green, day, Reset)
+	return nil
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Niveles de riesgo que se muestran en la tabla
+const (
+	RiskLow    = "bajo"
+	RiskMedium = "medio"
+	RiskHigh   = "alto"
+)
+
+// El score se recalcula una vez por día; por ciclo se refrescan pocos símbolos para no demorar el primero
+const riskRefreshPerCycle = 5
+
+// RiskProfile es el score de riesgo de un activo (0 a 100) y sus componentes, calculados sobre el último año
+type RiskProfile struct {
+	Score      float64 `json:"score"`
+	Level      string  `json:"level"`
+	Volatility float64 `json:"volatility"`  // Anualizada, en %
+	MaxDD      float64 `json:"maxDrawdown"` // Peor caída del año, en % (negativo)
+	Liquidity  float64 `json:"liquidity"`   // Monto promedio operado por día en las últimas 20 ruedas, en dólares
+}
+
+// Pesos de cada componente en el score y valores a partir de los cuales el componente suma el máximo
+const (
+	riskVolWeight       = 40
+	riskDrawdownWeight  = 30
+	riskLiquidityWeight = 30
+
+	riskVolCeiling      = 80.0      // Volatilidad anual (%)
+	riskDrawdownCeiling = 70.0      // Caída desde el máximo (%)
+	riskLiquidHigh      = 5_000_000 // Monto diario (USD) desde el que el papel se considera líquido
+	riskLiquidLow       = 50_000    // Monto diario (USD) por debajo del cual es ilíquido
+)
+
+// computeRisk combina volatilidad, drawdown e iliquidez en un score; usdRate convierte a dólares los montos en pesos (0 si ya están en dólares)
+func computeRisk(points []HistoryPoint, usdRate float64) (RiskProfile, bool) {
+	if len(points) < 20 {
+		return RiskProfile{}, false
+	}
+
+	closes := make([]float64, len(points))
+	for i, p := range points {
+		closes[i] = p.Close
+	}
+	vol, ok := annualizedVolatility(closes)
+	if !ok {
+		return RiskProfile{}, false
+	}
+	dd, _ := computeDrawdown("", points)
+
+	var traded float64
+	recent := points[len(points)-20:]
+	for _, p := range recent {
+		traded += p.Close * float64(p.Volume)
+	}
+	traded /= float64(len(recent))
+	if usdRate > 0 {
+		traded /= usdRate
+	}
+
+	// La iliquidez se mide en escala logarítmica entre los dos umbrales
+	illiquidity := 1.0
+	if traded > 0 {
+		illiquidity = (math.Log(riskLiquidHigh) - math.Log(traded)) / (math.Log(riskLiquidHigh) - math.Log(riskLiquidLow))
+	}
+
+	score := riskVolWeight*clamp01(vol/riskVolCeiling) +
+		riskDrawdownWeight*clamp01(-dd.MaxDD/riskDrawdownCeiling) +
+		riskLiquidityWeight*clamp01(illiquidity)
+
+	return RiskProfile{Score: score, Level: riskLevel(score), Volatility: vol, MaxDD: dd.MaxDD, Liquidity: traded}, true
+}
+
+// clamp01 limita un valor al intervalo [0, 1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// riskLevel traduce el score a la etiqueta de la tabla
+func riskLevel(score float64) string {
+	switch {
+	case score < 40:
+		return RiskLow
+	case score < 65:
+		return RiskMedium
+	}
+	return RiskHigh
+}
+
+// riskColor devuelve el color de la etiqueta de riesgo
+func riskColor(level string) string {
+	switch level {
+	case RiskLow:
+		return Green
+	case RiskMedium:
+		return Yellow
+	}
+	return Red
+}
+
+// riskEntry es un score cacheado; un error se guarda vacío para no reintentar hasta el día siguiente
+type riskEntry struct {
+	Profile RiskProfile
+	OK      bool
+	Day     string
+}
+
+var (
+	riskMu    sync.Mutex
+	riskCache = make(map[string]riskEntry)
+)
+
+// riskProfileFor calcula el score de un símbolo a partir de su histórico de un año
+func riskProfileFor(symbol string, dolarRate float64, client QuoteFetcher) (RiskProfile, bool, error) {
+	points, err := getHistory(symbol, "1y", "1d", client)
+	if err != nil {
+		return RiskProfile{}, false, err
+	}
+
+	// Los papeles de BYMA operan en pesos; los de NYSE ya están en dólares
+	usdRate := 0.0
+	if strings.HasSuffix(symbol, ".BA") {
+		if dolarRate <= 0 {
+			return RiskProfile{}, false, fmt.Errorf("sin tipo de cambio para medir la liquidez")
+		}
+		usdRate = dolarRate
+	}
+	profile, ok := computeRisk(points, usdRate)
+	return profile, ok, nil
+}
+
+// getRiskProfiles devuelve el score de riesgo de cada símbolo, recalculando como mucho unos pocos por ciclo
+func getRiskProfiles(symbols []string, dolarRate float64, client QuoteFetcher, errs *fetchErrors) map[string]RiskProfile {
+	today := time.Now().Format("2006-01-02")
+
+	var stale []string
+	riskMu.Lock()
+	for _, symbol := range symbols {
+		if entry, ok := riskCache[symbol]; !ok || entry.Day != today {
+			stale = append(stale, symbol)
+		}
+	}
+	riskMu.Unlock()
+
+	if len(stale) > riskRefreshPerCycle {
+		stale = stale[:riskRefreshPerCycle]
+	}
+	// El score es accesorio: con el presupuesto en reserva se posterga hasta el día siguiente
+	if budgetTight("yahoo") {
+		stale = nil
+	}
+	for _, symbol := range stale {
+		profile, ok, err := riskProfileFor(symbol, dolarRate, client)
+		if err != nil {
+			errs.add("Riesgo", symbol, err)
+		}
+		riskMu.Lock()
+		riskCache[symbol] = riskEntry{Profile: profile, OK: ok, Day: today}
+		riskMu.Unlock()
+	}
+
+	profiles := make(map[string]RiskProfile)
+	riskMu.Lock()
+	defer riskMu.Unlock()
+	for _, symbol := range symbols {
+		if entry, ok := riskCache[symbol]; ok && entry.OK {
+			profiles[symbol] = entry.Profile
+		}
+	}
+	return profiles
+}
+
+// Scores del último snapshot mostrado, para la columna de riesgo de la tabla
+var (
+	shownRisk   map[string]RiskProfile
+	shownRiskMu sync.Mutex
+)
+
+// setRiskProfiles reemplaza los scores que muestra la tabla (los del snapshot recibido)
+func setRiskProfiles(profiles map[string]RiskProfile) {
+	shownRiskMu.Lock()
+	defer shownRiskMu.Unlock()
+	shownRisk = profiles
+}
+
+// getRiskProfile devuelve el score mostrado para un símbolo
+func getRiskProfile(symbol string) (RiskProfile, bool) {
+	shownRiskMu.Lock()
+	defer shownRiskMu.Unlock()
+	profile, ok := shownRisk[symbol]
+	return profile, ok
+}
+
+// RiskConcentration resume cuánto de la cartera está en cada nivel de riesgo
+type RiskConcentration struct {
+	Total   float64
+	ByLevel map[string]float64 // Valor en pesos por nivel
+	Unrated float64            // Valor de las tenencias sin score
+}
+
+// HighShare devuelve el porcentaje de la cartera en activos de riesgo alto
+func (c RiskConcentration) HighShare() float64 {
+	if c.Total == 0 {
+		return 0
+	}
+	return c.ByLevel[RiskHigh] / c.Total * 100
+}
+
+// riskConcentration agrupa el valor de las tenencias por nivel de riesgo
+func riskConcentration(holdings []pricedHolding, profiles map[string]RiskProfile) RiskConcentration {
+	c := RiskConcentration{ByLevel: make(map[string]float64)}
+	for _, h := range holdings {
+		c.Total += h.Value
+		if profile, ok := profiles[h.Symbol]; ok {
+			c.ByLevel[profile.Level] += h.Value
+		} else {
+			c.Unrated += h.Value
+		}
+	}
+	return c
+}
+
+// runRisk implementa `bolsa risk [SIMBOLOS...]`: score de riesgo por activo y concentración de la cartera
+func runRisk(args []string) error {
+	fs := flag.NewFlagSet("risk", flag.ExitOnError)
+	maxHigh := fs.Float64("max-high", appConfig().RiskMaxHigh, "advertir si los activos de riesgo alto superan este porcentaje de la cartera")
+	fs.Parse(args)
+
+	client := NewHTTPClient()
+	dolarRate, _, _, _, err := getTickerData("ARS=X", client)
+	if err != nil {
+		fmt.Printf("%sNo se pudo obtener el dólar oficial, los papeles de BYMA quedan sin score: %v%s\n", Yellow, err, Reset)
+	}
+
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		for _, stock := range watchlistSnapshot() {
+			symbols = append(symbols, stock.Symbol)
+		}
+	}
+
+	// Las tenencias de la cartera se evalúan por su símbolo de cotización (los bonos por el de Yahoo)
+	portfolio, portfolioErr := loadPortfolio()
+	var holdings []pricedHolding
+	quoteSymbols := make(map[string]string)
+	if portfolioErr == nil && len(fs.Args()) == 0 {
+		holdings, err = pricePortfolio(portfolio, client)
+		if err != nil {
+			return err
+		}
+		bonds, _ := loadBonds()
+		for _, h := range holdings {
+			quoteSymbols[h.Symbol] = h.Symbol
+			if bond := findBond(bonds, h.Symbol); h.Class == bondAssetClass && bond != nil {
+				quoteSymbols[h.Symbol] = bond.QuoteSymbol
+			}
+		}
+	}
+
+	profiles := make(map[string]RiskProfile)
+	evaluate := func(symbol, quoteSymbol string) {
+		if _, done := profiles[symbol]; done {
+			return
+		}
+		profile, ok, err := riskProfileFor(quoteSymbol, dolarRate, client)
+		if err != nil {
+			fmt.Printf("%s%s: %v%s\n", Red, symbol, err, Reset)
+			return
+		}
+		if ok {
+			profiles[symbol] = profile
+		}
+	}
+	for _, symbol := range symbols {
+		symbol = strings.ToUpper(symbol)
+		evaluate(symbol, symbol)
+	}
+	for symbol, quoteSymbol := range quoteSymbols {
+		evaluate(symbol, quoteSymbol)
+	}
+	if len(profiles) == 0 {
+		return fmt.Errorf("no se pudo calcular el riesgo de ningún activo")
+	}
+
+	var names []string
+	for symbol := range profiles {
+		names = append(names, symbol)
+	}
+	sort.Slice(names, func(i, j int) bool { return profiles[names[i]].Score > profiles[names[j]].Score })
+
+	fmt.Printf("\n%s=== RIESGO POR ACTIVO (último año) ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-10s %6s  %-6s %11s %10s %14s\n", "Símbolo", "Score", "Nivel", "Volatilidad", "DD máximo", "Operado/día")
+	for _, symbol := range names {
+		p := profiles[symbol]
+		fmt.Printf("%-10s %6.0f  %s%-6s%s %10.1f%% %9.1f%% %14s\n",
+			symbol, p.Score, riskColor(p.Level), p.Level, Reset, p.Volatility, p.MaxDD, "US$ "+formatCompact(p.Liquidity))
+	}
+
+	if len(holdings) == 0 {
+		return nil
+	}
+
+	c := riskConcentration(holdings, profiles)
+	if c.Total == 0 {
+		return nil
+	}
+	fmt.Printf("\n%s=== CONCENTRACIÓN DE RIESGO DE LA CARTERA ===%s\n\n", Cyan, Reset)
+	for _, level := range []string{RiskLow, RiskMedium, RiskHigh} {
+		fmt.Printf("  %s%-6s%s %6.1f%%  $%.2f\n", riskColor(level), level, Reset, c.ByLevel[level]/c.Total*100, c.ByLevel[level])
+	}
+	if c.Unrated > 0 {
+		fmt.Printf("  %-6s %6.1f%%  $%.2f\n", "s/d", c.Unrated/c.Total*100, c.Unrated)
+	}
+	if *maxHigh > 0 && c.HighShare() > *maxHigh {
+		fmt.Printf("\n%s⚠️ El %.1f%% de la cartera está en activos de riesgo alto (límite %.0f%%)%s\n", Red, c.HighShare(), *maxHigh, Reset)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Severidades de las alertas, de menor a mayor
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// NotifyConfig agrupa los canales extra y la matriz de ruteo de alertas
+type NotifyConfig struct {
+	TelegramToken    string       `json:"telegramToken"`    // TELEGRAM_BOT_TOKEN tiene prioridad
+	TelegramChats    []string     `json:"telegramChats"`    // Chats adicionales a TELEGRAM_CHAT_ID
+	TelegramCommands bool         `json:"telegramCommands"` // Responder consultas (/precio, /dolar, /cartera, /alerta) de esos chats
+	Email            *EmailConfig `json:"email"`
+	Routes           []Route      `json:"routes"`
+}
+
+// Route envía las alertas que coinciden a un conjunto de canales; gana la primera ruta que coincide
+type Route struct {
+	Rule     string   `json:"rule"`     // Nombre de la regla ("gap_apertura", "*" o vacío para todas)
+	Severity string   `json:"severity"` // Severidad ("" para todas)
+	Channels []string `json:"channels"` // console, log, telegram, email
+}
+
+// validSeverity indica si la severidad existe ("" se toma como warning)
+func validSeverity(severity string) bool {
+	switch severity {
+	case "", SeverityInfo, SeverityWarning, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+// matches indica si la ruta aplica a la alerta
+func (r Route) matches(alert Alert) bool {
+	if r.Rule != "" && r.Rule != "*" && r.Rule != alert.Rule {
+		return false
+	}
+	return r.Severity == "" || r.Severity == alert.severity()
+}
+
+// severity devuelve la severidad de la alerta, warning si no tiene
+func (a Alert) severity() string {
+	if a.Severity == "" {
+		return SeverityWarning
+	}
+	return a.Severity
+}
+
+// validateRoutes revisa que las rutas usen severidades y canales conocidos
+func validateRoutes(routes []Route) error {
+	known := map[string]bool{"console": true, "log": true, "telegram": true, "email": true}
+	for i, route := range routes {
+		if !validSeverity(route.Severity) {
+			return fmt.Errorf("ruta %d: severidad desconocida %q (info, warning o critical)", i+1, route.Severity)
+		}
+		for _, channel := range route.Channels {
+			if !known[channel] {
+				return fmt.Errorf("ruta %d: canal desconocido %q (console, log, telegram o email)", i+1, channel)
+			}
+		}
+	}
+	return nil
+}
+
+// routeAlerts agrupa las alertas según los canales que les corresponden; sin rutas van a todos los canales
+func routeAlerts(notifiers []Notifier, alerts []Alert) map[string][]Alert {
+	routes := appConfig().Notify.Routes
+	groups := make(map[string][]Alert)
+
+	for _, alert := range alerts {
+		var channels []string
+		matched := false
+		for _, route := range routes {
+			if route.matches(alert) {
+				channels, matched = route.Channels, true
+				break
+			}
+		}
+		if !matched {
+			for _, notifier := range notifiers {
+				channels = append(channels, notifier.Name())
+			}
+		}
+
+		sorted := append([]string(nil), channels...)
+		sort.Strings(sorted)
+		key := strings.Join(sorted, ",")
+		groups[key] = append(groups[key], alert)
+	}
+	return groups
+}
+
+// notifiersFor filtra los canales configurados por nombre
+func notifiersFor(notifiers []Notifier, channels string) []Notifier {
+	wanted := make(map[string]bool)
+	for _, channel := range strings.Split(channels, ",") {
+		wanted[channel] = true
+	}
+
+	var selected []Notifier
+	for _, notifier := range notifiers {
+		if wanted[notifier.Name()] {
+			selected = append(selected, notifier)
+		}
+	}
+	return selected
+}
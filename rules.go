@@ -0,0 +1,258 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Condition es una expresión del DSL de alertas, por ejemplo "changePercent < -3 AND volume > 1000000"
+type Condition interface {
+	Eval(fields map[string]float64) (bool, error)
+	String() string
+}
+
+// comparison compara un campo contra un valor numérico
+type comparison struct {
+	Field string
+	Op    string
+	Value float64
+}
+
+// logical combina dos condiciones con AND u OR
+type logical struct {
+	Op          string
+	Left, Right Condition
+}
+
+// Campos disponibles en las condiciones, en minúsculas para compararlos sin distinguir mayúsculas
+var conditionFields = map[string]string{
+	"price":         "price",
+	"previousclose": "previousClose",
+	"change":        "change",
+	"changepercent": "changePercent",
+	"volume":        "volume",
+}
+
+// Eval evalúa la comparación con los valores de un símbolo
+func (c comparison) Eval(fields map[string]float64) (bool, error) {
+	value, ok := fields[c.Field]
+	if !ok {
+		return false, fmt.Errorf("campo %s no disponible", c.Field)
+	}
+
+	switch c.Op {
+	case "<":
+		return value < c.Value, nil
+	case "<=":
+		return value <= c.Value, nil
+	case ">":
+		return value > c.Value, nil
+	case ">=":
+		return value >= c.Value, nil
+	case "==":
+		return value == c.Value, nil
+	case "!=":
+		return value != c.Value, nil
+	}
+	return false, fmt.Errorf("operador desconocido %s", c.Op)
+}
+
+func (c comparison) String() string {
+	return fmt.Sprintf("%s %s %s", c.Field, c.Op, strconv.FormatFloat(c.Value, 'f', -1, 64))
+}
+
+// Eval evalúa la combinación lógica
+func (l logical) Eval(fields map[string]float64) (bool, error) {
+	left, err := l.Left.Eval(fields)
+	if err != nil {
+		return false, err
+	}
+	// Evaluación en cortocircuito
+	if l.Op == "AND" && !left {
+		return false, nil
+	}
+	if l.Op == "OR" && left {
+		return true, nil
+	}
+	return l.Right.Eval(fields)
+}
+
+func (l logical) String() string {
+	return fmt.Sprintf("(%s %s %s)", l.Left, l.Op, l.Right)
+}
+
+// tokenize separa una condición en identificadores, operadores, números y paréntesis
+func tokenize(input string) ([]string, error) {
+	var tokens []string
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(' || r == ')':
+			tokens = append(tokens, string(r))
+			i++
+		case strings.ContainsRune("<>=!", r):
+			op := string(r)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+			switch op {
+			case "=":
+				op = "=="
+			case "!":
+				return nil, fmt.Errorf("operador inválido '!' en la posición %d", i)
+			}
+			tokens = append(tokens, op)
+		case r == '-' || r == '+' || r == '.' || unicode.IsDigit(r):
+			start := i
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			return nil, fmt.Errorf("carácter inesperado %q en la posición %d", r, i+1)
+		}
+	}
+	return tokens, nil
+}
+
+// conditionParser es un parser descendente recursivo para el DSL de alertas
+type conditionParser struct {
+	tokens []string
+	pos    int
+}
+
+// parseCondition interpreta una condición del DSL de alertas
+func parseCondition(input string) (Condition, error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("condición vacía")
+	}
+
+	p := &conditionParser{tokens: tokens}
+	cond, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("texto sobrante en la condición: %q", strings.Join(p.tokens[p.pos:], " "))
+	}
+	return cond, nil
+}
+
+func (p *conditionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *conditionParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *conditionParser) parseOr() (Condition, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "OR") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{Op: "OR", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseAnd() (Condition, error) {
+	left, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	for strings.EqualFold(p.peek(), "AND") {
+		p.next()
+		right, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		left = logical{Op: "AND", Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *conditionParser) parseTerm() (Condition, error) {
+	if p.peek() == "(" {
+		p.next()
+		cond, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, fmt.Errorf("falta cerrar un paréntesis")
+		}
+		return cond, nil
+	}
+
+	name := p.next()
+	field, ok := conditionFields[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("campo desconocido %q (campos válidos: price, previousClose, change, changePercent, volume)", name)
+	}
+
+	op := p.next()
+	switch op {
+	case "<", "<=", ">", ">=", "==", "!=":
+	default:
+		return nil, fmt.Errorf("se esperaba un operador de comparación después de %s, se encontró %q", name, op)
+	}
+
+	raw := p.next()
+	value, err := strconv.ParseFloat(strings.ReplaceAll(raw, "_", ""), 64)
+	if err != nil {
+		return nil, fmt.Errorf("valor numérico inválido %q", raw)
+	}
+
+	return comparison{Field: field, Op: op, Value: value}, nil
+}
+
+// stockFields expone los valores de una acción para evaluar condiciones
+func stockFields(stock StockInfo) map[string]float64 {
+	return map[string]float64{
+		"price":         stock.Price,
+		"previousClose": stock.PreviousClose,
+		"change":        stock.Change,
+		"changePercent": stock.ChangePercent,
+		"volume":        float64(stock.Volume),
+	}
+}
+
+// forexFields expone los valores de un tipo de cambio para evaluar condiciones
+func forexFields(forex ForexInfo) map[string]float64 {
+	return map[string]float64{
+		"price":         forex.Price,
+		"previousClose": forex.PreviousClose,
+		"change":        forex.Change,
+		"changePercent": forex.ChangePercent,
+	}
+}
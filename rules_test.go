@@ -0,0 +1,107 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCondition(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"price > 100", "price > 100"},
+		{"changepercent<=-3", "changePercent <= -3"},
+		{"PRICE = 10.5", "price == 10.5"},
+		{"volume >= 1_000_000", "volume >= 1000000"},
+		{"price != .5", "price != 0.5"},
+		// AND liga más fuerte que OR y ambos asocian a la izquierda
+		{"price > 1 OR change < 0 AND volume > 5", "(price > 1 OR (change < 0 AND volume > 5))"},
+		{"price > 1 and change < 0 and volume > 5", "((price > 1 AND change < 0) AND volume > 5)"},
+		{"(price > 1 OR change < 0) AND volume > 5", "((price > 1 OR change < 0) AND volume > 5)"},
+	}
+	for _, tt := range tests {
+		cond, err := parseCondition(tt.input)
+		if err != nil {
+			t.Errorf("parseCondition(%q): %v", tt.input, err)
+			continue
+		}
+		if got := cond.String(); got != tt.want {
+			t.Errorf("parseCondition(%q) = %s, se esperaba %s", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseConditionErrors(t *testing.T) {
+	tests := []struct {
+		input   string
+		wantErr string
+	}{
+		{"", "condición vacía"},
+		{"   ", "condición vacía"},
+		{"precio > 10", "campo desconocido"},
+		{"price 10", "se esperaba un operador"},
+		{"price >", "valor numérico inválido"},
+		{"price > abc", "valor numérico inválido"},
+		{"price ! 3", "operador inválido"},
+		{"price > 1 $", "carácter inesperado"},
+		{"(price > 1", "falta cerrar un paréntesis"},
+		{"price > 1)", "texto sobrante"},
+		{"price > 1 AND", "campo desconocido"},
+	}
+	for _, tt := range tests {
+		_, err := parseCondition(tt.input)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseCondition(%q) = %v, se esperaba un error con %q", tt.input, err, tt.wantErr)
+		}
+	}
+}
+
+func TestConditionEval(t *testing.T) {
+	stock := stockFields(StockInfo{Price: 50, PreviousClose: 52, Change: -2, ChangePercent: -3.85, Volume: 2_000_000})
+	tests := []struct {
+		input string
+		want  bool
+	}{
+		{"changePercent < -3 AND volume > 1000000", true},
+		{"changePercent < -5 OR price == 50", true},
+		{"changePercent < -5 OR price != 50", false},
+		{"previousClose >= 52 AND change <= -2", true},
+		{"(price > 60 OR change < 0) AND volume < 1000", false},
+	}
+	for _, tt := range tests {
+		cond, err := parseCondition(tt.input)
+		if err != nil {
+			t.Fatalf("parseCondition(%q): %v", tt.input, err)
+		}
+		got, err := cond.Eval(stock)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = %v, se esperaba %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestConditionEvalMissingField(t *testing.T) {
+	// Los tipos de cambio no tienen volumen
+	forex := forexFields(ForexInfo{Price: 1000, ChangePercent: 1})
+	cond, err := parseCondition("volume > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cond.Eval(forex); err == nil {
+		t.Error("se esperaba un error por el campo volume ausente")
+	}
+
+	// El cortocircuito evita evaluar el campo faltante
+	cond, err = parseCondition("price > 500 OR volume > 0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matched, err := cond.Eval(forex); err != nil || !matched {
+		t.Errorf("Eval = %v, %v; se esperaba true sin error", matched, err)
+	}
+}
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Regla con la que se registran y rutean las alertas de volumen relativo
+const rvolRule = "rvol"
+
+// Franja horaria del perfil de volumen y ruedas que se promedian
+const (
+	rvolSlot    = 15 * time.Minute
+	rvolDays    = 20
+	rvolMinDays = 5 // Con menos ruedas el promedio no es representativo
+)
+
+// volumeProfile es el volumen acumulado promedio desde la apertura al final de cada franja horaria
+type volumeProfile struct {
+	cumulative []float64
+	days       int
+}
+
+// buildVolumeProfile promedia, para las últimas ruedas anteriores a today, el volumen acumulado al final de cada franja
+func buildVolumeProfile(points []HistoryPoint, hours MarketHours, today string) volumeProfile {
+	open0, close0 := hours.sessionTimes(time.Now())
+	slots := int((close0.Sub(open0) + rvolSlot - 1) / rvolSlot)
+
+	byDay := make(map[string][]float64)
+	for _, p := range points {
+		day := p.Time.In(hours.Location).Format("2006-01-02")
+		if day >= today {
+			continue
+		}
+		open, _ := hours.sessionTimes(p.Time)
+		slot := int(p.Time.Sub(open) / rvolSlot)
+		if slot < 0 || slot >= slots {
+			continue
+		}
+		if byDay[day] == nil {
+			byDay[day] = make([]float64, slots)
+		}
+		byDay[day][slot] += float64(p.Volume)
+	}
+
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	sort.Strings(days)
+	if len(days) > rvolDays {
+		days = days[len(days)-rvolDays:]
+	}
+
+	profile := volumeProfile{cumulative: make([]float64, slots), days: len(days)}
+	for _, day := range days {
+		var cum float64
+		for slot, volume := range byDay[day] {
+			cum += volume
+			profile.cumulative[slot] += cum
+		}
+	}
+	for slot := range profile.cumulative {
+		if profile.days > 0 {
+			profile.cumulative[slot] /= float64(profile.days)
+		}
+	}
+	return profile
+}
+
+// expected devuelve el volumen acumulado esperado a una altura de la rueda, interpolando dentro de la franja
+func (p volumeProfile) expected(sinceOpen time.Duration) float64 {
+	if len(p.cumulative) == 0 || sinceOpen <= 0 {
+		return 0
+	}
+	slot := int(sinceOpen / rvolSlot)
+	if slot >= len(p.cumulative) {
+		return p.cumulative[len(p.cumulative)-1]
+	}
+	prev := 0.0
+	if slot > 0 {
+		prev = p.cumulative[slot-1]
+	}
+	fraction := float64(sinceOpen%rvolSlot) / float64(rvolSlot)
+	return prev + (p.cumulative[slot]-prev)*fraction
+}
+
+// RVOLWatcher calcula el volumen relativo de cada papel contra el promedio a la misma hora y alerta los picos
+type RVOLWatcher struct {
+	mu         sync.Mutex
+	profiles   map[string]volumeProfile // Perfil por símbolo, recalculado una vez por día
+	profileDay string
+	values     map[string]float64 // Último RVOL calculado por símbolo
+	alerted    map[string]string  // Último día alertado por símbolo
+}
+
+var rvols = &RVOLWatcher{
+	profiles: make(map[string]volumeProfile),
+	values:   make(map[string]float64),
+	alerted:  make(map[string]string),
+}
+
+// profile devuelve el perfil de volumen de un símbolo, consultando el intradiario una vez por día
+func (w *RVOLWatcher) profile(symbol string, hours MarketHours, today string, client QuoteFetcher) volumeProfile {
+	w.mu.Lock()
+	p, ok := w.profiles[symbol]
+	w.mu.Unlock()
+	if ok {
+		return p
+	}
+
+	// Un error se guarda como perfil vacío para no reintentar en cada ciclo
+	points, err := getHistory(symbol, "1mo", "15m", client)
+	if err == nil {
+		p = buildVolumeProfile(points, hours, today)
+	}
+	w.mu.Lock()
+	w.profiles[symbol] = p
+	w.mu.Unlock()
+	return p
+}
+
+// Value devuelve el último RVOL calculado de un símbolo
+func (w *RVOLWatcher) Value(symbol string) (float64, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	v, ok := w.values[symbol]
+	return v, ok
+}
+
+// Check calcula el RVOL de los papeles con el mercado abierto y alerta, una vez por día, los que superan el umbral
+func (w *RVOLWatcher) Check(snapshot *Snapshot, notifiers []Notifier, client QuoteFetcher) {
+	threshold := appConfig().RVOLAlert
+	if threshold <= 0 {
+		return
+	}
+	now := time.Now()
+	// En horario silenciado se sigue calculando el RVOL, pero sin consumir la alerta del día
+	allowed := alertAllowed(rvolRule, now)
+
+	w.mu.Lock()
+	today := now.In(argentinaLocation).Format("2006-01-02")
+	if w.profileDay != today {
+		w.profiles = make(map[string]volumeProfile)
+		w.values = make(map[string]float64)
+		w.profileDay = today
+	}
+	w.mu.Unlock()
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var alerts []Alert
+	for _, stock := range snapshot.Stocks {
+		hours := stock.Market.Hours()
+		open, _ := hours.sessionTimes(now)
+		// En la primera franja el acumulado es demasiado chico para compararlo
+		if !hours.IsOpen(now) || now.Sub(open) < rvolSlot || stock.Volume <= 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func(stock StockInfo, hours MarketHours, sinceOpen time.Duration) {
+			defer wg.Done()
+			p := w.profile(stock.Symbol, hours, now.In(hours.Location).Format("2006-01-02"), client)
+			expected := p.expected(sinceOpen)
+			if p.days < rvolMinDays || expected <= 0 {
+				return
+			}
+			rvol := float64(stock.Volume) / expected
+
+			w.mu.Lock()
+			w.values[stock.Symbol] = rvol
+			due := allowed && rvol >= threshold && w.alerted[stock.Symbol] != today
+			if due {
+				w.alerted[stock.Symbol] = today
+			}
+			w.mu.Unlock()
+			if !due {
+				return
+			}
+
+			mu.Lock()
+			alerts = append(alerts, Alert{
+				Rule:     rvolRule,
+				Severity: SeverityWarning,
+				Symbol:   stock.Symbol,
+				Value:    rvol,
+				Message: fmt.Sprintf("%s opera %.1fx su volumen habitual a esta hora (%d vs. %.0f promedio de %d ruedas), %+.2f%%",
+					stock.Symbol, rvol, stock.Volume, expected, p.days, stock.ChangePercent),
+				Time: now,
+			})
+			mu.Unlock()
+		}(stock, hours, now.Sub(open))
+	}
+	wg.Wait()
+
+	if len(alerts) > 0 {
+		sort.Slice(alerts, func(i, j int) bool { return alerts[i].Value > alerts[j].Value })
+		dispatchAlerts(notifiers, fmt.Sprintf("Volumen relativo mayor a %.1fx", threshold), alerts, snapshot)
+	}
+}
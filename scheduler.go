@@ -0,0 +1,115 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Fracción del intervalo que se les da a las acciones en un ciclo recortado: el resto queda de margen para forex, bonos y alertas
+const cycleTargetShare = 0.75
+
+// CycleScheduler decide qué acciones se consultan en cada ciclo. Mientras consultarlas tarda menos que el
+// intervalo se pide toda la watchlist; si se excede, los ciclos siguientes consultan siempre los símbolos
+// importantes y solo una tanda rotativa del resto, que conserva su última cotización hasta que le toque
+type CycleScheduler struct {
+	mu        sync.Mutex
+	quota     int                  // Símbolos no prioritarios por ciclo; 0 sin límite
+	cursor    int                  // Posición de la rotación entre los no prioritarios
+	requested int                  // Símbolos consultados en el ciclo en curso
+	important int                  // Símbolos importantes de la watchlist
+	last      map[string]StockInfo // Última cotización obtenida de cada símbolo, para los postergados
+}
+
+var scheduler = &CycleScheduler{last: make(map[string]StockInfo)}
+
+// importantSymbols son los que nunca se postergan: los marcados en config.json, los fijados y las tenencias
+func importantSymbols() map[string]bool {
+	important := prioritySymbols()
+	for _, symbol := range appConfig().Important {
+		important[strings.ToUpper(strings.TrimSpace(symbol))] = true
+	}
+	return important
+}
+
+// Plan devuelve los símbolos de la watchlist que se consultan en este ciclo; nil consulta todos
+func (s *CycleScheduler) Plan(watchlist []WatchlistEntry) map[string]bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	important := importantSymbols()
+	plan := make(map[string]bool)
+	var rest []string
+	for _, stock := range watchlist {
+		if important[stock.Symbol] {
+			plan[stock.Symbol] = true
+		} else {
+			rest = append(rest, stock.Symbol)
+		}
+	}
+	s.important = len(plan)
+	if s.quota == 0 {
+		s.requested = len(watchlist)
+		return nil
+	}
+
+	if len(rest) > 0 {
+		s.cursor %= len(rest)
+		for i := 0; i < min(s.quota, len(rest)); i++ {
+			plan[rest[(s.cursor+i)%len(rest)]] = true
+		}
+		s.cursor += s.quota
+	}
+
+	s.requested = len(plan)
+	return plan
+}
+
+// Remember guarda las cotizaciones obtenidas para reusarlas mientras el símbolo esté postergado
+func (s *CycleScheduler) Remember(stocks []StockInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, stock := range stocks {
+		if !stock.Deferred {
+			s.last[stock.Symbol] = stock
+		}
+	}
+}
+
+// Deferred devuelve la última cotización de un símbolo postergado, marcada como tal
+func (s *CycleScheduler) Deferred(symbol string) (StockInfo, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stock, ok := s.last[symbol]
+	stock.Deferred = true
+	return stock, ok
+}
+
+// Record ajusta la tanda de no prioritarios según lo que tardaron las consultas: la achica si se pasaron del
+// intervalo y la agranda de a poco cuando sobra tiempo, hasta volver a consultar toda la watchlist
+func (s *CycleScheduler) Record(elapsed, interval time.Duration, watchlist int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if interval <= 0 || s.requested == 0 {
+		return
+	}
+
+	important := s.important
+	switch {
+	case elapsed > interval:
+		perSymbol := elapsed / time.Duration(s.requested)
+		capacity := int(float64(interval) * cycleTargetShare / float64(perSymbol))
+		quota := max(capacity-important, 1)
+		if s.quota == 0 || quota < s.quota {
+			s.quota = quota
+			fmt.Printf("%s⚠️ Las acciones tardaron %v (intervalo %v): se consultan las importantes y %d más por ciclo%s\n",
+				Yellow, elapsed.Round(time.Second), interval, quota, Reset)
+		}
+	case s.quota > 0 && elapsed < time.Duration(float64(interval)*cycleTargetShare/2):
+		s.quota += max(s.quota/2, 1)
+		if s.quota+important >= watchlist {
+			s.quota = 0
+			fmt.Printf("%sLas consultas volvieron a entrar en el intervalo: se consulta toda la watchlist%s\n", Green, Reset)
+		}
+	}
+}
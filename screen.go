@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ScreenResult es un símbolo que cumple el filtro de `bolsa screen`
+type ScreenResult struct {
+	Symbol        string  `json:"symbol"`
+	Name          string  `json:"name"`
+	Market        Market  `json:"market"`
+	Sector        string  `json:"sector,omitempty"`
+	Price         float64 `json:"price"`
+	ChangePercent float64 `json:"change_percent"`
+	Volume        int64   `json:"volume"`
+
+	fields map[string]float64
+}
+
+// screenUniverse junta todos los símbolos conocidos: catálogo, watchlist activa y guardadas,
+// equivalencias ADR/CEDEAR y tenencias de la cartera
+func screenUniverse(market Market) []WatchlistEntry {
+	var universe []WatchlistEntry
+	seen := make(map[string]bool)
+	add := func(symbol string, m Market) {
+		symbol = normalizeSymbol(symbol)
+		if symbol == "" || seen[symbol] || (market != "" && m != market) {
+			return
+		}
+		seen[symbol] = true
+		universe = append(universe, WatchlistEntry{Symbol: symbol, Market: m})
+	}
+
+	for _, entry := range catalog {
+		add(entry.Symbol, entry.Market)
+	}
+	for _, entry := range watchlistSnapshot() {
+		add(entry.Symbol, entry.Market)
+	}
+	if watchlists, err := loadWatchlists(); err == nil {
+		// Orden estable entre ejecuciones: los mapas se recorren en cualquier orden
+		var names []string
+		for name := range watchlists {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, entry := range watchlists[name] {
+				add(entry.Symbol, entry.Market)
+			}
+		}
+	}
+	for _, l := range companyListings() {
+		add(l.Foreign, marketForSymbol(l.Foreign))
+		add(l.Local, marketForSymbol(l.Local))
+	}
+	if portfolio, err := loadPortfolio(); err == nil {
+		for _, h := range portfolio.Holdings {
+			add(h.Symbol, marketForSymbol(h.Symbol))
+		}
+	}
+	return universe
+}
+
+// screenSymbols cotiza el universo y devuelve los símbolos que cumplen la condición y los que no se pudieron cotizar
+func screenSymbols(universe []WatchlistEntry, cond Condition, client QuoteFetcher) ([]ScreenResult, []Quote) {
+	symbols := make([]string, len(universe))
+	for i, entry := range universe {
+		symbols[i] = entry.Symbol
+	}
+
+	var results []ScreenResult
+	var failed []Quote
+	for i, q := range fetchQuotes(symbols, client) {
+		if q.Error != "" {
+			failed = append(failed, q)
+			continue
+		}
+		fields := stockFields(StockInfo{
+			Symbol: q.Symbol, Price: q.Price, PreviousClose: q.PreviousClose,
+			Change: q.Change, ChangePercent: q.ChangePercent, Volume: q.Volume,
+		})
+		if matched, err := cond.Eval(fields); err != nil || !matched {
+			continue
+		}
+		result := ScreenResult{
+			Symbol: q.Symbol, Name: q.Name, Market: universe[i].Market,
+			Price: q.Price, ChangePercent: q.ChangePercent, Volume: q.Volume, fields: fields,
+		}
+		if entry, ok := catalogLookup(q.Symbol); ok {
+			result.Sector = entry.Sector
+			if result.Name == "" {
+				result.Name = entry.Name
+			}
+		}
+		results = append(results, result)
+	}
+	return results, failed
+}
+
+// displayScreenResults muestra los símbolos que cumplen el filtro
+func displayScreenResults(filter string, results []ScreenResult, total int) {
+	fmt.Printf("\n%s=== SCREENING: %s ===%s\n\n", Cyan, filter, Reset)
+	if len(results) == 0 {
+		fmt.Printf("Ninguno de los %d símbolos cumple el filtro.\n", total)
+		return
+	}
+
+	fmt.Printf("%-10s %-30s %-18s %14s %9s %12s\n", "Símbolo", "Nombre", "Sector", "Precio", "Var %", "Volumen")
+	for _, r := range results {
+		color := Green
+		if r.ChangePercent < 0 {
+			color = Red
+		}
+		fmt.Printf("%-10s %-30.30s %-18.18s %14.2f %s%+8.2f%%%s %12s\n",
+			r.Symbol, r.Name, r.Sector, r.Price, color, r.ChangePercent, Reset, formatCompact(float64(r.Volume)))
+	}
+	fmt.Printf("\n%d de %d símbolos cumplen el filtro.\n", len(results), total)
+}
+
+// runScreen implementa `bolsa screen --filter "changePercent < -3 AND volume > 1000000"` sobre todos los símbolos conocidos
+func runScreen(args []string) error {
+	fs := flag.NewFlagSet("screen", flag.ExitOnError)
+	filter := fs.String("filter", "", "condición con la sintaxis de las alertas (price, previousClose, change, changePercent, volume; AND, OR, paréntesis)")
+	sortBy := fs.String("sort", "changePercent", "campo por el que se ordenan los resultados")
+	asc := fs.Bool("asc", false, "ordenar de menor a mayor")
+	limit := fs.Int("limit", 0, "mostrar como máximo esta cantidad de resultados (0 = todos)")
+	marketName := fs.String("market", "", "limitar el universo a un mercado: NYSE o BYMA")
+	format := fs.String("format", "table", "formato de salida: table o json")
+	verbose := fs.Bool("v", false, "mostrar los símbolos que no se pudieron cotizar")
+	fs.Parse(args)
+
+	// El filtro también se acepta como argumento: bolsa screen "changePercent < -3"
+	if *filter == "" {
+		*filter = strings.Join(fs.Args(), " ")
+	}
+	if strings.TrimSpace(*filter) == "" {
+		return fmt.Errorf("uso: bolsa screen --filter \"changePercent < -3 AND volume > 1000000\"")
+	}
+	cond, err := parseCondition(*filter)
+	if err != nil {
+		return fmt.Errorf("filtro inválido: %v", err)
+	}
+	field, ok := conditionFields[strings.ToLower(*sortBy)]
+	if !ok {
+		return fmt.Errorf("campo de orden desconocido %q (usar price, previousClose, change, changePercent o volume)", *sortBy)
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("formato desconocido %q (usar table o json)", *format)
+	}
+	var market Market
+	if *marketName != "" {
+		if market, err = ParseMarket(*marketName); err != nil {
+			return err
+		}
+	}
+
+	universe := screenUniverse(market)
+	if len(universe) == 0 {
+		return fmt.Errorf("no hay símbolos conocidos del mercado %s", *marketName)
+	}
+
+	// Los mensajes de progreso van a stderr para que el JSON se pueda procesar
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	results, failed := screenSymbols(universe, cond, NewHTTPClient())
+	os.Stdout = stdout
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if *asc {
+			return results[i].fields[field] < results[j].fields[field]
+		}
+		return results[i].fields[field] > results[j].fields[field]
+	})
+	if *limit > 0 && len(results) > *limit {
+		results = results[:*limit]
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(results)
+	}
+
+	displayScreenResults(cond.String(), results, len(universe)-len(failed))
+	if len(failed) > 0 {
+		fmt.Printf("%s%d símbolo(s) sin cotización%s\n", Yellow, len(failed), Reset)
+		if *verbose {
+			for _, q := range failed {
+				fmt.Printf("  %-10s %s\n", q.Symbol, q.Error)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// SearchResult representa un resultado del endpoint de búsqueda de Yahoo Finance
+type SearchResult struct {
+	Symbol    string `json:"symbol"`
+	ShortName string `json:"shortname"`
+	LongName  string `json:"longname"`
+	Exchange  string `json:"exchange"`
+	QuoteType string `json:"quoteType"`
+}
+
+// Mutex que protege la watchlist activa (stocks), que puede modificarse en caliente
+var stocksMu sync.RWMutex
+
+// Mutex que pausa el refresco de pantalla mientras el usuario busca un símbolo
+var screenMu sync.Mutex
+
+// searchSymbols busca tickers por nombre usando el endpoint de búsqueda de Yahoo
+func searchSymbols(query string, client QuoteFetcher) ([]SearchResult, error) {
+	searchURL := fmt.Sprintf("https://query2.finance.yahoo.com/v1/finance/search?q=%s&quotesCount=10&newsCount=0",
+		url.QueryEscape(query))
+
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Accept":     "application/json",
+		"Referer":    "https://finance.yahoo.com/",
+	}
+
+	resp, err := client.GetWithRetry(searchURL, headers)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "al buscar %q", query)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var searchResp struct {
+		Quotes []SearchResult `json:"quotes"`
+	}
+	if err := json.Unmarshal(body, &searchResp); err != nil {
+		return nil, fmt.Errorf("error al decodificar la búsqueda %q: %v", query, err)
+	}
+
+	// Nos quedamos solo con instrumentos que podemos cotizar
+	var results []SearchResult
+	for _, quote := range searchResp.Quotes {
+		if quote.Symbol == "" {
+			continue
+		}
+		switch quote.QuoteType {
+		case "EQUITY", "ETF", "INDEX", "CURRENCY", "CRYPTOCURRENCY", "MUTUALFUND":
+			results = append(results, quote)
+		}
+	}
+
+	return results, nil
+}
+
+// marketFromExchange traduce el código de bolsa de Yahoo al mercado usado en la watchlist
+func marketFromExchange(exchange string) Market {
+	if market, err := ParseMarket(exchange); err == nil {
+		return market
+	}
+	return Market(exchange)
+}
+
+// addStock agrega un símbolo a la watchlist activa si no estaba presente
+func addStock(symbol string, market Market) bool {
+	stocksMu.Lock()
+	defer stocksMu.Unlock()
+
+	for _, stock := range stocks {
+		if stock.Symbol == symbol {
+			return false
+		}
+	}
+	stocks = append(stocks, WatchlistEntry{Symbol: symbol, Market: market})
+	return true
+}
+
+// watchlistSnapshot devuelve una copia de la watchlist activa para iterarla sin bloquear
+func watchlistSnapshot() []WatchlistEntry {
+	stocksMu.RLock()
+	defer stocksMu.RUnlock()
+
+	snapshot := make([]WatchlistEntry, len(stocks))
+	copy(snapshot, stocks)
+	return snapshot
+}
+
+// handleInput lee comandos del teclado: "/texto" busca un ticker y permite agregarlo a la watchlist
+func handleInput(client QuoteFetcher) {
+	scanner := bufio.NewScanner(os.Stdin)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		// ":heatmap" alterna el mapa de calor y se aplica en el próximo refresco
+		if line == ":heatmap" {
+			if toggleHeatmap().Heatmap() {
+				fmt.Printf("%sMapa de calor activado%s\n", Green, Reset)
+			} else {
+				fmt.Printf("%sVista de tablas activada%s\n", Green, Reset)
+			}
+			continue
+		}
+
+		// ":empresas" agrupa ADR y papel local por empresa; ":expand GGAL" despliega sus dos filas
+		if fields := strings.Fields(line); len(fields) > 0 && (fields[0] == ":empresas" || fields[0] == ":expand") {
+			switch {
+			case fields[0] == ":empresas":
+				if toggleCompanyView() {
+					fmt.Printf("%sVista por empresa activada%s\n", Green, Reset)
+				} else {
+					fmt.Printf("%sVista de filas sueltas activada%s\n", Green, Reset)
+				}
+			case len(fields) == 2:
+				if _, _, err := toggleCompanyExpanded(fields[1]); err != nil {
+					fmt.Printf("%s%v%s\n", Red, err, Reset)
+					continue
+				}
+			default:
+				fmt.Printf("%suso: :expand SIMBOLO%s\n", Red, Reset)
+				continue
+			}
+			redrawScreen()
+			continue
+		}
+
+		// ":next", ":prev" y ":page 3" recorren las páginas de la tabla de acciones y redibujan enseguida
+		if fields := strings.Fields(line); len(fields) > 0 && (fields[0] == ":next" || fields[0] == ":prev" || fields[0] == ":page") {
+			switch {
+			case fields[0] == ":next":
+				moveStockPage(1)
+			case fields[0] == ":prev":
+				moveStockPage(-1)
+			case len(fields) == 2:
+				if err := setStockPage(fields[1]); err != nil {
+					fmt.Printf("%s%v%s\n", Red, err, Reset)
+					continue
+				}
+			default:
+				fmt.Printf("%suso: :page N%s\n", Red, Reset)
+				continue
+			}
+			redrawScreen()
+			continue
+		}
+
+		// ":sector energía" agrega un sector completo; ":pin GGAL" y ":unpin GGAL" fijan símbolos arriba;
+		// ":sort change desc" ordena la tabla; ":detail YPF" abre el panel de detalle con el short interest;
+		// ":ack regla", ":snooze regla 2h", ":disable regla", ":enable regla" gestionan alertas
+		if strings.HasPrefix(line, ":") {
+			screenMu.Lock()
+			fields := strings.Fields(strings.TrimPrefix(line, ":"))
+			if len(fields) == 2 && fields[0] == "detail" {
+				displayDetail(fields[1], client)
+			} else if len(fields) > 1 && fields[0] == "sector" {
+				added, err := addSectorToActiveWatchlist(strings.Join(fields[1:], " "), MarketOther)
+				if err != nil {
+					fmt.Printf("%s%v%s\n", Red, err, Reset)
+				} else {
+					fmt.Printf("%s✅ %d papeles agregados a la watchlist%s\n", Green, added, Reset)
+				}
+			} else if len(fields) > 1 && fields[0] == "sort" {
+				order, err := parseTableSort(strings.Join(fields[1:], " "))
+				if err == nil {
+					err = setTableSort(order)
+				}
+				if err != nil {
+					fmt.Printf("%s%v%s\n", Red, err, Reset)
+				} else {
+					fmt.Printf("%sTabla ordenada por %s%s\n", Green, order, Reset)
+				}
+			} else if len(fields) > 1 && (fields[0] == "pin" || fields[0] == "unpin") {
+				symbol := strings.Join(fields[1:], " ")
+				if err := setPinned(symbol, fields[0] == "pin"); err != nil {
+					fmt.Printf("%s%v%s\n", Red, err, Reset)
+				} else if fields[0] == "pin" {
+					fmt.Printf("%s📌 %s fijado arriba%s\n", Green, strings.ToUpper(symbol), Reset)
+				} else {
+					fmt.Printf("%s%s ya no está fijado%s\n", Green, strings.ToUpper(symbol), Reset)
+				}
+			} else if err := alertManagementCommand(fields); err != nil {
+				fmt.Printf("%s%v%s\n", Red, err, Reset)
+			}
+			fmt.Println("Presioná Enter para continuar...")
+			scanner.Scan()
+			screenMu.Unlock()
+			continue
+		}
+
+		if !strings.HasPrefix(line, "/") {
+			continue
+		}
+
+		query := strings.TrimSpace(strings.TrimPrefix(line, "/"))
+		if query == "" {
+			continue
+		}
+
+		// Pausar el refresco mientras el usuario elige
+		screenMu.Lock()
+		fmt.Printf("\n%sBuscando \"%s\"...%s\n", Cyan, query, Reset)
+
+		results, err := searchSymbols(query, client)
+		if err != nil {
+			fmt.Printf("%sError al buscar \"%s\": %v%s\n", Red, query, err, Reset)
+			screenMu.Unlock()
+			continue
+		}
+		if len(results) == 0 {
+			fmt.Printf("%sNo se encontraron resultados para \"%s\"%s\n", Yellow, query, Reset)
+			screenMu.Unlock()
+			continue
+		}
+
+		for i, result := range results {
+			name := result.LongName
+			if name == "" {
+				name = result.ShortName
+			}
+			fmt.Printf("  %s%2d)%s %-12s %-40s %s\n", Yellow, i+1, Reset, result.Symbol, name, result.Exchange)
+		}
+		fmt.Print("Elegí un número para agregarlo a la watchlist (Enter para cancelar): ")
+
+		if !scanner.Scan() {
+			screenMu.Unlock()
+			return
+		}
+
+		choice, err := strconv.Atoi(strings.TrimSpace(scanner.Text()))
+		if err != nil || choice < 1 || choice > len(results) {
+			fmt.Println("Búsqueda cancelada.")
+			screenMu.Unlock()
+			continue
+		}
+
+		selected := results[choice-1]
+		market := marketFromExchange(selected.Exchange)
+		if addStock(selected.Symbol, market) {
+			fmt.Printf("%s✅ %s agregado a la watchlist (%s)%s\n", Green, selected.Symbol, market, Reset)
+		} else {
+			fmt.Printf("%s%s ya estaba en la watchlist%s\n", Yellow, selected.Symbol, Reset)
+		}
+		screenMu.Unlock()
+	}
+}
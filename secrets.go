@@ -0,0 +1,447 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+const secretsFile = "secrets.enc"
+
+// Iteraciones de PBKDF2 para derivar la clave del archivo de secretos a partir de la passphrase
+const secretsIterations = 600000
+
+// Servicio bajo el que se guardan los secretos en el keyring del sistema
+const keyringService = "bolsa"
+
+// Prefijos con los que un valor de config.json referencia un secreto en lugar de contenerlo
+const (
+	secretEnvPrefix     = "env:"     // env:TELEGRAM_BOT_TOKEN
+	secretFilePrefix    = "secret:"  // secret:telegram (archivo cifrado, passphrase en BOLSA_SECRETS_PASSPHRASE)
+	secretKeyringPrefix = "keyring:" // keyring:telegram (Keychain en macOS, secret-tool en Linux)
+)
+
+// encryptedSecrets es el formato de secrets.enc: los secretos en JSON cifrados con AES-256-GCM
+type encryptedSecrets struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// Los secretos descifrados y la passphrase se guardan en memoria para pedirla una sola vez; secretsMu protege a ambos
+var (
+	secretsMu     sync.Mutex
+	secretsCache  map[string]string
+	secretsPhrase string
+)
+
+// isSecretRef indica si el valor es una referencia a un secreto y no el secreto en texto plano
+func isSecretRef(value string) bool {
+	return strings.HasPrefix(value, secretEnvPrefix) || strings.HasPrefix(value, secretFilePrefix) || strings.HasPrefix(value, secretKeyringPrefix)
+}
+
+// resolveSecret devuelve el valor de un campo sensible, resolviendo las referencias env:, secret: y keyring:;
+// un valor sin prefijo se usa tal cual
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, secretEnvPrefix):
+		name := strings.TrimPrefix(value, secretEnvPrefix)
+		secret := os.Getenv(name)
+		if secret == "" {
+			return "", fmt.Errorf("la variable de entorno %s no está definida", name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, secretFilePrefix):
+		name := strings.TrimPrefix(value, secretFilePrefix)
+		secrets, err := loadSecrets()
+		if err != nil {
+			return "", err
+		}
+		secret, ok := secrets[name]
+		if !ok {
+			return "", fmt.Errorf("el secreto %q no está en %s (bolsa secrets set %s)", name, secretsFile, name)
+		}
+		return secret, nil
+	case strings.HasPrefix(value, secretKeyringPrefix):
+		return keyringGet(strings.TrimPrefix(value, secretKeyringPrefix))
+	}
+	return value, nil
+}
+
+// secretsPassphrase devuelve la passphrase del archivo de secretos: BOLSA_SECRETS_PASSPHRASE o, en una terminal,
+// la pide sin eco. Se llama con secretsMu tomado, que protege también la passphrase recordada
+func secretsPassphrase() (string, error) {
+	if secretsPhrase != "" {
+		return secretsPhrase, nil
+	}
+	if phrase := os.Getenv("BOLSA_SECRETS_PASSPHRASE"); phrase != "" {
+		secretsPhrase = phrase
+		return phrase, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("falta BOLSA_SECRETS_PASSPHRASE para descifrar %s", secretsFile)
+	}
+	phrase, err := readHidden("Passphrase de los secretos: ")
+	if err != nil {
+		return "", err
+	}
+	if phrase == "" {
+		return "", fmt.Errorf("passphrase vacía")
+	}
+	secretsPhrase = phrase
+	return phrase, nil
+}
+
+// readHidden pide un valor en la terminal sin mostrar lo que se escribe
+func readHidden(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	value, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(value), "\r\n"), nil
+}
+
+// secretsKey deriva la clave AES de la passphrase
+func secretsKey(passphrase string, salt []byte) ([]byte, error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, secretsIterations, 32)
+}
+
+// loadSecrets descifra el archivo de secretos; sin archivo devuelve un conjunto vacío
+func loadSecrets() (map[string]string, error) {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+	if secretsCache != nil {
+		return secretsCache, nil
+	}
+
+	path, err := appFile(secretsFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var enc encryptedSecrets
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	phrase, err := secretsPassphrase()
+	if err != nil {
+		return nil, err
+	}
+	secrets, err := openSecrets(phrase, enc)
+	if err != nil {
+		// La passphrase equivocada no se recuerda: la próxima vez se vuelve a pedir
+		secretsPhrase = ""
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	secretsCache = secrets
+	return secrets, nil
+}
+
+// sealSecrets cifra los secretos con una sal y un nonce nuevos
+func sealSecrets(phrase string, secrets map[string]string) (encryptedSecrets, error) {
+	enc := encryptedSecrets{Version: 1, Salt: make([]byte, 16)}
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return enc, err
+	}
+	if _, err := rand.Read(enc.Salt); err != nil {
+		return enc, err
+	}
+	gcm, err := secretsCipher(phrase, enc.Salt)
+	if err != nil {
+		return enc, err
+	}
+	enc.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(enc.Nonce); err != nil {
+		return enc, err
+	}
+	enc.Data = gcm.Seal(nil, enc.Nonce, plain, nil)
+	return enc, nil
+}
+
+// openSecrets descifra el contenido de secrets.enc
+func openSecrets(phrase string, enc encryptedSecrets) (map[string]string, error) {
+	gcm, err := secretsCipher(phrase, enc.Salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc.Nonce) != gcm.NonceSize() {
+		return nil, fmt.Errorf("nonce inválido")
+	}
+	plain, err := gcm.Open(nil, enc.Nonce, enc.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("no se pudo descifrar: passphrase incorrecta o archivo dañado")
+	}
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("contenido inválido: %v", err)
+	}
+	return secrets, nil
+}
+
+// secretsCipher arma el AES-256-GCM con la clave derivada de la passphrase
+func secretsCipher(phrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := secretsKey(phrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// saveSecrets cifra y escribe el archivo de secretos con una sal y un nonce nuevos
+func saveSecrets(secrets map[string]string) error {
+	secretsMu.Lock()
+	defer secretsMu.Unlock()
+
+	phrase, err := secretsPassphrase()
+	if err != nil {
+		return err
+	}
+	enc, err := sealSecrets(phrase, secrets)
+	if err != nil {
+		return err
+	}
+
+	path, err := appFile(secretsFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(enc, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return err
+	}
+	secretsCache = secrets
+	return nil
+}
+
+// keyringGet lee un secreto del keyring del sistema usando su herramienta de línea de comandos
+func keyringGet(name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("security", "find-generic-password", "-s", keyringService, "-a", name, "-w")
+	case "linux", "freebsd", "openbsd":
+		cmd = exec.Command("secret-tool", "lookup", "service", keyringService, "account", name)
+	default:
+		return "", fmt.Errorf("keyring no soportado en %s: usar secret:%s o env:", runtime.GOOS, name)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("no se pudo leer %q del keyring: %v %s", name, err, strings.TrimSpace(stderr.String()))
+	}
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", fmt.Errorf("el keyring no tiene el secreto %q", name)
+	}
+	return secret, nil
+}
+
+// keyringSet guarda un secreto en el keyring del sistema
+func keyringSet(name, secret string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		// Con -w al final y sin valor, security pide el secreto (dos veces) y lo lee de stdin en lugar de argv
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", keyringService, "-a", name, "-w")
+		cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+	case "linux", "freebsd", "openbsd":
+		// secret-tool lee el secreto de stdin: no queda en la lista de procesos
+		cmd = exec.Command("secret-tool", "store", "--label=bolsa: "+name, "service", keyringService, "account", name)
+		cmd.Stdin = strings.NewReader(secret)
+	default:
+		return fmt.Errorf("keyring no soportado en %s: usar el archivo cifrado (sin --keyring)", runtime.GOOS)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("no se pudo guardar %q en el keyring: %v %s", name, err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// secretFields devuelve los campos sensibles de la configuración, por su nombre en config.json
+func secretFields(cfg *Config) map[string]*string {
+	fields := map[string]*string{"notify.telegramToken": &cfg.Notify.TelegramToken}
+	if cfg.Notify.Email != nil {
+		fields["notify.email.password"] = &cfg.Notify.Email.Password
+	}
+	if cfg.IOL != nil {
+		fields["iol.password"] = &cfg.IOL.Password
+	}
+	if cfg.Publish != nil && cfg.Publish.S3 != nil {
+		fields["publish.s3.secretAccessKey"] = &cfg.Publish.S3.SecretAccessKey
+	}
+	if cfg.Publish != nil && cfg.Publish.GitHub != nil {
+		fields["publish.github.token"] = &cfg.Publish.GitHub.Token
+	}
+	if cfg.Social != nil && cfg.Social.Mastodon != nil {
+		fields["social.mastodon.token"] = &cfg.Social.Mastodon.Token
+	}
+	if cfg.Social != nil && cfg.Social.Twitter != nil {
+		fields["social.twitter.token"] = &cfg.Social.Twitter.Token
+	}
+	if cfg.Redis != nil {
+		fields["redis.password"] = &cfg.Redis.Password
+	}
+	return fields
+}
+
+// resolveConfigSecrets reemplaza las referencias de los campos sensibles por su valor. Una referencia que no se
+// puede resolver deja vacío solo ese campo, para no usarla como si fuera el secreto, y vuelve como advertencia:
+// el resto de la configuración se conserva
+func resolveConfigSecrets(cfg *Config) []string {
+	var warnings []string
+	for name, field := range secretFields(cfg) {
+		secret, err := resolveSecret(*field)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: %v; el campo queda vacío", name, err))
+		}
+		*field = secret
+	}
+	sort.Strings(warnings)
+	return warnings
+}
+
+// readSecretValue lee el valor de un secreto de stdin, sin pasarlo por argumentos de la línea de comandos
+func readSecretValue(name string) (string, error) {
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		value, err := readHidden(fmt.Sprintf("Valor de %s: ", name))
+		if err != nil {
+			return "", fmt.Errorf("no se pudo leer el valor de %s: %v", name, err)
+		}
+		if value == "" {
+			return "", fmt.Errorf("valor vacío para %s", name)
+		}
+		return value, nil
+	}
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("no se pudo leer el valor de %s: %v", name, err)
+	}
+	value := strings.TrimRight(line, "\r\n")
+	if value == "" {
+		return "", fmt.Errorf("valor vacío para %s", name)
+	}
+	return value, nil
+}
+
+// runSecrets implementa `bolsa secrets`: alta, baja y listado de secretos en el archivo cifrado o el keyring
+func runSecrets(args []string) error {
+	fs := flag.NewFlagSet("secrets", flag.ExitOnError)
+	useKeyring := fs.Bool("keyring", false, "guardar en el keyring del sistema en lugar del archivo cifrado")
+	fs.Usage = func() {
+		fmt.Println("Uso: bolsa secrets list | set [--keyring] NOMBRE | remove NOMBRE")
+		fmt.Println("El valor se lee de stdin. En config.json se referencia como secret:NOMBRE, keyring:NOMBRE o env:VARIABLE.")
+	}
+	if len(args) > 0 && args[0] == "set" {
+		// El flag puede ir después de la acción
+		fs.Parse(args[1:])
+		args = append([]string{"set"}, fs.Args()...)
+	} else {
+		fs.Parse(args)
+		args = fs.Args()
+	}
+	if len(args) == 0 {
+		fs.Usage()
+		return nil
+	}
+
+	switch args[0] {
+	case "list":
+		secrets, err := loadSecrets()
+		if err != nil {
+			return err
+		}
+		if len(secrets) == 0 {
+			fmt.Printf("No hay secretos en %s.\n", secretsFile)
+			return nil
+		}
+		names := make([]string, 0, len(secrets))
+		for name := range secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("secret:%s\n", name)
+		}
+	case "set":
+		if len(args) != 2 {
+			fs.Usage()
+			return fmt.Errorf("falta el nombre del secreto")
+		}
+		name := args[1]
+		value, err := readSecretValue(name)
+		if err != nil {
+			return err
+		}
+		if *useKeyring {
+			if err := keyringSet(name, value); err != nil {
+				return err
+			}
+			fmt.Printf("Guardado en el keyring. Usarlo en config.json como \"keyring:%s\".\n", name)
+			return nil
+		}
+		secrets, err := loadSecrets()
+		if err != nil {
+			return err
+		}
+		secrets[name] = value
+		if err := saveSecrets(secrets); err != nil {
+			return err
+		}
+		fmt.Printf("Guardado en %s. Usarlo en config.json como \"secret:%s\".\n", secretsFile, name)
+	case "remove":
+		if len(args) != 2 {
+			fs.Usage()
+			return fmt.Errorf("falta el nombre del secreto")
+		}
+		secrets, err := loadSecrets()
+		if err != nil {
+			return err
+		}
+		if _, ok := secrets[args[1]]; !ok {
+			return fmt.Errorf("el secreto %q no existe", args[1])
+		}
+		delete(secrets, args[1])
+		if err := saveSecrets(secrets); err != nil {
+			return err
+		}
+		fmt.Printf("Secreto %s eliminado.\n", args[1])
+	default:
+		fs.Usage()
+		return fmt.Errorf("acción desconocida %q", args[0])
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSecretsRoundTrip(t *testing.T) {
+	secrets := map[string]string{"telegram": "123456:AAH-secreto", "iol": "contraseña con ñ y espacios "}
+
+	enc, err := sealSecrets("passphrase correcta", secrets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(enc.Data, []byte("123456:AAH-secreto")) {
+		t.Fatal("el secreto quedó en texto plano")
+	}
+
+	got, err := openSecrets("passphrase correcta", enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, secrets) {
+		t.Errorf("descifrado = %v, se esperaba %v", got, secrets)
+	}
+
+	if _, err := openSecrets("otra passphrase", enc); err == nil {
+		t.Error("se descifró con una passphrase incorrecta")
+	}
+	enc.Data[0] ^= 0xff
+	if _, err := openSecrets("passphrase correcta", enc); err == nil {
+		t.Error("se descifró un archivo alterado")
+	}
+}
+
+func TestSealSecretsUsesFreshSaltAndNonce(t *testing.T) {
+	a, err := sealSecrets("p", map[string]string{"x": "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := sealSecrets("p", map[string]string{"x": "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a.Salt, b.Salt) || bytes.Equal(a.Nonce, b.Nonce) || bytes.Equal(a.Data, b.Data) {
+		t.Error("dos cifrados del mismo contenido repitieron sal, nonce o datos")
+	}
+}
+
+func TestSaveAndLoadSecretsConcurrently(t *testing.T) {
+	t.Setenv("BOLSA_SECRETS_PASSPHRASE", "passphrase de prueba")
+	path, err := appFile(secretsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(path)
+	reset := func() {
+		secretsMu.Lock()
+		secretsCache, secretsPhrase = nil, ""
+		secretsMu.Unlock()
+	}
+	reset()
+	defer reset()
+
+	if err := saveSecrets(map[string]string{"telegram": "token"}); err != nil {
+		t.Fatal(err)
+	}
+	reset()
+
+	// Con -race detecta accesos a la passphrase o la caché fuera de secretsMu
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if secret, err := resolveSecret("secret:telegram"); err != nil || secret != "token" {
+				t.Errorf("resolveSecret = %q, %v", secret, err)
+			}
+		}()
+	}
+	wg.Wait()
+}
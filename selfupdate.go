@@ -0,0 +1,352 @@
+package main
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// version es la versión del binario; los releases la fijan con -ldflags "-X main.version=v1.2.3"
+var version = "dev"
+
+// Repositorio de GitHub donde se publican los releases con los binarios de cada plataforma
+const releasesRepo = "elkanika/bolsa-valores-argentina-GO"
+
+// Asset con los sha256 de todos los binarios del release, en el formato de sha256sum
+const checksumsAsset = "checksums.txt"
+
+const updateCheckFile = "last_update_check"
+
+// Release es la parte de la respuesta de la API de releases de GitHub que usamos
+type Release struct {
+	Tag    string         `json:"tag_name"`
+	Assets []ReleaseAsset `json:"assets"`
+}
+
+// ReleaseAsset es un archivo adjunto a un release
+type ReleaseAsset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+}
+
+// asset busca un archivo del release por nombre
+func (r *Release) asset(name string) (ReleaseAsset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return ReleaseAsset{}, false
+}
+
+// platformAssetName devuelve el nombre del binario publicado para el sistema actual (bolsa_linux_amd64, bolsa_windows_amd64.exe)
+func platformAssetName() string {
+	name := fmt.Sprintf("bolsa_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// fetchRelease consulta un release de GitHub; con tag vacío devuelve el último publicado
+func fetchRelease(tag string, client QuoteFetcher) (*Release, error) {
+	url := "https://api.github.com/repos/" + releasesRepo + "/releases/latest"
+	if tag != "" {
+		url = "https://api.github.com/repos/" + releasesRepo + "/releases/tags/" + tag
+	}
+
+	resp, err := client.GetWithRetry(url, map[string]string{"Accept": "application/vnd.github+json"})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("no se encontró el release %q", tag)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, statusError(resp.StatusCode, "al consultar los releases")
+	}
+
+	var release Release
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return nil, fmt.Errorf("error al decodificar el release: %v", err)
+	}
+	return &release, nil
+}
+
+// releaseChecksum descarga checksums.txt del release y devuelve el sha256 esperado del asset
+func releaseChecksum(release *Release, assetName string, client QuoteFetcher) (string, error) {
+	asset, ok := release.asset(checksumsAsset)
+	if !ok {
+		return "", fmt.Errorf("el release %s no publica %s: no se puede verificar el binario", release.Tag, checksumsAsset)
+	}
+
+	resp, err := client.GetWithRetry(asset.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp.StatusCode, "al descargar %s", checksumsAsset)
+	}
+
+	// Cada línea es "<sha256>  <archivo>"; el nombre puede venir con * (modo binario de sha256sum)
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == assetName {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("%s no tiene el checksum de %s", checksumsAsset, assetName)
+}
+
+// downloadVerified descarga el asset a un archivo temporal en dir y verifica su sha256 antes de devolverlo
+func downloadVerified(asset ReleaseAsset, checksum, dir string, client QuoteFetcher) (string, error) {
+	resp, err := client.GetWithRetry(asset.URL, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", statusError(resp.StatusCode, "al descargar %s", asset.Name)
+	}
+
+	// El temporal va junto al ejecutable para que el reemplazo sea un rename dentro del mismo filesystem
+	tmp, err := os.CreateTemp(dir, ".bolsa-update-*")
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	_, err = io.Copy(io.MultiWriter(tmp, hash), resp.Body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("error al descargar %s: %v", asset.Name, err)
+	}
+
+	if sum := hex.EncodeToString(hash.Sum(nil)); sum != checksum {
+		os.Remove(tmp.Name())
+		return "", fmt.Errorf("checksum inválido para %s: se esperaba %s y se descargó %s", asset.Name, checksum, sum)
+	}
+	if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+// replaceExecutable reemplaza el binario en ejecución por el descargado
+func replaceExecutable(exe, newPath string) error {
+	// Windows no deja sobrescribir un ejecutable abierto pero sí renombrarlo: se aparta el viejo primero
+	if runtime.GOOS == "windows" {
+		old := exe + ".old"
+		os.Remove(old)
+		if err := os.Rename(exe, old); err != nil {
+			return err
+		}
+		if err := os.Rename(newPath, exe); err != nil {
+			os.Rename(old, exe)
+			return err
+		}
+		return nil
+	}
+	return os.Rename(newPath, exe)
+}
+
+// runSelfUpdate implementa `bolsa self-update`: descarga el binario del último release y reemplaza al actual
+func runSelfUpdate(args []string) error {
+	fs := flag.NewFlagSet("self-update", flag.ExitOnError)
+	check := fs.Bool("check", false, "solo informar si hay una versión nueva, sin descargarla")
+	tag := fs.String("version", "", "instalar este release (por ejemplo v1.4.0) en lugar del último")
+	force := fs.Bool("force", false, "reinstalar aunque la versión sea la misma")
+	fs.Parse(args)
+
+	client := NewProviderClient("github")
+	release, err := fetchRelease(*tag, client)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Versión instalada: %s\n", version)
+	fmt.Printf("Versión disponible: %s\n", release.Tag)
+	if release.Tag == version && !*force {
+		fmt.Printf("%sYa tenés la última versión.%s\n", Green, Reset)
+		return nil
+	}
+	if *check {
+		fmt.Println("Actualizá con: bolsa self-update")
+		return nil
+	}
+
+	name := platformAssetName()
+	asset, ok := release.asset(name)
+	if !ok {
+		return fmt.Errorf("el release %s no tiene binario para %s/%s (%s)", release.Tag, runtime.GOOS, runtime.GOARCH, name)
+	}
+	checksum, err := releaseChecksum(release, name, client)
+	if err != nil {
+		return err
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("no se pudo ubicar el ejecutable actual: %v", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("no se pudo ubicar el ejecutable actual: %v", err)
+	}
+
+	fmt.Printf("Descargando %s (%.1f MB)...\n", name, float64(asset.Size)/(1<<20))
+	downloaded, err := downloadVerified(asset, checksum, filepath.Dir(exe), client)
+	if err != nil {
+		return err
+	}
+	if err := replaceExecutable(exe, downloaded); err != nil {
+		os.Remove(downloaded)
+		return fmt.Errorf("no se pudo reemplazar %s: %v", exe, err)
+	}
+
+	fmt.Printf("%s✅ bolsa actualizado a %s (sha256 verificado)%s\n", Green, release.Tag, Reset)
+	return nil
+}
+
+// semver es una versión major.minor.patch con su prerelease opcional (v1.4.0-rc.1); el build (+...) no cuenta
+type semver struct {
+	core       [3]int
+	prerelease []string
+}
+
+// parseSemver interpreta una versión con o sin la v inicial
+func parseSemver(v string) (semver, bool) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "+")
+	core, pre, hasPre := strings.Cut(v, "-")
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return semver{}, false
+	}
+	var parsed semver
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return semver{}, false
+		}
+		parsed.core[i] = n
+	}
+	if hasPre {
+		if pre == "" {
+			return semver{}, false
+		}
+		parsed.prerelease = strings.Split(pre, ".")
+	}
+	return parsed, true
+}
+
+// compare devuelve -1, 0 o 1 según la precedencia de semver: una prerelease es anterior a su versión final y
+// sus identificadores numéricos se comparan como números y van antes que los alfanuméricos
+func (a semver) compare(b semver) int {
+	for i := range a.core {
+		if a.core[i] != b.core[i] {
+			if a.core[i] < b.core[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a.prerelease) == 0 && len(b.prerelease) == 0:
+		return 0
+	case len(a.prerelease) == 0:
+		return 1
+	case len(b.prerelease) == 0:
+		return -1
+	}
+	for i := 0; i < len(a.prerelease) && i < len(b.prerelease); i++ {
+		x, y := a.prerelease[i], b.prerelease[i]
+		nx, errX := strconv.Atoi(x)
+		ny, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil:
+			if nx != ny {
+				if nx < ny {
+					return -1
+				}
+				return 1
+			}
+		case errX == nil:
+			return -1
+		case errY == nil:
+			return 1
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a.prerelease) < len(b.prerelease):
+		return -1
+	case len(a.prerelease) > len(b.prerelease):
+		return 1
+	}
+	return 0
+}
+
+// newerVersion informa si candidate es estrictamente posterior a current; si alguna no es semver no lo es
+func newerVersion(candidate, current string) bool {
+	c, ok := parseSemver(candidate)
+	if !ok {
+		return false
+	}
+	cur, ok := parseSemver(current)
+	if !ok {
+		return false
+	}
+	return c.compare(cur) > 0
+}
+
+// checkForUpdate avisa al iniciar el monitor si hay un release más nuevo; consulta GitHub como mucho una vez por día
+func checkForUpdate() {
+	if version == "dev" {
+		return
+	}
+
+	path, err := appFile(updateCheckFile)
+	if err != nil {
+		return
+	}
+	if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) < 24*time.Hour {
+		return
+	}
+	os.WriteFile(path, []byte(time.Now().Format(time.RFC3339)), 0o644)
+
+	release, err := fetchRelease("", NewProviderClient("github"))
+	if err != nil {
+		debugf("No se pudo consultar si hay actualizaciones: %v\n", err)
+		return
+	}
+	// Un binario más nuevo que el último release (o un release con otro formato de tag) no se anuncia
+	if newerVersion(release.Tag, version) {
+		fmt.Printf("%sHay una versión nueva de bolsa (%s, tenés %s): actualizá con `bolsa self-update`%s\n", Yellow, release.Tag, version, Reset)
+	}
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestNewerVersion(t *testing.T) {
+	tests := []struct {
+		candidate, current string
+		want               bool
+	}{
+		{"v1.4.0", "v1.3.9", true},
+		{"v1.10.0", "v1.9.0", true},
+		{"v2.0.0", "v1.99.99", true},
+		{"1.4.1", "v1.4.0", true},
+		{"v1.4.0", "v1.4.0", false},
+		{"v1.3.0", "v1.4.0", false},
+		{"v1.4.0", "v1.4.0-rc.2", true},
+		{"v1.4.0-rc.2", "v1.4.0", false},
+		{"v1.4.0-rc.10", "v1.4.0-rc.2", true},
+		{"v1.4.0-rc.1", "v1.4.0-beta", true},
+		{"v1.4.0-beta.1", "v1.4.0-beta", true},
+		{"v1.4.0-1", "v1.4.0-alpha", false},
+		{"v1.4.0+build.5", "v1.4.0", false},
+		{"v1.4.0", "dev", false},
+		{"latest", "v1.4.0", false},
+		{"v1.4", "v1.3.0", false},
+		{"v1.4.0-", "v1.3.0", false},
+	}
+	for _, tt := range tests {
+		if got := newerVersion(tt.candidate, tt.current); got != tt.want {
+			t.Errorf("newerVersion(%q, %q) = %v, se esperaba %v", tt.candidate, tt.current, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// Dirección por defecto del servidor de snapshots; solo local, los clientes remotos entran por ssh
+const defaultServerAddr = "127.0.0.1:7070"
+
+// Tiempo máximo para entregarle un snapshot a un cliente antes de desconectarlo
+const hubWriteTimeout = 5 * time.Second
+
+// snapshotHub distribuye cada snapshot a todos los clientes conectados como una línea JSON
+type snapshotHub struct {
+	mu       sync.Mutex
+	clients  map[*hubClient]struct{}
+	last     []byte
+	snapshot *Snapshot // El último difundido, para el dashboard web
+}
+
+// hubClient es un cliente conectado; su goroutine de escritura le manda los snapshots de a uno, así un
+// cliente lento no demora a los demás
+type hubClient struct {
+	conn net.Conn
+	send chan []byte // Capacidad 1: solo importa el snapshot más reciente
+}
+
+func newSnapshotHub() *snapshotHub {
+	return &snapshotHub{clients: make(map[*hubClient]struct{})}
+}
+
+// broadcast encola el snapshot para todos los clientes; a los que todavía no recibieron el anterior se les
+// reemplaza por este
+func (h *snapshotHub) broadcast(snapshot *Snapshot) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		fmt.Printf("Error al codificar el snapshot: %v\n", err)
+		return
+	}
+	data = append(data, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.last = data
+	h.snapshot = snapshot
+	for c := range h.clients {
+		c.queue(data)
+	}
+}
+
+// latest devuelve el último snapshot difundido, o nil si todavía no hubo ninguno
+func (h *snapshotHub) latest() *Snapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.snapshot
+}
+
+// queue deja data como el próximo snapshot a enviar, descartando el pendiente si lo hay.
+// Se llama con h.mu tomado, así que nadie más encola entre el descarte y el envío
+func (c *hubClient) queue(data []byte) {
+	select {
+	case <-c.send:
+	default:
+	}
+	c.send <- data
+}
+
+// write envía los snapshots encolados al cliente hasta que falle una escritura
+func (h *snapshotHub) write(c *hubClient) {
+	for data := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(hubWriteTimeout))
+		if _, err := c.conn.Write(data); err != nil {
+			fmt.Printf("Cliente %s desconectado: %v\n", c.conn.RemoteAddr(), err)
+			h.mu.Lock()
+			delete(h.clients, c)
+			h.mu.Unlock()
+			c.conn.Close()
+			return
+		}
+	}
+}
+
+// serve acepta clientes y les envía de inmediato el último snapshot disponible
+func (h *snapshotHub) serve(listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			fmt.Printf("Error al aceptar conexión: %v\n", err)
+			return
+		}
+
+		c := &hubClient{conn: conn, send: make(chan []byte, 1)}
+		h.mu.Lock()
+		h.clients[c] = struct{}{}
+		count := len(h.clients)
+		if h.last != nil {
+			c.queue(h.last)
+		}
+		h.mu.Unlock()
+		go h.write(c)
+
+		fmt.Printf("Cliente conectado desde %s (%d conectados)\n", conn.RemoteAddr(), count)
+	}
+}
+
+// runServe implementa `bolsa serve`: un único proceso hace el fetch y lo comparte con las terminales conectadas
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("listen", defaultServerAddr, "dirección donde escuchan los clientes de `bolsa attach`")
+	gapThreshold := fs.Float64("gap", 3, "alertar en la apertura los papeles con gap mayor a este porcentaje (0 desactiva)")
+	recordDir := fs.String("record", "", "guardar cada respuesta HTTP cruda con sus metadatos en este directorio")
+	pprofAddr := fs.String("pprof", "", "exponer net/http/pprof en esta dirección (por ejemplo :6060)")
+	debug := fs.Bool("debug", false, "mostrar el detalle de cada request (headers, reintentos); sin él solo se muestra un resumen por ciclo")
+	apiAddr := fs.String("http", "", "exponer la API HTTP (/api/stats/{símbolo}) y el dashboard web (/ y /m) en esta dirección (por ejemplo 127.0.0.1:7071)")
+	follow := fs.Bool("follow", false, "no consultar proveedores: servir los snapshots que otro proceso publica en Redis")
+	fs.Parse(args)
+
+	if *debug {
+		debugLogging = true
+	}
+
+	if *pprofAddr != "" {
+		if err := startPprof(*pprofAddr); err != nil {
+			return err
+		}
+	}
+
+	hub := newSnapshotHub()
+	if cached, err := loadLastSnapshot(); err == nil && cached != nil {
+		// Los clientes que se conectan antes del primer ciclo reciben la última tabla conocida
+		hub.broadcast(cached)
+	}
+
+	if *apiAddr != "" {
+		if err := startAPI(*apiAddr, hub); err != nil {
+			return err
+		}
+	}
+
+	if *recordDir != "" {
+		if err := enableRecording(*recordDir); err != nil {
+			return err
+		}
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return fmt.Errorf("no se pudo escuchar en %s: %v", *addr, err)
+	}
+	defer listener.Close()
+
+	applyWatchedSymbols()
+	applyUserWatchlists()
+	client := NewHTTPClient()
+	if err := loadSessionRanges(); err != nil {
+		fmt.Printf("No se pudieron cargar los rangos de sesión: %v\n", err)
+	}
+	bonds, err := loadBonds()
+	if err != nil {
+		fmt.Printf("Error al cargar bonos personalizados: %v\n", err)
+	}
+
+	go hub.serve(listener)
+
+	pipeline := NewPipeline(client, bonds, configuredNotifiers(), NewGapWatcher(*gapThreshold))
+	pipeline.OnSnapshot(hub.broadcast)
+	pipeline.OnSnapshot(evaluateUserAlerts)
+	startTelegramBot(pipeline, client)
+	if *follow {
+		if err := pipeline.Follow(); err != nil {
+			return err
+		}
+	}
+	go pipeline.Run()
+
+	fmt.Printf("Servidor de cotizaciones escuchando en %s\n", *addr)
+	fmt.Println("Conectá terminales con: bolsa attach --addr", *addr, "--view all|forex|stocks|bonds")
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, shutdownSignals()...)
+	<-sigChan
+	leaderLock.Release()
+	fmt.Println("\nServidor finalizado.")
+	return nil
+}
+
+// runAttach implementa `bolsa attach`: muestra una vista de los datos de un servidor sin hacer requests propios
+func runAttach(args []string) error {
+	fs := flag.NewFlagSet("attach", flag.ExitOnError)
+	addr := fs.String("addr", defaultServerAddr, "dirección del servidor iniciado con `bolsa serve`")
+	viewName := fs.String("view", "all", "vista a mostrar: all, forex, stocks, bonds, funds, commodities o heatmap")
+	rotate := fs.Duration("rotate", 0, "pasar sola a la siguiente página de acciones cada este intervalo (por ejemplo 10s)")
+	fs.Parse(args)
+
+	view, err := parseView(*viewName)
+	if err != nil {
+		return err
+	}
+
+	go watchResize(redrawScreen)
+	if *rotate > 0 {
+		go rotateStockPages(*rotate)
+	}
+	for {
+		if err := attachOnce(*addr, view); err != nil {
+			fmt.Printf("%sConexión con %s perdida: %v. Reintentando en 5 segundos...%s\n", Red, *addr, err, Reset)
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// attachOnce se conecta al servidor y muestra cada snapshot recibido hasta que se corte la conexión
+func attachOnce(addr string, view View) error {
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	fmt.Printf("Conectado a %s, esperando datos...\n", addr)
+
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var snapshot Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &snapshot); err != nil {
+			fmt.Printf("Snapshot inválido: %v\n", err)
+			continue
+		}
+		setSessionRanges(snapshot.Ranges)
+		displayData(&snapshot, view)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("el servidor cerró la conexión")
+}
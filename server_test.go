@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"testing"
+	"time"
+)
+
+// hubPipe conecta un cliente al hub por un net.Pipe, que no tiene buffer: si nadie lee, la escritura se bloquea
+func hubPipe(t *testing.T, h *snapshotHub) *bufio.Reader {
+	t.Helper()
+	server, client := net.Pipe()
+	t.Cleanup(func() { client.Close() })
+	c := &hubClient{conn: server, send: make(chan []byte, 1)}
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+	go h.write(c)
+	return bufio.NewReader(client)
+}
+
+// readSymbol lee un snapshot del cliente y devuelve el símbolo de su primera cotización
+func readSymbol(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	line, err := r.ReadBytes('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(line, &snapshot); err != nil {
+		t.Fatal(err)
+	}
+	return snapshot.Stocks[0].Symbol
+}
+
+func TestSnapshotHubSlowClient(t *testing.T) {
+	h := newSnapshotHub()
+	slow := hubPipe(t, h)
+	fast := hubPipe(t, h)
+
+	// El cliente lento no lee nada: broadcast no puede quedar esperándolo
+	start := time.Now()
+	for _, symbol := range []string{"S1", "S2", "S3"} {
+		h.broadcast(&Snapshot{Stocks: []StockInfo{{Symbol: symbol}}})
+		if got := readSymbol(t, fast); got != symbol {
+			t.Errorf("el cliente rápido recibió %s, se esperaba %s", got, symbol)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("broadcast tardó %v con un cliente que no lee", elapsed)
+	}
+
+	// El lento recibe a lo sumo el snapshot que ya se le estaba escribiendo y después el más reciente: los
+	// intermedios se descartan
+	var received []string
+	for len(received) == 0 || received[len(received)-1] != "S3" {
+		received = append(received, readSymbol(t, slow))
+	}
+	if len(received) > 2 {
+		t.Errorf("el cliente lento recibió %v, se esperaba que se descarten los intermedios", received)
+	}
+}
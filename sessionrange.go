@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// SessionRange representa el mínimo y máximo que el programa vio para un símbolo durante el día
+type SessionRange struct {
+	Low       float64   `json:"low"`
+	High      float64   `json:"high"`
+	FirstSeen time.Time `json:"firstSeen"`
+	LastSeen  time.Time `json:"lastSeen"`
+}
+
+// sessionRangeStore agrupa los rangos de un día, tal como se persisten en disco
+type sessionRangeStore struct {
+	Date   string                   `json:"date"`
+	Ranges map[string]*SessionRange `json:"ranges"`
+}
+
+// sessionRanges guarda los rangos propios por símbolo, independientes del DayHigh/Low de Yahoo
+var (
+	sessionRanges   = sessionRangeStore{Ranges: make(map[string]*SessionRange)}
+	sessionRangesMu sync.Mutex
+)
+
+const sessionRangesFile = "session_ranges.json"
+
+// recordPrice actualiza el mínimo/máximo de sesión de un símbolo con un nuevo precio observado
+func recordPrice(symbol string, price float64) {
+	if price <= 0 {
+		return
+	}
+
+	sessionRangesMu.Lock()
+	defer sessionRangesMu.Unlock()
+
+	// Los rangos son intradiarios: al cambiar el día empezamos de nuevo
+	today := time.Now().Format("2006-01-02")
+	if sessionRanges.Date != today {
+		sessionRanges.Date = today
+		sessionRanges.Ranges = make(map[string]*SessionRange)
+	}
+
+	now := time.Now()
+	r, ok := sessionRanges.Ranges[symbol]
+	if !ok {
+		sessionRanges.Ranges[symbol] = &SessionRange{Low: price, High: price, FirstSeen: now, LastSeen: now}
+		return
+	}
+
+	if price < r.Low {
+		r.Low = price
+	}
+	if price > r.High {
+		r.High = price
+	}
+	r.LastSeen = now
+}
+
+// getSessionRange devuelve el rango de sesión registrado para un símbolo
+func getSessionRange(symbol string) (SessionRange, bool) {
+	sessionRangesMu.Lock()
+	defer sessionRangesMu.Unlock()
+
+	r, ok := sessionRanges.Ranges[symbol]
+	if !ok {
+		return SessionRange{}, false
+	}
+	return *r, true
+}
+
+// trackSessionRanges registra los precios de un ciclo de actualización y persiste el resultado
+func trackSessionRanges(forexData []ForexInfo, stocksData []StockInfo) {
+	for _, forex := range forexData {
+		recordPrice(forex.Symbol, forex.Price)
+	}
+	for _, stock := range stocksData {
+		recordPrice(stock.Symbol, stock.Price)
+	}
+
+	if err := saveSessionRanges(); err != nil {
+		fmt.Printf("Error al guardar los rangos de sesión: %v\n", err)
+	}
+}
+
+// loadSessionRanges recupera los rangos guardados si corresponden al día de hoy
+func loadSessionRanges() error {
+	path, err := appFile(sessionRangesFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	sessionRangesMu.Lock()
+	defer sessionRangesMu.Unlock()
+
+	if err := json.Unmarshal(data, &sessionRanges); err != nil {
+		return fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	if sessionRanges.Ranges == nil || sessionRanges.Date != time.Now().Format("2006-01-02") {
+		sessionRanges.Date = ""
+		sessionRanges.Ranges = make(map[string]*SessionRange)
+	}
+	return nil
+}
+
+// saveSessionRanges escribe los rangos de sesión a disco
+func saveSessionRanges() error {
+	path, err := appFile(sessionRangesFile)
+	if err != nil {
+		return err
+	}
+
+	sessionRangesMu.Lock()
+	data, err := json.MarshalIndent(&sessionRanges, "", "  ")
+	sessionRangesMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o644)
+}
+
+// sessionRangesSnapshot devuelve una copia de los rangos de sesión para enviarla a otros procesos
+func sessionRangesSnapshot() map[string]SessionRange {
+	sessionRangesMu.Lock()
+	defer sessionRangesMu.Unlock()
+
+	ranges := make(map[string]SessionRange, len(sessionRanges.Ranges))
+	for symbol, r := range sessionRanges.Ranges {
+		ranges[symbol] = *r
+	}
+	return ranges
+}
+
+// setSessionRanges reemplaza los rangos locales por los recibidos de un servidor
+func setSessionRanges(ranges map[string]SessionRange) {
+	sessionRangesMu.Lock()
+	defer sessionRangesMu.Unlock()
+
+	sessionRanges.Ranges = make(map[string]*SessionRange, len(ranges))
+	for symbol, r := range ranges {
+		sessionRanges.Ranges[symbol] = &r
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FINRA publica el short interest dos veces por mes: pasado este plazo desde la fecha del dato se espera uno nuevo
+const shortInterestPeriod = 15 * 24 * time.Hour
+
+const shortInterestFile = "short_interest.json"
+
+// ShortInterest son las posiciones cortas de un ADR según el último reporte de FINRA (vía Yahoo statistics)
+type ShortInterest struct {
+	Symbol        string    `json:"symbol"`
+	SharesShort   float64   `json:"sharesShort"`
+	PriorShares   float64   `json:"sharesShortPriorMonth"`
+	PercentFloat  float64   `json:"shortPercentOfFloat"` // En %
+	DaysToCover   float64   `json:"shortRatio"`          // Acciones en corto sobre volumen promedio diario
+	ReportDate    time.Time `json:"reportDate"`          // Fecha de liquidación del reporte de FINRA
+	PriorReported time.Time `json:"priorReportDate"`
+	Fetched       time.Time `json:"fetched"`
+}
+
+// Change devuelve la variación % de las acciones en corto contra el reporte del mes anterior
+func (s ShortInterest) Change() (float64, bool) {
+	if s.PriorShares <= 0 {
+		return 0, false
+	}
+	return (s.SharesShort/s.PriorShares - 1) * 100, true
+}
+
+// Pressure describe la presión bajista según el porcentaje del float en corto y su tendencia
+func (s ShortInterest) Pressure() (string, string) {
+	change, _ := s.Change()
+	switch {
+	case s.PercentFloat >= 10 || (s.PercentFloat >= 5 && change >= 20):
+		return "alta", Red
+	case s.PercentFloat >= 3 || change >= 20:
+		return "moderada", Yellow
+	}
+	return "baja", Green
+}
+
+// reportDay devuelve la fecha del reporte como texto, o "s/f" si Yahoo no la informa
+func (s ShortInterest) reportDay() string {
+	if s.ReportDate.IsZero() {
+		return "s/f"
+	}
+	return s.ReportDate.Format("2006-01-02")
+}
+
+// stale indica si corresponde volver a consultar: ya debería haber un reporte nuevo y no se consultó hoy
+func (s ShortInterest) stale(now time.Time) bool {
+	if now.Sub(s.Fetched) < 24*time.Hour {
+		return false
+	}
+	return s.ReportDate.IsZero() || now.Sub(s.ReportDate) > shortInterestPeriod
+}
+
+var (
+	shortInterestMu    sync.Mutex
+	shortInterestCache map[string]ShortInterest
+)
+
+// loadShortInterestCache lee los datos guardados la primera vez que se necesitan
+func loadShortInterestCache() {
+	if shortInterestCache != nil {
+		return
+	}
+	shortInterestCache = make(map[string]ShortInterest)
+
+	path, err := appFile(shortInterestFile)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	if err := json.Unmarshal(data, &shortInterestCache); err != nil {
+		fmt.Printf("Error al leer %s: %v\n", path, err)
+	}
+}
+
+// saveShortInterestCache persiste los datos para no consultarlos de nuevo hasta el próximo reporte
+func saveShortInterestCache() error {
+	path, err := appFile(shortInterestFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(shortInterestCache, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// getShortInterest devuelve el short interest de un ADR, consultándolo solo cuando hay un reporte nuevo
+func getShortInterest(symbol string, client QuoteFetcher) (ShortInterest, error) {
+	shortInterestMu.Lock()
+	defer shortInterestMu.Unlock()
+	loadShortInterestCache()
+
+	now := time.Now()
+	cached, ok := shortInterestCache[symbol]
+	if ok && !cached.stale(now) {
+		return cached, nil
+	}
+
+	fetched, err := fetchShortInterest(symbol, client)
+	if err != nil {
+		// Ante un error se sigue mostrando el último dato conocido
+		if ok {
+			return cached, nil
+		}
+		return ShortInterest{}, err
+	}
+	fetched.Fetched = now
+	shortInterestCache[symbol] = fetched
+	if err := saveShortInterestCache(); err != nil {
+		fmt.Printf("Error al guardar el short interest: %v\n", err)
+	}
+	return fetched, nil
+}
+
+// fetchShortInterest consulta el módulo defaultKeyStatistics de Yahoo, que replica los reportes de FINRA
+func fetchShortInterest(symbol string, client QuoteFetcher) (ShortInterest, error) {
+	statsURL := fmt.Sprintf("https://query2.finance.yahoo.com/v10/finance/quoteSummary/%s?modules=defaultKeyStatistics",
+		url.PathEscape(symbol))
+	headers := map[string]string{
+		"User-Agent": "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/108.0.0.0 Safari/537.36",
+		"Accept":     "application/json",
+		"Referer":    "https://finance.yahoo.com/",
+	}
+
+	resp, err := client.GetWithRetry(statsURL, headers)
+	if err != nil {
+		return ShortInterest{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ShortInterest{}, statusError(resp.StatusCode, "para las estadísticas de %s", symbol)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ShortInterest{}, err
+	}
+
+	var statsResp struct {
+		QuoteSummary struct {
+			Result []struct {
+				Stats struct {
+					SharesShort           FlexFloat `json:"sharesShort"`
+					SharesShortPriorMonth FlexFloat `json:"sharesShortPriorMonth"`
+					ShortPercentOfFloat   FlexFloat `json:"shortPercentOfFloat"`
+					ShortRatio            FlexFloat `json:"shortRatio"`
+					DateShortInterest     FlexFloat `json:"dateShortInterest"`
+					SharesShortPriorDate  FlexFloat `json:"sharesShortPreviousMonthDate"`
+				} `json:"defaultKeyStatistics"`
+			} `json:"result"`
+		} `json:"quoteSummary"`
+	}
+	if err := json.Unmarshal(body, &statsResp); err != nil {
+		return ShortInterest{}, schemaError("defaultKeyStatistics", symbol, body, err.Error())
+	}
+	if len(statsResp.QuoteSummary.Result) == 0 || !statsResp.QuoteSummary.Result[0].Stats.SharesShort.Valid {
+		return ShortInterest{}, fmt.Errorf("%s no tiene datos de short interest (solo se publican para ADRs y acciones de EE.UU.)", symbol)
+	}
+
+	stats := statsResp.QuoteSummary.Result[0].Stats
+	si := ShortInterest{
+		Symbol:       symbol,
+		SharesShort:  stats.SharesShort.Value,
+		PriorShares:  stats.SharesShortPriorMonth.Value,
+		PercentFloat: stats.ShortPercentOfFloat.Value * 100,
+		DaysToCover:  stats.ShortRatio.Value,
+	}
+	if stats.DateShortInterest.Valid {
+		si.ReportDate = time.Unix(stats.DateShortInterest.Int(), 0).UTC()
+	}
+	if stats.SharesShortPriorDate.Valid {
+		si.PriorReported = time.Unix(stats.SharesShortPriorDate.Int(), 0).UTC()
+	}
+	return si, nil
+}
+
+// displayShortInterest muestra el bloque de short interest del panel de detalle
+func displayShortInterest(si ShortInterest) {
+	pressure, color := si.Pressure()
+	fmt.Printf("\n%sPosiciones cortas (FINRA, %s)%s\n", Cyan, si.reportDay(), Reset)
+	fmt.Printf("  Acciones en corto: %s", formatCompact(si.SharesShort))
+	if change, ok := si.Change(); ok {
+		fmt.Printf(" (%s%+.1f%%%s vs. %s)", variationColor(-change), change, Reset, formatCompact(si.PriorShares))
+	}
+	fmt.Println()
+	fmt.Printf("  %% del float: %.2f%%   Días para cubrir: %.1f\n", si.PercentFloat, si.DaysToCover)
+	fmt.Printf("  Presión bajista: %s%s%s\n", color, pressure, Reset)
+}
+
+// displayDetail muestra el panel de detalle de un símbolo con los datos del último snapshot y el short interest
+func displayDetail(symbol string, client QuoteFetcher) {
+	symbol = strings.ToUpper(symbol)
+	fmt.Printf("\n%s=== DETALLE DE %s ===%s\n", Cyan, symbol, Reset)
+
+	if lastScreen != nil {
+		for _, stock := range lastScreen.Stocks {
+			if stock.Symbol != symbol {
+				continue
+			}
+			fmt.Printf("%s (%s)\n", stock.Name, stock.Market)
+			if description := companyDescription(symbol); description != "" {
+				fmt.Printf("  %s\n", description)
+			}
+			fmt.Printf("  Precio: $%.2f %s%+.2f (%+.2f%%)%s   Vol: %d\n",
+				stock.Price, variationColor(stock.ChangePercent), stock.Change, stock.ChangePercent, Reset, stock.Volume)
+			if r, ok := getSessionRange(symbol); ok {
+				fmt.Printf("  %sSesión: %.2f - %.2f%s\n", Blue, r.Low, r.High, Reset)
+			}
+			if rvol, ok := rvols.Value(symbol); ok {
+				fmt.Printf("  Volumen relativo: %.2fx el promedio de %d ruedas a esta hora\n", rvol, rvolDays)
+			}
+			if len(stock.Sources) > 0 {
+				fmt.Printf("  Fuentes: %s\n", sourcesText(stock.Sources))
+			}
+		}
+	}
+	if risk, ok := getRiskProfile(symbol); ok {
+		fmt.Printf("  Riesgo: %s%s%s (score %.0f; volatilidad %.1f%%, DD máximo %.1f%%)\n",
+			riskColor(risk.Level), risk.Level, Reset, risk.Score, risk.Volatility, risk.MaxDD)
+	}
+
+	si, err := getShortInterest(symbol, client)
+	if err != nil {
+		fmt.Printf("\n%sPosiciones cortas: %v%s\n", Yellow, err, Reset)
+		return
+	}
+	displayShortInterest(si)
+}
+
+// runShort implementa `bolsa short [SIMBOLOS...]`: short interest de los ADRs de la watchlist
+func runShort(args []string) error {
+	fs := flag.NewFlagSet("short", flag.ExitOnError)
+	fs.Parse(args)
+
+	symbols := fs.Args()
+	if len(symbols) == 0 {
+		for _, stock := range watchlistSnapshot() {
+			if classifySymbol(stock.Symbol, stock.Market) == AssetADR {
+				symbols = append(symbols, stock.Symbol)
+			}
+		}
+	}
+
+	client := NewHTTPClient()
+	var results []ShortInterest
+	for _, symbol := range symbols {
+		si, err := getShortInterest(strings.ToUpper(symbol), client)
+		if err != nil {
+			fmt.Printf("%s%s: %v%s\n", Red, strings.ToUpper(symbol), err, Reset)
+			continue
+		}
+		results = append(results, si)
+	}
+	if len(results) == 0 {
+		return fmt.Errorf("no se obtuvo el short interest de ningún símbolo")
+	}
+
+	fmt.Printf("\n%s=== POSICIONES CORTAS DE ADRs (FINRA) ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-8s %12s %9s %9s %8s %-11s %s\n", "Símbolo", "En corto", "Var mes", "% float", "Días", "Reporte", "Presión")
+	for _, si := range results {
+		change := "-"
+		if c, ok := si.Change(); ok {
+			change = fmt.Sprintf("%+.1f%%", c)
+		}
+		pressure, color := si.Pressure()
+		fmt.Printf("%-8s %12s %9s %8.2f%% %8.1f %-11s %s%s%s\n",
+			si.Symbol, formatCompact(si.SharesShort), change, si.PercentFloat, si.DaysToCover,
+			si.reportDay(), color, pressure, Reset)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Snapshot representa el resultado completo de un ciclo de actualización
+type Snapshot struct {
+	Time    time.Time               `json:"time"`
+	Forex   []ForexInfo             `json:"forex"`
+	Stocks  []StockInfo             `json:"stocks"`
+	Bonds   []BondQuote             `json:"bonds"`
+	Funds   []FundQuote             `json:"funds,omitempty"`
+	Ranges  map[string]SessionRange `json:"ranges"`
+	Status  MarketStatus            `json:"status"`
+	Merval  *IndexQuote             `json:"merval,omitempty"`
+	Errors  []FetchError            `json:"errors,omitempty"`  // Consultas fallidas del ciclo
+	Quality *CycleQuality           `json:"quality,omitempty"` // Símbolos actualizados, antigüedad de los datos y proveedores
+
+	MarketCaps  map[string]float64     `json:"market_caps,omitempty"`  // En pesos, para el mapa de calor
+	Risk        map[string]RiskProfile `json:"risk,omitempty"`         // Score de riesgo por símbolo
+	OrderBooks  map[string]OrderBook   `json:"order_books,omitempty"`  // Mejores puntas de los papeles de BYMA (IOL)
+	Carry       *CarryPanel            `json:"carry,omitempty"`        // Caución cubierta con futuros de dólar
+	Equilibrium *EquilibriumRate       `json:"equilibrium,omitempty"`  // Tipo de cambio de equilibrio con datos del BCRA
+	Commodities []CommodityQuote       `json:"commodities,omitempty"`  // Granos, petróleo, oro y litio
+	ExportRates []ExportRate           `json:"export_rates,omitempty"` // Dólar exportador de los programas vigentes
+
+	// Símbolos que solo siguen usuarios de la API (/api/me/watchlist): no se muestran en las tablas ni se envían a las terminales
+	UserStocks []StockInfo `json:"-"`
+
+	Cached bool `json:"cached,omitempty"` // Snapshot de la sesión anterior, mostrado mientras llega el primer ciclo
+}
+
+// View selecciona qué secciones del snapshot se muestran en una terminal
+type View string
+
+const (
+	ViewAll    View = "all"
+	ViewForex  View = "forex"
+	ViewStocks View = "stocks"
+	ViewBonds  View = "bonds"
+	ViewFunds  View = "funds"
+
+	// ViewCommodities muestra granos, petróleo, oro y litio
+	ViewCommodities View = "commodities"
+
+	// ViewHeatmap reemplaza las tablas por el mapa de calor de las acciones
+	ViewHeatmap View = "heatmap"
+
+	// viewRemote es la vista completa en un cliente conectado, sin búsqueda interactiva
+	viewRemote View = "remote"
+
+	// viewLocalHeatmap es el mapa de calor en el monitor local, que sigue aceptando comandos
+	viewLocalHeatmap View = "local-heatmap"
+)
+
+// Includes indica si la vista muestra la sección indicada
+func (v View) Includes(section View) bool {
+	return v == ViewAll || v == viewRemote || v == section
+}
+
+// Interactive indica si la vista acepta comandos de teclado
+func (v View) Interactive() bool {
+	return v == ViewAll || v == viewLocalHeatmap
+}
+
+// Heatmap indica si la vista es el mapa de calor
+func (v View) Heatmap() bool {
+	return v == ViewHeatmap || v == viewLocalHeatmap
+}
+
+var (
+	monitorViewMu sync.Mutex
+	monitorView   = ViewAll
+)
+
+// currentMonitorView devuelve la vista activa del monitor local
+func currentMonitorView() View {
+	monitorViewMu.Lock()
+	defer monitorViewMu.Unlock()
+	return monitorView
+}
+
+// toggleHeatmap alterna el monitor local entre las tablas y el mapa de calor
+func toggleHeatmap() View {
+	monitorViewMu.Lock()
+	defer monitorViewMu.Unlock()
+	if monitorView == viewLocalHeatmap {
+		monitorView = ViewAll
+	} else {
+		monitorView = viewLocalHeatmap
+	}
+	return monitorView
+}
+
+// parseView valida el nombre de una vista
+func parseView(name string) (View, error) {
+	switch View(name) {
+	case ViewAll:
+		return viewRemote, nil
+	case ViewForex, ViewStocks, ViewBonds, ViewFunds, ViewCommodities, ViewHeatmap:
+		return View(name), nil
+	}
+	return "", fmt.Errorf("vista desconocida: %s (usar all, forex, stocks, bonds, funds, commodities o heatmap)", name)
+}
+
+// fetchSnapshot obtiene forex, acciones y bonos en un ciclo de actualización.
+// Cada sección se arma con lo que se haya podido obtener y las consultas fallidas quedan en
+// Snapshot.Errors; el ciclo solo falla si no se obtuvo ningún dato
+func fetchSnapshot(client QuoteFetcher, bonds []*Bond) (*Snapshot, error) {
+	fmt.Println("\n=== INICIANDO CICLO DE ACTUALIZACIÓN ===")
+	// Cada ciclo consulta los símbolos de nuevo, una sola vez aunque aparezcan en varias secciones
+	if saved := tickerCache.Reset(); saved > 0 {
+		fmt.Printf("Consultas duplicadas evitadas en el ciclo anterior: %d\n", saved)
+	}
+
+	errs := &fetchErrors{}
+	resetCycleLog()
+
+	// Obtener datos de forex primero para tener la tasa de cambio
+	fmt.Println("Obteniendo datos de FOREX...")
+	// Las consultas que no arrancaron antes del timeout del watchdog ya no tienen sentido en este ciclo
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(appConfig().WatchdogTimeout))
+	defer cancel()
+
+	forexData, err := getForexData(ctx, client, errs)
+	if err != nil {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+
+	fmt.Printf("Se obtuvieron %d registros de FOREX\n", len(forexData))
+
+	// Obtener tasa de cambio del dólar si está disponible
+	var dolarRate float64
+	for _, forex := range forexData {
+		if strings.Contains(forex.Name, "Dólar Oficial") {
+			dolarRate = forex.Price
+			fmt.Printf("Tasa de cambio del dólar: %.2f\n", dolarRate)
+			break
+		}
+	}
+
+	if dolarRate == 0 {
+		fmt.Println("⚠️ No se pudo obtener la tasa del dólar oficial")
+	}
+
+	// Obtener datos de acciones
+	fmt.Println("Obteniendo datos de acciones...")
+	stocksData, err := getStockData(ctx, dolarRate, client, errs)
+	if err != nil && debugLogging {
+		fmt.Printf("⚠️ %v\n", err)
+	}
+
+	fmt.Printf("Se obtuvieron %d registros de acciones\n", len(stocksData))
+	userStocks := getUserStockData(ctx, dolarRate, client)
+
+	var symbols []string
+	for _, stock := range stocksData {
+		symbols = append(symbols, stock.Symbol)
+	}
+	marketCaps := getMarketCaps(symbols, dolarRate, client, errs)
+	orderBooks := getOrderBooks(stocksData, errs)
+	carry := getCarryPanel(forexData, client, errs)
+	equilibrium := getEquilibrium(forexData, errs)
+	exportRates := getExportRates(forexData, client, errs)
+	risk := getRiskProfiles(symbols, dolarRate, client, errs)
+
+	// Obtener precios de bonos y valuarlos
+	fmt.Println("Obteniendo datos de bonos...")
+	bondQuotes := getBondData(bonds, client, errs)
+
+	// Fondos comunes de inversión (CAFCI)
+	funds := getFundQuotes(errs)
+
+	// Granos, petróleo, oro y litio, con los granos convertidos a FOB y a pesos
+	commodities := getCommodities(ctx, forexData, client, errs)
+
+	now := time.Now()
+
+	// El índice es informativo: si falla, el resto del snapshot sigue siendo válido
+	merval, err := getMerval(now, client)
+	if err != nil {
+		errs.add("MERVAL", mervalSymbol, err)
+	}
+
+	fmt.Printf("%s, %d consultas fallidas\n", cycleLogSummary(), len(errs.all()))
+
+	if len(forexData) == 0 && len(stocksData) == 0 && len(bondQuotes) == 0 && len(funds) == 0 {
+		return nil, fmt.Errorf("no se obtuvo ningún dato (%d consultas fallidas): %w", len(errs.all()), errs.err())
+	}
+
+	snapshot := &Snapshot{
+		Time:   now,
+		Forex:  forexData,
+		Stocks: stocksData,
+		Bonds:  bondQuotes,
+		Funds:  funds,
+		Status: currentMarketStatus(now, client),
+		Merval: merval,
+		Errors: errs.all(),
+
+		MarketCaps:  marketCaps,
+		Risk:        risk,
+		OrderBooks:  orderBooks,
+		Carry:       carry,
+		Equilibrium: equilibrium,
+		Commodities: commodities,
+		ExportRates: exportRates,
+
+		UserStocks: userStocks,
+	}
+
+	// Se esperaba actualizar todos los tipos de cambio, la watchlist y los bonos no vencidos
+	expected := len(forexSymbols) + len(watchlistSnapshot())
+	for _, bond := range bonds {
+		if bond.Residual(now.UTC()) > 0 {
+			expected++
+		}
+	}
+	snapshot.Quality = computeQuality(snapshot, expected, now)
+	return snapshot, nil
+}
+
+// Pipeline es el ciclo de actualización compartido por el monitor y el servidor
+type Pipeline struct {
+	client     *HTTPClient
+	bonds      []*Bond
+	notifiers  []Notifier
+	gapWatcher *GapWatcher
+	drawdown   *DrawdownWatcher
+	announces  *AnnouncementWatcher
+	news       *NewsWatcher
+	interval   time.Duration
+	handlers   []func(*Snapshot)
+	follow     bool // Recibe los snapshots de otro proceso por Redis en lugar de consultar proveedores
+
+	// Estado del watchdog: cada reinicio crea una generación nueva del ciclo
+	mu         sync.Mutex
+	generation int
+	lastBeat   time.Time
+	last       *Snapshot // Último snapshot completo, base de los ciclos fuera de horario
+}
+
+// NewPipeline crea el ciclo de actualización con sus alertas
+func NewPipeline(client *HTTPClient, bonds []*Bond, notifiers []Notifier, gapWatcher *GapWatcher) *Pipeline {
+	leaderLock = NewLeaderLock(appConfig().Failover)
+	if appConfig().DisableBonds {
+		bonds = nil
+	}
+	return &Pipeline{
+		client:     client,
+		bonds:      bonds,
+		notifiers:  notifiers,
+		gapWatcher: gapWatcher,
+		drawdown:   NewDrawdownWatcher(appConfig().DrawdownAlert),
+		announces:  NewAnnouncementWatcher(appConfig().AnnouncementFeeds),
+		news:       NewNewsWatcher(appConfig().NewsFeeds, appConfig().NewsKeywords),
+		interval:   time.Duration(appConfig().Interval),
+	}
+}
+
+// OnSnapshot registra una función que recibe cada snapshot nuevo (pantalla local, clientes remotos...)
+func (p *Pipeline) OnSnapshot(handler func(*Snapshot)) {
+	p.handlers = append(p.handlers, handler)
+}
+
+// Run ejecuta el ciclo de actualización indefinidamente, vigilado por el watchdog
+func (p *Pipeline) Run() {
+	if p.follow {
+		p.followRedis()
+		return
+	}
+
+	if err := startStats(); err != nil {
+		fmt.Printf("No se pudieron cargar las estadísticas: %v\n", err)
+	}
+	if err := saveStats(); err != nil {
+		fmt.Printf("No se pudieron guardar las estadísticas: %v\n", err)
+	}
+
+	p.mu.Lock()
+	p.lastBeat = time.Now()
+	client := p.client
+	p.mu.Unlock()
+
+	go p.loop(0, client)
+	p.watchdog()
+}
+
+// loop es una generación del ciclo; termina sola si el watchdog la reemplazó
+func (p *Pipeline) loop(generation int, client QuoteFetcher) {
+	for p.beat(generation) {
+		// Con failover, solo la instancia que tiene el lock consulta proveedores y envía alertas
+		if !leaderLock.Hold(time.Now()) {
+			time.Sleep(p.interval)
+			continue
+		}
+
+		// Fuera de horario solo se consulta lo que opera 24 h, a partir del último snapshot completo
+		p.mu.Lock()
+		last := p.last
+		p.mu.Unlock()
+		economy := economyMode(time.Now()) && last != nil
+
+		var snapshot *Snapshot
+		var err error
+		if economy {
+			snapshot, err = refreshOffHours(client, last)
+		} else {
+			snapshot, err = fetchSnapshot(client, p.bonds)
+		}
+		recordCycle(err == nil)
+		if err := saveStats(); err != nil {
+			fmt.Printf("No se pudieron guardar las estadísticas: %v\n", err)
+		}
+		if err != nil {
+			fmt.Printf("\n%v\n", err)
+			fmt.Printf("Reintentando en 5 segundos... (%s)\n", cycleErrorHint(err))
+			time.Sleep(5 * time.Second)
+			continue
+		}
+
+		// Un ciclo colgado que se destraba después del reinicio no publica datos viejos
+		if !p.current(generation) {
+			return
+		}
+
+		p.mu.Lock()
+		p.last = snapshot
+		p.mu.Unlock()
+
+		// Registrar el mínimo/máximo propio de la sesión
+		trackSessionRanges(snapshot.Forex, snapshot.Stocks)
+		snapshot.Ranges = sessionRangesSnapshot()
+
+		// Guardar el snapshot para poder reproducir la rueda con bolsa replay
+		recordHistory(snapshot)
+
+		// Guardar el último snapshot para el arranque rápido de la próxima sesión
+		if err := saveLastSnapshot(snapshot); err != nil {
+			fmt.Printf("No se pudo guardar el último snapshot: %v\n", err)
+		}
+
+		// Y publicarlo a los demás procesos conectados al mismo Redis
+		broadcastRedis(snapshot)
+
+		// Mostrar datos
+		for _, handler := range p.handlers {
+			handler(snapshot)
+		}
+
+		// Evaluar las reglas de alerta del usuario
+		evaluateAlertRules(snapshot, p.notifiers)
+
+		// Alertar gaps significativos en la apertura de cada mercado
+		p.gapWatcher.Check(snapshot, p.notifiers)
+
+		// Alertar caídas desde máximos de cada activo y de la cartera
+		p.drawdown.Check(snapshot, p.notifiers, client)
+
+		// Alertar los papeles que operan mucho más volumen que el habitual a esta hora
+		rvols.Check(snapshot, p.notifiers, client)
+
+		// Alertar los spreads que se amplían anormalmente en las puntas de BYMA
+		spreads.Check(snapshot, p.notifiers)
+
+		// Alertar los hechos relevantes publicados por papeles de la watchlist
+		p.announces.Check(snapshot, p.notifiers, client)
+
+		// Alertar el día de cada licitación del Tesoro y sus resultados
+		auctionWatcher.Check(snapshot, p.notifiers, client)
+
+		// Alertar los titulares con palabras clave sobre la watchlist o la macro argentina
+		p.news.Check(snapshot, p.notifiers, client)
+
+		// Registrar la apertura y el cierre oficiales de cada mercado al terminar su rueda
+		officials.Check(snapshot, client)
+
+		// Enviar el resumen de cierre una vez terminada la rueda
+		maybeSendCloseSummary(snapshot, p.notifiers, client)
+
+		// Y publicar la página estática con la tabla del día
+		maybePublishSnapshot(snapshot)
+
+		// Y postear el resumen en las redes configuradas
+		maybePublishSocial(snapshot)
+
+		// El último día hábil del mes se guarda la valuación de la cartera
+		maybeSaveMonthEnd(client)
+
+		// Esperar antes de la siguiente actualización; fuera de horario, el intervalo económico
+		wait := p.interval
+		if economyMode(time.Now()) {
+			wait = offHoursWait(time.Now(), time.Duration(appConfig().OffHoursInterval))
+			fmt.Printf("Mercados cerrados: modo económico, próxima actualización en %v\n", wait.Round(time.Second))
+		} else {
+			fmt.Printf("Esperando %v para la próxima actualización...\n", wait)
+		}
+		leaderLock.Sleep(wait)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Día de la última publicación en cada red, para no repetir el resumen tras un reinicio
+const socialPublishedFile = "social_published.json"
+
+// Largo máximo de un post en cada red
+const (
+	mastodonMaxChars = 500
+	twitterMaxChars  = 280
+)
+
+// SocialConfig define las cuentas donde se postea el resumen de cierre diario
+type SocialConfig struct {
+	Mastodon *MastodonTarget `json:"mastodon"`
+	Twitter  *TwitterTarget  `json:"twitter"`
+}
+
+// MastodonTarget es una cuenta de Mastodon con un token de aplicación (permiso write:statuses)
+type MastodonTarget struct {
+	Instance   string `json:"instance"`   // https://mastodon.social
+	Token      string `json:"token"`      // Si está vacío se usa MASTODON_TOKEN
+	Visibility string `json:"visibility"` // public, unlisted o private; por defecto public
+}
+
+// TwitterTarget es una cuenta de X con un token OAuth 2.0 de usuario (permiso tweet.write)
+type TwitterTarget struct {
+	Token string `json:"token"` // Si está vacío se usa TWITTER_TOKEN
+}
+
+// validate completa los valores por defecto y verifica las cuentas
+func (c *SocialConfig) validate() error {
+	if c.Mastodon != nil {
+		if c.Mastodon.Instance == "" {
+			return fmt.Errorf("mastodon: falta \"instance\"")
+		}
+		c.Mastodon.Instance = strings.TrimRight(c.Mastodon.Instance, "/")
+		switch c.Mastodon.Visibility {
+		case "":
+			c.Mastodon.Visibility = "public"
+		case "public", "unlisted", "private":
+		default:
+			return fmt.Errorf("mastodon: visibilidad desconocida %q (public, unlisted o private)", c.Mastodon.Visibility)
+		}
+	}
+	return nil
+}
+
+// SocialPostMessage son los datos disponibles en el template "social_post"
+type SocialPostMessage struct {
+	Date     string
+	Snapshot *Snapshot
+	Gainers  []StockInfo // Mayores subas, de mayor a menor
+	Losers   []StockInfo // Mayores bajas, de menor a mayor
+	Default  string
+}
+
+// topMovers devuelve las n mayores subas y bajas del día
+func topMovers(stocksData []StockInfo, n int) (gainers, losers []StockInfo) {
+	sorted := make([]StockInfo, len(stocksData))
+	copy(sorted, stocksData)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].ChangePercent > sorted[j].ChangePercent })
+	n = min(n, len(sorted))
+	gainers = sorted[:n]
+	for i := len(sorted) - 1; i >= len(sorted)-n; i-- {
+		losers = append(losers, sorted[i])
+	}
+	return gainers, losers
+}
+
+// socialPostText arma el post del cierre: dólares, MERVAL y top movers, con el template del usuario si hay
+func socialPostText(snapshot *Snapshot, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cierre %s\n", now.In(argentinaLocation).Format("02/01/2006"))
+	for _, forex := range snapshot.Forex {
+		if strings.HasPrefix(forex.Name, "Dólar") && !strings.Contains(forex.Name, "(alt)") {
+			fmt.Fprintf(&b, "%s $%.2f (%+.2f%%)\n", forex.Name, forex.Price, forex.ChangePercent)
+		}
+	}
+	if m := snapshot.Merval; m != nil && m.Price > 0 {
+		fmt.Fprintf(&b, "MERVAL %.0f (%+.2f%%)\n", m.Price, m.ChangePercent)
+	}
+
+	gainers, losers := topMovers(snapshot.Stocks, 3)
+	movers := func(mark string, list []StockInfo) {
+		if len(list) == 0 {
+			return
+		}
+		var parts []string
+		for _, stock := range list {
+			parts = append(parts, fmt.Sprintf("%s %+.1f%%", stock.Symbol, stock.ChangePercent))
+		}
+		fmt.Fprintf(&b, "%s %s\n", mark, strings.Join(parts, " · "))
+	}
+	movers("▲", gainers)
+	movers("▼", losers)
+	text := strings.TrimSpace(b.String())
+
+	return renderMessage(TemplateSocialPost, SocialPostMessage{
+		Date:     now.In(argentinaLocation).Format("2006-01-02"),
+		Snapshot: snapshot,
+		Gainers:  gainers,
+		Losers:   losers,
+		Default:  text,
+	}, text)
+}
+
+// truncatePost recorta el post al largo máximo de la red, cortando en el último renglón completo
+func truncatePost(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	cut := string(runes[:limit])
+	if i := strings.LastIndex(cut, "\n"); i > 0 {
+		return cut[:i]
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// socialToken devuelve el token configurado o el de la variable de entorno
+func socialToken(token, env string) (string, error) {
+	if token == "" {
+		token = os.Getenv(env)
+	}
+	if token == "" {
+		return "", fmt.Errorf("falta el token (en config.json o %s)", env)
+	}
+	return token, nil
+}
+
+// postMastodon publica un estado; la clave de idempotencia evita duplicarlo si se reintenta el mismo día
+func postMastodon(target *MastodonTarget, text, day string) error {
+	token, err := socialToken(target.Token, "MASTODON_TOKEN")
+	if err != nil {
+		return err
+	}
+	payload, _ := json.Marshal(map[string]string{"status": truncatePost(text, mastodonMaxChars), "visibility": target.Visibility})
+	req, err := http.NewRequest(http.MethodPost, target.Instance+"/api/v1/statuses", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Idempotency-Key", "bolsa-cierre-"+day)
+
+	resp, err := NewProviderClient("mastodon").client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("Mastodon respondió %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// postTwitter publica un tweet; X rechaza el texto repetido, lo que se toma como ya publicado
+func postTwitter(target *TwitterTarget, text string) error {
+	token, err := socialToken(target.Token, "TWITTER_TOKEN")
+	if err != nil {
+		return err
+	}
+	payload, _ := json.Marshal(map[string]string{"text": truncatePost(text, twitterMaxChars)})
+	req, err := http.NewRequest(http.MethodPost, "https://api.twitter.com/2/tweets", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := NewProviderClient("twitter").client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	if resp.StatusCode == http.StatusForbidden && strings.Contains(strings.ToLower(string(body)), "duplicate") {
+		return nil
+	}
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("X respondió %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// loadSocialPublished lee el último día publicado en cada red
+func loadSocialPublished() map[string]string {
+	published := make(map[string]string)
+	path, err := appFile(socialPublishedFile)
+	if err != nil {
+		return published
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &published)
+	}
+	return published
+}
+
+// saveSocialPublished registra el día publicado en cada red
+func saveSocialPublished(published map[string]string) error {
+	path, err := appFile(socialPublishedFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(published, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// publishSocial postea el resumen en cada red que todavía no lo tiene; con force publica igual
+func publishSocial(cfg *SocialConfig, snapshot *Snapshot, now time.Time, force bool) error {
+	day := now.In(argentinaLocation).Format("2006-01-02")
+	text := socialPostText(snapshot, now)
+	published := loadSocialPublished()
+
+	posts := make(map[string]func() error)
+	if cfg.Mastodon != nil {
+		posts["mastodon"] = func() error { return postMastodon(cfg.Mastodon, text, day) }
+	}
+	if cfg.Twitter != nil {
+		posts["twitter"] = func() error { return postTwitter(cfg.Twitter, text) }
+	}
+
+	var errs []string
+	for name, post := range posts {
+		if published[name] == day && !force {
+			continue
+		}
+		if err := post(); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+			continue
+		}
+		published[name] = day
+		fmt.Printf("%sResumen de cierre publicado en %s.%s\n", Green, name, Reset)
+	}
+	if err := saveSocialPublished(published); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// maybePublishSocial postea el resumen una vez por día y red, tras el cierre de BYMA
+func maybePublishSocial(snapshot *Snapshot) {
+	cfg := appConfig().Social
+	now := time.Now()
+	if cfg == nil || !bymaHours.IsTradingDay(now) || !bymaHours.IsClosedForDay(now) {
+		return
+	}
+	if err := publishSocial(cfg, snapshot, now, false); err != nil {
+		fmt.Printf("%sNo se pudo publicar el resumen de cierre: %v%s\n", Yellow, err, Reset)
+	}
+}
+
+// runSocial implementa `bolsa social`: muestra o publica el resumen de cierre con el último snapshot guardado
+func runSocial(args []string) error {
+	fs := flag.NewFlagSet("social", flag.ExitOnError)
+	post := fs.Bool("post", false, "publicar en las cuentas de config.json (sin --post solo se muestra el texto)")
+	force := fs.Bool("force", false, "publicar aunque hoy ya se haya publicado")
+	fs.Parse(args)
+
+	snapshot, err := loadLastSnapshot()
+	if err != nil {
+		return err
+	}
+	if snapshot == nil {
+		return fmt.Errorf("no hay un snapshot guardado: corré el monitor al menos un ciclo")
+	}
+	text := socialPostText(snapshot, snapshot.Time)
+	if !*post {
+		fmt.Println(text)
+		fmt.Printf("\n%d caracteres (Mastodon admite %d, X %d)\n", len([]rune(text)), mastodonMaxChars, twitterMaxChars)
+		return nil
+	}
+
+	cfg := appConfig().Social
+	if cfg == nil || (cfg.Mastodon == nil && cfg.Twitter == nil) {
+		return fmt.Errorf("no hay cuentas configuradas: agregar \"social\" con \"mastodon\" o \"twitter\" en config.json")
+	}
+	return publishSocial(cfg, snapshot, snapshot.Time, *force)
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// SourceComparison es el precio de un símbolo según cada proveedor y el desvío entre ellos
+type SourceComparison struct {
+	Symbol  string
+	Prices  map[string]float64 // Proveedor → último precio
+	Errors  map[string]error   // Proveedor → por qué no informó precio
+	Median  float64
+	Spread  float64 // (máximo - mínimo) / mediana, en %
+	Outlier string  // Proveedor más alejado de la mediana, con tres o más fuentes
+}
+
+// compareSources consulta cada proveedor por separado, sin las reglas de merge
+func compareSources(symbol string, market Market, client QuoteFetcher) SourceComparison {
+	c := SourceComparison{Symbol: symbol, Prices: make(map[string]float64), Errors: make(map[string]error)}
+	for _, source := range quoteSources {
+		var r tickerResult
+		switch source {
+		case "yahoo":
+			r = yahooQuote(symbol, client)
+		case "iol":
+			r = iolQuote(symbol, market)
+		case "byma":
+			r = bymaQuote(symbol, market)
+		}
+		switch {
+		case r.err != nil:
+			c.Errors[source] = r.err
+		case r.price <= 0:
+			c.Errors[source] = fmt.Errorf("sin precio")
+		default:
+			c.Prices[source] = r.price
+		}
+	}
+	if len(c.Prices) < 2 {
+		return c
+	}
+
+	var prices []float64
+	for _, price := range c.Prices {
+		prices = append(prices, price)
+	}
+	sort.Float64s(prices)
+	n := len(prices)
+	c.Median = prices[n/2]
+	if n%2 == 0 {
+		c.Median = (prices[n/2-1] + prices[n/2]) / 2
+	}
+	c.Spread = (prices[n-1] - prices[0]) / c.Median * 100
+
+	if n >= 3 {
+		var worst float64
+		for _, source := range quoteSources {
+			price, ok := c.Prices[source]
+			if ok && math.Abs(price-c.Median) > worst {
+				worst, c.Outlier = math.Abs(price-c.Median), source
+			}
+		}
+	}
+	return c
+}
+
+// displaySourceComparisons muestra el precio de cada fuente y resalta los desvíos por encima del umbral
+func displaySourceComparisons(comparisons []SourceComparison, threshold float64) {
+	fmt.Printf("\n%s=== COMPARACIÓN DE FUENTES ===%s\n\n", Cyan, Reset)
+	fmt.Printf("%-10s", "Símbolo")
+	for _, source := range quoteSources {
+		fmt.Printf(" %14s", source)
+	}
+	fmt.Printf(" %9s  %s\n", "Desvío", "Más alejada")
+
+	outliers := make(map[string]int)
+	deviations := make(map[string][]float64)
+	for _, c := range comparisons {
+		fmt.Printf("%-10s", c.Symbol)
+		for _, source := range quoteSources {
+			if price, ok := c.Prices[source]; ok {
+				fmt.Printf(" %14.2f", price)
+				if c.Median > 0 {
+					deviations[source] = append(deviations[source], math.Abs(price/c.Median-1)*100)
+				}
+			} else {
+				fmt.Printf(" %14s", "-")
+			}
+		}
+		if len(c.Prices) < 2 {
+			fmt.Printf(" %9s  %s\n", "-", "una sola fuente")
+			continue
+		}
+		color := Green
+		if c.Spread > threshold {
+			color = Red
+			if c.Outlier != "" {
+				outliers[c.Outlier]++
+			}
+		}
+		fmt.Printf(" %s%8.2f%%%s  %s\n", color, c.Spread, Reset, c.Outlier)
+	}
+
+	// Una fuente que queda sistemáticamente lejos de las demás probablemente está desactualizada
+	fmt.Printf("\n%-10s %12s %14s\n", "Fuente", "Desvío prom.", "Veces alejada")
+	for _, source := range quoteSources {
+		devs := deviations[source]
+		if len(devs) == 0 {
+			fmt.Printf("%-10s %12s %14s\n", source, "-", "-")
+			continue
+		}
+		var sum float64
+		for _, d := range devs {
+			sum += d
+		}
+		color := ""
+		if outliers[source] > 0 && outliers[source]*2 >= len(devs) {
+			color = Yellow
+		}
+		fmt.Printf("%s%-10s %11.2f%% %14d%s\n", color, source, sum/float64(len(devs)), outliers[source], Reset)
+	}
+	fmt.Printf("\nDesvío: (máximo - mínimo) / mediana. En rojo, los que superan el %.2f%%.\n", threshold)
+}
+
+// runSources implementa `bolsa sources`: el mismo símbolo según Yahoo, IOL y BYMA, para detectar fuentes desactualizadas
+func runSources(args []string) error {
+	fs := flag.NewFlagSet("sources", flag.ExitOnError)
+	threshold := fs.Float64("threshold", 0.5, "desvío entre fuentes (%) a partir del cual se resalta el símbolo")
+	verbose := fs.Bool("v", false, "mostrar por qué cada fuente no informó precio")
+	fs.Parse(args)
+
+	// Sin símbolos se comparan los papeles de BYMA de la watchlist: los ADRs solo cotizan en Yahoo
+	var symbols []WatchlistEntry
+	for _, arg := range fs.Args() {
+		symbol := normalizeSymbol(arg)
+		symbols = append(symbols, WatchlistEntry{Symbol: symbol, Market: marketForSymbol(symbol)})
+	}
+	if len(symbols) == 0 {
+		for _, stock := range watchlistSnapshot() {
+			if stock.Market == MarketBYMA {
+				symbols = append(symbols, stock)
+			}
+		}
+	}
+	if len(symbols) == 0 {
+		return fmt.Errorf("no hay papeles de BYMA en la watchlist: indicá los símbolos (bolsa sources GGAL.BA YPFD.BA)")
+	}
+
+	client := NewHTTPClient()
+	comparisons := make([]SourceComparison, len(symbols))
+	group := newWorkGroup(context.Background(), fetchConcurrency)
+	for i, stock := range symbols {
+		i, stock := i, stock
+		group.Go(func(ctx context.Context) error {
+			comparisons[i] = compareSources(stock.Symbol, stock.Market, client)
+			return nil
+		})
+	}
+	group.Wait()
+
+	displaySourceComparisons(comparisons, *threshold)
+	if *verbose {
+		fmt.Println()
+		for _, c := range comparisons {
+			var reasons []string
+			for _, source := range quoteSources {
+				if err, ok := c.Errors[source]; ok {
+					reasons = append(reasons, fmt.Sprintf("%s: %v", source, err))
+				}
+			}
+			if len(reasons) > 0 {
+				fmt.Printf("%-10s %s\n", c.Symbol, strings.Join(reasons, "; "))
+			}
+		}
+	}
+	return nil
+}
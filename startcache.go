@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Último snapshot obtenido, para mostrar algo apenas arranca el programa mientras se completa el primer ciclo
+const lastSnapshotFile = "last_snapshot.json"
+
+// saveLastSnapshot guarda el snapshot del ciclo; se escribe en un temporal y se renombra para no dejarlo a medias
+func saveLastSnapshot(snapshot *Snapshot) error {
+	path, err := appFile(lastSnapshotFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// loadLastSnapshot lee el snapshot de la sesión anterior marcado como cacheado; devuelve nil si no hay
+func loadLastSnapshot() (*Snapshot, error) {
+	path, err := appFile(lastSnapshotFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	snapshot.Cached = true
+	return &snapshot, nil
+}
+
+// displayCachedBanner avisa que la tabla es de la sesión anterior mientras llega el primer ciclo
+func displayCachedBanner(snapshot *Snapshot) {
+	fmt.Printf("%s⏳ Datos de la sesión anterior (%s): actualizando...%s\n",
+		Yellow, snapshot.Time.Format("2006-01-02 15:04"), Reset)
+}
@@ -0,0 +1,287 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const statsFile = "stats.json"
+
+// ProviderStats acumula las solicitudes a un proveedor de datos
+type ProviderStats struct {
+	Requests int64    `json:"requests"`
+	Errors   int64    `json:"errors"`
+	Bytes    int64    `json:"bytes"`
+	Latency  Duration `json:"latency"` // Suma de latencias, para calcular el promedio
+
+	Day         string `json:"day,omitempty"` // Día al que corresponde DayRequests, para el presupuesto diario
+	DayRequests int64  `json:"dayRequests,omitempty"`
+}
+
+// AverageLatency devuelve la latencia promedio de las solicitudes
+func (p ProviderStats) AverageLatency() time.Duration {
+	if p.Requests == 0 {
+		return 0
+	}
+	return time.Duration(p.Latency) / time.Duration(p.Requests)
+}
+
+// SessionStats son las estadísticas del daemon, persistidas entre reinicios en stats.json
+type SessionStats struct {
+	FirstStart   time.Time                 `json:"firstStart"`
+	StartedAt    time.Time                 `json:"startedAt"`   // Inicio del proceso actual
+	UpdatedAt    time.Time                 `json:"updatedAt"`   // Último guardado del proceso actual
+	TotalUptime  Duration                  `json:"totalUptime"` // Uptime de los procesos anteriores
+	Restarts     int                       `json:"restarts"`
+	CyclesOK     int64                     `json:"cyclesOk"`
+	CyclesFailed int64                     `json:"cyclesFailed"`
+	Providers    map[string]*ProviderStats `json:"providers"`
+}
+
+var (
+	statsMu sync.Mutex
+	stats   = &SessionStats{Providers: make(map[string]*ProviderStats)}
+)
+
+// loadStats lee stats.json
+func loadStats() (*SessionStats, error) {
+	loaded := &SessionStats{Providers: make(map[string]*ProviderStats)}
+
+	path, err := appFile(statsFile)
+	if err != nil {
+		return loaded, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return loaded, nil
+	}
+	if err != nil {
+		return loaded, err
+	}
+	if err := json.Unmarshal(data, loaded); err != nil {
+		return loaded, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	if loaded.Providers == nil {
+		loaded.Providers = make(map[string]*ProviderStats)
+	}
+	return loaded, nil
+}
+
+// startStats retoma las estadísticas guardadas y abre una sesión nueva del daemon
+func startStats() error {
+	loaded, err := loadStats()
+
+	statsMu.Lock()
+	defer statsMu.Unlock()
+
+	now := time.Now()
+	if !loaded.StartedAt.IsZero() {
+		// El proceso anterior corrió hasta su último guardado
+		loaded.TotalUptime += Duration(loaded.UpdatedAt.Sub(loaded.StartedAt))
+		loaded.Restarts++
+	}
+	if loaded.FirstStart.IsZero() {
+		loaded.FirstStart = now
+	}
+	loaded.StartedAt = now
+	loaded.UpdatedAt = now
+
+	// Lo contado antes de iniciar (solicitudes de arranque) se suma a lo guardado
+	for name, p := range stats.Providers {
+		total := providerStats(loaded, name)
+		total.Requests += p.Requests
+		total.Errors += p.Errors
+		total.Bytes += p.Bytes
+		total.Latency += p.Latency
+		if total.Day != p.Day {
+			total.Day, total.DayRequests = p.Day, 0
+		}
+		total.DayRequests += p.DayRequests
+	}
+	stats = loaded
+	return err
+}
+
+// providerStats devuelve los contadores de un proveedor, creándolos si hace falta
+func providerStats(s *SessionStats, provider string) *ProviderStats {
+	p, ok := s.Providers[provider]
+	if !ok {
+		p = &ProviderStats{}
+		s.Providers[provider] = p
+	}
+	return p
+}
+
+// recordRequest registra una solicitud HTTP terminada (err != nil si no hubo respuesta)
+func recordRequest(provider string, latency time.Duration, err error) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	p := providerStats(stats, provider)
+	p.Requests++
+	p.Latency += Duration(latency)
+	if today := budgetDay(time.Now()); p.Day != today {
+		p.Day, p.DayRequests = today, 0
+	}
+	p.DayRequests++
+	if err != nil {
+		p.Errors++
+	}
+}
+
+// recordBytes suma bytes recibidos de un proveedor
+func recordBytes(provider string, n int) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	providerStats(stats, provider).Bytes += int64(n)
+}
+
+// recordCycle registra el resultado de un ciclo de actualización
+func recordCycle(ok bool) {
+	statsMu.Lock()
+	defer statsMu.Unlock()
+	if ok {
+		stats.CyclesOK++
+	} else {
+		stats.CyclesFailed++
+	}
+}
+
+// saveStats guarda las estadísticas actuales en stats.json
+func saveStats() error {
+	statsMu.Lock()
+	stats.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(stats, "", "  ")
+	statsMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	path, err := appFile(statsFile)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// countingBody cuenta los bytes leídos del body de una respuesta
+type countingBody struct {
+	io.ReadCloser
+	provider string
+}
+
+func (b countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if n > 0 {
+		recordBytes(b.provider, n)
+	}
+	return n, err
+}
+
+// formatBytes muestra una cantidad de bytes en KB/MB/GB
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, s := range []string{"MB", "GB", "TB"} {
+		if value < unit {
+			break
+		}
+		value /= unit
+		suffix = s
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// runStats implementa `bolsa stats`: uptime, ciclos y uso de cada proveedor del daemon
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	reset := fs.Bool("reset", false, "borrar las estadísticas acumuladas")
+	fs.Parse(args)
+
+	if *reset {
+		path, err := appFile(statsFile)
+		if err != nil {
+			return err
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		fmt.Println("Estadísticas borradas.")
+		return nil
+	}
+
+	s, err := loadStats()
+	if err != nil {
+		return err
+	}
+	if s.StartedAt.IsZero() {
+		fmt.Println("Todavía no hay estadísticas: se registran mientras corre el monitor o `bolsa serve`.")
+		return nil
+	}
+
+	// Si el último guardado es reciente, el daemon sigue corriendo
+	running := time.Since(s.UpdatedAt) < 2*time.Minute
+	current := s.UpdatedAt.Sub(s.StartedAt)
+	if running {
+		current = time.Since(s.StartedAt)
+	}
+
+	fmt.Printf("%s=== ESTADÍSTICAS DEL DAEMON ===%s\n\n", Cyan, Reset)
+	if running {
+		fmt.Printf("Estado:          %sen ejecución%s desde %s\n", Green, Reset, s.StartedAt.Format("2006-01-02 15:04:05"))
+	} else {
+		fmt.Printf("Estado:          %sdetenido%s (última actividad %s)\n", Red, Reset, s.UpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+	fmt.Printf("Uptime actual:   %v\n", current.Round(time.Second))
+	fmt.Printf("Uptime total:    %v desde %s (%d reinicios)\n",
+		(time.Duration(s.TotalUptime) + current).Round(time.Second), s.FirstStart.Format("2006-01-02"), s.Restarts)
+
+	cycles := s.CyclesOK + s.CyclesFailed
+	fmt.Printf("Ciclos:          %d exitosos, %d fallidos", s.CyclesOK, s.CyclesFailed)
+	if cycles > 0 {
+		fmt.Printf(" (%.1f%% de éxito)", float64(s.CyclesOK)/float64(cycles)*100)
+	}
+	fmt.Println()
+
+	if len(s.Providers) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range s.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("\n%-12s %10s %8s %12s %12s %16s\n", "Proveedor", "Requests", "Errores", "Recibido", "Latencia", "Hoy / presupuesto")
+	today := budgetDay(time.Now())
+	for _, name := range names {
+		p := s.Providers[name]
+		var used int64
+		if p.Day == today {
+			used = p.DayRequests
+		}
+		usage := fmt.Sprintf("%d", used)
+		color := White
+		if budget := int64(appConfig().provider(name).DailyBudget); budget > 0 {
+			usage = fmt.Sprintf("%d / %d", used, budget)
+			if float64(used) >= float64(budget)*budgetReserve {
+				color = Yellow
+			}
+			if used >= budget {
+				color = Red
+			}
+		}
+		fmt.Printf("%-12s %10d %8d %12s %12v %s%16s%s\n", name, p.Requests, p.Errors, formatBytes(p.Bytes),
+			p.AverageLatency().Round(time.Millisecond), color, usage, Reset)
+	}
+	return nil
+}
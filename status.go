@@ -0,0 +1,157 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Futuros del S&P 500, que cotizan casi todo el día y anticipan la apertura de Nueva York
+const spFuturesSymbol = "ES=F"
+
+// ProviderHealth es el estado de un proveedor de datos según sus últimas solicitudes
+type ProviderHealth struct {
+	LastOK    time.Time `json:"last_ok"`
+	LastError time.Time `json:"last_error"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Healthy indica si la última solicitud al proveedor terminó bien
+func (h ProviderHealth) Healthy() bool {
+	return !h.LastOK.IsZero() && !h.LastOK.Before(h.LastError)
+}
+
+// MarketStatus es el contexto global que se muestra en la cabecera
+type MarketStatus struct {
+	Markets   []MarketState             `json:"markets"`
+	Futures   *ForexInfo                `json:"futures,omitempty"`
+	Providers map[string]ProviderHealth `json:"providers"`
+}
+
+// MarketState indica si un mercado está en rueda y cuándo abre o cierra, en hora argentina
+type MarketState struct {
+	Name    string    `json:"name"`
+	Open    bool      `json:"open"`
+	Close   time.Time `json:"close"`
+	Holiday string    `json:"holiday,omitempty"` // Feriado del día, si no hay rueda por eso
+}
+
+var (
+	providerHealthMu sync.Mutex
+	providerHealth   = make(map[string]ProviderHealth)
+)
+
+// markProviderOK registra una solicitud exitosa al proveedor
+func markProviderOK(provider string) {
+	providerHealthMu.Lock()
+	defer providerHealthMu.Unlock()
+	h := providerHealth[provider]
+	h.LastOK = time.Now()
+	h.Error = ""
+	providerHealth[provider] = h
+}
+
+// markProviderError registra una solicitud fallida al proveedor
+func markProviderError(provider string, err error) {
+	providerHealthMu.Lock()
+	h := providerHealth[provider]
+	wasHealthy := h.Error == ""
+	h.LastError = time.Now()
+	h.Error = err.Error()
+	providerHealth[provider] = h
+	providerHealthMu.Unlock()
+
+	// Los hooks se avisan solo en la transición, no en cada request fallido
+	if wasHealthy {
+		runHooks(EventProviderError, struct {
+			Provider string `json:"provider"`
+			Error    string `json:"error"`
+		}{provider, err.Error()})
+	}
+}
+
+// providerHealthSnapshot devuelve una copia del estado de los proveedores
+func providerHealthSnapshot() map[string]ProviderHealth {
+	providerHealthMu.Lock()
+	defer providerHealthMu.Unlock()
+	snapshot := make(map[string]ProviderHealth, len(providerHealth))
+	for name, h := range providerHealth {
+		snapshot[name] = h
+	}
+	return snapshot
+}
+
+// currentMarketStatus arma el estado de los mercados, los futuros y los proveedores en el instante now
+func currentMarketStatus(now time.Time, client QuoteFetcher) MarketStatus {
+	status := MarketStatus{}
+	for _, m := range []MarketHours{nyseHours, bymaHours} {
+		_, close := m.sessionTimes(now)
+		state := MarketState{Name: m.Name, Open: m.IsOpen(now), Close: close}
+		if holiday, ok := holidayOn(Market(m.Name), now); ok {
+			state.Holiday = holiday.Name
+		}
+		status.Markets = append(status.Markets, state)
+	}
+
+	// Los futuros son contexto: si fallan la cabecera se muestra igual
+	price, previousClose, _, _, err := getTickerData(spFuturesSymbol, client)
+	if err == nil && price > 0 {
+		futures := ForexInfo{Symbol: spFuturesSymbol, Name: "S&P fut", Price: price, PreviousClose: previousClose}
+		if previousClose > 0 {
+			futures.Change = price - previousClose
+			futures.ChangePercent = futures.Change / previousClose * 100
+		}
+		status.Futures = &futures
+	}
+
+	status.Providers = providerHealthSnapshot()
+	return status
+}
+
+// statusLight devuelve el semáforo ● en verde o ○ en rojo
+func statusLight(ok bool) string {
+	if ok {
+		return Green + "●" + Reset
+	}
+	return Red + "○" + Reset
+}
+
+// displayStatusHeader muestra la línea superior con el estado de los mercados y los proveedores
+func displayStatusHeader(status MarketStatus, quality *CycleQuality) {
+	var parts []string
+	for _, m := range status.Markets {
+		part := fmt.Sprintf("%s %s", m.Name, statusLight(m.Open))
+		if m.Open {
+			part += fmt.Sprintf(" cierra %s", m.Close.In(argentinaLocation).Format("15:04"))
+		} else if m.Holiday != "" {
+			part += " feriado"
+		}
+		parts = append(parts, part)
+	}
+
+	if f := status.Futures; f != nil {
+		changeColor := variationColor(f.ChangePercent)
+		parts = append(parts, fmt.Sprintf("%s %.2f %s%+.2f%%%s", f.Name, f.Price, changeColor, f.ChangePercent, Reset))
+	}
+
+	if len(status.Providers) > 0 {
+		var names []string
+		for name := range status.Providers {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var providers []string
+		for _, name := range names {
+			providers = append(providers, fmt.Sprintf("%s %s", name, statusLight(status.Providers[name].Healthy())))
+		}
+		parts = append(parts, "Datos: "+strings.Join(providers, " "))
+	}
+	if quality != nil {
+		parts = append(parts, qualityHeader(quality))
+	}
+
+	fmt.Println(strings.Join(parts, "  │  "))
+}
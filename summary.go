@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PanelSummary resume el panel de acciones mostrado: variación promedio y amplitud del mercado
+type PanelSummary struct {
+	WeightedChange float64 // Variación % promedio ponderada por volumen
+	Advancing      int
+	Declining      int
+	Unchanged      int
+	Volume         int64   // Nominales operados
+	Turnover       float64 // Monto operado en pesos (precio en pesos × volumen)
+}
+
+// summarizePanel calcula el resumen de las acciones; sin volumen la variación es el promedio simple
+func summarizePanel(stocksData []StockInfo) PanelSummary {
+	var s PanelSummary
+	var weighted, simple float64
+	for _, stock := range stocksData {
+		switch {
+		case stock.ChangePercent > 0:
+			s.Advancing++
+		case stock.ChangePercent < 0:
+			s.Declining++
+		default:
+			s.Unchanged++
+		}
+		s.Volume += stock.Volume
+		s.Turnover += stock.Price * float64(stock.Volume)
+		weighted += stock.ChangePercent * float64(stock.Volume)
+		simple += stock.ChangePercent
+	}
+
+	switch {
+	case s.Volume > 0:
+		s.WeightedChange = weighted / float64(s.Volume)
+	case len(stocksData) > 0:
+		s.WeightedChange = simple / float64(len(stocksData))
+	}
+	return s
+}
+
+// formatCompact abrevia cantidades grandes (1,2 K, 3,4 M, 5,6 MM)
+func formatCompact(v float64) string {
+	switch {
+	case v >= 1e9:
+		return strings.Replace(fmt.Sprintf("%.1f MM", v/1e9), ".", ",", 1)
+	case v >= 1e6:
+		return strings.Replace(fmt.Sprintf("%.1f M", v/1e6), ".", ",", 1)
+	case v >= 1e3:
+		return strings.Replace(fmt.Sprintf("%.1f K", v/1e3), ".", ",", 1)
+	}
+	return fmt.Sprintf("%.0f", v)
+}
+
+// displayPanelSummary muestra la fila de resumen al pie de la tabla de acciones
+func displayPanelSummary(stocksData []StockInfo) {
+	s := summarizePanel(stocksData)
+
+	if plainMode {
+		fmt.Println(plainRow("Resumen", fmt.Sprintf("var. pond. %+.2f%%", s.WeightedChange),
+			fmt.Sprintf("alza %d", s.Advancing), fmt.Sprintf("baja %d", s.Declining), fmt.Sprintf("sin cambios %d", s.Unchanged),
+			"vol. "+formatCompact(float64(s.Volume)), "monto $"+formatCompact(s.Turnover)))
+		return
+	}
+
+	fmt.Printf("\n%sResumen:%s var. ponderada %s%+.2f%%%s | %s▲ %d%s %s▼ %d%s = %d | Vol: %s | Monto: $%s\n",
+		White, Reset, variationColor(s.WeightedChange), s.WeightedChange, Reset,
+		Green, s.Advancing, Reset, Red, s.Declining, Reset, s.Unchanged,
+		formatCompact(float64(s.Volume)), formatCompact(s.Turnover))
+}
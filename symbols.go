@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Caracteres válidos en un símbolo de Yahoo: letras, números, punto (.BA), guion (BTC-USD), = (ARS=X), ^ (^MERV)
+var validSymbol = regexp.MustCompile(`^[A-Z0-9.\-=^]+$`)
+
+// SymbolWarning es un problema con un símbolo de config.json que se corrigió o conviene revisar al cargarla
+type SymbolWarning struct {
+	Raw        string // Símbolo tal como está en el archivo, para ubicar la línea
+	Message    string
+	Suggestion string
+}
+
+func (w SymbolWarning) String() string {
+	if w.Suggestion != "" {
+		return fmt.Sprintf("%s (%s)", w.Message, w.Suggestion)
+	}
+	return w.Message
+}
+
+// normalizeSymbol quita espacios y pasa a mayúsculas: "ggal", " GGAL " y "GGAL" son el mismo símbolo
+func normalizeSymbol(symbol string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol))
+}
+
+// symbolNormalizer normaliza los símbolos de una carga de config.json y junta las advertencias
+type symbolNormalizer struct {
+	warnings []SymbolWarning
+	catalog  []string
+}
+
+func (n *symbolNormalizer) warn(raw, message, suggestion string) {
+	n.warnings = append(n.warnings, SymbolWarning{Raw: raw, Message: message, Suggestion: suggestion})
+}
+
+// normalize devuelve el símbolo normalizado, avisando si cambió o si no tiene un formato válido
+func (n *symbolNormalizer) normalize(list, raw string) string {
+	symbol := normalizeSymbol(raw)
+	if symbol != raw {
+		n.warn(raw, fmt.Sprintf("%s: %q normalizado a %s", list, raw, symbol), "")
+	}
+	if symbol != "" && !validSymbol.MatchString(symbol) {
+		n.warn(raw, fmt.Sprintf("%s: %q no parece un símbolo válido", list, symbol), "solo letras, números y . - = ^")
+	}
+	return symbol
+}
+
+// checkCatalog sugiere el papel del catálogo más parecido a un símbolo que no está en él (GGAL.B → GGAL.BA)
+func (n *symbolNormalizer) checkCatalog(list, raw, symbol string) {
+	if _, ok := catalogLookup(symbol); ok {
+		return
+	}
+	if hint := suggest(symbol, n.catalog); hint != "" {
+		n.warn(raw, fmt.Sprintf("%s: %s no está en el catálogo", list, symbol), hint)
+	}
+}
+
+// dedupe normaliza una lista de símbolos y fusiona los repetidos, conservando el primero
+func (n *symbolNormalizer) dedupe(list string, symbols []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, raw := range symbols {
+		symbol := n.normalize(list, raw)
+		if symbol == "" {
+			continue
+		}
+		if seen[symbol] {
+			n.warn(raw, fmt.Sprintf("%s: %s repetido, se fusionó", list, symbol), "")
+			continue
+		}
+		seen[symbol] = true
+		result = append(result, symbol)
+	}
+	return result
+}
+
+// normalizeConfigSymbols normaliza los símbolos de todas las listas de config.json, fusiona los duplicados y
+// advierte los que no están en el catálogo o que se referencian sin estar en seguimiento
+func normalizeConfigSymbols(cfg *Config) []SymbolWarning {
+	n := &symbolNormalizer{}
+	for _, entry := range catalog {
+		n.catalog = append(n.catalog, entry.Symbol)
+	}
+
+	// Watchlist: un símbolo repetido con otro mercado se queda con el primero
+	watched := make(map[string]bool)
+	var stocks []WatchlistEntry
+	markets := make(map[string]Market)
+	for _, entry := range cfg.Stocks {
+		raw := entry.Symbol
+		entry.Symbol = n.normalize("stocks", raw)
+		if entry.Symbol == "" {
+			continue
+		}
+		if market, ok := markets[entry.Symbol]; ok {
+			if market != entry.Market {
+				n.warn(raw, fmt.Sprintf("stocks: %s repetido con mercados distintos (%s y %s), se usa %s", entry.Symbol, market, entry.Market, market), "")
+			} else {
+				n.warn(raw, fmt.Sprintf("stocks: %s repetido, se fusionó", entry.Symbol), "")
+			}
+			continue
+		}
+		markets[entry.Symbol] = entry.Market
+		watched[entry.Symbol] = true
+		n.checkCatalog("stocks", raw, entry.Symbol)
+		stocks = append(stocks, entry)
+	}
+	if cfg.Stocks != nil {
+		cfg.Stocks = stocks
+	}
+
+	var forex []ForexSymbol
+	for _, fx := range cfg.Forex {
+		raw := fx.Symbol
+		fx.Symbol = n.normalize("forex", raw)
+		if fx.Symbol == "" {
+			continue
+		}
+		if watched[fx.Symbol] {
+			n.warn(raw, fmt.Sprintf("forex: %s ya está en seguimiento, se fusionó", fx.Symbol), "")
+			continue
+		}
+		watched[fx.Symbol] = true
+		forex = append(forex, fx)
+	}
+	if cfg.Forex != nil {
+		cfg.Forex = forex
+	}
+
+	// Las listas que referencian símbolos: fijados (también aceptan nombres de tipos de cambio) e importantes
+	pinned := cfg.Pinned
+	cfg.Pinned = nil
+	seen := make(map[string]bool)
+	for _, raw := range pinned {
+		// Los nombres de tipos de cambio ("Dólar Blue") se fijan tal cual, sin pasarlos a mayúsculas
+		if strings.Contains(strings.TrimSpace(raw), " ") {
+			cfg.Pinned = append(cfg.Pinned, strings.TrimSpace(raw))
+			continue
+		}
+		symbol := n.normalize("pinned", raw)
+		if symbol == "" {
+			continue
+		}
+		if seen[symbol] {
+			n.warn(raw, fmt.Sprintf("pinned: %s repetido, se fusionó", symbol), "")
+			continue
+		}
+		seen[symbol] = true
+		cfg.Pinned = append(cfg.Pinned, symbol)
+	}
+	cfg.Important = n.dedupe("important", cfg.Important)
+	if len(cfg.Stocks) > 0 {
+		for _, symbol := range cfg.Important {
+			if !watched[symbol] {
+				n.warn(symbol, fmt.Sprintf("important: %s no está en stocks, no tiene efecto", symbol), "")
+			}
+		}
+	}
+
+	// Reglas de alerta y ponderaciones del MERVAL
+	for i := range cfg.Alerts {
+		if cfg.Alerts[i].Symbol != "*" {
+			cfg.Alerts[i].Symbol = n.normalize(fmt.Sprintf("alerta %q", cfg.Alerts[i].Name), cfg.Alerts[i].Symbol)
+		}
+	}
+	if len(cfg.MervalWeights) > 0 {
+		weights := make(map[string]float64, len(cfg.MervalWeights))
+		for raw, weight := range cfg.MervalWeights {
+			symbol := n.normalize("mervalWeights", raw)
+			if _, ok := weights[symbol]; ok {
+				n.warn(raw, fmt.Sprintf("mervalWeights: %s repetido, se sumaron las ponderaciones", symbol), "")
+			}
+			weights[symbol] += weight
+		}
+		cfg.MervalWeights = weights
+	}
+	for i := range cfg.Companies {
+		cfg.Companies[i].Foreign = n.normalize("companies", cfg.Companies[i].Foreign)
+		cfg.Companies[i].Local = n.normalize("companies", cfg.Companies[i].Local)
+	}
+	return n.warnings
+}
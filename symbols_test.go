@@ -0,0 +1,128 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// warningsText junta las advertencias para buscar en ellas
+func warningsText(warnings []SymbolWarning) string {
+	var lines []string
+	for _, w := range warnings {
+		lines = append(lines, w.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := map[string]string{
+		"ggal":       "GGAL",
+		" GGAL ":     "GGAL",
+		"GGAL":       "GGAL",
+		"ggal.ba":    "GGAL.BA",
+		"\tGGAL.BA ": "GGAL.BA",
+		"ars=x":      "ARS=X",
+		"":           "",
+	}
+	for raw, want := range tests {
+		if got := normalizeSymbol(raw); got != want {
+			t.Errorf("normalizeSymbol(%q) = %q, se esperaba %q", raw, got, want)
+		}
+	}
+}
+
+func TestNormalizeConfigSymbolsMergesSpellings(t *testing.T) {
+	cfg := &Config{
+		// GGAL es el ADR de Nueva York y GGAL.BA la acción en BYMA: son dos papeles distintos y no se fusionan
+		Stocks: []WatchlistEntry{
+			{Symbol: "ggal", Market: MarketNYSE},
+			{Symbol: " GGAL ", Market: MarketNYSE},
+			{Symbol: "GGAL", Market: MarketNYSE},
+			{Symbol: "ggal.ba", Market: MarketBYMA},
+			{Symbol: "GGAL.BA", Market: MarketBYMA},
+			{Symbol: "ypf", Market: MarketBYMA},
+		},
+		Forex:         []ForexSymbol{{Symbol: "ars=x", Name: "Oficial"}, {Symbol: "ggal", Name: "Repetido"}},
+		Pinned:        []string{"ggal", "GGAL ", " Dólar Blue ", "ggal.ba"},
+		Important:     []string{"Ggal", "GGAL", "BMA"},
+		Alerts:        []AlertRule{{Name: "suba", Symbol: " ggal.ba"}, {Name: "todas", Symbol: "*"}},
+		MervalWeights: map[string]float64{"ggal.ba": 10, "GGAL.BA": 5},
+	}
+	warnings := normalizeConfigSymbols(cfg)
+
+	wantStocks := []WatchlistEntry{{"GGAL", MarketNYSE}, {"GGAL.BA", MarketBYMA}, {"YPF", MarketBYMA}}
+	if !reflect.DeepEqual(cfg.Stocks, wantStocks) {
+		t.Errorf("stocks = %v, se esperaba %v", cfg.Stocks, wantStocks)
+	}
+	if len(cfg.Forex) != 1 || cfg.Forex[0].Symbol != "ARS=X" {
+		t.Errorf("forex = %v, se esperaba solo ARS=X (GGAL ya está en stocks)", cfg.Forex)
+	}
+	if want := []string{"GGAL", "Dólar Blue", "GGAL.BA"}; !reflect.DeepEqual(cfg.Pinned, want) {
+		t.Errorf("pinned = %q, se esperaba %q", cfg.Pinned, want)
+	}
+	if want := []string{"GGAL", "BMA"}; !reflect.DeepEqual(cfg.Important, want) {
+		t.Errorf("important = %q, se esperaba %q", cfg.Important, want)
+	}
+	if cfg.Alerts[0].Symbol != "GGAL.BA" || cfg.Alerts[1].Symbol != "*" {
+		t.Errorf("alertas = %v", cfg.Alerts)
+	}
+	if want := map[string]float64{"GGAL.BA": 15}; !reflect.DeepEqual(cfg.MervalWeights, want) {
+		t.Errorf("mervalWeights = %v, se esperaba %v", cfg.MervalWeights, want)
+	}
+
+	text := warningsText(warnings)
+	for _, want := range []string{
+		`stocks: "ggal" normalizado a GGAL`,
+		"stocks: GGAL repetido, se fusionó",
+		"stocks: GGAL.BA repetido, se fusionó",
+		"forex: GGAL ya está en seguimiento, se fusionó",
+		"pinned: GGAL repetido, se fusionó",
+		"important: GGAL repetido, se fusionó",
+		"important: BMA no está en stocks",
+		"mervalWeights: GGAL.BA repetido, se sumaron las ponderaciones",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("falta la advertencia %q en:\n%s", want, text)
+		}
+	}
+	if strings.Count(text, "stocks: GGAL repetido, se fusionó") != 2 {
+		t.Errorf("se esperaban dos fusiones de GGAL en stocks:\n%s", text)
+	}
+}
+
+func TestNormalizeConfigSymbolsMarketConflict(t *testing.T) {
+	cfg := &Config{Stocks: []WatchlistEntry{{"GGAL", MarketNYSE}, {"ggal", MarketBYMA}}}
+	warnings := normalizeConfigSymbols(cfg)
+	if len(cfg.Stocks) != 1 || cfg.Stocks[0].Market != MarketNYSE {
+		t.Errorf("stocks = %v, se esperaba el primero (NYSE)", cfg.Stocks)
+	}
+	if text := warningsText(warnings); !strings.Contains(text, "mercados distintos") {
+		t.Errorf("falta la advertencia de mercados distintos:\n%s", text)
+	}
+}
+
+func TestNormalizeConfigSymbolsInvalidAndSuggestion(t *testing.T) {
+	cfg := &Config{Stocks: []WatchlistEntry{{"GGAL.B", MarketBYMA}, {"GG AL", MarketNYSE}}}
+	text := warningsText(normalizeConfigSymbols(cfg))
+	if !strings.Contains(text, "GGAL.B no está en el catálogo") || !strings.Contains(text, "GGAL.BA") {
+		t.Errorf("se esperaba la sugerencia GGAL.BA para GGAL.B:\n%s", text)
+	}
+	if !strings.Contains(text, `"GG AL" no parece un símbolo válido`) {
+		t.Errorf("se esperaba la advertencia de símbolo inválido:\n%s", text)
+	}
+}
+
+func TestLoadConfigNormalizesSymbols(t *testing.T) {
+	writeConfig(t, `{"stocks": [{"symbol": "ggal", "market": "NYSE"}, {"symbol": " GGAL ", "market": "NYSE"}, {"symbol": "GGAL", "market": "NYSE"}], "pinned": ["ggal"]}`)
+	cfg, err := loadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Stocks) != 1 || cfg.Stocks[0].Symbol != "GGAL" || !reflect.DeepEqual(cfg.Pinned, []string{"GGAL"}) {
+		t.Errorf("stocks = %v, pinned = %q; se esperaba un único GGAL", cfg.Stocks, cfg.Pinned)
+	}
+	if len(cfg.symbolWarnings) == 0 {
+		t.Error("la carga debería dejar advertencias para mostrar")
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Campos por los que se puede ordenar la tabla de acciones
+const (
+	SortSymbol = "symbol"
+	SortChange = "change"
+	SortVolume = "volume"
+	SortPrice  = "price"
+	SortSector = "sector"
+)
+
+// Nombres de cada campo para el encabezado de la tabla
+var sortFieldNames = map[string]string{
+	SortSymbol: "símbolo",
+	SortChange: "variación %",
+	SortVolume: "volumen",
+	SortPrice:  "precio",
+	SortSector: "sector",
+}
+
+// TableSort es el ordenamiento de la tabla de acciones, recordado entre ejecuciones
+type TableSort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+const tableSortFile = "table_sort.json"
+
+var (
+	tableSortMu     sync.Mutex
+	tableSortLoaded bool
+	tableSort       = TableSort{Field: SortSymbol}
+)
+
+// String describe el ordenamiento como "variación % descendente"
+func (s TableSort) String() string {
+	direction := "ascendente"
+	if s.Desc {
+		direction = "descendente"
+	}
+	return sortFieldNames[s.Field] + " " + direction
+}
+
+// parseTableSort interpreta "campo", "campo:desc" o "campo desc"
+func parseTableSort(spec string) (TableSort, error) {
+	spec = strings.ToLower(strings.TrimSpace(strings.ReplaceAll(spec, ":", " ")))
+	fields := strings.Fields(spec)
+	if len(fields) == 0 || len(fields) > 2 {
+		return TableSort{}, fmt.Errorf("ordenamiento inválido %q (usar campo[:asc|desc])", spec)
+	}
+
+	s := TableSort{Field: fields[0]}
+	if _, ok := sortFieldNames[s.Field]; !ok {
+		return TableSort{}, fmt.Errorf("campo de orden desconocido %q (usar symbol, change, volume, price o sector)", fields[0])
+	}
+	if len(fields) == 2 {
+		switch fields[1] {
+		case "asc":
+		case "desc":
+			s.Desc = true
+		default:
+			return TableSort{}, fmt.Errorf("dirección de orden inválida %q (usar asc o desc)", fields[1])
+		}
+	}
+	return s, nil
+}
+
+// currentTableSort devuelve el ordenamiento activo, leyendo el guardado la primera vez
+func currentTableSort() TableSort {
+	tableSortMu.Lock()
+	defer tableSortMu.Unlock()
+
+	if !tableSortLoaded {
+		tableSortLoaded = true
+		if path, err := appFile(tableSortFile); err == nil {
+			if data, err := os.ReadFile(path); err == nil {
+				var saved TableSort
+				if err := json.Unmarshal(data, &saved); err == nil && sortFieldNames[saved.Field] != "" {
+					tableSort = saved
+				}
+			}
+		}
+	}
+	return tableSort
+}
+
+// setTableSort cambia el ordenamiento activo y lo guarda para las próximas ejecuciones
+func setTableSort(s TableSort) error {
+	tableSortMu.Lock()
+	defer tableSortMu.Unlock()
+
+	tableSort, tableSortLoaded = s, true
+
+	path, err := appFile(tableSortFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// stockSector devuelve el sector de un papel según el catálogo ("" si no está)
+func stockSector(symbol string) string {
+	for _, entry := range catalog {
+		if entry.Symbol == symbol {
+			return entry.Sector
+		}
+	}
+	return ""
+}
+
+// sortStocks ordena las acciones según el ordenamiento indicado; los empates se ordenan por símbolo
+// y los papeles sin sector conocido van al final al ordenar por sector
+func sortStocks(list []StockInfo, s TableSort) {
+	less := func(a, b StockInfo) (bool, bool) {
+		switch s.Field {
+		case SortChange:
+			return a.ChangePercent < b.ChangePercent, a.ChangePercent == b.ChangePercent
+		case SortVolume:
+			return a.Volume < b.Volume, a.Volume == b.Volume
+		case SortPrice:
+			return a.Price < b.Price, a.Price == b.Price
+		case SortSector:
+			sa, sb := stockSector(a.Symbol), stockSector(b.Symbol)
+			return sa < sb, sa == sb
+		}
+		return a.Symbol < b.Symbol, a.Symbol == b.Symbol
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		a, b := list[i], list[j]
+		if s.Field == SortSector {
+			if sa, sb := stockSector(a.Symbol), stockSector(b.Symbol); (sa == "") != (sb == "") {
+				return sb == ""
+			}
+		}
+		isLess, equal := less(a, b)
+		if equal {
+			return a.Symbol < b.Symbol
+		}
+		if s.Desc {
+			return !isLess
+		}
+		return isLess
+	})
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TelegramNotifier envía notificaciones a un chat mediante la Bot API de Telegram
+type TelegramNotifier struct {
+	token  string
+	chatID string
+	http   *HTTPClient
+}
+
+// NewTelegramNotifier crea un notificador de Telegram para un bot y chat dados
+func NewTelegramNotifier(token, chatID string) *TelegramNotifier {
+	return &TelegramNotifier{
+		token:  token,
+		chatID: chatID,
+		http:   NewProviderClient("telegram"),
+	}
+}
+
+// Name devuelve el nombre del canal
+func (t *TelegramNotifier) Name() string { return "telegram" }
+
+// Send envía el texto y, si hay adjuntos, cada uno como foto con el título como epígrafe
+func (t *TelegramNotifier) Send(n Notification) error {
+	text := n.Text
+	if n.Title != "" {
+		text = n.Title + "\n\n" + n.Text
+	}
+
+	if err := t.retry(func() error { return t.sendMessage(text) }); err != nil {
+		return err
+	}
+	for _, a := range n.Attachments {
+		if err := t.retry(func() error { return t.sendPhoto(a, n.Title) }); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// retry reintenta un envío según la política configurada para el proveedor telegram
+func (t *TelegramNotifier) retry(send func() error) error {
+	var err error
+	for i := 0; i < t.http.config.MaxRetries || i == 0; i++ {
+		if i > 0 {
+			time.Sleep(t.http.backoff(i - 1))
+		}
+		if err = send(); err == nil {
+			markProviderOK(t.Name())
+			return nil
+		}
+	}
+	markProviderError(t.Name(), err)
+	return err
+}
+
+// endpoint arma la URL de un método de la Bot API
+func (t *TelegramNotifier) endpoint(method string) string {
+	return fmt.Sprintf("https://api.telegram.org/bot%s/%s", t.token, method)
+}
+
+// telegramError saca el token de un error de red de la Bot API: *url.Error incluye la URL completa, que lleva
+// el token, y el error termina en el estado de los proveedores, el historial de alertas y los hooks
+func telegramError(token string, err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = fmt.Errorf("%s api.telegram.org: %w", urlErr.Op, urlErr.Err)
+	}
+	if token != "" && strings.Contains(err.Error(), token) {
+		return errors.New(strings.ReplaceAll(err.Error(), token, "<token>"))
+	}
+	return err
+}
+
+// sendMessage envía un mensaje de texto
+func (t *TelegramNotifier) sendMessage(text string) error {
+	resp, err := t.http.client.PostForm(t.endpoint("sendMessage"), url.Values{
+		"chat_id": {t.chatID},
+		"text":    {text},
+	})
+	if err != nil {
+		return telegramError(t.token, err)
+	}
+	return checkTelegramResponse(resp)
+}
+
+// sendPhoto sube una imagen como multipart/form-data
+func (t *TelegramNotifier) sendPhoto(a Attachment, caption string) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	writer.WriteField("chat_id", t.chatID)
+	if caption != "" {
+		writer.WriteField("caption", caption)
+	}
+
+	part, err := writer.CreateFormFile("photo", a.Name)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(a.Data); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	resp, err := t.http.client.Post(t.endpoint("sendPhoto"), writer.FormDataContentType(), &body)
+	if err != nil {
+		return telegramError(t.token, err)
+	}
+	return checkTelegramResponse(resp)
+}
+
+// checkTelegramResponse valida la respuesta de la Bot API y cierra el cuerpo
+func checkTelegramResponse(resp *http.Response) error {
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("respuesta inválida de Telegram (código %d): %v", resp.StatusCode, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("Telegram rechazó el mensaje: %s", result.Description)
+	}
+	return nil
+}
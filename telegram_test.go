@@ -0,0 +1,59 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// failingTransport simula una caída de red: el cliente devuelve un *url.Error con la URL completa
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("dial tcp: connection refused")
+}
+
+func TestTelegramErrorsDoNotLeakToken(t *testing.T) {
+	const token = "123456:AAH-secreto"
+	notifier := NewTelegramNotifier(token, "42")
+	notifier.http.client.Transport = failingTransport{}
+
+	sends := map[string]func() error{
+		"sendMessage": func() error { return notifier.sendMessage("hola") },
+		"sendPhoto":   func() error { return notifier.sendPhoto(Attachment{Name: "a.png", Data: []byte("png")}, "título") },
+	}
+	for name, send := range sends {
+		err := send()
+		if err == nil {
+			t.Fatalf("%s: se esperaba un error", name)
+		}
+		if strings.Contains(err.Error(), token) {
+			t.Errorf("%s: el error contiene el token: %v", name, err)
+		}
+		if !strings.Contains(err.Error(), "connection refused") {
+			t.Errorf("%s: el error perdió la causa: %v", name, err)
+		}
+	}
+}
+
+func TestTelegramBotErrorsDoNotLeakToken(t *testing.T) {
+	const token = "123456:AAH-secreto"
+	bot := &TelegramBot{token: token, http: NewProviderClient("telegram")}
+	bot.http.client.Transport = failingTransport{}
+
+	_, err := bot.getUpdates()
+	if err == nil {
+		t.Fatal("se esperaba un error")
+	}
+	if strings.Contains(err.Error(), token) {
+		t.Errorf("el error de getUpdates contiene el token: %v", err)
+	}
+}
+
+func TestTelegramErrorRedactsTokenAnywhere(t *testing.T) {
+	err := telegramError("123:abc", errors.New("redirect a https://api.telegram.org/bot123:abc/getMe"))
+	if got, want := err.Error(), "redirect a https://api.telegram.org/bot<token>/getMe"; got != want {
+		t.Errorf("telegramError = %q, se esperaba %q", got, want)
+	}
+}
@@ -0,0 +1,502 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reglas de alerta creadas con /alerta; se evalúan junto con las de config.json
+const remoteAlertsFile = "telegram_alerts.json"
+
+// Espera de cada getUpdates (long polling): Telegram responde apenas llega un mensaje
+const telegramPollTimeout = 30 * time.Second
+
+// Los mensajes más viejos que esto al arrancar se descartan: son consultas que ya nadie espera
+const telegramStaleMessage = 5 * time.Minute
+
+var remoteAlertsMu sync.Mutex
+
+// loadRemoteAlerts lee las reglas creadas desde Telegram, ya con su condición interpretada
+func loadRemoteAlerts() ([]AlertRule, error) {
+	path, err := appFile(remoteAlertsFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []AlertRule
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	valid := rules[:0]
+	for _, rule := range rules {
+		cond, err := parseCondition(rule.Condition)
+		if err != nil {
+			continue // Se valida al crearla; una regla editada a mano no frena al resto
+		}
+		rule.parsed = cond
+		valid = append(valid, rule)
+	}
+	return valid, nil
+}
+
+// updateRemoteAlerts aplica un cambio a las reglas creadas desde Telegram y lo persiste
+func updateRemoteAlerts(change func(rules []AlertRule) ([]AlertRule, error)) error {
+	remoteAlertsMu.Lock()
+	defer remoteAlertsMu.Unlock()
+
+	rules, err := loadRemoteAlerts()
+	if err != nil {
+		return err
+	}
+	rules, err = change(rules)
+	if err != nil {
+		return err
+	}
+	path, err := appFile(remoteAlertsFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(rules, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// telegramUpdate es un mensaje recibido por el bot
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Date int64  `json:"date"`
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// TelegramBot responde las consultas de los chats configurados: cotizaciones, dólares, cartera y alertas
+type TelegramBot struct {
+	token  string
+	chats  map[string]bool // Solo se atienden los chats que reciben las notificaciones
+	http   *HTTPClient
+	client QuoteFetcher
+	offset int64
+
+	mu   sync.Mutex
+	last *Snapshot // Último snapshot del monitor, para responder sin consultar a los proveedores
+}
+
+// startTelegramBot arranca el bot si notify.telegramCommands está activo y hay token y chats
+func startTelegramBot(pipeline *Pipeline, client QuoteFetcher) {
+	if !appConfig().Notify.TelegramCommands {
+		return
+	}
+	token, chats := telegramCredentials()
+	if token == "" || len(chats) == 0 {
+		fmt.Printf("%sComandos de Telegram desactivados: faltan TELEGRAM_BOT_TOKEN o los chats%s\n", Yellow, Reset)
+		return
+	}
+
+	bot := &TelegramBot{token: token, chats: make(map[string]bool), http: NewProviderClient("telegram"), client: client}
+	bot.http.client.Timeout = telegramPollTimeout + 10*time.Second
+	for _, chat := range chats {
+		bot.chats[chat] = true
+	}
+	pipeline.OnSnapshot(func(snapshot *Snapshot) {
+		bot.mu.Lock()
+		bot.last = snapshot
+		bot.mu.Unlock()
+	})
+
+	// Los símbolos de las alertas remotas tienen que estar en la watchlist para evaluarse
+	if rules, err := loadRemoteAlerts(); err == nil {
+		for _, rule := range rules {
+			watchAlertSymbol(rule.Symbol)
+		}
+	}
+	go bot.run()
+}
+
+// run consulta los mensajes nuevos indefinidamente; ante un error espera y reintenta
+func (b *TelegramBot) run() {
+	started := time.Now()
+	for {
+		updates, err := b.getUpdates()
+		if err != nil {
+			markProviderError("telegram", err)
+			time.Sleep(10 * time.Second)
+			continue
+		}
+		for _, update := range updates {
+			b.offset = update.UpdateID + 1
+			msg := update.Message
+			if msg == nil || !strings.HasPrefix(msg.Text, "/") {
+				continue
+			}
+			chatID := strconv.FormatInt(msg.Chat.ID, 10)
+			if !b.chats[chatID] {
+				fmt.Printf("%sComando de Telegram ignorado: el chat %s no está configurado%s\n", Yellow, chatID, Reset)
+				continue
+			}
+			if started.Sub(time.Unix(msg.Date, 0)) > telegramStaleMessage {
+				continue
+			}
+			reply := b.handle(msg.Text)
+			if err := NewTelegramNotifier(b.token, chatID).sendMessage(reply); err != nil {
+				fmt.Printf("%sError al responder por Telegram: %v%s\n", Red, err, Reset)
+			}
+		}
+	}
+}
+
+// getUpdates pide los mensajes posteriores al último procesado
+func (b *TelegramBot) getUpdates() ([]telegramUpdate, error) {
+	query := url.Values{
+		"offset":          {strconv.FormatInt(b.offset, 10)},
+		"timeout":         {strconv.Itoa(int(telegramPollTimeout / time.Second))},
+		"allowed_updates": {`["message"]`},
+	}
+	resp, err := b.http.client.Get(fmt.Sprintf("https://api.telegram.org/bot%s/getUpdates?%s", b.token, query.Encode()))
+	if err != nil {
+		return nil, telegramError(b.token, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool             `json:"ok"`
+		Description string           `json:"description"`
+		Result      []telegramUpdate `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("respuesta inválida de Telegram (código %d): %v", resp.StatusCode, err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("Telegram rechazó getUpdates: %s", result.Description)
+	}
+	markProviderOK("telegram")
+	return result.Result, nil
+}
+
+const telegramHelp = `Comandos:
+/precio GGAL [YPF ...] — cotización actual
+/dolar — tipos de cambio
+/cartera — valuación de la cartera
+/alerta GGAL > 50000 — crear una alerta (también "/alerta GGAL changePercent < -3")
+/alertas — reglas creadas desde Telegram y su estado
+/borrar REGLA — eliminar una regla creada desde Telegram
+/ok REGLA — reconocer la alerta disparada
+/silenciar REGLA 2h — posponer una regla`
+
+// handle interpreta un comando y devuelve la respuesta
+func (b *TelegramBot) handle(text string) string {
+	fields := strings.Fields(text)
+	// En grupos los comandos llegan como /precio@nombre_del_bot
+	command := strings.ToLower(strings.SplitN(fields[0], "@", 2)[0])
+	args := fields[1:]
+
+	var reply string
+	var err error
+	switch command {
+	case "/precio":
+		reply, err = b.replyPrice(args)
+	case "/dolar":
+		reply, err = b.replyForex()
+	case "/cartera":
+		reply, err = b.replyPortfolio()
+	case "/alerta":
+		reply, err = replyNewAlert(args)
+	case "/alertas":
+		reply, err = replyRemoteAlerts()
+	case "/borrar":
+		reply, err = replyDeleteAlert(args)
+	case "/ok", "/silenciar":
+		reply, err = replyManageAlert(command, args)
+	default:
+		reply = telegramHelp
+	}
+	if err != nil {
+		return "⚠️ " + err.Error()
+	}
+	return reply
+}
+
+// snapshot devuelve el último snapshot del monitor, o el guardado de la sesión anterior
+func (b *TelegramBot) snapshot() *Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.last == nil {
+		if cached, err := loadLastSnapshot(); err == nil {
+			return cached
+		}
+	}
+	return b.last
+}
+
+// resolveBotSymbol completa los símbolos locales sin sufijo: GGAL → GGAL.BA si está en el catálogo
+func resolveBotSymbol(arg string) string {
+	symbol := normalizeSymbol(arg)
+	if strings.ContainsAny(symbol, ".=-^") {
+		return symbol
+	}
+	if _, ok := catalogLookup(symbol + ".BA"); ok {
+		return symbol + ".BA"
+	}
+	for _, stock := range watchlistSnapshot() {
+		if stock.Symbol == symbol+".BA" {
+			return stock.Symbol
+		}
+	}
+	return symbol
+}
+
+// replyPrice cotiza los símbolos pedidos: del snapshot si están en la watchlist, si no en vivo
+func (b *TelegramBot) replyPrice(args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("uso: /precio GGAL [YPF ...]")
+	}
+
+	known := make(map[string]StockInfo)
+	if snapshot := b.snapshot(); snapshot != nil {
+		for _, stock := range snapshot.Stocks {
+			known[stock.Symbol] = stock
+		}
+	}
+
+	var lines []string
+	for _, arg := range args {
+		symbol := resolveBotSymbol(arg)
+		stock, ok := known[symbol]
+		if !ok || stock.Price == 0 {
+			quote := fetchQuotes([]string{symbol}, b.client)[0]
+			if quote.Error != "" {
+				lines = append(lines, fmt.Sprintf("%s: %s", symbol, quote.Error))
+				continue
+			}
+			stock = StockInfo{Symbol: symbol, Name: quote.Name, Price: quote.Price, ChangePercent: quote.ChangePercent, Volume: quote.Volume}
+		}
+		line := fmt.Sprintf("%s %.2f (%+.2f%%) vol. %s", stock.Symbol, stock.Price, stock.ChangePercent, formatCompact(float64(stock.Volume)))
+		if stock.Name != "" {
+			line = fmt.Sprintf("%s — %s", line, stock.Name)
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// replyForex muestra los tipos de cambio del último ciclo
+func (b *TelegramBot) replyForex() (string, error) {
+	snapshot := b.snapshot()
+	if snapshot == nil || len(snapshot.Forex) == 0 {
+		return "", fmt.Errorf("todavía no hay cotizaciones del dólar: esperá el primer ciclo del monitor")
+	}
+	lines := []string{"💵 Dólares (" + snapshot.Time.Format("15:04") + ")"}
+	for _, forex := range snapshot.Forex {
+		lines = append(lines, fmt.Sprintf("%s: %.2f (%+.2f%%)", forex.Name, forex.Price, forex.ChangePercent))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// replyPortfolio valúa la cartera de portfolio.json y bond_holdings.json con precios en vivo
+func (b *TelegramBot) replyPortfolio() (string, error) {
+	portfolio, err := loadPortfolio()
+	if err != nil {
+		return "", err
+	}
+	priced, err := pricePortfolio(portfolio, b.client)
+	if err != nil {
+		return "", err
+	}
+	if len(priced) == 0 {
+		return "La cartera no tiene tenencias", nil
+	}
+
+	var total float64
+	for _, h := range priced {
+		total += h.Value
+	}
+	sort.Slice(priced, func(i, j int) bool { return priced[i].Value > priced[j].Value })
+
+	lines := []string{"💼 Cartera"}
+	for _, h := range priced {
+		lines = append(lines, fmt.Sprintf("%s: $%s (%.1f%%)", h.Symbol, formatCompact(h.Value), h.Value/total*100))
+	}
+	totalLine := fmt.Sprintf("Total: $%s", formatCompact(total))
+	if mep, err := liveMEP(b.client); err == nil && mep > 0 {
+		totalLine += fmt.Sprintf(" (US$%s al MEP %.2f)", formatCompact(total/mep), mep)
+	}
+	return strings.Join(append(lines, totalLine), "\n"), nil
+}
+
+// watchAlertSymbol agrega a la watchlist el símbolo de una alerta remota, salvo los tipos de cambio
+func watchAlertSymbol(symbol string) {
+	if symbol == "*" {
+		return
+	}
+	for _, fx := range appConfig().Forex {
+		if fx.Symbol == symbol {
+			return
+		}
+	}
+	if addStock(symbol, marketForSymbol(symbol)) {
+		fmt.Printf("%s agregado a la watchlist por una alerta de Telegram\n", symbol)
+	}
+}
+
+// replyNewAlert crea una regla: "/alerta GGAL > 50000" compara el precio; también acepta cualquier condición
+func replyNewAlert(args []string) (string, error) {
+	if len(args) < 2 {
+		return "", fmt.Errorf("uso: /alerta GGAL > 50000 o /alerta GGAL changePercent < -3")
+	}
+	symbol := resolveBotSymbol(args[0])
+	if args[0] == "*" {
+		symbol = "*"
+	}
+	condition := strings.Join(args[1:], " ")
+	if strings.ContainsAny(args[1][:1], "<>=!") {
+		condition = "price " + condition
+	}
+	cond, err := parseCondition(condition)
+	if err != nil {
+		return "", fmt.Errorf("condición inválida: %v", err)
+	}
+
+	var name string
+	err = updateRemoteAlerts(func(rules []AlertRule) ([]AlertRule, error) {
+		taken := make(map[string]bool)
+		for _, rule := range append(rules, appConfig().Alerts...) {
+			taken[rule.Name] = true
+		}
+		base := "tg_" + strings.ToLower(strings.TrimSuffix(symbol, ".BA"))
+		if symbol == "*" {
+			base = "tg_todos"
+		}
+		for i := 1; ; i++ {
+			name = fmt.Sprintf("%s_%d", base, i)
+			if !taken[name] {
+				break
+			}
+		}
+		return append(rules, AlertRule{Name: name, Symbol: symbol, Condition: condition}), nil
+	})
+	if err != nil {
+		return "", err
+	}
+	watchAlertSymbol(symbol)
+	return fmt.Sprintf("✅ Alerta %s: %s cuando %s", name, symbol, cond), nil
+}
+
+// replyRemoteAlerts lista las reglas creadas desde Telegram con su estado de gestión
+func replyRemoteAlerts() (string, error) {
+	rules, err := loadRemoteAlerts()
+	if err != nil {
+		return "", err
+	}
+	if len(rules) == 0 {
+		return "No hay alertas creadas desde Telegram. Creá una con /alerta GGAL > 50000", nil
+	}
+
+	alertStateMu.Lock()
+	state, err := loadAlertState()
+	alertStateMu.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	var lines []string
+	for _, rule := range rules {
+		status := "activa"
+		if rs, ok := state[rule.Name]; ok {
+			switch {
+			case rs.isDisabled(now):
+				status = "deshabilitada"
+			case rs.isSnoozed(now):
+				status = "pospuesta hasta " + rs.SnoozedUntil.Format("02/01 15:04")
+			}
+			if len(rs.Active) > 0 {
+				status += ", disparada"
+			}
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s %s (%s)", rule.Name, rule.Symbol, rule.Condition, status))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// replyDeleteAlert elimina una regla creada desde Telegram; las de config.json se editan en el archivo
+func replyDeleteAlert(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("uso: /borrar REGLA (ver /alertas)")
+	}
+	name := args[0]
+	err := updateRemoteAlerts(func(rules []AlertRule) ([]AlertRule, error) {
+		for i, rule := range rules {
+			if rule.Name == name {
+				return append(rules[:i], rules[i+1:]...), nil
+			}
+		}
+		return nil, fmt.Errorf("no hay una alerta de Telegram llamada %s", name)
+	})
+	if err != nil {
+		return "", err
+	}
+	alertStateMu.Lock()
+	defer alertStateMu.Unlock()
+	if state, err := loadAlertState(); err == nil {
+		delete(state, name)
+		saveAlertState(state)
+	}
+	return "🗑️ Alerta " + name + " eliminada", nil
+}
+
+// replyManageAlert reconoce (/ok) o pospone (/silenciar) cualquier regla, como `bolsa alerts ack|snooze`
+func replyManageAlert(command string, args []string) (string, error) {
+	if len(args) == 0 {
+		return "", fmt.Errorf("uso: /ok REGLA o /silenciar REGLA 2h")
+	}
+	name := args[0]
+	if err := knownAlertRule(name); err != nil {
+		return "", err
+	}
+
+	if command == "/ok" {
+		count := 0
+		err := updateAlertState(name, func(rs *AlertRuleState) {
+			for _, episode := range rs.Active {
+				episode.Acked = true
+				count++
+			}
+		})
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("👍 %s: %d alerta(s) reconocida(s)", name, count), nil
+	}
+
+	if len(args) < 2 {
+		return "", fmt.Errorf("uso: /silenciar REGLA 2h")
+	}
+	d, err := time.ParseDuration(args[1])
+	if err != nil {
+		return "", fmt.Errorf("duración inválida %q: %v", args[1], err)
+	}
+	until := time.Now().Add(d)
+	if err := updateAlertState(name, func(rs *AlertRuleState) { rs.SnoozedUntil = until }); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("🔕 %s pospuesta hasta %s", name, until.Format("02/01 15:04")), nil
+}
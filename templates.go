@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// Mensajes que se pueden personalizar con templates en config.json
+const (
+	TemplateAlerts       = "alerts"        // Cuerpo de una notificación de alertas
+	TemplateCloseSummary = "close_summary" // Cuerpo del resumen de cierre
+	TemplateSocialPost   = "social_post"   // Post del cierre en Mastodon o X
+)
+
+// AlertsMessage son los datos disponibles en el template "alerts"
+type AlertsMessage struct {
+	Title    string
+	Alerts   []Alert
+	Snapshot *Snapshot // Puede ser nil
+	Default  string    // El texto que se enviaría sin template
+}
+
+// CloseSummaryMessage son los datos disponibles en el template "close_summary"
+type CloseSummaryMessage struct {
+	Title    string
+	Date     string
+	Snapshot *Snapshot
+	Default  string
+}
+
+// Funciones auxiliares disponibles en los templates
+var templateFuncs = template.FuncMap{
+	"pct":   func(v float64) string { return fmt.Sprintf("%+.2f%%", v) },
+	"money": func(v float64) string { return fmt.Sprintf("$%.2f", v) },
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"join":  strings.Join,
+}
+
+// parseMessageTemplates valida los templates del usuario
+func parseMessageTemplates(sources map[string]string) (map[string]*template.Template, error) {
+	parsed := make(map[string]*template.Template, len(sources))
+	for name, source := range sources {
+		switch name {
+		case TemplateAlerts, TemplateCloseSummary, TemplateSocialPost:
+		default:
+			return nil, fmt.Errorf("template desconocido %q (templates: %s, %s, %s)", name, TemplateAlerts, TemplateCloseSummary, TemplateSocialPost)
+		}
+		tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(source)
+		if err != nil {
+			return nil, fmt.Errorf("template %s inválido: %v", name, err)
+		}
+		parsed[name] = tmpl
+	}
+	return parsed, nil
+}
+
+// renderMessage arma un mensaje con el template del usuario, o devuelve fallback si no hay o falla
+func renderMessage(name string, data interface{}, fallback string) string {
+	tmpl, ok := appConfig().templates[name]
+	if !ok {
+		return fallback
+	}
+
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		fmt.Printf("%sError en el template %s, se usa el mensaje por defecto: %v%s\n", Red, name, err, Reset)
+		return fallback
+	}
+	return b.String()
+}
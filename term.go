@@ -0,0 +1,160 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Tamaño de la terminal, leído una vez y actualizado en cada cambio de tamaño
+var (
+	termSizeMu   sync.Mutex
+	termCols     int
+	termRows     int
+	termSizeRead bool
+)
+
+// terminalSize devuelve columnas y filas de la terminal (COLUMNS/LINES, consola, stty o 100x30 por defecto)
+func terminalSize() (int, int) {
+	termSizeMu.Lock()
+	defer termSizeMu.Unlock()
+
+	if !termSizeRead {
+		termCols, termRows = readTerminalSize()
+		termSizeRead = true
+	}
+	return termCols, termRows
+}
+
+// readTerminalSize consulta el tamaño actual de la terminal
+func readTerminalSize() (int, int) {
+	cols, _ := strconv.Atoi(os.Getenv("COLUMNS"))
+	rows, _ := strconv.Atoi(os.Getenv("LINES"))
+	if cols > 0 && rows > 0 {
+		return cols, rows
+	}
+
+	if c, r, ok := consoleSize(); ok {
+		return c, r
+	}
+
+	cmd := exec.Command("stty", "size")
+	cmd.Stdin = os.Stdin
+	if out, err := cmd.Output(); err == nil {
+		if fields := strings.Fields(string(out)); len(fields) == 2 {
+			r, errR := strconv.Atoi(fields[0])
+			c, errC := strconv.Atoi(fields[1])
+			if errR == nil && errC == nil && r > 0 && c > 0 {
+				return c, r
+			}
+		}
+	}
+	return 100, 30
+}
+
+// watchResize vuelve a leer el tamaño de la terminal en cada cambio (SIGWINCH) y llama a redraw si cambió
+func watchResize(redraw func()) {
+	for range resizeEvents() {
+		cols, rows := readTerminalSize()
+
+		termSizeMu.Lock()
+		changed := cols != termCols || rows != termRows
+		termCols, termRows, termSizeRead = cols, rows, true
+		termSizeMu.Unlock()
+
+		if changed {
+			redraw()
+		}
+	}
+}
+
+// StockLayout son las columnas y filas de la tabla de acciones que entran en la terminal
+type StockLayout struct {
+	NameWidth int  // 0 oculta el nombre
+	Volume    bool // Columna de volumen
+	Session   bool // Mínimo y máximo de la sesión
+	Risk      bool // Etiqueta de riesgo (bajo, medio, alto)
+	MaxRows   int  // 0 = sin límite
+}
+
+// fullStockLayout muestra todas las columnas sin límite de filas (modo plano, salida redirigida)
+var fullStockLayout = StockLayout{NameWidth: 30, Volume: true, Session: true, Risk: true}
+
+// stockLayoutFor elige las columnas según el ancho y la cantidad de filas según el alto disponible
+func stockLayoutFor(cols, availableRows int) StockLayout {
+	layout := StockLayout{MaxRows: max(availableRows, 5)}
+	switch {
+	case cols >= 125:
+		layout.NameWidth, layout.Volume, layout.Session, layout.Risk = 30, true, true, true
+	case cols >= 95:
+		layout.NameWidth, layout.Volume, layout.Risk = 30, true, true
+	case cols >= 75:
+		layout.NameWidth = 20
+	}
+	return layout
+}
+
+// Último snapshot mostrado, para redibujarlo cuando cambia el tamaño de la terminal (protegido por screenMu)
+var (
+	lastScreen *Snapshot
+	lastView   View
+)
+
+// redrawScreen vuelve a mostrar el último snapshot con el tamaño actual de la terminal
+func redrawScreen() {
+	screenMu.Lock()
+	snapshot, view := lastScreen, lastView
+	screenMu.Unlock()
+
+	if snapshot != nil {
+		displayData(snapshot, view)
+	}
+}
+
+// reservedLines estima las líneas que ocupa la pantalla fuera de las filas de la tabla de acciones
+func reservedLines(snapshot *Snapshot, view View) int {
+	lines := 3 // Estado de mercados, hora de actualización y margen
+	if snapshot.Cached {
+		lines++ // Aviso de datos de la sesión anterior
+	}
+	if view.Heatmap() {
+		_, rows := terminalSize()
+		lines += rows / 2
+	}
+	if view.Includes(ViewForex) {
+		lines += len(snapshot.Forex) + 4
+		if snapshot.Equilibrium != nil {
+			lines += 2
+		}
+		if len(snapshot.ExportRates) > 0 {
+			lines += len(snapshot.ExportRates) + 1
+		}
+		if snapshot.Carry != nil && len(snapshot.Carry.Quotes) > 0 {
+			lines += len(snapshot.Carry.Quotes) + 4
+		}
+	}
+	if view.Includes(ViewStocks) {
+		lines += 11 // Índice, encabezados y resumen de la tabla
+		if len(snapshot.OrderBooks) > 0 {
+			lines += len(snapshot.OrderBooks) + 4
+		}
+	}
+	if view.Includes(ViewBonds) && len(snapshot.Bonds) > 0 {
+		lines += len(snapshot.Bonds) + 4
+	}
+	if view.Includes(ViewFunds) && len(snapshot.Funds) > 0 {
+		lines += len(snapshot.Funds) + 4
+	}
+	if view.Includes(ViewCommodities) && len(snapshot.Commodities) > 0 {
+		lines += len(snapshot.Commodities) + 5
+	}
+	if len(snapshot.Errors) > 0 {
+		lines += len(snapshot.Errors) + 2
+	}
+	if view.Interactive() {
+		lines += 6
+	}
+	return lines + 1
+}
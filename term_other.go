@@ -0,0 +1,38 @@
+//go:build !windows
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// enableANSI no hace nada fuera de Windows: las terminales interpretan las secuencias ANSI
+func enableANSI() bool {
+	return true
+}
+
+// shutdownSignals devuelve las señales que cierran el programa ordenadamente: Ctrl+C, kill y el cierre de la terminal
+func shutdownSignals() []os.Signal {
+	return []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+}
+
+// consoleSize no se usa fuera de Windows: el tamaño se obtiene con stty
+func consoleSize() (int, int, bool) {
+	return 0, 0, false
+}
+
+// resizeEvents avisa cada vez que la terminal cambia de tamaño (SIGWINCH)
+func resizeEvents() <-chan struct{} {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGWINCH)
+
+	events := make(chan struct{})
+	go func() {
+		for range signals {
+			events <- struct{}{}
+		}
+	}()
+	return events
+}
@@ -0,0 +1,90 @@
+//go:build windows
+
+package main
+
+import (
+	"os"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// Modo de consola de Windows que interpreta las secuencias ANSI (Windows 10 o posterior)
+const enableVirtualTerminalProcessing = 0x0004
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procGetConsoleMode = kernel32.NewProc("GetConsoleMode")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+
+	procSetConsoleOutputCP = kernel32.NewProc("SetConsoleOutputCP")
+
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+)
+
+// consoleScreenBufferInfo es la estructura CONSOLE_SCREEN_BUFFER_INFO de la API de consola
+type consoleScreenBufferInfo struct {
+	Size              [2]int16
+	CursorPosition    [2]int16
+	Attributes        uint16
+	Window            [4]int16 // Izquierda, arriba, derecha, abajo
+	MaximumWindowSize [2]int16
+}
+
+// Página de códigos UTF-8, para que los acentos y los símbolos de las tablas se vean igual que en Linux
+const codePageUTF8 = 65001
+
+// enableANSI activa las secuencias ANSI y la salida UTF-8 en la consola; devuelve false si la consola no las soporta
+func enableANSI() bool {
+	procSetConsoleOutputCP.Call(codePageUTF8)
+
+	// stderr también se activa: los errores se imprimen con colores
+	enableVirtualTerminal(os.Stderr)
+	return enableVirtualTerminal(os.Stdout)
+}
+
+// enableVirtualTerminal activa Virtual Terminal Processing en el handle de un archivo de consola
+func enableVirtualTerminal(f *os.File) bool {
+	handle := syscall.Handle(f.Fd())
+
+	var mode uint32
+	if ok, _, _ := procGetConsoleMode.Call(uintptr(handle), uintptr(unsafe.Pointer(&mode))); ok == 0 {
+		// No es una consola (salida redirigida a un archivo o a un pipe)
+		return false
+	}
+	if mode&enableVirtualTerminalProcessing != 0 {
+		return true
+	}
+	ok, _, _ := procSetConsoleMode.Call(uintptr(handle), uintptr(mode|enableVirtualTerminalProcessing))
+	return ok != 0
+}
+
+// shutdownSignals devuelve las señales que cierran el programa ordenadamente. Windows no tiene SIGTERM real:
+// el runtime de Go entrega CTRL_CLOSE_EVENT (cerrar la ventana), CTRL_LOGOFF_EVENT y CTRL_SHUTDOWN_EVENT
+// como syscall.SIGTERM, y Windows espera unos segundos a que el proceso termine antes de matarlo
+func shutdownSignals() []os.Signal {
+	return []os.Signal{os.Interrupt, syscall.SIGTERM}
+}
+
+// consoleSize devuelve el tamaño de la ventana visible de la consola
+func consoleSize() (int, int, bool) {
+	var info consoleScreenBufferInfo
+	handle := syscall.Handle(os.Stdout.Fd())
+	if ok, _, _ := procGetConsoleScreenBufferInfo.Call(uintptr(handle), uintptr(unsafe.Pointer(&info))); ok == 0 {
+		return 0, 0, false
+	}
+	cols := int(info.Window[2]-info.Window[0]) + 1
+	rows := int(info.Window[3]-info.Window[1]) + 1
+	return cols, rows, cols > 0 && rows > 0
+}
+
+// resizeEvents revisa el tamaño de la consola cada segundo: Windows no tiene SIGWINCH
+func resizeEvents() <-chan struct{} {
+	events := make(chan struct{})
+	go func() {
+		for range time.Tick(time.Second) {
+			events <- struct{}{}
+		}
+	}()
+	return events
+}
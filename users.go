@@ -0,0 +1,772 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const usersFile = "users.json"
+
+// Alertas disparadas que se conservan por usuario
+const userTriggeredKept = 200
+
+// Símbolos que cada usuario puede seguir desde la API
+const userWatchlistMax = 50
+
+var userNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,31}$`)
+
+// ServerUser es un usuario del modo servidor; de la API key se guarda solo el hash
+type ServerUser struct {
+	Name    string    `json:"name"`
+	KeyHash string    `json:"keyHash"` // SHA-256 en hexadecimal
+	Created time.Time `json:"created"`
+}
+
+// UserData es lo que cada usuario persiste en el servidor: watchlist, cartera y alertas
+type UserData struct {
+	Watchlist []WatchlistEntry `json:"watchlist"`
+	Portfolio *Portfolio       `json:"portfolio,omitempty"`
+	Alerts    []AlertRule      `json:"alerts"`
+	Triggered []Alert          `json:"triggered,omitempty"` // Últimas alertas disparadas, la más reciente al final
+	Active    map[string]bool  `json:"active,omitempty"`    // Condiciones en curso (regla|símbolo), para no repetir el disparo
+
+	TelegramChat string `json:"telegramChat,omitempty"` // Chat donde el bot del servidor le envía sus alertas
+}
+
+// Los datos de los usuarios se escriben desde la API y desde el pipeline
+var userDataMu sync.Mutex
+
+// Usuarios registrados y símbolos de sus watchlists, en memoria bajo un único lock. Los símbolos se cotizan
+// en cada ciclo sin entrar en la watchlist del servidor y dejan de cotizarse cuando ningún usuario los sigue;
+// users.json se relee solo cuando cambia (bolsa users lo escribe desde otro proceso)
+var userRegistry = struct {
+	sync.Mutex
+	users   []ServerUser
+	loaded  bool
+	modTime time.Time
+	size    int64
+	symbols map[string][]WatchlistEntry
+}{symbols: make(map[string][]WatchlistEntry)}
+
+// Último snapshot del servidor, para responder /api/me/quotes
+var (
+	userSnapshotMu sync.Mutex
+	userSnapshot   *Snapshot
+)
+
+// hashAPIKey devuelve el hash con el que se guarda y se compara una API key
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadUsers lee los usuarios registrados
+func loadUsers() ([]ServerUser, error) {
+	path, err := appFile(usersFile)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var users []ServerUser
+	if err := json.Unmarshal(data, &users); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return users, nil
+}
+
+// saveUsers escribe los usuarios registrados; el archivo tiene hashes de claves y no debe ser legible por otros
+func saveUsers(users []ServerUser) error {
+	path, err := appFile(usersFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(users, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// userForKey identifica al usuario dueño de una API key
+func userForKey(key string) (string, bool) {
+	if key == "" {
+		return "", false
+	}
+	users, err := registeredUsers()
+	if err != nil {
+		fmt.Printf("Error al leer los usuarios: %v\n", err)
+		return "", false
+	}
+	hash := []byte(hashAPIKey(key))
+	for _, u := range users {
+		if subtle.ConstantTimeCompare(hash, []byte(u.KeyHash)) == 1 {
+			return u.Name, true
+		}
+	}
+	return "", false
+}
+
+// userDataPath devuelve el archivo de datos de un usuario
+func userDataPath(name string) (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "users")
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name+".json"), nil
+}
+
+// loadUserData lee los datos de un usuario; un usuario nuevo empieza vacío
+func loadUserData(name string) (*UserData, error) {
+	path, err := userDataPath(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &UserData{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ud UserData
+	if err := json.Unmarshal(data, &ud); err != nil {
+		return nil, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return &ud, nil
+}
+
+// saveUserData escribe los datos de un usuario
+func saveUserData(name string, ud *UserData) error {
+	path, err := userDataPath(name)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(ud, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// updateUserData lee, modifica y guarda los datos de un usuario bajo el lock compartido
+func updateUserData(name string, update func(ud *UserData) error) (*UserData, error) {
+	userDataMu.Lock()
+	defer userDataMu.Unlock()
+
+	ud, err := loadUserData(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := update(ud); err != nil {
+		return nil, err
+	}
+	return ud, saveUserData(name, ud)
+}
+
+// registeredUsers devuelve los usuarios registrados; users.json se relee solo si cambió desde la última lectura
+func registeredUsers() ([]ServerUser, error) {
+	userRegistry.Lock()
+	defer userRegistry.Unlock()
+	if err := refreshUsersLocked(); err != nil {
+		return nil, err
+	}
+	return append([]ServerUser(nil), userRegistry.users...), nil
+}
+
+// refreshUsersLocked relee users.json si cambió su fecha o su tamaño: los usuarios eliminados dejan de cotizar
+// sus símbolos y de los nuevos se cargan las watchlists guardadas. Se llama con userRegistry tomado
+func refreshUsersLocked() error {
+	path, err := appFile(usersFile)
+	if err != nil {
+		return err
+	}
+	var modTime time.Time
+	var size int64
+	info, err := os.Stat(path)
+	switch {
+	case err == nil:
+		modTime, size = info.ModTime(), info.Size()
+	case !os.IsNotExist(err):
+		return err
+	}
+	if userRegistry.loaded && modTime.Equal(userRegistry.modTime) && size == userRegistry.size {
+		return nil
+	}
+
+	users, err := loadUsers()
+	if err != nil {
+		return err
+	}
+	known := make(map[string]bool)
+	for _, u := range userRegistry.users {
+		known[u.Name] = true
+	}
+	current := make(map[string]bool)
+	for _, u := range users {
+		current[u.Name] = true
+		if userRegistry.loaded && known[u.Name] {
+			continue
+		}
+		userDataMu.Lock()
+		ud, err := loadUserData(u.Name)
+		userDataMu.Unlock()
+		if err != nil {
+			fmt.Printf("Error al leer los datos de %s: %v\n", u.Name, err)
+			continue
+		}
+		if len(ud.Watchlist) > 0 {
+			userRegistry.symbols[u.Name] = ud.Watchlist
+		}
+	}
+	for name := range userRegistry.symbols {
+		if !current[name] {
+			delete(userRegistry.symbols, name)
+		}
+	}
+	userRegistry.users = users
+	userRegistry.loaded = true
+	userRegistry.modTime, userRegistry.size = modTime, size
+	return nil
+}
+
+// setUserSymbols reemplaza los símbolos que sigue un usuario; sin símbolos deja de contar para la cotización
+func setUserSymbols(user string, entries []WatchlistEntry) {
+	userRegistry.Lock()
+	defer userRegistry.Unlock()
+	if len(entries) == 0 {
+		delete(userRegistry.symbols, user)
+		return
+	}
+	userRegistry.symbols[user] = append([]WatchlistEntry(nil), entries...)
+}
+
+// userWatchlistEntries devuelve los símbolos que sigue algún usuario y no están en la watchlist del servidor
+func userWatchlistEntries() []WatchlistEntry {
+	watched := make(map[string]bool)
+	for _, entry := range watchlistSnapshot() {
+		watched[entry.Symbol] = true
+	}
+
+	userRegistry.Lock()
+	defer userRegistry.Unlock()
+	var entries []WatchlistEntry
+	for _, userEntries := range userRegistry.symbols {
+		for _, entry := range userEntries {
+			if !watched[entry.Symbol] {
+				watched[entry.Symbol] = true
+				entries = append(entries, entry)
+			}
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Symbol < entries[j].Symbol })
+	return entries
+}
+
+// applyUserWatchlists carga los usuarios registrados y los símbolos de sus watchlists al arrancar el servidor
+func applyUserWatchlists() {
+	if _, err := registeredUsers(); err != nil {
+		fmt.Printf("Error al leer los usuarios: %v\n", err)
+	}
+}
+
+// getUserStockData cotiza los símbolos que siguen los usuarios y no están en la watchlist del servidor;
+// los que fallan se omiten, sin sumarse a los errores del ciclo que ven todas las terminales
+func getUserStockData(ctx context.Context, dolarRate float64, client QuoteFetcher) []StockInfo {
+	entries := userWatchlistEntries()
+	results := make([]*StockInfo, len(entries))
+	group := newWorkGroup(ctx, fetchConcurrency)
+	for i, entry := range entries {
+		i, symbol, market := i, entry.Symbol, entry.Market
+		group.Go(func(ctx context.Context) error {
+			quote, err := getMergedQuote(symbol, market, client)
+			if err != nil {
+				debugf("Sin cotización de %s (watchlist de usuarios): %v\n", symbol, err)
+				return nil
+			}
+			stock := newStockInfo(symbol, market, quote, dolarRate)
+			results[i] = &stock
+			return nil
+		})
+	}
+	group.Wait()
+
+	var stocks []StockInfo
+	for _, stock := range results {
+		if stock != nil {
+			stocks = append(stocks, *stock)
+		}
+	}
+	return stocks
+}
+
+// evaluateUserAlerts guarda el snapshot para la API y evalúa las alertas de cada usuario: cada condición se
+// dispara una vez por episodio, cuando empieza a cumplirse, y se envía al chat de Telegram del usuario si lo
+// configuró. Los símbolos que se cotizan no se tocan acá: los cambia la API y la relectura de users.json
+func evaluateUserAlerts(snapshot *Snapshot) {
+	userSnapshotMu.Lock()
+	userSnapshot = snapshot
+	userSnapshotMu.Unlock()
+
+	users, err := registeredUsers()
+	if err != nil {
+		return
+	}
+
+	values := make(map[string]map[string]float64)
+	for _, forex := range snapshot.Forex {
+		values[forex.Symbol] = forexFields(forex)
+	}
+	for _, stock := range append(append([]StockInfo(nil), snapshot.Stocks...), snapshot.UserStocks...) {
+		values[stock.Symbol] = stockFields(stock)
+	}
+
+	for _, u := range users {
+		var fired []Alert
+		var chat string
+		_, err := updateUserData(u.Name, func(ud *UserData) error {
+			chat = ud.TelegramChat
+			if len(ud.Alerts) == 0 {
+				return nil
+			}
+			active := make(map[string]bool)
+			for _, rule := range ud.Alerts {
+				cond, err := parseCondition(rule.Condition)
+				if err != nil {
+					continue // Se valida al guardarla; una regla inválida no frena al resto
+				}
+				for symbol, fields := range values {
+					if rule.Symbol != "*" && !strings.EqualFold(rule.Symbol, symbol) {
+						continue
+					}
+					if matched, err := cond.Eval(fields); err != nil || !matched {
+						continue
+					}
+					key := rule.Name + "|" + symbol
+					active[key] = true
+					if ud.Active[key] {
+						continue
+					}
+					fired = append(fired, Alert{
+						Rule:     rule.Name,
+						Severity: rule.Severity,
+						Symbol:   symbol,
+						Value:    fields["price"],
+						Message:  fmt.Sprintf("%s: %s (%s)", symbol, rule.Name, rule.Condition),
+						Time:     snapshot.Time,
+					})
+				}
+			}
+			ud.Triggered = append(ud.Triggered, fired...)
+			ud.Active = active
+			if len(ud.Triggered) > userTriggeredKept {
+				ud.Triggered = ud.Triggered[len(ud.Triggered)-userTriggeredKept:]
+			}
+			return nil
+		})
+		if err != nil {
+			fmt.Printf("Error al evaluar las alertas de %s: %v\n", u.Name, err)
+			continue
+		}
+		notifyUserAlerts(u.Name, chat, fired)
+	}
+}
+
+// notifyUserAlerts envía las alertas disparadas de un usuario a su chat de Telegram con el bot del servidor;
+// sin chat o sin bot configurado quedan solo en /api/me/alerts/triggered
+func notifyUserAlerts(user, chat string, fired []Alert) {
+	if chat == "" || len(fired) == 0 {
+		return
+	}
+	token, _ := telegramCredentials()
+	if token == "" {
+		return
+	}
+
+	var lines []string
+	for _, alert := range fired {
+		lines = append(lines, "• "+alert.Message)
+	}
+	notifier := NewTelegramNotifier(token, chat)
+	notification := Notification{Title: "🔔 Alertas de " + user, Text: strings.Join(lines, "\n")}
+	if err := notifier.Send(notification); err != nil {
+		fmt.Printf("%sError al enviar las alertas de %s por Telegram: %v%s\n", Red, user, err, Reset)
+	}
+}
+
+// apiKey extrae la API key del request: Authorization: Bearer <key> o X-API-Key
+func apiKey(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return r.Header.Get("X-API-Key")
+}
+
+// withUser exige una API key válida y pasa el usuario al handler
+func withUser(handler func(w http.ResponseWriter, r *http.Request, user string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user, ok := userForKey(apiKey(r))
+		if !ok {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "API key inválida o ausente"})
+			return
+		}
+		handler(w, r, user)
+	}
+}
+
+// readUserBody decodifica el cuerpo JSON de un PUT, con un límite de tamaño
+func readUserBody(w http.ResponseWriter, r *http.Request, value interface{}) bool {
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(value); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("JSON inválido: %v", err)})
+		return false
+	}
+	return true
+}
+
+// handleUserWatchlist atiende GET y PUT /api/me/watchlist
+func handleUserWatchlist(w http.ResponseWriter, r *http.Request, user string) {
+	switch r.Method {
+	case http.MethodGet:
+		userDataMu.Lock()
+		ud, err := loadUserData(user)
+		userDataMu.Unlock()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, ud.Watchlist)
+	case http.MethodPut:
+		var entries []WatchlistEntry
+		if !readUserBody(w, r, &entries) {
+			return
+		}
+		if len(entries) > userWatchlistMax {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("la watchlist admite hasta %d símbolos", userWatchlistMax)})
+			return
+		}
+		for i := range entries {
+			entries[i].Symbol = strings.ToUpper(strings.TrimSpace(entries[i].Symbol))
+			if entries[i].Symbol == "" {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": "símbolo vacío en la watchlist"})
+				return
+			}
+			if entries[i].Market == MarketOther {
+				entries[i].Market = marketForSymbol(entries[i].Symbol)
+			}
+		}
+		ud, err := updateUserData(user, func(ud *UserData) error {
+			ud.Watchlist = entries
+			return nil
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		// Los símbolos nuevos se cotizan desde el próximo ciclo y los quitados dejan de cotizarse si nadie más los sigue
+		setUserSymbols(user, ud.Watchlist)
+		writeJSON(w, http.StatusOK, ud.Watchlist)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "método no permitido"})
+	}
+}
+
+// handleUserPortfolio atiende GET y PUT /api/me/portfolio
+func handleUserPortfolio(w http.ResponseWriter, r *http.Request, user string) {
+	switch r.Method {
+	case http.MethodGet:
+		userDataMu.Lock()
+		ud, err := loadUserData(user)
+		userDataMu.Unlock()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if ud.Portfolio == nil {
+			writeJSON(w, http.StatusNotFound, map[string]string{"error": "todavía no hay cartera cargada"})
+			return
+		}
+		writeJSON(w, http.StatusOK, ud.Portfolio)
+	case http.MethodPut:
+		var portfolio Portfolio
+		if !readUserBody(w, r, &portfolio) {
+			return
+		}
+		if len(portfolio.Targets) > 0 {
+			var total float64
+			for _, weight := range portfolio.Targets {
+				total += weight
+			}
+			if math.Abs(total-100) > 0.01 {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("los pesos objetivo suman %.2f%%, deben sumar 100%%", total)})
+				return
+			}
+		}
+		ud, err := updateUserData(user, func(ud *UserData) error {
+			ud.Portfolio = &portfolio
+			return nil
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, ud.Portfolio)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "método no permitido"})
+	}
+}
+
+// handleUserAlerts atiende GET y PUT /api/me/alerts (las reglas) y GET /api/me/alerts/triggered
+func handleUserAlerts(w http.ResponseWriter, r *http.Request, user string) {
+	triggered := strings.TrimSuffix(r.URL.Path, "/") == "/api/me/alerts/triggered"
+	switch {
+	case r.Method == http.MethodGet:
+		userDataMu.Lock()
+		ud, err := loadUserData(user)
+		userDataMu.Unlock()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		if triggered {
+			writeJSON(w, http.StatusOK, ud.Triggered)
+			return
+		}
+		writeJSON(w, http.StatusOK, ud.Alerts)
+	case r.Method == http.MethodPut && !triggered:
+		var rules []AlertRule
+		if !readUserBody(w, r, &rules) {
+			return
+		}
+		names := make(map[string]bool)
+		for i := range rules {
+			rule := &rules[i]
+			if rule.Name == "" || names[rule.Name] {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("cada alerta necesita un nombre único (%q)", rule.Name)})
+				return
+			}
+			names[rule.Name] = true
+			if rule.Symbol == "" {
+				rule.Symbol = "*"
+			}
+			if _, err := parseCondition(rule.Condition); err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("alerta %q: %v", rule.Name, err)})
+				return
+			}
+		}
+		ud, err := updateUserData(user, func(ud *UserData) error {
+			ud.Alerts = rules
+			ud.Active = nil
+			return nil
+		})
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, ud.Alerts)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "método no permitido"})
+	}
+}
+
+// UserNotify es el cuerpo de /api/me/notify: a dónde se envían las alertas del usuario
+type UserNotify struct {
+	TelegramChat string `json:"telegramChat"` // Chat con el bot del servidor; vacío desactiva el envío
+}
+
+// handleUserNotify atiende GET y PUT /api/me/notify
+func handleUserNotify(w http.ResponseWriter, r *http.Request, user string) {
+	switch r.Method {
+	case http.MethodGet:
+		userDataMu.Lock()
+		ud, err := loadUserData(user)
+		userDataMu.Unlock()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, UserNotify{TelegramChat: ud.TelegramChat})
+	case http.MethodPut:
+		var notify UserNotify
+		if !readUserBody(w, r, &notify) {
+			return
+		}
+		notify.TelegramChat = strings.TrimSpace(notify.TelegramChat)
+		if _, err := strconv.ParseInt(notify.TelegramChat, 10, 64); notify.TelegramChat != "" && err != nil {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": fmt.Sprintf("chat de Telegram inválido %q: es un número (negativo para grupos)", notify.TelegramChat)})
+			return
+		}
+		if token, _ := telegramCredentials(); notify.TelegramChat != "" && token == "" {
+			writeJSON(w, http.StatusConflict, map[string]string{"error": "el servidor no tiene un bot de Telegram configurado"})
+			return
+		}
+		if _, err := updateUserData(user, func(ud *UserData) error {
+			ud.TelegramChat = notify.TelegramChat
+			return nil
+		}); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		writeJSON(w, http.StatusOK, notify)
+	default:
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "método no permitido"})
+	}
+}
+
+// handleUserQuotes atiende GET /api/me/quotes: las cotizaciones del último ciclo de la watchlist del usuario
+func handleUserQuotes(w http.ResponseWriter, r *http.Request, user string) {
+	if r.Method != http.MethodGet {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "método no permitido"})
+		return
+	}
+	userDataMu.Lock()
+	ud, err := loadUserData(user)
+	userDataMu.Unlock()
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+
+	userSnapshotMu.Lock()
+	snapshot := userSnapshot
+	userSnapshotMu.Unlock()
+	if snapshot == nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "todavía no hay datos del primer ciclo"})
+		return
+	}
+
+	watched := make(map[string]bool)
+	for _, entry := range ud.Watchlist {
+		watched[entry.Symbol] = true
+	}
+	quotes := []StockInfo{}
+	for _, stock := range append(append([]StockInfo(nil), snapshot.Stocks...), snapshot.UserStocks...) {
+		if watched[stock.Symbol] {
+			quotes = append(quotes, stock)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"time": snapshot.Time, "stocks": quotes, "forex": snapshot.Forex})
+}
+
+// registerUserAPI agrega a la API los endpoints por usuario, autenticados con API key
+func registerUserAPI(mux *http.ServeMux) {
+	mux.HandleFunc("/api/me/watchlist", withUser(handleUserWatchlist))
+	mux.HandleFunc("/api/me/portfolio", withUser(handleUserPortfolio))
+	mux.HandleFunc("/api/me/alerts", withUser(handleUserAlerts))
+	mux.HandleFunc("/api/me/alerts/triggered", withUser(handleUserAlerts))
+	mux.HandleFunc("/api/me/quotes", withUser(handleUserQuotes))
+	mux.HandleFunc("/api/me/notify", withUser(handleUserNotify))
+}
+
+// runUsers implementa `bolsa users`: alta, baja y listado de los usuarios del modo servidor
+func runUsers(args []string) error {
+	fs := flag.NewFlagSet("users", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Println("Uso: bolsa users list | add NOMBRE | remove NOMBRE | rotate NOMBRE")
+	}
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return nil
+	}
+
+	users, err := loadUsers()
+	if err != nil {
+		return err
+	}
+	action := fs.Arg(0)
+	if action == "list" {
+		if len(users) == 0 {
+			fmt.Println("No hay usuarios: crear uno con bolsa users add NOMBRE")
+			return nil
+		}
+		sort.Slice(users, func(i, j int) bool { return users[i].Name < users[j].Name })
+		fmt.Printf("%-20s %s\n", "Usuario", "Alta")
+		for _, u := range users {
+			fmt.Printf("%-20s %s\n", u.Name, u.Created.Local().Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("falta el nombre del usuario")
+	}
+	name := strings.ToLower(fs.Arg(1))
+	if !userNamePattern.MatchString(name) {
+		return fmt.Errorf("nombre inválido %q: usar letras minúsculas, números, - y _", name)
+	}
+	index := -1
+	for i, u := range users {
+		if u.Name == name {
+			index = i
+		}
+	}
+
+	switch action {
+	case "add", "rotate":
+		if action == "add" && index >= 0 {
+			return fmt.Errorf("el usuario %s ya existe (bolsa users rotate %s genera una clave nueva)", name, name)
+		}
+		if action == "rotate" && index < 0 {
+			return fmt.Errorf("no existe el usuario %s", name)
+		}
+		raw := make([]byte, 24)
+		if _, err := rand.Read(raw); err != nil {
+			return err
+		}
+		key := hex.EncodeToString(raw)
+		if index >= 0 {
+			users[index].KeyHash = hashAPIKey(key)
+		} else {
+			users = append(users, ServerUser{Name: name, KeyHash: hashAPIKey(key), Created: time.Now()})
+		}
+		if err := saveUsers(users); err != nil {
+			return err
+		}
+		fmt.Printf("API key de %s (se muestra una sola vez):\n\n  %s\n\n", name, key)
+		fmt.Println("Usarla en la API de bolsa serve --http con el header Authorization: Bearer <key>")
+	case "remove":
+		if index < 0 {
+			return fmt.Errorf("no existe el usuario %s", name)
+		}
+		users = append(users[:index], users[index+1:]...)
+		if err := saveUsers(users); err != nil {
+			return err
+		}
+		if path, err := userDataPath(name); err == nil {
+			os.Remove(path)
+		}
+		fmt.Printf("Usuario %s eliminado junto con su watchlist, cartera y alertas.\n", name)
+	default:
+		fs.Usage()
+		return fmt.Errorf("acción desconocida %q", action)
+	}
+	return nil
+}
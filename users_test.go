@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// putUserWatchlist hace un PUT /api/me/watchlist como el usuario dado y devuelve el código de respuesta
+func putUserWatchlist(t *testing.T, user, body string) int {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handleUserWatchlist(rec, httptest.NewRequest(http.MethodPut, "/api/me/watchlist", strings.NewReader(body)), user)
+	return rec.Code
+}
+
+// userSymbolSet devuelve los símbolos de usuarios que se cotizan aparte de la watchlist del servidor
+func userSymbolSet() map[string]bool {
+	set := make(map[string]bool)
+	for _, entry := range userWatchlistEntries() {
+		set[entry.Symbol] = true
+	}
+	return set
+}
+
+// resetUserRegistry vacía los usuarios en memoria y users.json al terminar el test
+func resetUserRegistry(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		if path, err := appFile(usersFile); err == nil {
+			os.Remove(path)
+		}
+		userRegistry.Lock()
+		userRegistry.users = nil
+		userRegistry.loaded = false
+		userRegistry.symbols = make(map[string][]WatchlistEntry)
+		userRegistry.Unlock()
+	})
+}
+
+// writeUsers escribe users.json con la fecha dada, para no depender de la resolución del reloj del disco
+func writeUsers(t *testing.T, users []ServerUser, modTime time.Time) {
+	t.Helper()
+	if err := saveUsers(users); err != nil {
+		t.Fatal(err)
+	}
+	path, err := appFile(usersFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUserWatchlistsStayOutOfServerWatchlist(t *testing.T) {
+	resetUserRegistry(t)
+
+	if code := putUserWatchlist(t, "ana", `[{"symbol": "zzaa"}, {"symbol": "ZZBB"}]`); code != http.StatusOK {
+		t.Fatalf("PUT de ana: código %d", code)
+	}
+	if code := putUserWatchlist(t, "beto", `[{"symbol": "ZZAA"}]`); code != http.StatusOK {
+		t.Fatalf("PUT de beto: código %d", code)
+	}
+	for _, entry := range watchlistSnapshot() {
+		if strings.HasPrefix(entry.Symbol, "ZZ") {
+			t.Errorf("%s entró a la watchlist del servidor", entry.Symbol)
+		}
+	}
+	if got := userSymbolSet(); !got["ZZAA"] || !got["ZZBB"] || len(got) != 2 {
+		t.Errorf("símbolos de usuarios = %v, se esperaban ZZAA y ZZBB", got)
+	}
+
+	// ZZBB lo seguía solo ana: deja de cotizarse; ZZAA sigue por beto
+	if code := putUserWatchlist(t, "ana", `[]`); code != http.StatusOK {
+		t.Fatalf("PUT vacío de ana: código %d", code)
+	}
+	if got := userSymbolSet(); !got["ZZAA"] || got["ZZBB"] {
+		t.Errorf("símbolos de usuarios = %v, se esperaba solo ZZAA", got)
+	}
+}
+
+func TestUserWatchlistCap(t *testing.T) {
+	var entries []string
+	for i := 0; i <= userWatchlistMax; i++ {
+		entries = append(entries, fmt.Sprintf(`{"symbol": "ZZ%d"}`, i))
+	}
+	if code := putUserWatchlist(t, "carla", "["+strings.Join(entries, ",")+"]"); code != http.StatusBadRequest {
+		t.Errorf("PUT con %d símbolos: código %d, se esperaba %d", len(entries), code, http.StatusBadRequest)
+	}
+	if got := userSymbolSet(); len(got) != 0 {
+		t.Errorf("la watchlist rechazada se registró igual: %v", got)
+	}
+}
+
+func TestEvaluateUserAlertsKeepsSavedWatchlist(t *testing.T) {
+	resetUserRegistry(t)
+	writeUsers(t, []ServerUser{{Name: "dana", KeyHash: hashAPIKey("clave-dana")}}, time.Now())
+	applyUserWatchlists()
+
+	// Un PUT que termina mientras corre el ciclo: el ciclo no debe pisar la watchlist recién guardada
+	if code := putUserWatchlist(t, "dana", `[{"symbol": "ZZCC"}]`); code != http.StatusOK {
+		t.Fatalf("PUT de dana: código %d", code)
+	}
+	setUserSymbols("dana", []WatchlistEntry{{Symbol: "ZZDD"}})
+	evaluateUserAlerts(&Snapshot{Time: time.Now()})
+	if got := userSymbolSet(); !got["ZZDD"] || len(got) != 1 {
+		t.Errorf("símbolos de usuarios = %v, se esperaba solo ZZDD", got)
+	}
+}
+
+func TestRegisteredUsersReloadOnChange(t *testing.T) {
+	resetUserRegistry(t)
+	start := time.Now().Add(-time.Hour)
+	writeUsers(t, []ServerUser{
+		{Name: "eva", KeyHash: hashAPIKey("clave-eva")},
+		{Name: "fede", KeyHash: hashAPIKey("clave-fede")},
+	}, start)
+	for _, user := range []string{"eva", "fede"} {
+		if code := putUserWatchlist(t, user, fmt.Sprintf(`[{"symbol": "ZZ%s"}]`, strings.ToUpper(user))); code != http.StatusOK {
+			t.Fatalf("PUT de %s: código %d", user, code)
+		}
+	}
+
+	// Al arrancar se cargan las watchlists guardadas de los usuarios registrados
+	userRegistry.Lock()
+	userRegistry.loaded = false
+	userRegistry.symbols = make(map[string][]WatchlistEntry)
+	userRegistry.Unlock()
+	if user, ok := userForKey("clave-fede"); !ok || user != "fede" {
+		t.Fatalf("userForKey(clave-fede) = %q, %v", user, ok)
+	}
+	if got := userSymbolSet(); !got["ZZEVA"] || !got["ZZFEDE"] {
+		t.Errorf("símbolos de usuarios = %v, se esperaban ZZEVA y ZZFEDE", got)
+	}
+
+	// bolsa users remove fede desde otro proceso: se nota en la próxima consulta sin reiniciar el servidor
+	writeUsers(t, []ServerUser{{Name: "eva", KeyHash: hashAPIKey("clave-eva")}}, start.Add(time.Minute))
+	if _, ok := userForKey("clave-fede"); ok {
+		t.Error("la clave de un usuario eliminado sigue valiendo")
+	}
+	if user, ok := userForKey("clave-eva"); !ok || user != "eva" {
+		t.Errorf("userForKey(clave-eva) = %q, %v", user, ok)
+	}
+	if got := userSymbolSet(); !got["ZZEVA"] || got["ZZFEDE"] {
+		t.Errorf("símbolos de usuarios = %v, se esperaba solo ZZEVA", got)
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Plazo de renovación del plazo fijo simulado
+const plazoFijoDays = 30
+
+// plazoFijoFactor simula renovar un plazo fijo cada 30 días a la tasa vigente en cada renovación
+func plazoFijoFactor(rates []RatePoint, fixedRate float64, from, to time.Time) float64 {
+	factor := 1.0
+	for start := from; start.Before(to); start = start.AddDate(0, 0, plazoFijoDays) {
+		rate := fixedRate
+		if rates != nil {
+			rate = rateAt(rates, start)
+		}
+
+		// El último tramo puede ser más corto (se asume precancelación sin penalidad)
+		days := float64(plazoFijoDays)
+		if end := start.AddDate(0, 0, plazoFijoDays); end.After(to) {
+			days = to.Sub(start).Hours() / 24
+		}
+		factor *= 1 + rate/100*days/365
+	}
+	return factor
+}
+
+// mepAt calcula el dólar MEP implícito (AL30 / AL30D) en la primera rueda en o después de t
+func mepAt(pesos, dollars []HistoryPoint, t time.Time) (float64, error) {
+	ars, ok1 := priceOnOrAfter(pesos, t)
+	usd, ok2 := priceOnOrAfter(dollars, t)
+	if !ok1 || !ok2 || usd.Close == 0 {
+		return 0, fmt.Errorf("no hay cotización del MEP para %s", t.Format("2006-01-02"))
+	}
+	return ars.Close / usd.Close, nil
+}
+
+// runVs implementa `bolsa vs --symbol GGAL --from 2024-01-01`: retorno en pesos del papel contra plazo fijo y dólar MEP
+func runVs(args []string) error {
+	fs := flag.NewFlagSet("vs", flag.ExitOnError)
+	symbol := fs.String("symbol", "", "símbolo a comparar (GGAL, GGAL.BA, YPF...)")
+	fromStr := fs.String("from", "", "fecha de inicio (AAAA-MM-DD)")
+	toStr := fs.String("to", "", "fecha de fin (AAAA-MM-DD, por defecto hoy)")
+	amount := fs.Float64("amount", 100000, "monto inicial en pesos para la simulación")
+	rate := fs.Float64("rate", 0, "TNA fija del plazo fijo en %, en lugar de la serie del BCRA")
+	fs.Parse(args)
+
+	if *symbol == "" || *fromStr == "" {
+		return fmt.Errorf("uso: bolsa vs --symbol GGAL --from 2024-01-01 [--to 2024-12-31] [--rate 40]")
+	}
+	sym := strings.ToUpper(*symbol)
+
+	from, err := time.ParseInLocation("2006-01-02", *fromStr, argentinaLocation)
+	if err != nil {
+		return fmt.Errorf("fecha de inicio inválida %q: %v", *fromStr, err)
+	}
+	to := time.Now()
+	if *toStr != "" {
+		if to, err = time.ParseInLocation("2006-01-02", *toStr, argentinaLocation); err != nil {
+			return fmt.Errorf("fecha de fin inválida %q: %v", *toStr, err)
+		}
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("la fecha de inicio debe ser anterior a la de fin")
+	}
+
+	client := NewHTTPClient()
+
+	// El MEP se usa tanto como alternativa de inversión como para pasar a pesos los papeles en dólares
+	mepPesos, err := getHistoryBetween(mepPesosSymbol, from, to, "1d", client)
+	if err != nil {
+		return fmt.Errorf("error al obtener el histórico de %s: %v", mepPesosSymbol, err)
+	}
+	mepDollars, err := getHistoryBetween(mepDollarSymbol, from, to, "1d", client)
+	if err != nil {
+		return fmt.Errorf("error al obtener el histórico de %s: %v", mepDollarSymbol, err)
+	}
+	mepStart, err := mepAt(mepPesos, mepDollars, from)
+	if err != nil {
+		return err
+	}
+	mepEnd := mepPesos[len(mepPesos)-1].Close / mepDollars[len(mepDollars)-1].Close
+
+	points, err := getHistoryBetween(sym, from, to, "1d", client)
+	if err != nil {
+		return fmt.Errorf("error al obtener el histórico de %s: %v", sym, err)
+	}
+	first, ok := priceOnOrAfter(points, from)
+	if !ok || first.Close == 0 {
+		return fmt.Errorf("no hay cotizaciones de %s en el período", sym)
+	}
+	last := points[len(points)-1]
+
+	// Los papeles que no cotizan en BYMA están en dólares: se valúan en pesos al MEP de cada fecha
+	stockFactor := last.Close / first.Close
+	currencyNote := "en pesos"
+	if !strings.HasSuffix(sym, ".BA") {
+		stockFactor *= mepEnd / mepStart
+		currencyNote = "en dólares, valuado en pesos al MEP"
+	}
+
+	var rates []RatePoint
+	rateNote := fmt.Sprintf("TNA fija %.2f%%", *rate)
+	if *rate == 0 {
+		rates, err = getBCRASeries(bcraVariablePlazoFijo, from.AddDate(0, 0, -10), to, NewProviderClient("bcra"))
+		if err != nil {
+			return fmt.Errorf("no se pudo obtener la tasa de plazo fijo del BCRA (usar --rate): %v", err)
+		}
+		rateNote = "tasa BCRA a 30 días, renovado mensualmente"
+	}
+	pfFactor := plazoFijoFactor(rates, *rate, from, to)
+	mepFactor := mepEnd / mepStart
+
+	fmt.Printf("\n%s=== %s vs. plazo fijo y dólar MEP ===%s\n", Cyan, sym, Reset)
+	fmt.Printf("Período: %s a %s (%d días)\n\n", from.Format("2006-01-02"), to.Format("2006-01-02"), int(to.Sub(from).Hours()/24))
+
+	rows := []struct {
+		name   string
+		note   string
+		factor float64
+	}{
+		{sym, currencyNote, stockFactor},
+		{"Plazo fijo", rateNote, pfFactor},
+		{"Dólar MEP", fmt.Sprintf("%.2f → %.2f", mepStart, mepEnd), mepFactor},
+	}
+
+	best := rows[0]
+	fmt.Printf("%-12s %12s %16s  %s\n", "Alternativa", "Retorno", "Final", "Detalle")
+	for _, row := range rows {
+		color := Green
+		if row.factor < 1 {
+			color = Red
+		}
+		fmt.Printf("%-12s %s%+11.2f%%%s %16.2f  %s\n", row.name, color, (row.factor-1)*100, Reset, *amount*row.factor, row.note)
+		if row.factor > best.factor {
+			best = row
+		}
+	}
+	fmt.Printf("\nMejor alternativa: %s%s%s\n", Green, best.name, Reset)
+	return nil
+}
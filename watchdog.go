@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"time"
+)
+
+// beat marca el inicio de un ciclo; devuelve false si la generación ya fue reemplazada
+func (p *Pipeline) beat(generation int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if generation != p.generation {
+		return false
+	}
+	p.lastBeat = time.Now()
+	return true
+}
+
+// current indica si la generación sigue siendo la activa
+func (p *Pipeline) current(generation int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return generation == p.generation
+}
+
+// watchdog detecta ciclos colgados (request sin respuesta, deadlock) y reinicia el ciclo de fetch
+func (p *Pipeline) watchdog() {
+	timeout := time.Duration(appConfig().WatchdogTimeout)
+	ticker := time.NewTicker(timeout / 4)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		p.mu.Lock()
+		// El intervalo entre ciclos no cuenta como ciclo colgado
+		stalled := time.Since(p.lastBeat) - p.interval
+		p.mu.Unlock()
+
+		if stalled > timeout {
+			p.restart(stalled)
+		}
+	}
+}
+
+// restart guarda un dump de goroutines y arranca una generación nueva del ciclo con un cliente HTTP nuevo
+func (p *Pipeline) restart(stalled time.Duration) {
+	path, err := dumpGoroutines()
+	if err != nil {
+		fmt.Printf("%s⚠️ No se pudo guardar el dump de goroutines: %v%s\n", Yellow, err, Reset)
+	}
+	fmt.Printf("%s⚠️ Watchdog: el ciclo de actualización lleva %v sin terminar; reiniciando (dump en %s)%s\n",
+		Yellow, stalled.Round(time.Second), path, Reset)
+
+	p.mu.Lock()
+	old := p.client
+	p.client = NewProviderClient(old.provider)
+	p.generation++
+	p.lastBeat = time.Now()
+	generation, client := p.generation, p.client
+	p.mu.Unlock()
+
+	// Las consultas colgadas del ciclo anterior no deben bloquear a las nuevas del mismo símbolo
+	tickerCache.Reset()
+	old.client.CloseIdleConnections()
+
+	go p.loop(generation, client)
+}
+
+// dumpGoroutines guarda el stack de todas las goroutines en el directorio de diagnóstico
+func dumpGoroutines() (string, error) {
+	dir, err := appDir()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, "diagnostics")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-goroutines.txt", time.Now().Format("20060102-150405")))
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if err := pprof.Lookup("goroutine").WriteTo(file, 2); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// WatchlistEntry representa un símbolo de una watchlist guardada
+type WatchlistEntry struct {
+	Symbol string `json:"symbol"`
+	Market Market `json:"market"`
+}
+
+const watchlistsFile = "watchlists.json"
+
+// loadWatchlists lee las watchlists guardadas por nombre
+func loadWatchlists() (map[string][]WatchlistEntry, error) {
+	watchlists := make(map[string][]WatchlistEntry)
+
+	path, err := appFile(watchlistsFile)
+	if err != nil {
+		return watchlists, err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return watchlists, nil
+	}
+	if err != nil {
+		return watchlists, err
+	}
+
+	if err := json.Unmarshal(data, &watchlists); err != nil {
+		return watchlists, fmt.Errorf("error al decodificar %s: %v", path, err)
+	}
+	return watchlists, nil
+}
+
+// saveWatchlists escribe las watchlists guardadas
+func saveWatchlists(watchlists map[string][]WatchlistEntry) error {
+	path, err := appFile(watchlistsFile)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(watchlists, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
+
+// addToWatchlist agrega papeles del catálogo a una watchlist guardada, sin duplicarlos
+func addToWatchlist(name string, entries []CatalogEntry) (int, error) {
+	watchlists, err := loadWatchlists()
+	if err != nil {
+		return 0, err
+	}
+
+	list := watchlists[name]
+	added := 0
+	for _, entry := range entries {
+		exists := false
+		for _, existing := range list {
+			if existing.Symbol == entry.Symbol {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			list = append(list, WatchlistEntry{Symbol: entry.Symbol, Market: entry.Market})
+			added++
+		}
+	}
+	watchlists[name] = list
+
+	return added, saveWatchlists(watchlists)
+}
+
+// useWatchlist reemplaza la watchlist activa del monitor por una guardada
+func useWatchlist(name string) error {
+	watchlists, err := loadWatchlists()
+	if err != nil {
+		return err
+	}
+
+	list, ok := watchlists[name]
+	if !ok || len(list) == 0 {
+		return fmt.Errorf("la watchlist %q no existe o está vacía", name)
+	}
+
+	stocksMu.Lock()
+	defer stocksMu.Unlock()
+
+	stocks = nil
+	stocks = append(stocks, list...)
+	return nil
+}
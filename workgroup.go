@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// Consultas simultáneas por sección; el resto espera un lugar libre en lugar de abrir otra goroutine
+const fetchConcurrency = 8
+
+// workGroup corre tareas con un límite de concurrencia y un contexto compartido, al estilo de errgroup,
+// pero junta los errores de todas las tareas: una consulta fallida no cancela a las demás
+type workGroup struct {
+	ctx  context.Context
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// newWorkGroup crea un grupo que corre como mucho limit tareas a la vez
+func newWorkGroup(ctx context.Context, limit int) *workGroup {
+	return &workGroup{ctx: ctx, sem: make(chan struct{}, limit)}
+}
+
+// Go lanza una tarea cuando hay lugar; si el contexto se cancela antes, la tarea no corre y se registra el motivo
+func (g *workGroup) Go(task func(ctx context.Context) error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		select {
+		case g.sem <- struct{}{}:
+		case <-g.ctx.Done():
+			g.fail(g.ctx.Err())
+			return
+		}
+		defer func() { <-g.sem }()
+
+		if err := g.ctx.Err(); err != nil {
+			g.fail(err)
+			return
+		}
+		if err := task(g.ctx); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+func (g *workGroup) fail(err error) {
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+}
+
+// Wait espera todas las tareas y devuelve sus errores unidos, o nil si todas terminaron bien
+func (g *workGroup) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Políticas ante cada error de Yahoo
+const (
+	PolicyDisable = "disable" // Desactivar el símbolo hasta que se reactive con bolsa disabled enable
+	PolicyPause   = "pause"   // No reintentar y pausar todas las consultas a Yahoo
+	PolicyRetry   = "retry"   // Reintentar con la espera inicial, sin backoff exponencial
+	PolicyBackoff = "backoff" // Reintentar con backoff exponencial
+	PolicyFail    = "fail"    // Fallar la consulta sin reintentar
+)
+
+// Errores de Yahoo con política propia, tal como se escriben en "yahooPolicies" de config.json
+const (
+	YahooDelisted = "delisted"  // "No data found, symbol may be delisted"
+	YahooNotFound = "not_found" // Símbolo inexistente
+	Yahoo429      = "429"       // Too Many Requests
+	Yahoo999      = "999"       // Bloqueo anti-bot de Yahoo
+	Yahoo5xx      = "5xx"       // Errores del servidor
+)
+
+// defaultYahooPolicies reproduce lo razonable para cada error; config.json puede reemplazar cualquiera
+var defaultYahooPolicies = map[string]string{
+	YahooDelisted: PolicyDisable,
+	YahooNotFound: PolicyFail,
+	Yahoo429:      PolicyPause,
+	Yahoo999:      PolicyPause,
+	Yahoo5xx:      PolicyRetry,
+}
+
+// Pausa por defecto ante un 429 o 999; se duplica con cada pausa seguida, hasta yahooMaxPause
+const (
+	defaultYahooPause = 5 * time.Minute
+	yahooMaxPause     = time.Hour
+)
+
+// validateYahooPolicies verifica los errores y las políticas configuradas
+func validateYahooPolicies(policies map[string]string) error {
+	valid := []string{PolicyDisable, PolicyPause, PolicyRetry, PolicyBackoff, PolicyFail}
+	for code, policy := range policies {
+		if _, ok := defaultYahooPolicies[code]; !ok {
+			return fmt.Errorf("error desconocido %q (usar delisted, not_found, 429, 999 o 5xx)", code)
+		}
+		if !contains(valid, policy) {
+			return fmt.Errorf("%s: política desconocida %q (usar %s)", code, policy, strings.Join(valid, ", "))
+		}
+		if policy == PolicyDisable && code != YahooDelisted && code != YahooNotFound {
+			return fmt.Errorf("%s: solo los errores de símbolo (delisted, not_found) pueden desactivarlo", code)
+		}
+	}
+	return nil
+}
+
+// yahooPolicy devuelve la política de un error de Yahoo
+func yahooPolicy(code string) string {
+	if policy, ok := appConfig().YahooPolicies[code]; ok {
+		return policy
+	}
+	return defaultYahooPolicies[code]
+}
+
+// yahooStatusCode clasifica un código HTTP de Yahoo; vacío si no es un error con política
+func yahooStatusCode(status int) string {
+	switch {
+	case status == 429:
+		return Yahoo429
+	case status == 999:
+		return Yahoo999
+	case status >= 500:
+		return Yahoo5xx
+	}
+	return ""
+}
+
+// yahooChartCode clasifica el error que Yahoo informa en el cuerpo de /v8/finance/chart
+func yahooChartCode(code, description string) string {
+	if strings.Contains(strings.ToLower(description), "delisted") {
+		return YahooDelisted
+	}
+	if code == "Not Found" {
+		return YahooNotFound
+	}
+	return ""
+}
+
+// Pausa global de las consultas a Yahoo
+var (
+	yahooPauseMu    sync.Mutex
+	yahooPauseUntil time.Time
+	yahooPauseLast  time.Duration // Duración de la última pausa, para duplicarla si Yahoo sigue limitando
+)
+
+// pauseYahoo pausa las consultas a Yahoo tras un 429 o 999 y devuelve el error de la consulta
+func pauseYahoo(code string, status int) error {
+	yahooPauseMu.Lock()
+	defer yahooPauseMu.Unlock()
+
+	now := time.Now()
+	if now.Before(yahooPauseUntil) {
+		return yahooPausedError()
+	}
+	pause := time.Duration(appConfig().YahooPause)
+	if pause <= 0 {
+		pause = defaultYahooPause
+	}
+	if yahooPauseLast > 0 {
+		pause = min(yahooPauseLast*2, yahooMaxPause)
+	}
+	yahooPauseLast = pause
+	yahooPauseUntil = now.Add(pause)
+	fmt.Printf("%s⚠️ Yahoo respondió %d (%s): consultas en pausa por %v%s\n", Yellow, status, code, pause, Reset)
+	return &QuoteError{Kind: ErrRateLimited, Status: status, Code: code,
+		Err: fmt.Errorf("Yahoo respondió %d: consultas en pausa hasta las %s", status, yahooPauseUntil.Format("15:04:05"))}
+}
+
+// yahooPaused devuelve un error si las consultas a Yahoo están en pausa
+func yahooPaused() error {
+	yahooPauseMu.Lock()
+	defer yahooPauseMu.Unlock()
+	if time.Now().Before(yahooPauseUntil) {
+		return yahooPausedError()
+	}
+	return nil
+}
+
+// yahooPausedError es el error de una consulta omitida durante la pausa; requiere yahooPauseMu tomado
+func yahooPausedError() error {
+	return &QuoteError{Kind: ErrRateLimited, Err: fmt.Errorf("consultas a Yahoo en pausa hasta las %s", yahooPauseUntil.Format("15:04:05"))}
+}
+
+// yahooRecovered reinicia la duración de la pausa cuando Yahoo vuelve a responder bien
+func yahooRecovered() {
+	yahooPauseMu.Lock()
+	yahooPauseLast = 0
+	yahooPauseMu.Unlock()
+}
+
+// Símbolos desactivados por la política de Yahoo, persistidos entre sesiones
+const disabledSymbolsFile = "disabled_symbols.json"
+
+// DisabledSymbol es un símbolo que no se vuelve a consultar
+type DisabledSymbol struct {
+	Reason string    `json:"reason"`
+	Since  time.Time `json:"since"`
+}
+
+var (
+	disabledMu     sync.Mutex
+	disabledLoaded bool
+	disabled       map[string]DisabledSymbol
+)
+
+// loadDisabledSymbols lee los símbolos desactivados; requiere disabledMu tomado
+func loadDisabledSymbols() {
+	if disabledLoaded {
+		return
+	}
+	disabledLoaded = true
+	disabled = make(map[string]DisabledSymbol)
+	path, err := appFile(disabledSymbolsFile)
+	if err != nil {
+		return
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &disabled); err != nil {
+			fmt.Printf("%s inválido: %v\n", disabledSymbolsFile, err)
+		}
+	}
+}
+
+// saveDisabledSymbols guarda los símbolos desactivados; requiere disabledMu tomado
+func saveDisabledSymbols() error {
+	path, err := appFile(disabledSymbolsFile)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(disabled, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// symbolDisabled indica si el símbolo está desactivado
+func symbolDisabled(symbol string) (DisabledSymbol, bool) {
+	disabledMu.Lock()
+	defer disabledMu.Unlock()
+	loadDisabledSymbols()
+	d, ok := disabled[symbol]
+	return d, ok
+}
+
+// applySymbolPolicy desactiva el símbolo si su error de Yahoo tiene esa política
+func applySymbolPolicy(symbol string, err error) {
+	qe, ok := err.(*QuoteError)
+	if !ok || qe.Code == "" || yahooPolicy(qe.Code) != PolicyDisable {
+		return
+	}
+
+	disabledMu.Lock()
+	defer disabledMu.Unlock()
+	loadDisabledSymbols()
+	disabled[symbol] = DisabledSymbol{Reason: qe.Err.Error(), Since: time.Now()}
+	if err := saveDisabledSymbols(); err != nil {
+		fmt.Printf("No se pudo guardar %s: %v\n", disabledSymbolsFile, err)
+	}
+	fmt.Printf("%s⚠️ %s desactivado (%s): reactivarlo con bolsa disabled enable %s%s\n", Yellow, symbol, qe.Err, symbol, Reset)
+}
+
+// runDisabled implementa `bolsa disabled`: lista y reactiva los símbolos desactivados por la política de Yahoo
+func runDisabled(args []string) error {
+	disabledMu.Lock()
+	defer disabledMu.Unlock()
+	loadDisabledSymbols()
+
+	if len(args) == 0 || args[0] == "list" {
+		if len(disabled) == 0 {
+			fmt.Println("No hay símbolos desactivados.")
+			return nil
+		}
+		var symbols []string
+		for symbol := range disabled {
+			symbols = append(symbols, symbol)
+		}
+		sort.Strings(symbols)
+		for _, symbol := range symbols {
+			d := disabled[symbol]
+			fmt.Printf("%-10s desde %s  %s\n", symbol, d.Since.In(argentinaLocation).Format("02/01/2006 15:04"), d.Reason)
+		}
+		return nil
+	}
+
+	switch args[0] {
+	case "enable":
+		if len(args) < 2 {
+			return fmt.Errorf("uso: bolsa disabled enable SIMBOLO...")
+		}
+		for _, symbol := range args[1:] {
+			symbol = strings.ToUpper(symbol)
+			if _, ok := disabled[symbol]; !ok {
+				return fmt.Errorf("%s no está desactivado", symbol)
+			}
+			delete(disabled, symbol)
+			fmt.Printf("%s reactivado\n", symbol)
+		}
+	case "clear":
+		fmt.Printf("%d símbolos reactivados\n", len(disabled))
+		disabled = make(map[string]DisabledSymbol)
+	default:
+		return fmt.Errorf("uso: bolsa disabled [list|enable SIMBOLO...|clear]")
+	}
+	return saveDisabledSymbols()
+}
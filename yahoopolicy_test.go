@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// resetYahooState levanta la pausa de Yahoo y reactiva los símbolos al terminar el test
+func resetYahooState(t *testing.T) {
+	t.Helper()
+	reset := func() {
+		yahooPauseMu.Lock()
+		yahooPauseUntil, yahooPauseLast = time.Time{}, 0
+		yahooPauseMu.Unlock()
+		disabledMu.Lock()
+		loadDisabledSymbols()
+		disabled = make(map[string]DisabledSymbol)
+		saveDisabledSymbols()
+		disabledMu.Unlock()
+		tickerCache.Reset()
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
+func TestYahooStatusCode(t *testing.T) {
+	tests := map[int]string{200: "", 401: "", 404: "", 429: Yahoo429, 999: Yahoo999, 500: Yahoo5xx, 503: Yahoo5xx}
+	for status, want := range tests {
+		if got := yahooStatusCode(status); got != want {
+			t.Errorf("yahooStatusCode(%d) = %q, se esperaba %q", status, got, want)
+		}
+	}
+}
+
+func TestYahooChartCode(t *testing.T) {
+	tests := []struct {
+		code, description, want string
+	}{
+		{"Not Found", "No data found, symbol may be delisted", YahooDelisted},
+		{"Not Found", "No data found", YahooNotFound},
+		{"Bad Request", "Invalid input - interval=1x", ""},
+	}
+	for _, tt := range tests {
+		if got := yahooChartCode(tt.code, tt.description); got != tt.want {
+			t.Errorf("yahooChartCode(%q, %q) = %q, se esperaba %q", tt.code, tt.description, got, tt.want)
+		}
+	}
+}
+
+func TestValidateYahooPolicies(t *testing.T) {
+	tests := []struct {
+		policies map[string]string
+		want     string
+	}{
+		{map[string]string{Yahoo429: PolicyBackoff, YahooNotFound: PolicyDisable}, ""},
+		{map[string]string{"404": PolicyFail}, "error desconocido"},
+		{map[string]string{Yahoo5xx: "ignorar"}, "política desconocida"},
+		{map[string]string{Yahoo429: PolicyDisable}, "solo los errores de símbolo"},
+	}
+	for _, tt := range tests {
+		err := validateYahooPolicies(tt.policies)
+		if (tt.want == "") != (err == nil) || (err != nil && !strings.Contains(err.Error(), tt.want)) {
+			t.Errorf("%v: err = %v, se esperaba %q", tt.policies, err, tt.want)
+		}
+	}
+}
+
+func TestYahooStatusPolicyPerEndpoint(t *testing.T) {
+	chart := "https://query1.finance.yahoo.com/v8/finance/chart/GGAL.BA"
+	summary := "https://query2.finance.yahoo.com/v10/finance/quoteSummary/GGAL.BA?modules=price"
+	tests := []struct {
+		name     string
+		provider string
+		url      string
+		statuses []int // Respuestas sucesivas del servidor
+		attempts int
+		paused   bool
+		kind     error // nil si la consulta devuelve la respuesta
+	}{
+		{"429 en chart pausa Yahoo", "yahoo", chart, []int{429}, 1, true, ErrRateLimited},
+		{"999 en quoteSummary pausa Yahoo", "yahoo", summary, []int{999}, 1, true, ErrRateLimited},
+		{"5xx en chart reintenta", "yahoo", chart, []int{503, 502, 200}, 3, false, nil},
+		{"5xx en quoteSummary reintenta", "yahoo", summary, []int{500, 200}, 2, false, nil},
+		{"404 en chart no tiene política", "yahoo", chart, []int{404}, 1, false, nil},
+		{"429 de otro proveedor no pausa Yahoo", "iol", "https://api.invertironline.com/api/v2/bCBA/Titulos/GGAL/Cotizacion", []int{429}, 1, false, nil},
+	}
+	for _, tt := range tests {
+		resetYahooState(t)
+		attempts := 0
+		client := NewHTTPClientWithDoer(tt.provider, doerFunc(func(req *http.Request) (*http.Response, error) {
+			status := tt.statuses[min(attempts, len(tt.statuses)-1)]
+			attempts++
+			return &http.Response{StatusCode: status, Body: io.NopCloser(strings.NewReader("{}")), Header: http.Header{}, Request: req}, nil
+		}))
+		client.config.MaxRetries, client.config.Backoff = 3, Duration(time.Millisecond)
+
+		resp, err := client.GetWithRetry(tt.url, nil)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if attempts != tt.attempts {
+			t.Errorf("%s: %d intentos, se esperaban %d", tt.name, attempts, tt.attempts)
+		}
+		if paused := yahooPaused() != nil; paused != tt.paused {
+			t.Errorf("%s: Yahoo en pausa = %v, se esperaba %v", tt.name, paused, tt.paused)
+		}
+		switch {
+		case tt.kind == nil && err != nil:
+			t.Errorf("%s: error inesperado %v", tt.name, err)
+		case tt.kind != nil && !errors.Is(err, tt.kind):
+			t.Errorf("%s: error = %v, se esperaba %v", tt.name, err, tt.kind)
+		}
+	}
+
+	// Durante la pausa ninguna consulta a Yahoo llega al servidor, por ningún endpoint
+	resetYahooState(t)
+	pauseYahoo(Yahoo429, 429)
+	client := NewHTTPClientWithDoer("yahoo", doerFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("consulta a %s durante la pausa", req.URL)
+		return nil, fmt.Errorf("no debería consultarse")
+	}))
+	for _, url := range []string{chart, summary} {
+		if _, err := client.GetWithRetry(url, nil); !errors.Is(err, ErrRateLimited) {
+			t.Errorf("%s en pausa: error = %v, se esperaba ErrRateLimited", url, err)
+		}
+	}
+}
+
+func TestYahooChartErrorPolicy(t *testing.T) {
+	resetYahooState(t)
+	client := NewHTTPClientWithDoer("yahoo", doerFunc(func(req *http.Request) (*http.Response, error) {
+		description := "No data found"
+		if strings.Contains(req.URL.Path, "VIEJO.BA") {
+			description = "No data found, symbol may be delisted"
+		}
+		body := `{"chart": {"result": null, "error": {"code": "Not Found", "description": "` + description + `"}}}`
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(body)), Header: http.Header{}, Request: req}, nil
+	}))
+
+	// delisted desactiva el símbolo; not_found solo falla la consulta
+	for symbol, wantDisabled := range map[string]bool{"VIEJO.BA": true, "NOEXISTE.BA": false} {
+		_, _, _, _, err := getTickerData(symbol, client)
+		if !errors.Is(err, ErrInvalidSymbol) {
+			t.Errorf("%s: error = %v, se esperaba ErrInvalidSymbol", symbol, err)
+		}
+		if _, ok := symbolDisabled(symbol); ok != wantDisabled {
+			t.Errorf("%s desactivado = %v, se esperaba %v", symbol, ok, wantDisabled)
+		}
+	}
+
+	// Un símbolo desactivado no se vuelve a consultar en el ciclo siguiente
+	tickerCache.Reset()
+	client.doer = doerFunc(func(req *http.Request) (*http.Response, error) {
+		t.Errorf("se consultó %s estando desactivado", req.URL)
+		return nil, fmt.Errorf("no debería consultarse")
+	})
+	if _, _, _, _, err := getTickerData("VIEJO.BA", client); err == nil || !strings.Contains(err.Error(), "desactivado") {
+		t.Errorf("error = %v, se esperaba el de símbolo desactivado", err)
+	}
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/elkanika/bolsa-valores-argentina-GO/bolsa"
+)
+
+// FlexFloat decodifica un número que Yahoo puede enviar como número, texto, null u objeto {raw, fmt}
+type FlexFloat = bolsa.FlexFloat
+
+// saveDiagnostic guarda el body crudo de una respuesta que no se pudo interpretar, para reportarlo
+func saveDiagnostic(source, symbol string, body []byte, reason string) string {
+	dir, err := appDir()
+	if err != nil {
+		return ""
+	}
+	dir = filepath.Join(dir, "diagnostics")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return ""
+	}
+
+	safeSymbol := strings.NewReplacer("=", "_", "^", "_", "/", "_").Replace(symbol)
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s-%s.txt", time.Now().Format("20060102-150405"), source, safeSymbol))
+
+	content := fmt.Sprintf("// motivo: %s\n%s", reason, body)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return ""
+	}
+	return path
+}
+
+// schemaError registra una respuesta con esquema desconocido y devuelve el error para el llamador
+func schemaError(source, symbol string, body []byte, reason string) error {
+	path := saveDiagnostic(source, symbol, body, reason)
+	if path != "" {
+		fmt.Printf("%s⚠️ Esquema desconocido de Yahoo (%s) para %s: %s. Respuesta guardada en %s%s\n",
+			Yellow, source, symbol, reason, path, Reset)
+	}
+	return &QuoteError{Kind: ErrParse, Symbol: symbol, Err: fmt.Errorf("esquema desconocido en la respuesta %s de %s: %s", source, symbol, reason)}
+}
+
+// yahooError traduce un error del paquete bolsa a QuoteError: guarda el diagnóstico de las respuestas con
+// esquema desconocido y aplica el código de Yahoo a los símbolos inexistentes o deslistados
+func yahooError(symbol string, err error) error {
+	var schemaErr *bolsa.SchemaError
+	var chartErr *bolsa.ChartError
+	var statusErr *bolsa.StatusError
+	switch {
+	case errors.As(err, &schemaErr):
+		return schemaError(schemaErr.Source, symbol, schemaErr.Body, schemaErr.Reason)
+	case errors.As(err, &chartErr):
+		if code := yahooChartCode(chartErr.Code, chartErr.Description); code != "" {
+			return &QuoteError{Kind: ErrInvalidSymbol, Symbol: symbol, Code: code, Err: err}
+		}
+		return &QuoteError{Kind: ErrParse, Symbol: symbol, Err: err}
+	case errors.Is(err, bolsa.ErrNoData):
+		return symbolError(symbol, err)
+	case errors.As(err, &statusErr):
+		return statusError(statusErr.Status, "para %s", symbol)
+	}
+	// Los errores del transporte ya vienen clasificados por GetWithRetry
+	return err
+}